@@ -0,0 +1,43 @@
+//go:build fuzz
+
+package main
+
+import (
+	"testing"
+	"unicode"
+)
+
+// referenceIsPalindrome is the oracle for FuzzIsPalindrome
+func referenceIsPalindrome(s string) bool {
+	var runes []rune
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, unicode.ToLower(r))
+		}
+	}
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		if runes[i] != runes[j] {
+			return false
+		}
+	}
+	return true
+}
+
+func FuzzIsPalindrome(f *testing.F) {
+	for _, seed := range []string{"", "racecar", "hello", "A man, a plan, a canal: Panama", "A1b2c3c2b1A", "!@#$%^&*()"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		// Case folding of non-ASCII letters is outside the spec
+		for _, r := range s {
+			if r > unicode.MaxASCII {
+				t.Skip("input is not ASCII")
+			}
+		}
+		got := IsPalindrome(s)
+		if want := referenceIsPalindrome(s); got != want {
+			t.Errorf("IsPalindrome(%q) = %v; want %v", s, got, want)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+//go:build hidden
+
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestHiddenFindMaxExtremes(t *testing.T) {
+	tests := []struct {
+		input    []int
+		expected int
+	}{
+		{[]int{math.MinInt64}, math.MinInt64},
+		{[]int{math.MinInt64, math.MaxInt64}, math.MaxInt64},
+		{[]int{0, -1, -2}, 0},
+		{[]int{-7, -7, -7}, -7},
+	}
+
+	for _, tt := range tests {
+		if result := FindMax(tt.input); result != tt.expected {
+			t.Errorf("FindMax(%v) = %d, expected %d", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestHiddenInputNotModified(t *testing.T) {
+	input := []int{5, 3, 5, 2, 8, 3}
+	original := append([]int{}, input...)
+
+	RemoveDuplicates(input)
+	ReverseSlice(input)
+	FilterEven(input)
+
+	if !reflect.DeepEqual(input, original) {
+		t.Errorf("input slice was modified: got %v, expected %v", input, original)
+	}
+}
+
+func TestHiddenReturnsNewSlice(t *testing.T) {
+	input := []int{2, 4, 6}
+
+	reversed := ReverseSlice(input)
+	if len(reversed) > 0 {
+		reversed[0] = 100
+	}
+	filtered := FilterEven(input)
+	if len(filtered) > 0 {
+		filtered[0] = 100
+	}
+
+	if !reflect.DeepEqual(input, []int{2, 4, 6}) {
+		t.Errorf("ReverseSlice or FilterEven returned a slice sharing memory with the input: %v", input)
+	}
+}
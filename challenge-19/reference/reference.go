@@ -0,0 +1,60 @@
+// Package reference is the hidden reference solution used for differential testing
+package reference
+
+import "math/rand"
+
+// FindMax returns the maximum value in a slice of integers, or 0 if it is empty
+func FindMax(numbers []int) int {
+	if len(numbers) == 0 {
+		return 0
+	}
+	max := numbers[0]
+	for _, n := range numbers[1:] {
+		if n > max {
+			max = n
+		}
+	}
+	return max
+}
+
+// RemoveDuplicates returns the distinct values of numbers in their original order
+func RemoveDuplicates(numbers []int) []int {
+	seen := make(map[int]bool)
+	result := []int{}
+	for _, n := range numbers {
+		if !seen[n] {
+			seen[n] = true
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// GenerateRemoveDuplicates draws from a small range so duplicates are common
+func GenerateRemoveDuplicates(r *rand.Rand) []interface{} {
+	numbers := make([]int, r.Intn(30))
+	for i := range numbers {
+		numbers[i] = r.Intn(10) - 5
+	}
+	return []interface{}{numbers}
+}
+
+// ReverseSlice returns a new slice with the elements in reverse order
+func ReverseSlice(slice []int) []int {
+	result := make([]int, len(slice))
+	for i, n := range slice {
+		result[len(slice)-1-i] = n
+	}
+	return result
+}
+
+// FilterEven returns the even numbers of the slice
+func FilterEven(numbers []int) []int {
+	result := []int{}
+	for _, n := range numbers {
+		if n%2 == 0 {
+			result = append(result, n)
+		}
+	}
+	return result
+}
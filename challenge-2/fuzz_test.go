@@ -0,0 +1,33 @@
+//go:build fuzz
+
+package main
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+// referenceReverseString is the oracle for FuzzReverseString
+func referenceReverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func FuzzReverseString(f *testing.F) {
+	for _, seed := range []string{"hello", "Go is fun!", "", "madam", "12345!@#$%", "GoLang"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		if !utf8.ValidString(s) {
+			t.Skip("input is not valid UTF-8")
+		}
+		got := ReverseString(s)
+		if want := referenceReverseString(s); got != want {
+			t.Errorf("ReverseString(%q) = %q, want %q", s, got, want)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+// Package reference is the hidden reference solution used for differential testing
+package reference
+
+import "math/rand"
+
+// BinarySearch returns the index of target in the sorted array, or -1
+func BinarySearch(arr []int, target int) int {
+	left, right := 0, len(arr)-1
+	for left <= right {
+		mid := left + (right-left)/2
+		switch {
+		case arr[mid] == target:
+			return mid
+		case arr[mid] < target:
+			left = mid + 1
+		default:
+			right = mid - 1
+		}
+	}
+	return -1
+}
+
+// BinarySearchRecursive returns the index of target in arr[left:right+1], or -1
+func BinarySearchRecursive(arr []int, target int, left int, right int) int {
+	if left > right {
+		return -1
+	}
+	mid := left + (right-left)/2
+	switch {
+	case arr[mid] == target:
+		return mid
+	case arr[mid] < target:
+		return BinarySearchRecursive(arr, target, mid+1, right)
+	default:
+		return BinarySearchRecursive(arr, target, left, mid-1)
+	}
+}
+
+// FindInsertPosition returns the index where target belongs in the sorted array
+func FindInsertPosition(arr []int, target int) int {
+	left, right := 0, len(arr)
+	for left < right {
+		mid := left + (right-left)/2
+		if arr[mid] < target {
+			left = mid + 1
+		} else {
+			right = mid
+		}
+	}
+	return left
+}
+
+// sortedInput returns a sorted array of distinct values and a target that is found about half the time
+func sortedInput(r *rand.Rand) ([]int, int) {
+	arr := make([]int, r.Intn(40))
+	value := r.Intn(20) - 10
+	for i := range arr {
+		arr[i] = value
+		value += 1 + r.Intn(5)
+	}
+	if len(arr) > 0 && r.Intn(2) == 0 {
+		return arr, arr[r.Intn(len(arr))]
+	}
+	return arr, r.Intn(value+20) - 15
+}
+
+// GenerateBinarySearch generates sorted arrays of distinct values
+func GenerateBinarySearch(r *rand.Rand) []interface{} {
+	arr, target := sortedInput(r)
+	return []interface{}{arr, target}
+}
+
+// GenerateBinarySearchRecursive searches the whole array, as the challenge's callers do
+func GenerateBinarySearchRecursive(r *rand.Rand) []interface{} {
+	arr, target := sortedInput(r)
+	return []interface{}{arr, target, 0, len(arr) - 1}
+}
+
+// GenerateFindInsertPosition generates sorted arrays of distinct values
+func GenerateFindInsertPosition(r *rand.Rand) []interface{} {
+	arr, target := sortedInput(r)
+	return []interface{}{arr, target}
+}
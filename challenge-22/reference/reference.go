@@ -0,0 +1,59 @@
+// Package reference is the hidden reference solution used for differential testing
+package reference
+
+import "math/rand"
+
+// coinSystems are canonical denomination sets, for which the greedy choice is optimal
+var coinSystems = [][]int{
+	{1, 5, 10, 25, 50},
+	{1, 5, 10, 25},
+	{1, 5, 10},
+	{1, 2, 5, 10, 20, 50, 100},
+	{5, 10, 25},
+	{10, 50},
+}
+
+// MinCoins returns the greedy minimum number of coins for amount, or -1 if it cannot be made
+func MinCoins(amount int, denominations []int) int {
+	count := 0
+	for _, n := range CoinCombination(amount, denominations) {
+		count += n
+	}
+	if count == 0 && amount > 0 {
+		return -1
+	}
+	return count
+}
+
+// CoinCombination returns the coins the greedy approach uses, or an empty map if amount cannot be made
+func CoinCombination(amount int, denominations []int) map[int]int {
+	combination := map[int]int{}
+	remaining := amount
+	for i := len(denominations) - 1; i >= 0; i-- {
+		coin := denominations[i]
+		if coin <= 0 || remaining < coin {
+			continue
+		}
+		combination[coin] = remaining / coin
+		remaining %= coin
+	}
+	if remaining != 0 {
+		return map[int]int{}
+	}
+	return combination
+}
+
+// coinInput returns a random amount and one of the canonical coin systems
+func coinInput(r *rand.Rand) []interface{} {
+	return []interface{}{r.Intn(1000), coinSystems[r.Intn(len(coinSystems))]}
+}
+
+// GenerateMinCoins generates amounts up to 1000 with canonical coin systems
+func GenerateMinCoins(r *rand.Rand) []interface{} {
+	return coinInput(r)
+}
+
+// GenerateCoinCombination generates amounts up to 1000 with canonical coin systems
+func GenerateCoinCombination(r *rand.Rand) []interface{} {
+	return coinInput(r)
+}
@@ -0,0 +1,59 @@
+//go:build fuzz
+
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// referencePatternMatch is the oracle for FuzzPatternSearch
+func referencePatternMatch(text, pattern string) []int {
+	indices := []int{}
+	if pattern == "" {
+		return indices
+	}
+	for i := 0; i+len(pattern) <= len(text); i++ {
+		if strings.HasPrefix(text[i:], pattern) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+func FuzzPatternSearch(f *testing.F) {
+	f.Add("ABABDABACDABABCABAB", "ABABCABAB")
+	f.Add("AABAACAADAABAABA", "AABA")
+	f.Add("AAAAAA", "AA")
+	f.Add("ACACACACGTACACACA", "ACACACA")
+	f.Add("", "")
+
+	searches := []struct {
+		name   string
+		search func(text, pattern string) []int
+	}{
+		{"NaivePatternMatch", NaivePatternMatch},
+		{"KMPSearch", KMPSearch},
+		{"RabinKarpSearch", RabinKarpSearch},
+	}
+
+	f.Fuzz(func(t *testing.T, text, pattern string) {
+		// Indices are byte offsets, so multi-byte characters are outside the spec
+		for _, b := range []byte(text + pattern) {
+			if b > unicode.MaxASCII {
+				t.Skip("input is not ASCII")
+			}
+		}
+		want := referencePatternMatch(text, pattern)
+		for _, s := range searches {
+			got := append([]int{}, s.search(text, pattern)...)
+			sort.Ints(got)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s(%q, %q) = %v, expected %v", s.name, text, pattern, got, want)
+			}
+		}
+	})
+}
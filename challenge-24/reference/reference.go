@@ -0,0 +1,102 @@
+// Package reference is the hidden reference solution used for differential testing
+package reference
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// DPLongestIncreasingSubsequence returns the length of the longest strictly increasing subsequence
+func DPLongestIncreasingSubsequence(nums []int) int {
+	return len(GetLISElements(nums))
+}
+
+// OptimizedLIS returns the length of the longest strictly increasing subsequence
+func OptimizedLIS(nums []int) int {
+	tails := []int{}
+	for _, n := range nums {
+		i := sort.SearchInts(tails, n)
+		if i == len(tails) {
+			tails = append(tails, n)
+		} else {
+			tails[i] = n
+		}
+	}
+	return len(tails)
+}
+
+// GetLISElements returns one longest strictly increasing subsequence
+func GetLISElements(nums []int) []int {
+	if len(nums) == 0 {
+		return []int{}
+	}
+
+	lengths := make([]int, len(nums))
+	previous := make([]int, len(nums))
+	best := 0
+	for i := range nums {
+		lengths[i], previous[i] = 1, -1
+		for j := 0; j < i; j++ {
+			if nums[j] < nums[i] && lengths[j]+1 > lengths[i] {
+				lengths[i], previous[i] = lengths[j]+1, j
+			}
+		}
+		if lengths[i] > lengths[best] {
+			best = i
+		}
+	}
+
+	result := make([]int, lengths[best])
+	for i, k := best, len(result)-1; i >= 0; i, k = previous[i], k-1 {
+		result[k] = nums[i]
+	}
+	return result
+}
+
+// CheckGetLISElements accepts any strictly increasing subsequence of the reference's length
+func CheckGetLISElements(args, got, want []interface{}) error {
+	nums, result := args[0].([]int), got[0].([]int)
+	if len(result) != len(want[0].([]int)) {
+		return fmt.Errorf("subsequence has length %d, the longest has length %d", len(result), len(want[0].([]int)))
+	}
+
+	next := 0
+	for i, n := range result {
+		if i > 0 && n <= result[i-1] {
+			return fmt.Errorf("%v is not strictly increasing", result)
+		}
+		for next < len(nums) && nums[next] != n {
+			next++
+		}
+		if next == len(nums) {
+			return fmt.Errorf("%v is not a subsequence of the input", result)
+		}
+		next++
+	}
+	return nil
+}
+
+// lisInput draws from a small range so equal values and several longest subsequences are common
+func lisInput(r *rand.Rand) []interface{} {
+	nums := make([]int, r.Intn(40))
+	for i := range nums {
+		nums[i] = r.Intn(40) - 20
+	}
+	return []interface{}{nums}
+}
+
+// GenerateDPLongestIncreasingSubsequence generates short slices with repeated values
+func GenerateDPLongestIncreasingSubsequence(r *rand.Rand) []interface{} {
+	return lisInput(r)
+}
+
+// GenerateOptimizedLIS generates short slices with repeated values
+func GenerateOptimizedLIS(r *rand.Rand) []interface{} {
+	return lisInput(r)
+}
+
+// GenerateGetLISElements generates short slices with repeated values
+func GenerateGetLISElements(r *rand.Rand) []interface{} {
+	return lisInput(r)
+}
@@ -0,0 +1,210 @@
+// Package reference is the hidden reference solution used for differential testing
+package reference
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+)
+
+// unreachable is the distance reported for vertices that cannot be reached from the source
+const unreachable = 1000000000
+
+// BreadthFirstSearch returns shortest path distances and predecessors in an unweighted graph
+func BreadthFirstSearch(graph [][]int, source int) ([]int, []int) {
+	distances, predecessors := initPaths(len(graph), source)
+	queue := []int{source}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, v := range graph[u] {
+			if distances[v] == unreachable {
+				distances[v], predecessors[v] = distances[u]+1, u
+				queue = append(queue, v)
+			}
+		}
+	}
+	return distances, predecessors
+}
+
+// Dijkstra returns shortest path distances and predecessors for non-negative edge weights
+func Dijkstra(graph [][]int, weights [][]int, source int) ([]int, []int) {
+	distances, predecessors := initPaths(len(graph), source)
+	done := make([]bool, len(graph))
+	for {
+		u := -1
+		for v := range graph {
+			if !done[v] && distances[v] != unreachable && (u == -1 || distances[v] < distances[u]) {
+				u = v
+			}
+		}
+		if u == -1 {
+			return distances, predecessors
+		}
+		done[u] = true
+		for i, v := range graph[u] {
+			if d := distances[u] + weights[u][i]; d < distances[v] {
+				distances[v], predecessors[v] = d, u
+			}
+		}
+	}
+}
+
+// BellmanFord returns shortest path distances, reachability without negative cycles and predecessors
+func BellmanFord(graph [][]int, weights [][]int, source int) ([]int, []bool, []int) {
+	distances, predecessors := initPaths(len(graph), source)
+	for round := 1; round < len(graph); round++ {
+		for u := range graph {
+			if distances[u] == unreachable {
+				continue
+			}
+			for i, v := range graph[u] {
+				if d := distances[u] + weights[u][i]; d < distances[v] {
+					distances[v], predecessors[v] = d, u
+				}
+			}
+		}
+	}
+
+	hasPath := make([]bool, len(graph))
+	for v := range graph {
+		hasPath[v] = distances[v] != unreachable
+	}
+	// Vertices reachable from a negative cycle have no shortest path
+	for u := range graph {
+		for i, v := range graph[u] {
+			if distances[u] != unreachable && distances[u]+weights[u][i] < distances[v] {
+				markReachable(graph, v, hasPath)
+			}
+		}
+	}
+	return distances, hasPath, predecessors
+}
+
+// markReachable clears hasPath for every vertex reachable from v
+func markReachable(graph [][]int, v int, hasPath []bool) {
+	if !hasPath[v] {
+		return
+	}
+	hasPath[v] = false
+	for _, w := range graph[v] {
+		markReachable(graph, w, hasPath)
+	}
+}
+
+// initPaths returns the initial distances and predecessors for a search from source
+func initPaths(n, source int) ([]int, []int) {
+	distances := make([]int, n)
+	predecessors := make([]int, n)
+	for i := range distances {
+		distances[i], predecessors[i] = unreachable, -1
+	}
+	distances[source] = 0
+	return distances, predecessors
+}
+
+// CheckBreadthFirstSearch requires the reference distances and any valid shortest path tree
+func CheckBreadthFirstSearch(args, got, want []interface{}) error {
+	return checkPaths(args[0].([][]int), nil, args[1].(int), got[0].([]int), got[1].([]int), want[0].([]int))
+}
+
+// CheckDijkstra requires the reference distances and any valid shortest path tree
+func CheckDijkstra(args, got, want []interface{}) error {
+	return checkPaths(args[0].([][]int), args[1].([][]int), args[2].(int), got[0].([]int), got[1].([]int), want[0].([]int))
+}
+
+// CheckBellmanFord requires the reference distances and reachability and any valid shortest path tree
+func CheckBellmanFord(args, got, want []interface{}) error {
+	if !reflect.DeepEqual(got[1], want[1]) {
+		return fmt.Errorf("hasPath = %v, want %v", got[1], want[1])
+	}
+	return checkPaths(args[0].([][]int), args[1].([][]int), args[2].(int), got[0].([]int), got[2].([]int), want[0].([]int))
+}
+
+// checkPaths compares distances and checks that each predecessor is on a shortest path.
+// A nil weights slice means every edge has weight 1.
+func checkPaths(graph, weights [][]int, source int, distances, predecessors, want []int) error {
+	if !reflect.DeepEqual(distances, want) {
+		return fmt.Errorf("distances = %v, want %v", distances, want)
+	}
+	if len(predecessors) != len(graph) {
+		return fmt.Errorf("predecessors has length %d, want %d", len(predecessors), len(graph))
+	}
+
+	for v, u := range predecessors {
+		if v == source || distances[v] == unreachable {
+			if u != -1 {
+				return fmt.Errorf("predecessors[%d] = %d, want -1", v, u)
+			}
+			continue
+		}
+		valid := false
+		if u >= 0 && u < len(graph) {
+			for i, w := range graph[u] {
+				weight := 1
+				if weights != nil {
+					weight = weights[u][i]
+				}
+				if w == v && distances[u]+weight == distances[v] {
+					valid = true
+				}
+			}
+		}
+		if !valid {
+			return fmt.Errorf("predecessors[%d] = %d is not on a shortest path to %d", v, u, v)
+		}
+	}
+	return nil
+}
+
+// randomGraph returns a directed graph without self loops or parallel edges
+func randomGraph(r *rand.Rand) [][]int {
+	n := 1 + r.Intn(8)
+	graph := make([][]int, n)
+	for u := range graph {
+		graph[u] = []int{}
+		for v := 0; v < n; v++ {
+			if v != u && r.Intn(3) == 0 {
+				graph[u] = append(graph[u], v)
+			}
+		}
+	}
+	return graph
+}
+
+// GenerateBreadthFirstSearch generates small directed graphs
+func GenerateBreadthFirstSearch(r *rand.Rand) []interface{} {
+	graph := randomGraph(r)
+	return []interface{}{graph, r.Intn(len(graph))}
+}
+
+// GenerateDijkstra generates small directed graphs with non-negative weights
+func GenerateDijkstra(r *rand.Rand) []interface{} {
+	graph := randomGraph(r)
+	weights := make([][]int, len(graph))
+	for u := range graph {
+		weights[u] = make([]int, len(graph[u]))
+		for i := range graph[u] {
+			weights[u][i] = r.Intn(20)
+		}
+	}
+	return []interface{}{graph, weights, r.Intn(len(graph))}
+}
+
+// GenerateBellmanFord generates small directed graphs with negative weights but no negative cycles.
+// Only edges to higher vertices are negative and edges back are heavy enough to outweigh them.
+func GenerateBellmanFord(r *rand.Rand) []interface{} {
+	graph := randomGraph(r)
+	weights := make([][]int, len(graph))
+	for u := range graph {
+		weights[u] = make([]int, len(graph[u]))
+		for i, v := range graph[u] {
+			if v > u {
+				weights[u][i] = r.Intn(20) - 5
+			} else {
+				weights[u][i] = 5*len(graph) + r.Intn(20)
+			}
+		}
+	}
+	return []interface{}{graph, weights, r.Intn(len(graph))}
+}
@@ -0,0 +1,62 @@
+//go:build fuzz
+
+package regex
+
+import (
+	"regexp"
+	"testing"
+)
+
+// referencePhone is the oracle for FuzzValidatePhone
+var referencePhone = regexp.MustCompile(`^\(\d{3}\) \d{3}-\d{4}$`)
+
+func FuzzValidatePhone(f *testing.F) {
+	for _, seed := range []string{"(555) 123-4567", "555 123-4567", "555-123-4567", "(555) 123-45678", "(555)123-4567"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, phone string) {
+		got := ValidatePhone(phone)
+		if want := referencePhone.MatchString(phone); got != want {
+			t.Errorf("ValidatePhone(%q) = %v, want %v", phone, got, want)
+		}
+	})
+}
+
+// referenceMaskCreditCard is the oracle for FuzzMaskCreditCard
+func referenceMaskCreditCard(cardNumber string) string {
+	digits := 0
+	for _, r := range cardNumber {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+
+	masked := []rune(cardNumber)
+	for i, r := range masked {
+		if r >= '0' && r <= '9' && digits > 4 {
+			masked[i] = 'X'
+			digits--
+		}
+	}
+	return string(masked)
+}
+
+func FuzzMaskCreditCard(f *testing.F) {
+	for _, seed := range []string{"1234-5678-9012-3456", "1234567890123456", "XXXX-XXXX-XXXX-3456", "1234-5678", ""} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, cardNumber string) {
+		// Card numbers contain only digits, hyphens and already masked digits
+		for _, r := range cardNumber {
+			if !(r >= '0' && r <= '9' || r == '-' || r == 'X') {
+				t.Skip("input is not a card number")
+			}
+		}
+		got := MaskCreditCard(cardNumber)
+		if want := referenceMaskCreditCard(cardNumber); got != want {
+			t.Errorf("MaskCreditCard(%q) = %v, want %v", cardNumber, got, want)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+//go:build fuzz
+
+package challenge6
+
+import (
+	"strings"
+	"testing"
+)
+
+// referenceCountWordFrequency is the oracle for FuzzCountWordFrequency.
+// Words are runs of ASCII letters and digits, compared case-insensitively.
+func referenceCountWordFrequency(text string) map[string]int {
+	counts := map[string]int{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	for _, word := range words {
+		counts[word]++
+	}
+	return counts
+}
+
+func FuzzCountWordFrequency(f *testing.F) {
+	for _, seed := range []string{
+		"The quick brown fox jumps over the lazy dog.",
+		"Hello, hello! How are you doing today?",
+		"  Spaces,   tabs,\t\tand\nnew-lines are ignored!  ",
+		"Numbers123 and456 mixed789 content",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		// Apostrophes join words ("Let's" is "lets") and non-ASCII letters are outside the spec
+		for _, r := range text {
+			if r == '\'' || r > 127 {
+				t.Skip("input outside the fuzzed alphabet")
+			}
+		}
+		got := formatMap(CountWordFrequency(text))
+		if want := formatMap(referenceCountWordFrequency(text)); got != want {
+			t.Errorf("CountWordFrequency(%q) = %v, want %v", text, got, want)
+		}
+	})
+}
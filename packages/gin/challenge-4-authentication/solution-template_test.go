@@ -0,0 +1,382 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	m.Run()
+}
+
+// resetStore clears the in-memory users and tokens between tests
+func resetStore() {
+	users = []User{}
+	blacklistedTokens = make(map[string]bool)
+	refreshTokens = make(map[string]int)
+	nextUserID = 1
+}
+
+// sendJSON sends a request with an optional JSON body and bearer token
+func sendJSON(router *gin.Engine, method, path string, body interface{}, token string) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// registerAndLogin registers a user through the API and returns the tokens from logging in
+func registerAndLogin(t *testing.T, router *gin.Engine, username string) TokenResponse {
+	t.Helper()
+	w := sendJSON(router, "POST", "/auth/register", map[string]string{
+		"username":         username,
+		"email":            username + "@example.com",
+		"password":         "Secure#Pass1",
+		"confirm_password": "Secure#Pass1",
+		"first_name":       "Test",
+		"last_name":        "User",
+	}, "")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = sendJSON(router, "POST", "/auth/login", map[string]string{"username": username, "password": "Secure#Pass1"}, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response struct {
+		Success bool          `json:"success"`
+		Data    TokenResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.True(t, response.Success)
+	return response.Data
+}
+
+func TestPasswordStrength(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		expected bool
+	}{
+		{"Strong password", "Secure#Pass1", true},
+		{"Too short", "Se#1a", false},
+		{"No uppercase", "secure#pass1", false},
+		{"No lowercase", "SECURE#PASS1", false},
+		{"No number", "Secure#Pass", false},
+		{"No special character", "SecurePass1", false},
+		{"Empty string", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isStrongPassword(tt.password))
+		})
+	}
+}
+
+func TestPasswordHashing(t *testing.T) {
+	hash, err := hashPassword("Secure#Pass1")
+	require.NoError(t, err)
+	assert.NotEqual(t, "Secure#Pass1", hash)
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	require.NoError(t, err, "hash is not a bcrypt hash")
+	assert.Equal(t, 12, cost)
+
+	assert.True(t, verifyPassword("Secure#Pass1", hash))
+	assert.False(t, verifyPassword("Wrong#Pass1", hash))
+}
+
+func TestTokenGenerationAndValidation(t *testing.T) {
+	resetStore()
+
+	tokens, err := generateTokens(7, "alice", RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.Equal(t, 7, refreshTokens[tokens.RefreshToken])
+
+	parsed, err := jwt.ParseWithClaims(tokens.AccessToken, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	})
+	require.NoError(t, err, "access token is not a JWT signed with jwtSecret")
+	assert.Equal(t, jwt.SigningMethodHS256.Alg(), parsed.Method.Alg())
+
+	claims, err := validateToken(tokens.AccessToken)
+	require.NoError(t, err)
+	require.NotNil(t, claims)
+	assert.Equal(t, 7, claims.UserID)
+	assert.Equal(t, "alice", claims.Username)
+	assert.Equal(t, RoleAdmin, claims.Role)
+
+	_, err = validateToken(tokens.AccessToken + "x")
+	assert.Error(t, err, "tampered token accepted")
+
+	blacklistedTokens[tokens.AccessToken] = true
+	_, err = validateToken(tokens.AccessToken)
+	assert.Error(t, err, "blacklisted token accepted")
+}
+
+func TestExpiredTokenRejected(t *testing.T) {
+	claims := &JWTClaims{
+		UserID:   1,
+		Username: "alice",
+		Role:     RoleUser,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+	require.NoError(t, err)
+
+	_, err = validateToken(token)
+	assert.Error(t, err)
+}
+
+func TestRegister(t *testing.T) {
+	valid := map[string]string{
+		"username":         "alice",
+		"email":            "alice@example.com",
+		"password":         "Secure#Pass1",
+		"confirm_password": "Secure#Pass1",
+		"first_name":       "Alice",
+		"last_name":        "Smith",
+	}
+	with := func(field, value string) map[string]string {
+		request := make(map[string]string)
+		for k, v := range valid {
+			request[k] = v
+		}
+		request[field] = value
+		return request
+	}
+
+	tests := []struct {
+		name           string
+		request        map[string]string
+		expectedStatus int
+	}{
+		{"Valid registration", valid, http.StatusCreated},
+		{"Passwords do not match", with("confirm_password", "Other#Pass1"), http.StatusBadRequest},
+		{"Weak password", map[string]string{
+			"username": "alice", "email": "alice@example.com", "password": "weakpassword",
+			"confirm_password": "weakpassword", "first_name": "Alice", "last_name": "Smith",
+		}, http.StatusBadRequest},
+		{"Invalid email", with("email", "not-an-email"), http.StatusBadRequest},
+		{"Short username", with("username", "al"), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetStore()
+			w := sendJSON(setupRouter(), "POST", "/auth/register", tt.request, "")
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+		})
+	}
+
+	t.Run("Stores a hashed password", func(t *testing.T) {
+		resetStore()
+		w := sendJSON(setupRouter(), "POST", "/auth/register", valid, "")
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		user := findUserByUsername("alice")
+		require.NotNil(t, user, "registered user not found")
+		assert.Equal(t, RoleUser, user.Role)
+		assert.NotEqual(t, "Secure#Pass1", user.PasswordHash)
+		assert.True(t, verifyPassword("Secure#Pass1", user.PasswordHash))
+		assert.NotContains(t, w.Body.String(), user.PasswordHash)
+		assert.Equal(t, user, findUserByEmail("alice@example.com"))
+		assert.Equal(t, user, findUserByID(user.ID))
+	})
+
+	t.Run("Duplicate username and email", func(t *testing.T) {
+		resetStore()
+		router := setupRouter()
+		require.Equal(t, http.StatusCreated, sendJSON(router, "POST", "/auth/register", valid, "").Code)
+
+		w := sendJSON(router, "POST", "/auth/register", with("email", "other@example.com"), "")
+		assert.NotEqual(t, http.StatusCreated, w.Code, "duplicate username accepted")
+		w = sendJSON(router, "POST", "/auth/register", with("username", "alice2"), "")
+		assert.NotEqual(t, http.StatusCreated, w.Code, "duplicate email accepted")
+		assert.Len(t, users, 1)
+	})
+}
+
+func TestLogin(t *testing.T) {
+	resetStore()
+	router := setupRouter()
+	tokens := registerAndLogin(t, router, "alice")
+
+	claims, err := validateToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", claims.Username)
+	assert.NotNil(t, findUserByUsername("alice").LastLogin)
+
+	tests := []struct {
+		name           string
+		username       string
+		password       string
+		expectedStatus int
+	}{
+		{"Wrong password", "alice", "Wrong#Pass1", http.StatusUnauthorized},
+		{"Unknown user", "nobody", "Secure#Pass1", http.StatusUnauthorized},
+		{"Short password", "alice", "short", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := sendJSON(router, "POST", "/auth/login", map[string]string{"username": tt.username, "password": tt.password}, "")
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestAccountLockout(t *testing.T) {
+	resetStore()
+	router := setupRouter()
+	registerAndLogin(t, router, "alice")
+
+	wrong := map[string]string{"username": "alice", "password": "Wrong#Pass1"}
+	for i := 0; i < maxFailedAttempts; i++ {
+		assert.Equal(t, http.StatusUnauthorized, sendJSON(router, "POST", "/auth/login", wrong, "").Code)
+	}
+
+	user := findUserByUsername("alice")
+	assert.True(t, isAccountLocked(user))
+
+	// Even the right password is refused while the account is locked
+	w := sendJSON(router, "POST", "/auth/login", map[string]string{"username": "alice", "password": "Secure#Pass1"}, "")
+	assert.Equal(t, http.StatusLocked, w.Code)
+
+	resetFailedAttempts(user)
+	assert.False(t, isAccountLocked(user))
+	assert.Equal(t, 0, user.FailedAttempts)
+}
+
+func TestProtectedRoutes(t *testing.T) {
+	resetStore()
+	router := setupRouter()
+	tokens := registerAndLogin(t, router, "alice")
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{"No token", "", http.StatusUnauthorized},
+		{"Invalid token", "not-a-token", http.StatusUnauthorized},
+		{"Valid token", tokens.AccessToken, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := sendJSON(router, "GET", "/user/profile", nil, tt.token)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+
+	t.Run("Profile data", func(t *testing.T) {
+		w := sendJSON(router, "GET", "/user/profile", nil, tokens.AccessToken)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "alice", response.Data["username"])
+		assert.NotContains(t, w.Body.String(), "Secure#Pass1")
+	})
+}
+
+func TestRoleAuthorization(t *testing.T) {
+	resetStore()
+	router := setupRouter()
+	userTokens := registerAndLogin(t, router, "alice")
+	registerAndLogin(t, router, "bob")
+	findUserByUsername("bob").Role = RoleAdmin
+	adminTokens, err := generateTokens(findUserByUsername("bob").ID, "bob", RoleAdmin)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusForbidden, sendJSON(router, "GET", "/admin/users", nil, userTokens.AccessToken).Code)
+	w := sendJSON(router, "GET", "/admin/users", nil, adminTokens.AccessToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), findUserByUsername("alice").PasswordHash)
+
+	alice := findUserByUsername("alice")
+	path := "/admin/users/" + strconv.Itoa(alice.ID) + "/role"
+	assert.Equal(t, http.StatusForbidden, sendJSON(router, "PUT", path, map[string]string{"role": RoleAdmin}, userTokens.AccessToken).Code)
+	assert.Equal(t, http.StatusBadRequest, sendJSON(router, "PUT", path, map[string]string{"role": "superuser"}, adminTokens.AccessToken).Code)
+	assert.Equal(t, http.StatusOK, sendJSON(router, "PUT", path, map[string]string{"role": RoleModerator}, adminTokens.AccessToken).Code)
+	assert.Equal(t, RoleModerator, findUserByUsername("alice").Role)
+}
+
+func TestRefreshAndLogout(t *testing.T) {
+	resetStore()
+	router := setupRouter()
+	tokens := registerAndLogin(t, router, "alice")
+
+	w := sendJSON(router, "POST", "/auth/refresh", map[string]string{"refresh_token": "unknown"}, "")
+	assert.NotEqual(t, http.StatusOK, w.Code, "unknown refresh token accepted")
+
+	w = sendJSON(router, "POST", "/auth/refresh", map[string]string{"refresh_token": tokens.RefreshToken}, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response struct {
+		Data TokenResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	claims, err := validateToken(response.Data.AccessToken)
+	require.NoError(t, err, "refreshed access token is invalid")
+	assert.Equal(t, "alice", claims.Username)
+
+	w = sendJSON(router, "POST", "/auth/logout", nil, response.Data.AccessToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = sendJSON(router, "GET", "/user/profile", nil, response.Data.AccessToken)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "token still accepted after logout")
+}
+
+func TestChangePassword(t *testing.T) {
+	resetStore()
+	router := setupRouter()
+	tokens := registerAndLogin(t, router, "alice")
+
+	tests := []struct {
+		name     string
+		current  string
+		next     string
+		expected bool
+	}{
+		{"Wrong current password", "Wrong#Pass1", "Newer#Pass2", false},
+		{"Weak new password", "Secure#Pass1", "weakpassword", false},
+		{"Valid change", "Secure#Pass1", "Newer#Pass2", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := sendJSON(router, "POST", "/user/change-password", map[string]string{
+				"current_password": tt.current,
+				"new_password":     tt.next,
+			}, tokens.AccessToken)
+			assert.Equal(t, tt.expected, w.Code == http.StatusOK, w.Body.String())
+		})
+	}
+
+	assert.True(t, verifyPassword("Newer#Pass2", findUserByUsername("alice").PasswordHash))
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+// runChallenge dispatches the challenge authoring subcommands
+func runChallenge(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gip challenge new|validate ...")
+	}
+	switch args[0] {
+	case "new":
+		return runChallengeNew(args[1:])
+	case "validate":
+		return runChallengeValidate(args[1:])
+	}
+	return fmt.Errorf("unknown challenge command %q, expected new or validate", args[0])
+}
+
+// newAuthoringService creates the authoring service with loaded classic challenges
+func newAuthoringService() (*services.AuthoringService, error) {
+	challengeService := services.NewChallengeService()
+	if err := challengeService.LoadChallenges(); err != nil {
+		return nil, err
+	}
+	return services.NewAuthoringService(challengeService, services.NewPackageService(), services.NewExecutionService()), nil
+}
+
+// runChallengeNew scaffolds a new classic or package challenge
+func runChallengeNew(args []string) error {
+	ws := &workspace{}
+	fs := newFlagSet("challenge new", ws)
+	title := fs.String("title", "", "challenge title (required)")
+	difficulty := fs.String("difficulty", "", "Beginner, Intermediate or Advanced (default Beginner)")
+	function := fs.String("func", "", "exported function the template asks for (default Solve)")
+	tags := fs.String("tags", "", "comma-separated metadata tags")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: gip challenge new <challenge|pkg/challenge> -title \"Title\"")
+	}
+
+	ref, err := parseChallengeRef(positional[0])
+	if err != nil {
+		return err
+	}
+	if err := ws.prepare(false); err != nil {
+		return err
+	}
+
+	authoring, err := newAuthoringService()
+	if err != nil {
+		return err
+	}
+	opts := services.ScaffoldOptions{Ref: ref.Ref(), Title: *title, Difficulty: *difficulty, Function: *function}
+	for _, tag := range strings.Split(*tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			opts.Tags = append(opts.Tags, tag)
+		}
+	}
+
+	created, err := authoring.Scaffold(opts)
+	for _, path := range created {
+		rel, _ := filepath.Rel(ws.root, filepath.Join(ws.root, "web-ui", path))
+		fmt.Printf("wrote %s\n", rel)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Fill in the TODOs, then run 'gip challenge validate %s'.\n", ref)
+	return nil
+}
+
+// runChallengeValidate validates one or more challenges, or the whole repository with -all
+func runChallengeValidate(args []string) error {
+	ws := &workspace{}
+	fs := newFlagSet("challenge validate", ws)
+	all := fs.Bool("all", false, "validate every classic and package challenge")
+	solutionPath := fs.String("solution", "", "solution file or directory to check instead of the reference/ directory")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *all == (len(positional) > 0) {
+		return errors.New("usage: gip challenge validate <challenge|pkg/challenge>... [-solution path] | -all")
+	}
+	if *solutionPath != "" {
+		if len(positional) != 1 {
+			return errors.New("-solution needs exactly one challenge")
+		}
+		// The services run from web-ui, so resolve the path first
+		if *solutionPath, err = filepath.Abs(*solutionPath); err != nil {
+			return err
+		}
+	}
+	if err := ws.prepare(false); err != nil {
+		return err
+	}
+
+	authoring, err := newAuthoringService()
+	if err != nil {
+		return err
+	}
+
+	var reports []services.ValidationReport
+	if *all {
+		reports = authoring.ValidateAll()
+	}
+	for _, arg := range positional {
+		ref, err := parseChallengeRef(arg)
+		if err != nil {
+			return err
+		}
+		challenge, err := loadChallenge(ref)
+		if err != nil {
+			return err
+		}
+		var solution models.SubmissionFiles
+		if *solutionPath != "" {
+			if solution, err = services.SolutionFromPath(challenge, *solutionPath); err != nil {
+				return err
+			}
+		}
+		reports = append(reports, authoring.Validate(challenge, solution))
+	}
+
+	failed := 0
+	for _, report := range reports {
+		status := "ok"
+		if !report.Passed() {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Printf("%-5s %s\n", status, report.Ref)
+		for _, check := range report.Checks {
+			mark := "ok"
+			switch {
+			case check.Skipped:
+				mark = "skip"
+			case !check.Passed:
+				mark = "FAIL"
+			}
+			line := fmt.Sprintf("      %-5s %s", mark, check.Name)
+			if check.Message != "" {
+				line += ": " + strings.ReplaceAll(check.Message, "\n", "\n            ")
+			}
+			fmt.Println(line)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d challenges failed validation", failed, len(reports))
+	}
+	return nil
+}
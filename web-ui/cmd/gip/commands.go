@@ -0,0 +1,360 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+// runInit creates the user's submission directory and copies the solution template into it
+func runInit(args []string) error {
+	ws := &workspace{}
+	fs := newFlagSet("init", ws)
+	force := fs.Bool("force", false, "overwrite an existing submission")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: gip init <challenge|pkg/challenge>")
+	}
+
+	ref, err := parseChallengeRef(positional[0])
+	if err != nil {
+		return err
+	}
+	if err := ws.prepare(true); err != nil {
+		return err
+	}
+
+	challengeDir := ws.path(ref.Dir())
+	if _, err := os.Stat(challengeDir); err != nil {
+		return fmt.Errorf("challenge directory '%s' does not exist", ref.Dir())
+	}
+
+	template, err := os.ReadFile(filepath.Join(challengeDir, "solution-template.go"))
+	if err != nil {
+		return fmt.Errorf("could not read solution template: %v", err)
+	}
+
+	submissionFile := ws.path(ref.SubmissionPath(ws.username))
+	if _, err := os.Stat(submissionFile); err == nil && !*force {
+		fmt.Printf("Submission already exists at '%s' (use -force to overwrite).\n", ref.SubmissionPath(ws.username))
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(submissionFile), 0755); err != nil {
+		return fmt.Errorf("failed to create submission directory: %v", err)
+	}
+	if err := os.WriteFile(submissionFile, template, 0644); err != nil {
+		return fmt.Errorf("failed to write solution file: %v", err)
+	}
+
+	fmt.Printf("Your submission is ready at '%s'.\n", ref.SubmissionPath(ws.username))
+	if _, err := os.Stat(filepath.Join(challengeDir, "learning.md")); err == nil {
+		fmt.Printf("Learning materials are available at '%s'.\n", filepath.Join(ref.Dir(), "learning.md"))
+	}
+	fmt.Printf("Run 'gip test %s' when you are ready.\n", ref)
+	return nil
+}
+
+// runTest runs the challenge tests against the user's submission in an isolated module
+func runTest(args []string) error {
+	ws := &workspace{}
+	fs := newFlagSet("test", ws)
+	mode := fs.String("mode", "", "run mode: race, cover, short, bench or fuzz")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: gip test <challenge|pkg/challenge>")
+	}
+
+	ref, err := parseChallengeRef(positional[0])
+	if err != nil {
+		return err
+	}
+	if err := ws.prepare(true); err != nil {
+		return err
+	}
+
+	challenge, err := loadChallenge(ref)
+	if err != nil {
+		return err
+	}
+
+	// Multi-file submissions are run with every file in the submission directory
+	files, err := services.ReadSolutionFiles(challenge.SubmissionDir(ws.username))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no solution found in '%s', run 'gip init %s' first", filepath.Dir(ref.SubmissionPath(ws.username)), ref)
+	}
+
+	if !services.ValidRunMode(*mode) {
+		return fmt.Errorf("invalid mode %q, expected race, cover, short, bench or fuzz", *mode)
+	}
+
+	fmt.Printf("Running tests for user '%s' on %s...\n", ws.username, ref)
+	result := services.NewExecutionService().RunWithOptions(files, challenge, services.RunOptions{Mode: *mode})
+	fmt.Print(result.Output)
+
+	for _, d := range result.Diagnostics {
+		fmt.Printf("%s:%d:%d: [%s] %s\n", d.File, d.Line, d.Column, d.Tool, d.Message)
+	}
+	if result.Coverage != nil {
+		fmt.Println()
+		for _, fn := range result.Coverage.Functions {
+			fmt.Printf("%s:%d\t%-30s %5.1f%%\n", fn.File, fn.Line, fn.Function, fn.Percent)
+		}
+		fmt.Printf("total coverage: %.1f%%\n", result.Coverage.Percent)
+	}
+	if result.Benchmark != nil && len(result.Benchmark.Comparisons) > 0 {
+		fmt.Println()
+		for _, c := range result.Benchmark.Comparisons {
+			marker := ""
+			if !c.Significant {
+				marker = " ~"
+			}
+			fmt.Printf("%-40s vs %-40s %8.2fx (p=%.3f)%s\n", c.Benchmark, c.Baseline, c.Speedup, c.PValue, marker)
+		}
+		fmt.Printf("geomean speedup: %.2fx\n", result.Benchmark.GeomeanSpeedup)
+	}
+	if result.Fuzz != nil && result.Fuzz.Failure != nil {
+		failure := result.Fuzz.Failure
+		fmt.Printf("\n%s found a failing input: %s\n", failure.Target, failure.Message)
+		if failure.Seed != "" {
+			fmt.Printf("Add it to your regression tests with:\n\t%s\n", failure.Seed)
+		}
+	}
+	fmt.Printf("\nFinished in %dms\n", result.ExecutionMs)
+
+	if !result.Passed {
+		return errors.New("tests failed")
+	}
+	return nil
+}
+
+// loadChallenge loads the challenge definition used by the execution service
+func loadChallenge(ref challengeRef) (*models.TrackChallenge, error) {
+	challengeService := services.NewChallengeService()
+	if !ref.IsPackage() {
+		if err := challengeService.LoadChallenges(); err != nil {
+			return nil, err
+		}
+	}
+	return services.ResolveChallenge(ref.Ref(), challengeService.GetChallenges(), services.NewPackageService())
+}
+
+// runStatus prints the user's progress across classic and package challenges
+func runStatus(args []string) error {
+	ws := &workspace{}
+	fs := newFlagSet("status", ws)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if err := ws.prepare(true); err != nil {
+		return err
+	}
+
+	challengeService := services.NewChallengeService()
+	if err := challengeService.LoadChallenges(); err != nil {
+		return err
+	}
+	challenges := challengeService.GetChallenges()
+	attempts := services.NewUserService().GetUserAttempts(ws.username, challenges)
+
+	ids := make([]int, 0, len(challenges))
+	for id := range challenges {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Progress for %s\n\n", ws.username)
+	fmt.Fprintln(tw, "CLASSIC\tTITLE\tSTATUS\tSCORE")
+
+	solved := 0
+	for _, id := range ids {
+		status, score := "-", ""
+		if attempts.AttemptedIDs[id] {
+			solved++
+			status = "submitted"
+			score = fmt.Sprintf("%d%%", attempts.Scores[id])
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", id, challenges[id].Title, status, score)
+	}
+	fmt.Fprintf(tw, "\t%d/%d submitted\t\t\n", solved, len(ids))
+
+	packageService := services.NewPackageService()
+	entries, _ := os.ReadDir(ws.path("packages"))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		packageChallenges, err := packageService.GetPackageChallenges(entry.Name())
+		if err != nil || len(packageChallenges) == 0 {
+			continue
+		}
+
+		challengeIDs := make([]string, 0, len(packageChallenges))
+		for id := range packageChallenges {
+			challengeIDs = append(challengeIDs, id)
+		}
+		sort.Strings(challengeIDs)
+
+		fmt.Fprintf(tw, "\n%s\tTITLE\tSTATUS\t\n", strings.ToUpper(entry.Name()))
+		done := 0
+		for _, id := range challengeIDs {
+			ref := challengeRef{PackageName: entry.Name(), ChallengeID: id}
+			status := "-"
+			if _, err := os.Stat(ws.path(ref.SubmissionPath(ws.username))); err == nil {
+				done++
+				status = "submitted"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t\n", id, packageChallenges[id].Title, status)
+		}
+		fmt.Fprintf(tw, "\t%d/%d submitted\t\t\n", done, len(challengeIDs))
+	}
+
+	return tw.Flush()
+}
+
+// runSubmit records the user's submission on a branch of its own, the same way the web UI does,
+// and pushes the branch when asked to
+func runSubmit(args []string) error {
+	ws := &workspace{}
+	fs := newFlagSet("submit", ws)
+	stageOnly := fs.Bool("no-commit", false, "stage the submission without committing")
+	push := fs.Bool("push", os.Getenv("GIP_GIT_PUSH") == "1", "push the submission branch")
+	remote := fs.String("remote", os.Getenv("GIP_GIT_REMOTE"), "remote to push to (default origin)")
+	dryRun := fs.Bool("dry-run", os.Getenv("GIP_GIT_DRY_RUN") == "1", "create the commit but not the branch, and only check the push")
+	message := fs.String("message", os.Getenv("GIP_GIT_MESSAGE"), "commit message template")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: gip submit <challenge|pkg/challenge>")
+	}
+
+	ref, err := parseChallengeRef(positional[0])
+	if err != nil {
+		return err
+	}
+	if err := ws.prepare(true); err != nil {
+		return err
+	}
+
+	challenge, err := loadChallenge(ref)
+	if err != nil {
+		return err
+	}
+	files, err := services.ReadSolutionFiles(challenge.SubmissionDir(ws.username))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no solution found in '%s', run 'gip init %s' first", filepath.Dir(ref.SubmissionPath(ws.username)), ref)
+	}
+	_, singleFile := files[challenge.SolutionFile]
+	gitPath := services.SubmissionGitPath(challenge, ws.username, !singleFile || len(files) > 1)
+
+	if *stageOnly {
+		if err := ws.git("add", "--", gitPath); err != nil {
+			return err
+		}
+		fmt.Printf("Staged '%s'.\n", gitPath)
+		return nil
+	}
+
+	gitService := services.NewGitService(ws.root, services.GitOptions{
+		Remote:          *remote,
+		Push:            *push,
+		DryRun:          *dryRun,
+		MessageTemplate: *message,
+	})
+	now := time.Now()
+	commit, err := gitService.CommitSubmission(challenge, ws.username, gitPath, now)
+	if errors.Is(err, services.ErrNothingToCommit) {
+		return fmt.Errorf("'%s' is unchanged since HEAD, nothing to submit", gitPath)
+	}
+	if commit != nil {
+		fmt.Printf("Committed %s on branch %s.\n", commit.SHA[:7], commit.Branch)
+		if commit.Pushed {
+			fmt.Printf("Pushed to %s. Open a pull request from %s to appear on the scoreboard.\n", commit.Remote, commit.Branch)
+		}
+	}
+
+	// A failed commit or push leaves the rest to do by hand
+	if commands := gitService.Commands(challenge, ws.username, gitPath, commit, now); len(commands) > 0 {
+		fmt.Println("\nTo finish the submission, run:")
+		for _, command := range commands {
+			fmt.Printf("\t%s\n", command)
+		}
+	}
+	return err
+}
+
+// runLintTrack checks the named package tracks, or all of them, and fails if any has problems
+func runLintTrack(args []string) error {
+	ws := &workspace{}
+	fs := newFlagSet("lint-track", ws)
+	names, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := ws.prepare(false); err != nil {
+		return err
+	}
+
+	results, err := services.NewPackageService().LintTracks(names...)
+	if err != nil {
+		return err
+	}
+
+	tracks := make([]string, 0, len(results))
+	for name := range results {
+		tracks = append(tracks, name)
+	}
+	sort.Strings(tracks)
+
+	failed := 0
+	for _, name := range tracks {
+		if len(results[name]) == 0 {
+			fmt.Printf("ok    %s\n", name)
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL  %s\n", name)
+		for _, problem := range results[name] {
+			fmt.Printf("      %s\n", problem)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tracks have problems", failed, len(tracks))
+	}
+	return nil
+}
+
+// git runs a git command in the repository root
+func (ws *workspace) git(args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = ws.root
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s failed: %v\n%s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Command gip is the command line companion to the web UI. It scaffolds
+// submissions, runs them against the challenge tests, reports progress and
+// commits them on a branch for a pull request.
+//
+// Usage:
+//
+//	gip init <challenge|pkg/challenge> [-user name]
+//	gip test <challenge|pkg/challenge> [-user name]
+//	gip status [-user name]
+//	gip submit <challenge|pkg/challenge> [-user name] [-push] [-dry-run]
+//	gip lint-track [package...]
+//	gip challenge new <challenge|pkg/challenge> -title "Title"
+//	gip challenge validate <challenge|pkg/challenge>... | -all
+//	gip similarity <challenge|pkg/challenge>... [-threshold 0.8] [-json]
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// command is a single gip subcommand
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"init", "gip init <challenge|pkg/challenge> [-user name]", "create your submission from the challenge template", runInit},
+	{"test", "gip test <challenge|pkg/challenge> [-user name]", "run the challenge tests against your submission", runTest},
+	{"status", "gip status [-user name]", "show progress across classic and package challenges", runStatus},
+	{"submit", "gip submit <challenge|pkg/challenge> [-user name] [-push]", "commit your submission on a branch of its own", runSubmit},
+	{"lint-track", "gip lint-track [package...]", "check package tracks' package.json and challenges", runLintTrack},
+	{"challenge", "gip challenge new|validate <challenge|pkg/challenge>", "scaffold a new challenge or validate challenges (-all)", runChallenge},
+	{"similarity", "gip similarity <challenge|pkg/challenge>...", "report clusters of similar submissions", runSimilarity},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		err := cmd.run(os.Args[2:])
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gip %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if name != "help" && name != "-h" && name != "--help" {
+		fmt.Fprintf(os.Stderr, "gip: unknown command %q\n\n", name)
+	}
+	usage()
+	os.Exit(2)
+}
+
+// usage prints the list of available subcommands
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-52s %s\n", cmd.usage, cmd.summary)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+	"web-ui/internal/utils"
+)
+
+// runSimilarity reports clusters of similar submissions for each challenge
+func runSimilarity(args []string) error {
+	ws := &workspace{}
+	fs := newFlagSet("similarity", ws)
+	threshold := fs.Float64("threshold", services.DefaultSimilarityThreshold, "minimum similarity score, from 0 to 1")
+	asJSON := fs.Bool("json", false, "print the reports as JSON")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("usage: gip similarity <challenge|pkg/challenge>... [-threshold 0.8] [-json]")
+	}
+	if *threshold <= 0 || *threshold > 1 {
+		return errors.New("-threshold must be in (0, 1]")
+	}
+	if err := ws.prepare(false); err != nil {
+		return err
+	}
+
+	similarityService := services.NewSimilarityService(filepath.Join(utils.DataDir(), "similarity"))
+	if err := similarityService.LoadFlags(); err != nil {
+		return err
+	}
+	flagged := map[string]bool{}
+	for _, flag := range similarityService.Flags() {
+		flagged[flag.Ref+"|"+flag.Username] = true
+	}
+
+	var reports []*models.SimilarityReport
+	for _, arg := range positional {
+		ref, err := parseChallengeRef(arg)
+		if err != nil {
+			return err
+		}
+		challenge, err := loadChallenge(ref)
+		if err != nil {
+			return err
+		}
+		report, err := similarityService.Analyze(challenge, *threshold)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, report)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	}
+
+	for _, report := range reports {
+		fmt.Printf("%s: %d submissions compared, %d similar pairs at >= %.2f\n", report.Ref, report.Submissions, len(report.Pairs), report.Threshold)
+		for _, cluster := range report.Clusters {
+			users := make([]string, len(cluster.Users))
+			for i, user := range cluster.Users {
+				users[i] = user
+				if flagged[report.Ref+"|"+user] {
+					users[i] += " (flagged)"
+				}
+			}
+			fmt.Printf("  %.3f  %s\n", cluster.MaxScore, strings.Join(users, ", "))
+		}
+		if len(report.TooShort) > 0 {
+			fmt.Printf("  too short to compare: %d\n", len(report.TooShort))
+		}
+		if len(report.Skipped) > 0 {
+			fmt.Printf("  could not parse: %s\n", strings.Join(report.Skipped, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"web-ui/internal/utils"
+)
+
+// challengeRef identifies a classic challenge ("7", "challenge-7") or a
+// package challenge ("gin/challenge-1-basic-routing")
+type challengeRef struct {
+	Number      int    // classic challenge number, 0 for package challenges
+	PackageName string // package track name, empty for classic challenges
+	ChallengeID string // package challenge directory name
+}
+
+// parseChallengeRef parses a challenge reference given on the command line
+func parseChallengeRef(arg string) (challengeRef, error) {
+	arg = strings.Trim(strings.TrimPrefix(filepath.ToSlash(arg), "packages/"), "/")
+	if arg == "" {
+		return challengeRef{}, errors.New("missing challenge")
+	}
+
+	if pkg, id, ok := strings.Cut(arg, "/"); ok {
+		if pkg == "" || !strings.HasPrefix(id, "challenge-") || strings.Contains(id, "/") {
+			return challengeRef{}, fmt.Errorf("invalid package challenge %q, expected <package>/challenge-N-name", arg)
+		}
+		return challengeRef{PackageName: pkg, ChallengeID: id}, nil
+	}
+
+	n, err := strconv.Atoi(strings.TrimPrefix(arg, "challenge-"))
+	if err != nil || n <= 0 {
+		return challengeRef{}, fmt.Errorf("invalid challenge %q, expected a number or <package>/challenge-N-name", arg)
+	}
+	return challengeRef{Number: n}, nil
+}
+
+// IsPackage reports whether the reference points to a package challenge
+func (c challengeRef) IsPackage() bool {
+	return c.PackageName != ""
+}
+
+// String returns the reference in the form accepted by parseChallengeRef
+func (c challengeRef) String() string {
+	if c.IsPackage() {
+		return c.PackageName + "/" + c.ChallengeID
+	}
+	return fmt.Sprintf("challenge-%d", c.Number)
+}
+
+// Ref returns the track-qualified ref the services use, e.g. "classic/7"
+func (c challengeRef) Ref() string {
+	if c.IsPackage() {
+		return c.PackageName + "/" + c.ChallengeID
+	}
+	return fmt.Sprintf("classic/%d", c.Number)
+}
+
+// Dir returns the challenge directory relative to the repository root
+func (c challengeRef) Dir() string {
+	if c.IsPackage() {
+		return filepath.Join("packages", c.PackageName, c.ChallengeID)
+	}
+	return fmt.Sprintf("challenge-%d", c.Number)
+}
+
+// SolutionFile returns the file name submissions are stored under
+func (c challengeRef) SolutionFile() string {
+	if c.IsPackage() {
+		return "solution.go"
+	}
+	return "solution-template.go"
+}
+
+// SubmissionPath returns the user's solution path relative to the repository root
+func (c challengeRef) SubmissionPath(username string) string {
+	return filepath.Join(c.Dir(), "submissions", username, c.SolutionFile())
+}
+
+// workspace holds the state shared by all subcommands
+type workspace struct {
+	root     string
+	username string
+}
+
+// newFlagSet creates a flag set with the flags every subcommand accepts
+func newFlagSet(name string, ws *workspace) *flag.FlagSet {
+	fs := flag.NewFlagSet("gip "+name, flag.ContinueOnError)
+	fs.StringVar(&ws.username, "user", "", "GitHub username (defaults to git config)")
+	fs.StringVar(&ws.root, "root", "", "repository root (defaults to the nearest parent containing packages/ and challenge-1/)")
+	return fs
+}
+
+// prepare resolves the repository root and username after flag parsing
+func (ws *workspace) prepare(needUser bool) error {
+	if ws.root == "" {
+		root, err := findRepoRoot()
+		if err != nil {
+			return err
+		}
+		ws.root = root
+	}
+
+	root, err := filepath.Abs(ws.root)
+	if err != nil {
+		return err
+	}
+	ws.root = root
+
+	// The services resolve challenge paths relative to the web-ui directory
+	if err := os.Chdir(filepath.Join(ws.root, "web-ui")); err != nil {
+		return fmt.Errorf("failed to enter web-ui directory: %v", err)
+	}
+
+	if ws.username == "" {
+		ws.username = utils.GetGitUsername().Username
+	}
+	if needUser && ws.username == "" {
+		return errors.New("could not determine your GitHub username, pass -user or set git config user.name")
+	}
+	if strings.ContainsAny(ws.username, `/\ `) || ws.username == "." || ws.username == ".." {
+		return fmt.Errorf("invalid username %q", ws.username)
+	}
+	return nil
+}
+
+// path joins elements onto the repository root
+func (ws *workspace) path(elem ...string) string {
+	return filepath.Join(append([]string{ws.root}, elem...)...)
+}
+
+// findRepoRoot walks up from the working directory looking for the repository root
+func findRepoRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if isRepoRoot(dir) {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("not inside a go-interview-practice checkout, pass -root")
+		}
+		dir = parent
+	}
+}
+
+// isRepoRoot reports whether dir looks like the repository root
+func isRepoRoot(dir string) bool {
+	for _, name := range []string{"packages", "web-ui", "challenge-1"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || !info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
+
+// parseArgs parses flags that may appear before or after the positional challenge argument
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
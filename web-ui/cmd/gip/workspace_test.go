@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseChallengeRef(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    challengeRef
+		wantErr bool
+	}{
+		{"7", challengeRef{Number: 7}, false},
+		{"challenge-7", challengeRef{Number: 7}, false},
+		{"challenge-7/", challengeRef{Number: 7}, false},
+		{"gin/challenge-1-basic-routing", challengeRef{PackageName: "gin", ChallengeID: "challenge-1-basic-routing"}, false},
+		{"packages/gin/challenge-1-basic-routing", challengeRef{PackageName: "gin", ChallengeID: "challenge-1-basic-routing"}, false},
+		{"", challengeRef{}, true},
+		{"0", challengeRef{}, true},
+		{"seven", challengeRef{}, true},
+		{"gin/basic-routing", challengeRef{}, true},
+		{"/challenge-1-basic-routing", challengeRef{}, true},
+		{"gin/challenge-1/extra", challengeRef{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := parseChallengeRef(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseChallengeRef(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseChallengeRef(%q) = %+v, want %+v", tt.arg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChallengeRefPaths(t *testing.T) {
+	tests := []struct {
+		ref            challengeRef
+		wantString     string
+		wantRef        string
+		wantSubmission string
+	}{
+		{challengeRef{Number: 7}, "challenge-7", "classic/7", filepath.Join("challenge-7", "submissions", "alice", "solution-template.go")},
+		{
+			challengeRef{PackageName: "gin", ChallengeID: "challenge-1-basic-routing"},
+			"gin/challenge-1-basic-routing",
+			"gin/challenge-1-basic-routing",
+			filepath.Join("packages", "gin", "challenge-1-basic-routing", "submissions", "alice", "solution.go"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantString, func(t *testing.T) {
+			if got := tt.ref.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+			if got := tt.ref.Ref(); got != tt.wantRef {
+				t.Errorf("Ref() = %q, want %q", got, tt.wantRef)
+			}
+			if got := tt.ref.SubmissionPath("alice"); got != tt.wantSubmission {
+				t.Errorf("SubmissionPath() = %q, want %q", got, tt.wantSubmission)
+			}
+			// String round-trips through the parser
+			if parsed, err := parseChallengeRef(tt.ref.String()); err != nil || parsed != tt.ref {
+				t.Errorf("parseChallengeRef(%q) = %+v, %v", tt.ref.String(), parsed, err)
+			}
+		})
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		wantPositional []string
+		wantUser       string
+		wantForce      bool
+	}{
+		{"flags first", []string{"-user", "alice", "-force", "7"}, []string{"7"}, "alice", true},
+		{"flags last", []string{"7", "-user", "alice"}, []string{"7"}, "alice", false},
+		{"interleaved", []string{"7", "-force", "8", "-user=bob"}, []string{"7", "8"}, "bob", true},
+		{"no arguments", nil, nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &workspace{}
+			fs := newFlagSet("init", ws)
+			force := fs.Bool("force", false, "")
+			positional, err := parseArgs(fs, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(positional, tt.wantPositional) || ws.username != tt.wantUser || *force != tt.wantForce {
+				t.Errorf("parseArgs(%q) = %q, user %q, force %v", tt.args, positional, ws.username, *force)
+			}
+		})
+	}
+
+	fs := newFlagSet("init", &workspace{})
+	fs.SetOutput(io.Discard)
+	if _, err := parseArgs(fs, []string{"-unknown"}); err == nil || err == flag.ErrHelp {
+		t.Errorf("parseArgs accepted an unknown flag: %v", err)
+	}
+}
+
+func TestWorkspacePrepareRejectsUsernames(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"packages", "web-ui", "challenge-1"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// prepare enters web-ui, so the working directory is restored for the other tests
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	tests := []struct {
+		username string
+		wantErr  bool
+	}{
+		{"alice", false},
+		{"..", true},
+		{"a/b", true},
+		{"a b", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			ws := &workspace{root: root, username: tt.username}
+			if err := ws.prepare(true); (err != nil) != tt.wantErr {
+				t.Errorf("prepare() with user %q error = %v, wantErr %v", tt.username, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+// Error codes of the admin API, which uses the /api/v1 error envelope
+const (
+	ErrorCodeUnauthorized = "unauthorized"
+	ErrorCodeForbidden    = "forbidden"
+	ErrorCodeInternal     = "internal"
+)
+
+// FlagRequest is the body of a request flagging a user's entry
+type FlagRequest struct {
+	Reason string `json:"reason"`
+}
+
+// AdminHandler serves the admin API for similarity reports and scoreboard flags.
+// Every request needs the admin token as a bearer token; without a configured token the API is disabled.
+type AdminHandler struct {
+	challengeService  *services.ChallengeService
+	packageService    *services.PackageService
+	scoreboardService *services.ScoreboardService
+	similarityService *services.SimilarityService
+	token             string
+	mux               *http.ServeMux
+}
+
+// NewAdminHandler creates the /api/admin router
+func NewAdminHandler(
+	challengeService *services.ChallengeService,
+	packageService *services.PackageService,
+	scoreboardService *services.ScoreboardService,
+	similarityService *services.SimilarityService,
+	token string,
+) *AdminHandler {
+	h := &AdminHandler{
+		challengeService:  challengeService,
+		packageService:    packageService,
+		scoreboardService: scoreboardService,
+		similarityService: similarityService,
+		token:             token,
+		mux:               http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET /api/admin/similarity/{track}/{challenge}", h.getSimilarity)
+	h.mux.HandleFunc("GET /api/admin/flags", h.listFlags)
+	h.mux.HandleFunc("PUT /api/admin/flags/{track}/{challenge}/{username}", h.flagEntry)
+	h.mux.HandleFunc("DELETE /api/admin/flags/{track}/{challenge}/{username}", h.unflagEntry)
+	return h
+}
+
+// ServeHTTP checks the admin token and routes the request
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.token == "" {
+		writeAPIError(w, http.StatusForbidden, ErrorCodeForbidden, "The admin API is disabled, set GIP_ADMIN_TOKEN to enable it")
+		return
+	}
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+		writeAPIError(w, http.StatusUnauthorized, ErrorCodeUnauthorized, "A valid admin token is required")
+		return
+	}
+
+	if _, pattern := h.mux.Handler(r); pattern == "" {
+		w = &envelopeWriter{ResponseWriter: w}
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// getSimilarity reports the similar submissions of a challenge
+func (h *AdminHandler) getSimilarity(w http.ResponseWriter, r *http.Request) {
+	challenge, ok := h.resolve(w, r)
+	if !ok {
+		return
+	}
+
+	threshold := services.DefaultSimilarityThreshold
+	if value := r.URL.Query().Get("threshold"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 || parsed > 1 {
+			writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "threshold must be a number in (0, 1]")
+			return
+		}
+		threshold = parsed
+	}
+
+	report, err := h.similarityService.Analyze(challenge, threshold)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrorCodeInternal, err.Error())
+		return
+	}
+	writeJSON(w, report)
+}
+
+// listFlags returns every flagged entry
+func (h *AdminHandler) listFlags(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.similarityService.Flags())
+}
+
+// flagEntry flags a user's entry and hides it from the challenge's scoreboard
+func (h *AdminHandler) flagEntry(w http.ResponseWriter, r *http.Request) {
+	challenge, ok := h.resolve(w, r)
+	if !ok {
+		return
+	}
+
+	var request FlagRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("Invalid request body: %v", err))
+			return
+		}
+	}
+
+	username := r.PathValue("username")
+	flag, err := h.similarityService.Flag(challenge.Ref, username, request.Reason, time.Now())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	h.scoreboardService.Hide(challenge.Ref, username, true)
+	writeJSON(w, flag)
+}
+
+// unflagEntry removes a flag and shows the entry on the scoreboard again
+func (h *AdminHandler) unflagEntry(w http.ResponseWriter, r *http.Request) {
+	challenge, ok := h.resolve(w, r)
+	if !ok {
+		return
+	}
+
+	username := r.PathValue("username")
+	removed, err := h.similarityService.Unflag(challenge.Ref, username)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrorCodeInternal, err.Error())
+		return
+	}
+	if !removed {
+		writeAPIError(w, http.StatusNotFound, ErrorCodeNotFound, fmt.Sprintf("%s is not flagged on %s", username, challenge.Ref))
+		return
+	}
+	h.scoreboardService.Hide(challenge.Ref, username, false)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resolve returns the challenge named by the {track} and {challenge} path values, writing an error if there is none
+func (h *AdminHandler) resolve(w http.ResponseWriter, r *http.Request) (*models.TrackChallenge, bool) {
+	ref := r.PathValue("track") + "/" + r.PathValue("challenge")
+	challenge, err := services.ResolveChallenge(ref, h.challengeService.GetChallenges(), h.packageService)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, ErrorCodeNotFound, fmt.Sprintf("Challenge %s not found", ref))
+		return nil, false
+	}
+	return challenge, true
+}
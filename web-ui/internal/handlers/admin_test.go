@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+const testAdminToken = "secret-token"
+
+// newAdminTestServer serves /api/admin over services loaded from the repository, with state in a temporary data directory
+func newAdminTestServer(t *testing.T, token string) (*httptest.Server, *services.ScoreboardService, *services.SimilarityService) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join("..", "..")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	dataDir := t.TempDir()
+	t.Setenv("GIP_DATA_DIR", dataDir)
+
+	challengeService := services.NewChallengeService()
+	if err := challengeService.LoadChallenges(); err != nil {
+		t.Fatalf("LoadChallenges: %v", err)
+	}
+	packageService := services.NewPackageService()
+	scoreboardService := services.NewScoreboardService()
+	if err := scoreboardService.LoadScoreboards(challengeService.GetChallenges(), packageService.GetPackages()); err != nil {
+		t.Fatalf("LoadScoreboards: %v", err)
+	}
+	similarityService := services.NewSimilarityService(filepath.Join(dataDir, "similarity"))
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/admin/", NewAdminHandler(challengeService, packageService, scoreboardService, similarityService, token))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, scoreboardService, similarityService
+}
+
+// adminRequest sends an authenticated admin request
+func adminRequest(t *testing.T, method, url, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// onScoreboard reports whether a user appears on a classic challenge's scoreboard
+func onScoreboard(entries []models.ScoreboardEntry, username string) bool {
+	for _, entry := range entries {
+		if entry.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAdminRequiresToken(t *testing.T) {
+	server, _, _ := newAdminTestServer(t, "")
+	resp := adminRequest(t, http.MethodGet, server.URL+"/api/admin/flags", "anything", "")
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("without a configured token: status %d, want 403", resp.StatusCode)
+	}
+
+	server, _, _ = newAdminTestServer(t, testAdminToken)
+	for _, token := range []string{"", "wrong"} {
+		resp := adminRequest(t, http.MethodGet, server.URL+"/api/admin/flags", token, "")
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("token %q: status %d, want 401", token, resp.StatusCode)
+		}
+		var body ErrorEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error.Code != ErrorCodeUnauthorized {
+			t.Errorf("token %q: error envelope %+v (%v)", token, body, err)
+		}
+	}
+
+	resp = adminRequest(t, http.MethodGet, server.URL+"/api/admin/nothing", testAdminToken, "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown route: status %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestAdminSimilarityReport(t *testing.T) {
+	server, _, _ := newAdminTestServer(t, testAdminToken)
+
+	resp := adminRequest(t, http.MethodGet, server.URL+"/api/admin/similarity/classic/2?threshold=0.9", testAdminToken, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d, want 200", resp.StatusCode)
+	}
+	var report models.SimilarityReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Ref != "classic/2" || report.Threshold != 0.9 || report.Submissions == 0 {
+		t.Fatalf("unexpected report header: %+v", report)
+	}
+	for _, pair := range report.Pairs {
+		if pair.Score < 0.9 || pair.Score > 1 {
+			t.Errorf("pair %v scored %v, outside [0.9, 1]", pair.Users, pair.Score)
+		}
+	}
+	for _, cluster := range report.Clusters {
+		if len(cluster.Users) < 2 {
+			t.Errorf("cluster %v has fewer than two users", cluster.Users)
+		}
+	}
+
+	resp = adminRequest(t, http.MethodGet, server.URL+"/api/admin/similarity/classic/2?threshold=2", testAdminToken, "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid threshold: status %d, want 400", resp.StatusCode)
+	}
+	resp = adminRequest(t, http.MethodGet, server.URL+"/api/admin/similarity/classic/9999", testAdminToken, "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown challenge: status %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestAdminFlagHidesScoreboardEntry(t *testing.T) {
+	server, scoreboardService, similarityService := newAdminTestServer(t, testAdminToken)
+
+	entries, _ := scoreboardService.Scoreboard("classic/2")
+	if len(entries) == 0 {
+		t.Skip("classic/2 has no scoreboard entries")
+	}
+	username := entries[0].Username
+	url := server.URL + "/api/admin/flags/classic/2/" + username
+
+	resp := adminRequest(t, http.MethodPut, url, testAdminToken, `{"reason":"copied"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("flag: status %d, want 200", resp.StatusCode)
+	}
+	entries, _ = scoreboardService.Scoreboard("classic/2")
+	if onScoreboard(entries, username) {
+		t.Errorf("%s is still on the classic/2 scoreboard after flagging", username)
+	}
+	if onScoreboard(scoreboardService.GetAllScoreboards()[2], username) {
+		t.Errorf("%s is still in GetAllScoreboards after flagging", username)
+	}
+
+	// Flags persist, so a restarted server hides the entry again
+	reloaded := services.NewSimilarityService(filepath.Join(os.Getenv("GIP_DATA_DIR"), "similarity"))
+	if err := reloaded.LoadFlags(); err != nil {
+		t.Fatal(err)
+	}
+	flags := reloaded.Flags()
+	if len(flags) != 1 || flags[0].Username != username || flags[0].Reason != "copied" {
+		t.Errorf("persisted flags = %+v", flags)
+	}
+	if len(similarityService.Flags()) != 1 {
+		t.Errorf("listed flags = %+v", similarityService.Flags())
+	}
+
+	resp = adminRequest(t, http.MethodDelete, url, testAdminToken, "")
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("unflag: status %d, want 204", resp.StatusCode)
+	}
+	entries, _ = scoreboardService.Scoreboard("classic/2")
+	if !onScoreboard(entries, username) {
+		t.Errorf("%s is missing from the classic/2 scoreboard after unflagging", username)
+	}
+
+	resp = adminRequest(t, http.MethodDelete, url, testAdminToken, "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unflag twice: status %d, want 404", resp.StatusCode)
+	}
+	resp = adminRequest(t, http.MethodPut, server.URL+"/api/admin/flags/classic/2/..bad", testAdminToken, "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid username: status %d, want 400", resp.StatusCode)
+	}
+}
@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"log"
 	"net/http"
-	"os"
-	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -18,12 +20,19 @@ import (
 
 // APIHandler handles all API endpoints
 type APIHandler struct {
-	challengeService  *services.ChallengeService
-	scoreboardService *services.ScoreboardService
-	userService       *services.UserService
-	executionService  *services.ExecutionService
-	packageService    *services.PackageService
-	submissions       []models.Submission
+	challengeService   *services.ChallengeService
+	scoreboardService  *services.ScoreboardService
+	userService        *services.UserService
+	executionService   *services.ExecutionService
+	packageService     *services.PackageService
+	practiceService    *services.PracticeService
+	pathService        *services.PathService
+	achievementService *services.AchievementService
+	leaderboardService *services.LeaderboardService
+	progressService    *services.ProgressService
+	gitService         *services.GitService
+	galleryService     *services.GalleryService
+	submissions        []models.Submission
 }
 
 // NewAPIHandler creates a new API handler
@@ -33,14 +42,27 @@ func NewAPIHandler(
 	userService *services.UserService,
 	executionService *services.ExecutionService,
 	packageService *services.PackageService,
+	practiceService *services.PracticeService,
+	pathService *services.PathService,
+	achievementService *services.AchievementService,
+	leaderboardService *services.LeaderboardService,
+	progressService *services.ProgressService,
+	gitService *services.GitService,
 ) *APIHandler {
 	return &APIHandler{
-		challengeService:  challengeService,
-		scoreboardService: scoreboardService,
-		userService:       userService,
-		executionService:  executionService,
-		packageService:    packageService,
-		submissions:       make([]models.Submission, 0),
+		challengeService:   challengeService,
+		scoreboardService:  scoreboardService,
+		userService:        userService,
+		executionService:   executionService,
+		packageService:     packageService,
+		practiceService:    practiceService,
+		pathService:        pathService,
+		achievementService: achievementService,
+		leaderboardService: leaderboardService,
+		progressService:    progressService,
+		gitService:         gitService,
+		galleryService:     services.NewGalleryService(scoreboardService, progressService),
+		submissions:        make([]models.Submission, 0),
 	}
 }
 
@@ -70,9 +92,10 @@ func (h *APIHandler) GetChallengeByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Extract challenge ID from URL
+	// Extract challenge ID and any sub-resource from URL
 	path := strings.TrimPrefix(r.URL.Path, "/api/challenges/")
-	id, err := strconv.Atoi(path)
+	idPart, resource, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idPart)
 	if err != nil {
 		http.Error(w, "Invalid challenge ID", http.StatusBadRequest)
 		return
@@ -84,8 +107,17 @@ func (h *APIHandler) GetChallengeByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(challenge)
+	switch resource {
+	case "":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(challenge)
+	case "solutions":
+		h.getChallengeSolutions(w, r, services.ClassicChallenge(challenge))
+	case "solutions/compare":
+		h.compareChallengeSolutions(w, r, services.ClassicChallenge(challenge))
+	default:
+		http.NotFound(w, r)
+	}
 }
 
 // HandleSubmissions handles submission operations
@@ -119,22 +151,108 @@ func (h *APIHandler) createSubmission(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Run the code
-	result := h.executionService.RunCode(submission.Code, challenge)
+	files := submissionFiles(submission.Code, submission.Files, services.ClassicChallenge(challenge).SolutionFile)
+	if err := services.ValidateSubmissionFiles(files); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.submitClassic(&submission, challenge, files); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(submission)
+}
+
+// submitClassic judges a classic submission and fills in its result
+func (h *APIHandler) submitClassic(submission *models.Submission, challenge *models.Challenge, files models.SubmissionFiles) (services.ExecutionResult, error) {
+	result, earned, err := h.submitChallenge(services.ClassicChallenge(challenge), submission.Username, files, services.RunModeDefault, submission.SubmittedAt)
+	if err != nil {
+		return result, err
+	}
 	submission.Passed = result.Passed
 	submission.TestOutput = result.Output
 	submission.ExecutionMs = result.ExecutionMs
+	submission.Diagnostics = result.Diagnostics
+	submission.HiddenTests = result.HiddenTests
+	submission.IncludesHidden = result.IncludesHidden
+	submission.Achievements = earned
+	submission.Quality = result.Quality
 
 	// Store submission
-	h.submissions = append(h.submissions, submission)
+	h.submissions = append(h.submissions, *submission)
+	return result, nil
+}
 
-	// Add to scoreboard if passed
-	if submission.Passed {
-		h.scoreboardService.AddSubmission(submission)
+// runChallenge runs a solution against a challenge's visible tests and counts the run towards the user's
+// practice attempt. Challenges locked by prerequisites are not run and return a *services.PrerequisitesError.
+func (h *APIHandler) runChallenge(challenge *models.TrackChallenge, username string, files models.SubmissionFiles, opts services.RunOptions) (services.ExecutionResult, error) {
+	if err := h.checkPrerequisites(username, challenge.Ref); err != nil {
+		return services.ExecutionResult{}, err
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(submission)
+	result := h.executionService.RunWithOptions(files, challenge, opts)
+	if username != "" && challenge.Classic != nil {
+		if err := h.practiceService.RecordRun(username, challenge.Classic.ID, result.Passed, time.Now()); err != nil {
+			log.Printf("Failed to record practice run: %v", err)
+		}
+	}
+	return result, nil
+}
+
+// submitChallenge judges a submission to a challenge of either track, hidden tests included, and records it
+// on the scoreboard, leaderboard and the user's progress. It returns the result and the achievements it earned.
+// Challenges locked by prerequisites are not judged and return a *services.PrerequisitesError.
+func (h *APIHandler) submitChallenge(challenge *models.TrackChallenge, username string, files models.SubmissionFiles, mode string, at time.Time) (services.ExecutionResult, []models.Achievement, error) {
+	if err := h.checkPrerequisites(username, challenge.Ref); err != nil {
+		return services.ExecutionResult{}, nil, err
+	}
+
+	// Submissions are judged on the hidden tests as well, and challenges with
+	// a speedup requirement are always judged on a bench run
+	opts := services.RunOptions{Mode: mode, IncludeHidden: true}
+	if services.RequiresBenchmark(challenge) {
+		opts.Mode = services.RunModeBench
+	}
+	result := h.executionService.RunWithOptions(files, challenge, opts)
+	if username == "" {
+		return result, nil, nil
+	}
+
+	// Only runs that included the hidden tests reach the scoreboard and leaderboard
+	if result.Passed && result.IncludesHidden {
+		h.scoreboardService.Record(challenge, username, at)
+		if err := h.leaderboardService.Record(username, challenge.Ref, at); err != nil {
+			log.Printf("Failed to update leaderboard: %v", err)
+		}
+		if result.Quality != nil {
+			if err := h.leaderboardService.RecordQuality(username, challenge.Ref, result.Quality.Score, at); err != nil {
+				log.Printf("Failed to update quality leaderboard: %v", err)
+			}
+		}
+	}
+
+	// Passing classic submissions are practice reviews that reschedule the challenge
+	if challenge.Classic != nil {
+		if _, err := h.practiceService.RecordSubmission(username, challenge.Classic, result.Passed, at); err != nil {
+			log.Printf("Failed to record practice review: %v", err)
+		}
+	}
+	earned := h.recordAchievements(username, challenge.Ref, result, at)
+	h.recordHistory(username, challenge.Ref, result, at)
+	return result, earned, nil
+}
+
+// checkPrerequisites returns a *services.PrerequisitesError when a challenge is locked for a user
+func (h *APIHandler) checkPrerequisites(username, ref string) error {
+	return h.pathService.CheckPrerequisites(username, ref, h.challengeService.GetChallenges(), h.packageService.GetPackages(), h.userService)
+}
+
+// resolveChallenge returns the challenge behind a ref of either track
+func (h *APIHandler) resolveChallenge(ref string) (*models.TrackChallenge, error) {
+	return services.ResolveChallenge(ref, h.challengeService.GetChallenges(), h.packageService)
 }
 
 // getSubmissions returns all submissions
@@ -175,12 +293,23 @@ func (h *APIHandler) RunCode(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var request struct {
-		ChallengeID int    `json:"challengeId"`
-		Code        string `json:"code"`
+		ChallengeID int                    `json:"challengeId"`
+		Code        string                 `json:"code"`
+		Files       models.SubmissionFiles `json:"files"`
+		Mode        string                 `json:"mode"` // "", "race", "cover", "short", "bench" or "fuzz"
+		Count       int                    `json:"count"`
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&request)
-	if err != nil {
+	if isMultipartRequest(r) {
+		files, err := parseSubmissionUpload(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		request.Files = files
+		request.ChallengeID, _ = strconv.Atoi(r.FormValue("challengeId"))
+		request.Mode = r.FormValue("mode")
+	} else if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		http.Error(w, "Invalid request data", http.StatusBadRequest)
 		return
 	}
@@ -191,7 +320,27 @@ func (h *APIHandler) RunCode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := h.executionService.RunCode(request.Code, challenge)
+	files := submissionFiles(request.Code, request.Files, services.ClassicChallenge(challenge).SolutionFile)
+	if err := services.ValidateSubmissionFiles(files); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !services.ValidRunMode(request.Mode) {
+		http.Error(w, "Invalid mode. Must be one of 'race', 'cover', 'short', 'bench' or 'fuzz'", http.StatusBadRequest)
+		return
+	}
+
+	// Runs are checked and counted for the user in the cookie
+	username := ""
+	if cookie, err := r.Cookie("username"); err == nil {
+		username = cookie.Value
+	}
+	result, err := h.runChallenge(services.ClassicChallenge(challenge), username, files, services.RunOptions{Mode: request.Mode, Count: request.Count})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
@@ -220,16 +369,21 @@ func (h *APIHandler) SaveSubmissionToFilesystem(w http.ResponseWriter, r *http.R
 	h.setUsernameCookie(w, request.Username)
 
 	// Validate challenge exists
-	_, exists := h.challengeService.GetChallenge(request.ChallengeID)
+	challenge, exists := h.challengeService.GetChallenge(request.ChallengeID)
 	if !exists {
 		http.Error(w, "Challenge not found", http.StatusNotFound)
 		return
 	}
 
-	response := h.executionService.SaveSubmissionToFilesystem(request)
+	classic := services.ClassicChallenge(challenge)
+	response := h.executionService.SaveSolution(classic, request.Username, request.Code, request.Files)
+	h.commitSubmission(classic, request.Username, &response)
 
 	// Clear user attempts cache
 	h.userService.RefreshUserAttempts(request.Username, h.challengeService.GetChallenges())
+	if response.Success {
+		h.evaluateAchievements(request.Username)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -314,6 +468,219 @@ func (h *APIHandler) setUsernameCookie(w http.ResponseWriter, username string) {
 	http.SetCookie(w, &cookie)
 }
 
+// GetPracticeNext returns the user's challenges due for review today, the most overdue first
+func (h *APIHandler) GetPracticeNext(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if cookie, err := r.Cookie("username"); username == "" && err == nil {
+		username = cookie.Value
+	}
+	if username == "" {
+		http.Error(w, "Username parameter required", http.StatusBadRequest)
+		return
+	}
+
+	challenges := h.challengeService.GetChallenges()
+	if err := h.practiceService.Seed(username, h.userService.GetSubmissionTimes(username, challenges)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	due, err := h.practiceService.Due(username, challenges, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var next *models.PracticeItem
+	if len(due) > 0 {
+		next = &due[0]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"username": username,
+		"next":     next,
+		"due":      due,
+	})
+}
+
+// GetPracticeReviews returns the user's practice schedule with every recorded review
+func (h *APIHandler) GetPracticeReviews(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		http.Error(w, "Username parameter required", http.StatusBadRequest)
+		return
+	}
+
+	cards, err := h.practiceService.Cards(username)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cards)
+}
+
+// GetLearningPath returns a user's topologically ordered plan over all challenges with the recommended next one
+func (h *APIHandler) GetLearningPath(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	username := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/paths/"), "/")
+	if username == "" || strings.Contains(username, "/") {
+		http.Error(w, "Username required", http.StatusBadRequest)
+		return
+	}
+
+	challenges := h.challengeService.GetChallenges()
+	packages := h.packageService.GetPackages()
+	completed := h.userService.GetCompletedRefs(username, challenges, packages)
+	plan := h.pathService.Plan(username, challenges, packages, completed)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plan)
+}
+
+// recordHistory adds a submission to the user's history
+func (h *APIHandler) recordHistory(username, ref string, result services.ExecutionResult, at time.Time) {
+	record := models.SubmissionRecord{Ref: ref, Passed: result.Passed, SubmittedAt: at, ExecutionMs: result.ExecutionMs}
+	if result.Passed && result.IncludesHidden && result.Quality != nil {
+		record.Quality = result.Quality.Score
+	}
+	if result.Benchmark != nil {
+		for _, stats := range result.Benchmark.Results {
+			record.Benchmarks = append(record.Benchmarks, models.BenchmarkResult{
+				Name:        stats.Name,
+				NsPerOp:     stats.NsPerOp,
+				BytesPerOp:  stats.BytesPerOp,
+				AllocsPerOp: stats.AllocsPerOp,
+			})
+		}
+	}
+	if err := h.progressService.RecordSubmission(username, record); err != nil {
+		log.Printf("Failed to record submission history: %v", err)
+	}
+}
+
+// recordAchievements evaluates the achievement rules after a submission and returns the achievements it earned
+func (h *APIHandler) recordAchievements(username, ref string, result services.ExecutionResult, at time.Time) []models.Achievement {
+	submission := services.AchievementSubmission{Ref: ref, Passed: result.Passed, At: at}
+	if result.Benchmark != nil {
+		submission.Speedup = result.Benchmark.GeomeanSpeedup
+	}
+
+	challenges := h.challengeService.GetChallenges()
+	packages := h.packageService.GetPackages()
+	completed := h.userService.GetCompletedRefs(username, challenges, packages)
+	earned, err := h.achievementService.RecordSubmission(username, submission, challenges, packages, completed)
+	if err != nil {
+		log.Printf("Failed to record achievements: %v", err)
+		return nil
+	}
+	return earned
+}
+
+// evaluateAchievements evaluates the achievement rules after a user saved a solution, which may complete a challenge
+func (h *APIHandler) evaluateAchievements(username string) {
+	challenges := h.challengeService.GetChallenges()
+	packages := h.packageService.GetPackages()
+	completed := h.userService.GetCompletedRefs(username, challenges, packages)
+	if _, err := h.achievementService.Evaluate(username, challenges, packages, completed); err != nil {
+		log.Printf("Failed to evaluate achievements for %s: %v", username, err)
+	}
+}
+
+// GetAchievements returns a user's earned achievements, their title and their progress in each package
+func (h *APIHandler) GetAchievements(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	username := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/achievements/"), "/")
+	if username == "" || strings.Contains(username, "/") {
+		http.Error(w, "Username required", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.userAchievements(username)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AchievementsResponse is a user's earned achievements, their title and their progress in each package
+type AchievementsResponse struct {
+	Username     string                   `json:"username"`
+	Title        *models.Achievement      `json:"title"` // Highest ranked title, unset before the first
+	Achievements []models.Achievement     `json:"achievements"`
+	Packages     []models.PackageProgress `json:"packages"`
+}
+
+// userAchievements returns a user's stored achievements and their progress in each package
+func (h *APIHandler) userAchievements(username string) (*AchievementsResponse, error) {
+	challenges := h.challengeService.GetChallenges()
+	packages := h.packageService.GetPackages()
+	completed := h.userService.GetCompletedRefs(username, challenges, packages)
+	achievements, err := h.achievementService.Earned(username)
+	if err != nil {
+		return nil, err
+	}
+
+	packageNames := make([]string, 0, len(packages))
+	for name := range packages {
+		packageNames = append(packageNames, name)
+	}
+	sort.Strings(packageNames)
+
+	progress := []models.PackageProgress{}
+	for _, name := range packageNames {
+		entry := models.PackageProgress{
+			Username:            username,
+			PackageName:         name,
+			CompletedChallenges: []string{},
+			Achievements:        []string{},
+		}
+		for _, challengeID := range packages[name].LearningPath {
+			if completed[services.PackageRef(name, challengeID)] {
+				entry.CompletedChallenges = append(entry.CompletedChallenges, challengeID)
+			} else if entry.InProgress == "" {
+				entry.InProgress = challengeID
+			}
+		}
+		for _, achievement := range achievements {
+			if achievement.Track == name {
+				entry.Achievements = append(entry.Achievements, achievement.ID)
+			}
+		}
+		entry.Score = len(entry.CompletedChallenges)
+		progress = append(progress, entry)
+	}
+
+	return &AchievementsResponse{
+		Username:     username,
+		Title:        services.TopTitle(achievements),
+		Achievements: achievements,
+		Packages:     progress,
+	}, nil
+}
+
 // GetMainScoreboardRank returns the user's rank in the main scoreboard
 func (h *APIHandler) GetMainScoreboardRank(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
@@ -346,55 +713,9 @@ func (h *APIHandler) GetMainScoreboardRank(w http.ResponseWriter, r *http.Reques
 
 // calculateMainScoreboardRank calculates the user's rank based on completed challenges
 func (h *APIHandler) calculateMainScoreboardRank(username string) int {
-	// Get all users and their completion counts (only count if ALL tests passed)
-	challenges := h.challengeService.GetChallenges()
 	userCompletions := make(map[string]int)
-
-	// Process all challenge scoreboards to count actual completions
-	for challengeID := range challenges {
-		// Read scoreboard file directly to check test results
-		scoreboardPath := fmt.Sprintf("../challenge-%d/SCOREBOARD.md", challengeID)
-		content, err := ioutil.ReadFile(scoreboardPath)
-		if err != nil {
-			// Try alternative path
-			scoreboardPath = fmt.Sprintf("challenge-%d/SCOREBOARD.md", challengeID)
-			content, err = ioutil.ReadFile(scoreboardPath)
-			if err != nil {
-				continue
-			}
-		}
-
-		// Parse scoreboard to find users who passed ALL tests
-		lines := strings.Split(string(content), "\n")
-		for _, line := range lines {
-			// Skip header and separator lines
-			if !strings.Contains(line, "|") || strings.Contains(line, "Username") || strings.Contains(line, "---") {
-				continue
-			}
-
-			parts := strings.Split(line, "|")
-			if len(parts) < 4 {
-				continue
-			}
-
-			username := strings.TrimSpace(parts[1])
-			passedTestsStr := strings.TrimSpace(parts[2])
-			totalTestsStr := strings.TrimSpace(parts[3])
-
-			// Skip empty usernames or placeholders
-			if username == "" || username == "------" {
-				continue
-			}
-
-			// Parse test counts
-			passedTests, err1 := strconv.Atoi(passedTestsStr)
-			totalTests, err2 := strconv.Atoi(totalTestsStr)
-
-			// Only count as completed if ALL tests passed
-			if err1 == nil && err2 == nil && passedTests > 0 && passedTests == totalTests {
-				userCompletions[username]++
-			}
-		}
+	for username, completions := range h.mainCompletions() {
+		userCompletions[username] = len(completions)
 	}
 
 	// Get the target user's completion count
@@ -436,71 +757,86 @@ func (h *APIHandler) GetMainLeaderboard(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
-// LeaderboardUser represents a user in the leaderboard
-type LeaderboardUser struct {
-	Username            string       `json:"username"`
-	CompletedCount      int          `json:"completedCount"`
-	CompletionRate      float64      `json:"completionRate"`
-	CompletedChallenges map[int]bool `json:"completedChallenges"`
-	Achievement         string       `json:"achievement"`
-	Rank                int          `json:"rank"`
-}
-
-// calculateMainLeaderboard calculates the main leaderboard data
-func (h *APIHandler) calculateMainLeaderboard() []LeaderboardUser {
-	challenges := h.challengeService.GetChallenges()
-	totalChallenges := len(challenges)
-	userCompletions := make(map[string]map[int]bool)
-
-	// Process all challenge scoreboards to find completions
-	for challengeID := range challenges {
-		// Read scoreboard file directly to check test results
-		scoreboardPath := fmt.Sprintf("../challenge-%d/SCOREBOARD.md", challengeID)
-		content, err := ioutil.ReadFile(scoreboardPath)
-		if err != nil {
-			// Try alternative path
-			scoreboardPath = fmt.Sprintf("challenge-%d/SCOREBOARD.md", challengeID)
-			content, err = ioutil.ReadFile(scoreboardPath)
-			if err != nil {
-				continue
-			}
-		}
+// GetLeaderboard returns a page of the leaderboard filtered by track, time window and difficulty,
+// with the caller's rank and the users around them
+func (h *APIHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-		// Parse scoreboard to find users who passed ALL tests
-		lines := strings.Split(string(content), "\n")
-		for _, line := range lines {
-			// Skip header and separator lines
-			if !strings.Contains(line, "|") || strings.Contains(line, "Username") || strings.Contains(line, "---") {
-				continue
-			}
+	request, err := leaderboardQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-			parts := strings.Split(line, "|")
-			if len(parts) < 4 {
-				continue
-			}
+	page, err := h.leaderboardService.Query(request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-			username := strings.TrimSpace(parts[1])
-			passedTestsStr := strings.TrimSpace(parts[2])
-			totalTestsStr := strings.TrimSpace(parts[3])
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
 
-			// Skip empty usernames or placeholders
-			if username == "" || username == "------" {
-				continue
+// leaderboardQuery reads the leaderboard filters and paging from the query string, defaulting the caller to the username cookie
+func leaderboardQuery(r *http.Request) (services.LeaderboardQuery, error) {
+	query := r.URL.Query()
+	request := services.LeaderboardQuery{
+		Track:      query.Get("track"),
+		Window:     query.Get("window"),
+		Difficulty: query.Get("difficulty"),
+		Order:      query.Get("order"),
+		Username:   query.Get("username"),
+	}
+	if cookie, err := r.Cookie("username"); request.Username == "" && err == nil {
+		request.Username = cookie.Value
+	}
+	for name, target := range map[string]*int{"page": &request.Page, "limit": &request.Limit} {
+		if value := query.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return request, fmt.Errorf("Invalid %s parameter", name)
 			}
+			*target = n
+		}
+	}
+	return request, nil
+}
 
-			// Parse test counts
-			passedTests, err1 := strconv.Atoi(passedTestsStr)
-			totalTests, err2 := strconv.Atoi(totalTestsStr)
+// LeaderboardUser represents a user in the leaderboard
+type LeaderboardUser struct {
+	Username            string               `json:"username"`
+	CompletedCount      int                  `json:"completedCount"`
+	CompletionRate      float64              `json:"completionRate"`
+	CompletedChallenges map[int]bool         `json:"completedChallenges"`
+	Achievement         string               `json:"achievement"`  // Highest ranked title
+	Achievements        []models.Achievement `json:"achievements"` // Every earned achievement
+	Rank                int                  `json:"rank"`
+}
 
-			// Only count as completed if ALL tests passed
-			if err1 == nil && err2 == nil && passedTests > 0 && passedTests == totalTests {
-				if userCompletions[username] == nil {
-					userCompletions[username] = make(map[int]bool)
-				}
-				userCompletions[username][challengeID] = true
+// mainCompletions returns the classic challenges each user passed every test of, by username.
+// They come from the scoreboard service, which leaves out entries hidden as copies.
+func (h *APIHandler) mainCompletions() map[string]map[int]bool {
+	passes := h.scoreboardService.FullPasses()
+	userCompletions := make(map[string]map[int]bool)
+	for challengeID := range h.challengeService.GetChallenges() {
+		for _, username := range passes[services.ClassicRef(challengeID)] {
+			if userCompletions[username] == nil {
+				userCompletions[username] = make(map[int]bool)
 			}
+			userCompletions[username][challengeID] = true
 		}
 	}
+	return userCompletions
+}
+
+// calculateMainLeaderboard calculates the main leaderboard data
+func (h *APIHandler) calculateMainLeaderboard() []LeaderboardUser {
+	totalChallenges := len(h.challengeService.GetChallenges())
+	userCompletions := h.mainCompletions()
 
 	// Convert to leaderboard format
 	var leaderboard []LeaderboardUser
@@ -508,16 +844,14 @@ func (h *APIHandler) calculateMainLeaderboard() []LeaderboardUser {
 		completedCount := len(completions)
 		completionRate := float64(completedCount) / float64(totalChallenges) * 100
 
-		// Determine achievement
-		achievement := "🌱 Beginner"
-		if completedCount >= 20 {
-			achievement = "🔥 Master"
-		} else if completedCount >= 15 {
-			achievement = "⭐ Expert"
-		} else if completedCount >= 10 {
-			achievement = "💪 Advanced"
-		} else if completedCount >= 5 {
-			achievement = "🚀 Intermediate"
+		// Achievements are evaluated when submissions are recorded; the leaderboard only reads them
+		achievements, err := h.achievementService.Earned(username)
+		if err != nil {
+			achievements = []models.Achievement{}
+		}
+		achievement := ""
+		if title := services.TopTitle(achievements); title != nil {
+			achievement = title.Icon + " " + title.Name
 		}
 
 		leaderboard = append(leaderboard, LeaderboardUser{
@@ -526,18 +860,17 @@ func (h *APIHandler) calculateMainLeaderboard() []LeaderboardUser {
 			CompletionRate:      completionRate,
 			CompletedChallenges: completions,
 			Achievement:         achievement,
+			Achievements:        achievements,
 		})
 	}
 
 	// Sort by completion count (descending), then by username
-	for i := 0; i < len(leaderboard); i++ {
-		for j := i + 1; j < len(leaderboard); j++ {
-			if leaderboard[j].CompletedCount > leaderboard[i].CompletedCount ||
-				(leaderboard[j].CompletedCount == leaderboard[i].CompletedCount && leaderboard[j].Username < leaderboard[i].Username) {
-				leaderboard[i], leaderboard[j] = leaderboard[j], leaderboard[i]
-			}
+	sort.Slice(leaderboard, func(i, j int) bool {
+		if leaderboard[i].CompletedCount != leaderboard[j].CompletedCount {
+			return leaderboard[i].CompletedCount > leaderboard[j].CompletedCount
 		}
-	}
+		return leaderboard[i].Username < leaderboard[j].Username
+	})
 
 	// Assign ranks
 	for i := range leaderboard {
@@ -575,57 +908,111 @@ func (h *APIHandler) HandlePackageChallenge(w http.ResponseWriter, r *http.Reque
 
 	// Parse request body
 	var request struct {
-		Code     string `json:"code"`
-		Username string `json:"username"`
+		Code     string                 `json:"code"`
+		Files    models.SubmissionFiles `json:"files"`
+		Username string                 `json:"username"`
+		Mode     string                 `json:"mode"`
 	}
 
-	body, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
-		return
-	}
+	if isMultipartRequest(r) {
+		files, err := parseSubmissionUpload(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		request.Files = files
+		request.Username = r.FormValue("username")
+		request.Mode = r.FormValue("mode")
+	} else {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
 
-	err = json.Unmarshal(body, &request)
-	if err != nil {
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
-		return
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+			return
+		}
 	}
 
-	if request.Code == "" {
+	if request.Code == "" && len(request.Files) == 0 {
 		http.Error(w, "Code is required", http.StatusBadRequest)
 		return
 	}
 
-	// Use the existing package service
-	packageService := h.packageService
+	if !services.ValidRunMode(request.Mode) {
+		http.Error(w, "Invalid mode. Must be one of 'race', 'cover', 'short', 'bench' or 'fuzz'", http.StatusBadRequest)
+		return
+	}
 
-	challenge, err := packageService.GetPackageChallenge(packageName, challengeId)
+	challenge, err := h.resolveChallenge(services.PackageRef(packageName, challengeId))
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Challenge not found: %v", err), http.StatusNotFound)
 		return
 	}
 
-	// Convert PackageChallenge to Challenge format for ExecutionService
-	challengeForExecution := &models.Challenge{
-		ID:       0, // Package challenges don't use numeric IDs
-		Title:    challenge.Title,
-		TestFile: challenge.TestFile,
+	files := submissionFiles(request.Code, request.Files, challenge.SolutionFile)
+	if err := services.ValidateSubmissionFiles(files); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	// Run the actual tests using ExecutionService
-	result := h.executionService.RunCode(request.Code, challengeForExecution)
+	// Run the actual tests using ExecutionService, with the hidden tests on submit
+	var result services.ExecutionResult
+	var earned []models.Achievement
+	if action == "submit" {
+		result, earned, err = h.submitChallenge(challenge, request.Username, files, request.Mode, time.Now())
+	} else {
+		result, err = h.runChallenge(challenge, request.Username, files, services.RunOptions{Mode: request.Mode})
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 
 	// Format response
 	response := map[string]interface{}{
-		"success":      result.Passed,
-		"execution_ms": result.ExecutionMs,
-		"output":       result.Output,
+		"success":         result.Passed,
+		"execution_ms":    result.ExecutionMs,
+		"output":          result.Output,
+		"diagnostics":     result.Diagnostics,
+		"mode":            result.Mode,
+		"race":            result.Race,
+		"race_detected":   result.RaceDetected,
+		"includes_hidden": result.IncludesHidden,
+	}
+
+	if result.Coverage != nil {
+		response["coverage"] = result.Coverage
+	}
+	if result.Benchmark != nil {
+		response["benchmark"] = result.Benchmark
+	}
+	if result.Fuzz != nil {
+		response["fuzz"] = result.Fuzz
+	}
+	if result.Differential != nil {
+		response["differential"] = result.Differential
+	}
+	if result.Quality != nil {
+		response["quality"] = result.Quality
 	}
 
 	// Count passed tests from output for display
 	testsPassed, testsTotal := h.parseTestResults(result.Output)
+	for _, hidden := range result.HiddenTests {
+		testsTotal++
+		if hidden.Passed {
+			testsPassed++
+		}
+	}
 	response["tests_passed"] = testsPassed
 	response["tests_total"] = testsTotal
+	if len(result.HiddenTests) > 0 {
+		response["hidden_tests"] = result.HiddenTests
+	}
 
 	if action == "submit" && result.Passed {
 		response["message"] = "Solution submitted successfully!"
@@ -637,6 +1024,10 @@ func (h *APIHandler) HandlePackageChallenge(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
+	if len(earned) > 0 {
+		response["achievements"] = earned
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -679,10 +1070,11 @@ func (h *APIHandler) SavePackageChallengeToFilesystem(w http.ResponseWriter, r *
 	}
 
 	var request struct {
-		Username    string `json:"username"`
-		PackageName string `json:"packageName"`
-		ChallengeID string `json:"challengeId"`
-		Code        string `json:"code"`
+		Username    string                 `json:"username"`
+		PackageName string                 `json:"packageName"`
+		ChallengeID string                 `json:"challengeId"`
+		Code        string                 `json:"code"`
+		Files       models.SubmissionFiles `json:"files"`
 	}
 
 	err := json.NewDecoder(r.Body).Decode(&request)
@@ -700,78 +1092,73 @@ func (h *APIHandler) SavePackageChallengeToFilesystem(w http.ResponseWriter, r *
 	h.setUsernameCookie(w, request.Username)
 
 	// Validate challenge exists
-	_, err = h.packageService.GetPackageChallenge(request.PackageName, request.ChallengeID)
+	challenge, err := h.resolveChallenge(services.PackageRef(request.PackageName, request.ChallengeID))
 	if err != nil {
 		http.Error(w, "Challenge not found", http.StatusNotFound)
 		return
 	}
 
 	// Save to filesystem
-	response := h.savePackageChallengeToFilesystem(request)
+	response := h.executionService.SaveSolution(challenge, request.Username, request.Code, request.Files)
+	h.commitSubmission(challenge, request.Username, &response)
+	if response.Success {
+		h.evaluateAchievements(request.Username)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-// savePackageChallengeToFilesystem handles the actual file saving for package challenges
-func (h *APIHandler) savePackageChallengeToFilesystem(request struct {
-	Username    string `json:"username"`
-	PackageName string `json:"packageName"`
-	ChallengeID string `json:"challengeId"`
-	Code        string `json:"code"`
-}) services.SaveSubmissionResponse {
-	// Get working directory for correct relative paths
-	workDir, _ := os.Getwd()
-
-	// Try different path approaches to handle potential path issues
-	var submissionDir string
-	var fileSaved bool
-
-	// Try multiple path options for package challenges
-	pathOptions := []string{
-		// Option 1: From web-ui directory (standard case)
-		filepath.Join("..", "packages", request.PackageName, request.ChallengeID, "submissions", request.Username),
-		// Option 2: From root workspace
-		filepath.Join("packages", request.PackageName, request.ChallengeID, "submissions", request.Username),
-		// Option 3: Absolute path from detected workspace root
-		filepath.Join(workDir, "..", "packages", request.PackageName, request.ChallengeID, "submissions", request.Username),
-	}
-
-	for _, dirPath := range pathOptions {
-		err := os.MkdirAll(dirPath, 0755)
-		if err != nil {
-			continue
-		}
+// commitSubmission records a saved submission on a branch of its own and fills in the git commands left to run.
+// A failed commit does not fail the save; the commands then create the branch and commit by hand.
+func (h *APIHandler) commitSubmission(challenge *models.TrackChallenge, username string, response *services.SaveSubmissionResponse) {
+	if !response.Success || h.gitService == nil {
+		return
+	}
 
-		solutionFile := filepath.Join(dirPath, "solution.go")
-		err = ioutil.WriteFile(solutionFile, []byte(request.Code), 0644)
-		if err != nil {
-			continue
+	now := time.Now()
+	commit, err := h.gitService.CommitSubmission(challenge, username, response.GitPath, now)
+	if err != nil {
+		log.Printf("Failed to commit submission of %s by %s: %v", challenge.Ref, username, err)
+		response.GitError = err.Error()
+		if errors.Is(err, services.ErrNothingToCommit) {
+			return
 		}
+	}
+	// A commit that failed to push is still reported, with the push left to run
+	response.Git = commit
+	response.GitCommands = h.gitService.Commands(challenge, username, response.GitPath, commit, now)
+}
 
-		submissionDir = dirPath
-		fileSaved = true
-		break
+// submissionFiles returns the multi-file submission, or wraps single-file code under defaultName
+func submissionFiles(code string, files models.SubmissionFiles, defaultName string) models.SubmissionFiles {
+	if len(files) > 0 {
+		return files
 	}
+	return models.SubmissionFiles{defaultName: code}
+}
 
-	if !fileSaved {
-		return services.SaveSubmissionResponse{
-			Success: false,
-			Message: "Failed to save solution to any available path",
-		}
+// isMultipartRequest reports whether the request carries a multipart form upload
+func isMultipartRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data")
+}
+
+// parseSubmissionUpload reads the "archive" file of a multipart upload into a set of files
+func parseSubmissionUpload(r *http.Request) (models.SubmissionFiles, error) {
+	if err := r.ParseMultipartForm(services.MaxSubmissionBytes * 2); err != nil {
+		return nil, fmt.Errorf("invalid upload: %v", err)
 	}
 
-	// Return success response with git commands
-	relativePath := filepath.Join("packages", request.PackageName, request.ChallengeID, "submissions", request.Username, "solution.go")
-	return services.SaveSubmissionResponse{
-		Success:  true,
-		Message:  "Solution saved to filesystem",
-		FilePath: filepath.Join(submissionDir, "solution.go"),
-		GitCommands: []string{
-			"cd " + filepath.Join(workDir, ".."),
-			fmt.Sprintf("git add %s", relativePath),
-			fmt.Sprintf("git commit -m \"Add solution for %s %s by %s\"", request.PackageName, request.ChallengeID, request.Username),
-			"git push origin main",
-		},
+	file, header, err := r.FormFile("archive")
+	if err != nil {
+		return nil, fmt.Errorf("archive file is required")
 	}
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(file, services.MaxSubmissionBytes*2))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %v", err)
+	}
+
+	return services.ExtractSubmissionArchive(header.Filename, data)
 }
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+// DraftHandler autosaves the code in challenge editors and serves its history
+type DraftHandler struct {
+	challengeService *services.ChallengeService
+	packageService   *services.PackageService
+	draftService     *services.DraftService
+}
+
+// NewDraftHandler creates a new draft handler
+func NewDraftHandler(challengeService *services.ChallengeService, packageService *services.PackageService, draftService *services.DraftService) *DraftHandler {
+	return &DraftHandler{
+		challengeService: challengeService,
+		packageService:   packageService,
+		draftService:     draftService,
+	}
+}
+
+// DraftRequest is the body of a draft save, either the challenge's solution file as Code or several Files
+type DraftRequest struct {
+	Code  string                 `json:"code,omitempty"`
+	Files models.SubmissionFiles `json:"files,omitempty"`
+}
+
+// RestoreRequest is the body of a draft restore
+type RestoreRequest struct {
+	Version int `json:"version"`
+}
+
+// v1Routes serves the requesting user's drafts of a challenge under /api/v1/drafts/{track}/{challenge}
+func (h *DraftHandler) v1Routes() []v1Route {
+	username := []string{"username"}
+	return []v1Route{
+		{Method: "GET", Pattern: "/api/v1/drafts/{track}/{challenge}", Summary: "Get the latest draft of a challenge",
+			Query: username, Response: models.DraftVersion{}, Errors: []int{400, 404, 500}, Operation: "getDraft", handle: h.draft(h.getDraft)},
+		{Method: "PUT", Pattern: "/api/v1/drafts/{track}/{challenge}", Summary: "Autosave a draft of a challenge",
+			Query: username, Request: DraftRequest{}, Response: models.DraftVersion{}, Errors: []int{400, 404}, Operation: "saveDraft", handle: h.draft(h.saveDraft)},
+		{Method: "GET", Pattern: "/api/v1/drafts/{track}/{challenge}/history", Summary: "List the versions of a challenge's draft, newest first",
+			Query: username, Response: models.Draft{}, Errors: []int{400, 404, 500}, Operation: "getDraftHistory", handle: h.draft(h.getHistory)},
+		{Method: "GET", Pattern: "/api/v1/drafts/{track}/{challenge}/versions/{version}", Summary: "Get a version of a challenge's draft",
+			Query: username, Response: models.DraftVersion{}, Errors: []int{400, 404, 500}, Operation: "getDraftVersion", handle: h.draft(h.getVersion)},
+		{Method: "POST", Pattern: "/api/v1/drafts/{track}/{challenge}/restore", Summary: "Copy a version of a challenge's draft into a new latest version",
+			Query: username, Request: RestoreRequest{}, Response: models.DraftVersion{}, Errors: []int{400, 404, 500}, Operation: "restoreDraft", handle: h.draft(h.restore)},
+	}
+}
+
+// draftHandler handles a request for a user's drafts of a challenge
+type draftHandler func(w http.ResponseWriter, r *http.Request, username string, challenge *models.TrackChallenge)
+
+// draft looks up the routed challenge and the requesting user before handling a request
+func (h *DraftHandler) draft(handle draftHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		challenge, ok := routedChallenge(w, r, h.challengeService, h.packageService)
+		if !ok {
+			return
+		}
+		username, ok := routedUsername(w, r)
+		if !ok {
+			return
+		}
+		handle(w, r, username, challenge)
+	}
+}
+
+// getDraft returns the latest version of a draft
+func (h *DraftHandler) getDraft(w http.ResponseWriter, r *http.Request, username string, challenge *models.TrackChallenge) {
+	draft, err := h.draftService.Latest(username, challenge.Ref)
+	writeDraft(w, draft, err)
+}
+
+// getHistory lists the versions of a draft
+func (h *DraftHandler) getHistory(w http.ResponseWriter, r *http.Request, username string, challenge *models.TrackChallenge) {
+	history, err := h.draftService.History(username, challenge.Ref)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrorCodeInternal, err.Error())
+		return
+	}
+	writeJSON(w, history)
+}
+
+// getVersion returns a version of a draft
+func (h *DraftHandler) getVersion(w http.ResponseWriter, r *http.Request, username string, challenge *models.TrackChallenge) {
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "Invalid version")
+		return
+	}
+	draft, err := h.draftService.Version(username, challenge.Ref, version)
+	writeDraft(w, draft, err)
+}
+
+// restore copies a version of a draft into a new latest version
+func (h *DraftHandler) restore(w http.ResponseWriter, r *http.Request, username string, challenge *models.TrackChallenge) {
+	var request RestoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "Invalid request data")
+		return
+	}
+	draft, err := h.draftService.Restore(username, challenge.Ref, request.Version, time.Now())
+	writeDraft(w, draft, err)
+}
+
+// saveDraft autosaves the code in a PUT body, either the challenge's solution file as "code" or several "files"
+func (h *DraftHandler) saveDraft(w http.ResponseWriter, r *http.Request, username string, challenge *models.TrackChallenge) {
+	var request DraftRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2*services.MaxSubmissionBytes)).Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "Invalid request data")
+		return
+	}
+	files := request.Files
+	if len(files) == 0 {
+		files = models.SubmissionFiles{challenge.SolutionFile: request.Code}
+	}
+
+	version, err := h.draftService.Save(username, challenge.Ref, files, time.Now())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	writeJSON(w, version)
+}
+
+// writeDraft writes a draft version, or 404 when the draft or version does not exist
+func writeDraft(w http.ResponseWriter, draft *models.DraftVersion, err error) {
+	if errors.Is(err, services.ErrDraftNotFound) {
+		writeAPIError(w, http.StatusNotFound, ErrorCodeNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrorCodeInternal, err.Error())
+		return
+	}
+	writeJSON(w, draft)
+}
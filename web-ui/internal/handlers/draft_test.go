@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+// newDraftTestServer serves the draft API with drafts stored in a temporary directory
+func newDraftTestServer(t *testing.T) (*httptest.Server, *services.DraftService) {
+	t.Helper()
+	_, v1 := newV1TestServer(t)
+	draftService := services.NewDraftService(filepath.Join(t.TempDir(), "drafts"))
+	server := httptest.NewServer(NewV1Handler(v1.api, NewDraftHandler(v1.api.challengeService, v1.api.packageService, draftService)))
+	t.Cleanup(server.Close)
+	return server, draftService
+}
+
+// doDraftRequest sends a request to the draft API and decodes a successful JSON response into out, checking that errors are envelopes
+func doDraftRequest(t *testing.T, server *httptest.Server, method, path, body string, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode == http.StatusOK && out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("%s %s: invalid JSON %s: %v", method, path, data, err)
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		var envelope ErrorEnvelope
+		if err := json.Unmarshal(data, &envelope); err != nil || envelope.Error.Status != resp.StatusCode || envelope.Error.Code == "" {
+			t.Errorf("%s %s: %d without an error envelope: %s", method, path, resp.StatusCode, data)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestDraftAutosaveAndRestore(t *testing.T) {
+	server, _ := newDraftTestServer(t)
+	const path = "/api/v1/drafts/classic/1?username=alice"
+
+	if status := doDraftRequest(t, server, "GET", path, "", nil); status != http.StatusNotFound {
+		t.Fatalf("GET without draft: status = %d, want 404", status)
+	}
+
+	var saved models.DraftVersion
+	if status := doDraftRequest(t, server, "PUT", path, `{"code": "package main // first"}`, &saved); status != http.StatusOK {
+		t.Fatalf("PUT: status = %d", status)
+	}
+	if saved.Version != 1 || saved.Files != nil || saved.Bytes != len("package main // first") {
+		t.Errorf("PUT = %+v, want version 1 without files", saved)
+	}
+
+	// Autosaves shortly after each other update the same version
+	if status := doDraftRequest(t, server, "PUT", path, `{"code": "package main // second"}`, &saved); status != http.StatusOK {
+		t.Fatalf("second PUT: status = %d", status)
+	}
+	if saved.Version != 1 {
+		t.Errorf("second PUT went into version %d, want 1", saved.Version)
+	}
+
+	var latest models.DraftVersion
+	if status := doDraftRequest(t, server, "GET", path, "", &latest); status != http.StatusOK {
+		t.Fatalf("GET: status = %d", status)
+	}
+	if latest.Files["solution-template.go"] != "package main // second" {
+		t.Errorf("latest files = %v", latest.Files)
+	}
+
+	// A restore starts a version of its own, and the next autosave another
+	var restored models.DraftVersion
+	if status := doDraftRequest(t, server, "POST", "/api/v1/drafts/classic/1/restore?username=alice", `{"version": 1}`, &restored); status != http.StatusOK {
+		t.Fatalf("restore: status = %d", status)
+	}
+	if restored.Version != 2 || restored.RestoredFrom != 1 || restored.Files["solution-template.go"] != "package main // second" {
+		t.Errorf("restore = %+v", restored)
+	}
+	if status := doDraftRequest(t, server, "PUT", path, `{"code": "package main // third"}`, &saved); status != http.StatusOK || saved.Version != 3 {
+		t.Errorf("PUT after restore: status = %d, version = %d, want 3", status, saved.Version)
+	}
+
+	var history models.Draft
+	if status := doDraftRequest(t, server, "GET", "/api/v1/drafts/classic/1/history?username=alice", "", &history); status != http.StatusOK {
+		t.Fatalf("history: status = %d", status)
+	}
+	if len(history.Versions) != 3 || history.Versions[0].Version != 3 || history.Versions[2].Version != 1 {
+		t.Fatalf("history = %+v, want versions 3, 2, 1", history.Versions)
+	}
+	for _, version := range history.Versions {
+		if version.Files != nil {
+			t.Errorf("history version %d includes its files", version.Version)
+		}
+	}
+
+	var first models.DraftVersion
+	if status := doDraftRequest(t, server, "GET", "/api/v1/drafts/classic/1/versions/1?username=alice", "", &first); status != http.StatusOK {
+		t.Fatalf("version 1: status = %d", status)
+	}
+	if first.Files["solution-template.go"] != "package main // second" {
+		t.Errorf("version 1 files = %v", first.Files)
+	}
+
+	// Drafts are per user
+	if status := doDraftRequest(t, server, "GET", "/api/v1/drafts/classic/1?username=bob", "", nil); status != http.StatusNotFound {
+		t.Errorf("GET as another user: status = %d, want 404", status)
+	}
+}
+
+func TestDraftErrors(t *testing.T) {
+	server, _ := newDraftTestServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"unknown challenge", "GET", "/api/v1/drafts/classic/9999?username=alice", "", http.StatusNotFound},
+		{"missing username", "GET", "/api/v1/drafts/classic/1", "", http.StatusBadRequest},
+		{"invalid username", "GET", "/api/v1/drafts/classic/1?username=../alice", "", http.StatusBadRequest},
+		{"test file", "PUT", "/api/v1/drafts/classic/1?username=alice", `{"files": {"solution_test.go": "package main"}}`, http.StatusBadRequest},
+		{"invalid body", "PUT", "/api/v1/drafts/classic/1?username=alice", `{`, http.StatusBadRequest},
+		{"missing version", "GET", "/api/v1/drafts/classic/1/versions/7?username=alice", "", http.StatusNotFound},
+		{"restore missing version", "POST", "/api/v1/drafts/classic/1/restore?username=alice", `{"version": 7}`, http.StatusNotFound},
+		{"method", "DELETE", "/api/v1/drafts/classic/1?username=alice", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := doDraftRequest(t, server, tt.method, tt.path, tt.body, nil); status != tt.status {
+				t.Errorf("status = %d, want %d", status, tt.status)
+			}
+		})
+	}
+}
+
+func TestDraftPreferredOverSubmittedSolution(t *testing.T) {
+	server, draftService := newDraftTestServer(t)
+	h := &WebHandler{draftService: draftService}
+	submitted := func() string { return "submitted" }
+
+	if got := h.draftSolution("alice", "classic/1", "solution-template.go", submitted); got != "submitted" {
+		t.Errorf("without draft = %q, want the submitted solution", got)
+	}
+	if status := doDraftRequest(t, server, "PUT", "/api/v1/drafts/classic/1?username=alice", `{"code": "draft"}`, nil); status != http.StatusOK {
+		t.Fatalf("PUT: status = %d", status)
+	}
+	if got := h.draftSolution("alice", "classic/1", "solution-template.go", submitted); got != "draft" {
+		t.Errorf("with draft = %q, want the draft", got)
+	}
+}
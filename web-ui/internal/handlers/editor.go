@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"web-ui/internal/services"
+	"web-ui/internal/utils"
+)
+
+// EditorHandler connects challenge editors to gopls sessions
+type EditorHandler struct {
+	challengeService *services.ChallengeService
+	packageService   *services.PackageService
+	editorService    *services.EditorService
+}
+
+// NewEditorHandler creates a new editor handler
+func NewEditorHandler(challengeService *services.ChallengeService, packageService *services.PackageService, editorService *services.EditorService) *EditorHandler {
+	return &EditorHandler{
+		challengeService: challengeService,
+		packageService:   packageService,
+		editorService:    editorService,
+	}
+}
+
+// v1Routes serves the editor's JSON-RPC WebSocket at /api/v1/editor/{track}/{challenge}
+func (h *EditorHandler) v1Routes() []v1Route {
+	return []v1Route{
+		{Method: "GET", Pattern: "/api/v1/editor/{track}/{challenge}", Summary: "Open a gopls session for a challenge's editor over a WebSocket",
+			Query: []string{"username"}, Status: http.StatusSwitchingProtocols, Errors: []int{400, 404, 429, 500, 503}, Operation: "openEditor", handle: h.openEditor},
+	}
+}
+
+// openEditor serves the JSON-RPC WebSocket of a challenge's editor.
+// The gopls session is started before the upgrade, so limits and errors are answered with an error envelope.
+func (h *EditorHandler) openEditor(w http.ResponseWriter, r *http.Request) {
+	challenge, ok := routedChallenge(w, r, h.challengeService, h.packageService)
+	if !ok {
+		return
+	}
+	username, ok := routedUsername(w, r)
+	if !ok {
+		return
+	}
+	if !utils.IsWebSocketRequest(r) {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "WebSocket upgrade required")
+		return
+	}
+
+	session, err := h.editorService.Open(challenge, username)
+	if err != nil {
+		writeEditorError(w, err)
+		return
+	}
+
+	conn, err := utils.UpgradeWebSocket(w, r)
+	if err != nil {
+		h.editorService.Close(session)
+		return
+	}
+
+	go func() {
+		defer conn.Close()
+		for {
+			select {
+			case message := <-session.Send:
+				if err := conn.WriteMessage(utils.TextMessage, message); err != nil {
+					return
+				}
+			case <-session.Done():
+				// Flush what was queued before the session closed, such as the reason it closed
+				for {
+					select {
+					case message := <-session.Send:
+						conn.WriteMessage(utils.TextMessage, message)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		h.editorService.HandleMessage(session, data)
+	}
+	h.editorService.Close(session)
+}
+
+// writeEditorError maps editor service errors to error envelopes
+func writeEditorError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrEditorSessionLimit):
+		writeAPIError(w, http.StatusTooManyRequests, ErrorCodeRateLimited, err.Error())
+	case errors.Is(err, services.ErrEditorUnavailable), errors.Is(err, services.ErrEditorBusy):
+		writeAPIError(w, http.StatusServiceUnavailable, ErrorCodeUnavailable, err.Error())
+	default:
+		writeAPIError(w, http.StatusInternalServerError, ErrorCodeInternal, err.Error())
+	}
+}
@@ -0,0 +1,385 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+	"web-ui/internal/utils"
+)
+
+// TestMain lets the test binary stand in for gopls when GIP_FAKE_GOPLS is set
+func TestMain(m *testing.M) {
+	if os.Getenv("GIP_FAKE_GOPLS") == "1" {
+		runFakeGopls(os.Stdin, os.Stdout)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runFakeGopls answers the LSP requests the editor bridge makes. Lines containing
+// "undefinedThing" get an error diagnostic, and every query gets a canned answer.
+func runFakeGopls(in io.Reader, out io.Writer) {
+	reader := bufio.NewReader(in)
+	write := func(message map[string]interface{}) {
+		message["jsonrpc"] = "2.0"
+		data, _ := json.Marshal(message)
+		fmt.Fprintf(out, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	}
+
+	for {
+		length := 0
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			if line == "" {
+				break
+			}
+			if value, found := strings.CutPrefix(line, "Content-Length:"); found {
+				length, _ = strconv.Atoi(strings.TrimSpace(value))
+			}
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return
+		}
+
+		var message struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params struct {
+				TextDocument struct {
+					URI     string `json:"uri"`
+					Version int    `json:"version"`
+					Text    string `json:"text"`
+				} `json:"textDocument"`
+				ContentChanges []struct {
+					Text string `json:"text"`
+				} `json:"contentChanges"`
+				Position struct {
+					Line      int `json:"line"`
+					Character int `json:"character"`
+				} `json:"position"`
+			} `json:"params"`
+		}
+		json.Unmarshal(body, &message)
+		document := message.Params.TextDocument
+		position := message.Params.Position
+
+		switch message.Method {
+		case "initialize":
+			write(map[string]interface{}{"id": message.ID, "result": map[string]interface{}{"capabilities": map[string]interface{}{}}})
+		case "initialized":
+			// gopls asks for its settings; the answer is ignored here
+			write(map[string]interface{}{"id": "settings", "method": "workspace/configuration", "params": map[string]interface{}{"items": []interface{}{map[string]string{"section": "gopls"}}}})
+		case "textDocument/didOpen", "textDocument/didChange":
+			text := document.Text
+			if len(message.Params.ContentChanges) > 0 {
+				text = message.Params.ContentChanges[0].Text
+			}
+			diagnostics := []interface{}{}
+			for i, line := range strings.Split(text, "\n") {
+				if column := strings.Index(line, "undefinedThing"); column >= 0 {
+					at := map[string]int{"line": i, "character": column}
+					diagnostics = append(diagnostics, map[string]interface{}{
+						"range":    map[string]interface{}{"start": at, "end": at},
+						"severity": 1,
+						"message":  "undefined: undefinedThing",
+					})
+				}
+			}
+			write(map[string]interface{}{"method": "textDocument/publishDiagnostics", "params": map[string]interface{}{
+				"uri": document.URI, "version": document.Version, "diagnostics": diagnostics,
+			}})
+		case "textDocument/completion":
+			write(map[string]interface{}{"id": message.ID, "result": map[string]interface{}{"isIncomplete": false, "items": []interface{}{
+				map[string]interface{}{"label": "Println", "kind": 3, "detail": "func(a ...any) (n int, err error)",
+					"documentation": map[string]string{"kind": "plaintext", "value": "Println formats its operands."},
+					"textEdit":      map[string]interface{}{"newText": "Println"}},
+			}}})
+		case "textDocument/hover":
+			at := map[string]int{"line": position.Line, "character": position.Character}
+			write(map[string]interface{}{"id": message.ID, "result": map[string]interface{}{
+				"contents": map[string]string{"kind": "plaintext", "value": fmt.Sprintf("hover %d:%d", position.Line, position.Character)},
+				"range":    map[string]interface{}{"start": at, "end": at},
+			}})
+		case "textDocument/definition":
+			start := map[string]interface{}{"start": map[string]int{"line": 2, "character": 5}, "end": map[string]int{"line": 2, "character": 9}}
+			write(map[string]interface{}{"id": message.ID, "result": []interface{}{
+				map[string]interface{}{"uri": document.URI, "range": start},
+				map[string]interface{}{"uri": "file:///usr/local/go/src/fmt/print.go", "range": start},
+			}})
+		case "shutdown":
+			write(map[string]interface{}{"id": message.ID, "result": nil})
+		case "exit":
+			return
+		}
+	}
+}
+
+// newEditorTestServer serves the editor route of /api/v1 with the test binary as gopls
+func newEditorTestServer(t *testing.T, limits services.EditorLimits) *httptest.Server {
+	t.Helper()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, v1 := newV1TestServer(t)
+	t.Setenv("GIP_FAKE_GOPLS", "1")
+
+	editorService := services.NewEditorService(v1.api.executionService, executable, limits)
+	handler := NewEditorHandler(v1.api.challengeService, v1.api.packageService, editorService)
+	server := httptest.NewServer(NewV1Handler(v1.api, handler))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// editorTestClient is a JSON-RPC client of an editor session
+type editorTestClient struct {
+	t      *testing.T
+	conn   *utils.WebSocketConn
+	nextID int
+}
+
+// editorTestMessage is a response or notification received from the server
+type editorTestMessage struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func dialEditor(server *httptest.Server, ref, username string) (*utils.WebSocketConn, error) {
+	return utils.DialWebSocket("ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/editor/" + ref + "?username=" + username)
+}
+
+func newEditorTestClient(t *testing.T, server *httptest.Server, ref, username string) *editorTestClient {
+	t.Helper()
+	conn, err := dialEditor(server, ref, username)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &editorTestClient{t: t, conn: conn}
+}
+
+// next returns the next message from the server
+func (c *editorTestClient) next() editorTestMessage {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	_, data, err := c.conn.ReadMessage()
+	if err != nil {
+		c.t.Fatalf("read: %v", err)
+	}
+	var message editorTestMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		c.t.Fatalf("invalid message %s: %v", data, err)
+	}
+	return message
+}
+
+// call sends a request and returns its response, collecting the notifications that arrive first
+func (c *editorTestClient) call(method string, params interface{}) (editorTestMessage, []editorTestMessage) {
+	c.t.Helper()
+	c.nextID++
+	data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	if err := c.conn.WriteMessage(utils.TextMessage, data); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+
+	var notifications []editorTestMessage
+	for {
+		message := c.next()
+		if message.ID != nil && *message.ID == c.nextID {
+			return message, notifications
+		}
+		notifications = append(notifications, message)
+	}
+}
+
+// expect returns the next notification of a method, skipping others
+func (c *editorTestClient) expect(method string) editorTestMessage {
+	c.t.Helper()
+	for {
+		if message := c.next(); message.Method == method {
+			return message
+		}
+	}
+}
+
+func TestEditorSession(t *testing.T) {
+	server := newEditorTestServer(t, services.DefaultEditorLimits)
+	client := newEditorTestClient(t, server, "classic/1", "alice")
+
+	code := "package main\n\nfunc Sum(a, b int) int {\n\treturn undefinedThing\n}\n"
+	response, notifications := client.call("update", map[string]string{"text": code})
+	if response.Error != nil || string(response.Result) != `{"version":2}` {
+		t.Fatalf("update response = %s, error %+v", response.Result, response.Error)
+	}
+
+	// Diagnostics for the update may arrive before or after its response
+	diagnostics := client.diagnostics(2, notifications)
+	if len(diagnostics.Diagnostics) != 1 {
+		t.Fatalf("diagnostics = %+v", diagnostics)
+	}
+	if d := diagnostics.Diagnostics[0]; d.Tool != "gopls" || d.Line != 4 || d.Column != 9 || d.Severity != models.SeverityError {
+		t.Errorf("diagnostic = %+v", d)
+	}
+
+	response, _ = client.call("completion", map[string]interface{}{"line": 4, "column": 9})
+	var completions []models.EditorCompletion
+	json.Unmarshal(response.Result, &completions)
+	if len(completions) != 1 || completions[0].Label != "Println" || completions[0].Kind != "function" ||
+		completions[0].InsertText != "Println" || completions[0].Documentation != "Println formats its operands." {
+		t.Errorf("completions = %s", response.Result)
+	}
+
+	response, _ = client.call("hover", map[string]interface{}{"line": 3, "column": 5})
+	var hover models.EditorHover
+	json.Unmarshal(response.Result, &hover)
+	if hover.Contents != "hover 2:4" || hover.Line != 3 || hover.Column != 5 {
+		t.Errorf("hover = %s", response.Result)
+	}
+
+	response, _ = client.call("definition", map[string]interface{}{"file": "solution_test.go", "line": 1, "column": 1})
+	var locations []models.EditorLocation
+	json.Unmarshal(response.Result, &locations)
+	want := []models.EditorLocation{
+		{File: "solution_test.go", Line: 3, Column: 6},
+		{File: "fmt/print.go", Line: 3, Column: 6, External: true},
+	}
+	if fmt.Sprint(locations) != fmt.Sprint(want) {
+		t.Errorf("definitions = %+v, want %+v", locations, want)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		method string
+		params map[string]interface{}
+		code   int
+	}{
+		{"unknown method", "format", nil, -32601},
+		{"unopened file", "hover", map[string]interface{}{"file": "other.go", "line": 1, "column": 1}, -32602},
+		{"position before the start", "completion", map[string]interface{}{"line": 0, "column": 1}, -32602},
+		{"editing the tests", "update", map[string]interface{}{"file": "solution_test.go", "text": ""}, -32602},
+		{"update without text", "update", map[string]interface{}{}, -32602},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			response, _ := client.call(tt.method, tt.params)
+			if response.Error == nil || response.Error.Code != tt.code {
+				t.Errorf("error = %+v, want code %d", response.Error, tt.code)
+			}
+		})
+	}
+}
+
+// diagnostics returns the diagnostics of a version of the solution file, from the notifications already received or the next ones
+func (c *editorTestClient) diagnostics(version int, received []editorTestMessage) models.EditorDiagnostics {
+	c.t.Helper()
+	for {
+		for _, message := range received {
+			var diagnostics models.EditorDiagnostics
+			if message.Method == "diagnostics" && json.Unmarshal(message.Params, &diagnostics) == nil &&
+				diagnostics.File == "solution-template.go" && diagnostics.Version == version {
+				return diagnostics
+			}
+		}
+		received = []editorTestMessage{c.next()}
+	}
+}
+
+func TestEditorSessionLimits(t *testing.T) {
+	server := newEditorTestServer(t, services.EditorLimits{SessionsPerUser: 1, MaxSessions: 2, IdleTimeout: time.Minute})
+
+	first := newEditorTestClient(t, server, "classic/1", "alice")
+	newEditorTestClient(t, server, "classic/1", "bob")
+
+	for _, tt := range []struct {
+		name     string
+		ref      string
+		username string
+		status   string
+	}{
+		{"second session of a user", "classic/1", "alice", "429"},
+		{"server full", "classic/1", "carol", "503"},
+		{"missing username", "classic/1", "", "400"},
+		{"unknown challenge", "classic/9999", "carol", "404"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := dialEditor(server, tt.ref, tt.username)
+			if err == nil {
+				conn.Close()
+				t.Fatalf("dial succeeded, want %s", tt.status)
+			}
+			if !strings.Contains(err.Error(), tt.status) {
+				t.Errorf("dial error = %v, want %s", err, tt.status)
+			}
+		})
+	}
+
+	// Disconnecting frees the user's slot
+	first.conn.Close()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		conn, err := dialEditor(server, "classic/1", "alice")
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the session was not released: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestEditorSessionIdleReaping(t *testing.T) {
+	server := newEditorTestServer(t, services.EditorLimits{SessionsPerUser: 1, MaxSessions: 1, IdleTimeout: 300 * time.Millisecond})
+	client := newEditorTestClient(t, server, "classic/1", "alice")
+
+	var params struct {
+		Reason string `json:"reason"`
+	}
+	json.Unmarshal(client.expect("closed").Params, &params)
+	if params.Reason != "idle" {
+		t.Errorf("close reason = %q, want idle", params.Reason)
+	}
+	client.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := client.conn.ReadMessage(); err == nil {
+		t.Errorf("the connection stayed open after the session was reaped")
+	}
+
+	// The reaped session no longer counts against the limits
+	conn, err := dialEditor(server, "classic/1", "bob")
+	if err != nil {
+		t.Fatalf("dial after reaping: %v", err)
+	}
+	conn.Close()
+}
+
+func TestEditorUnavailable(t *testing.T) {
+	_, v1 := newV1TestServer(t)
+	editorService := services.NewEditorService(v1.api.executionService, "gopls-not-installed", services.DefaultEditorLimits)
+	server := httptest.NewServer(NewV1Handler(v1.api, NewEditorHandler(v1.api.challengeService, v1.api.packageService, editorService)))
+	defer server.Close()
+
+	if _, err := dialEditor(server, "classic/1", "alice"); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("dial error = %v, want 503", err)
+	}
+}
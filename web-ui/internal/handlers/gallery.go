@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+// requestUsername returns the requesting user from the username query parameter or cookie
+func requestUsername(r *http.Request) string {
+	if username := r.URL.Query().Get("username"); username != "" {
+		return username
+	}
+	if cookie, err := r.Cookie("username"); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// gallerySolutions lists the other users' solutions to a challenge for the requesting user.
+// On failure it returns the HTTP status and error code to answer with.
+func (h *APIHandler) gallerySolutions(r *http.Request, challenge *models.TrackChallenge) (*models.SolutionGallery, int, string, error) {
+	requester := requestUsername(r)
+	if !services.ValidUsername(requester) {
+		return nil, http.StatusBadRequest, ErrorCodeInvalidRequest, errors.New("a valid username is required")
+	}
+	gallery, err := h.galleryService.Solutions(challenge, requester)
+	if err != nil {
+		status, code := galleryErrorStatus(err)
+		return nil, status, code, err
+	}
+	return gallery, http.StatusOK, "", nil
+}
+
+// compareGallerySolutions diffs the left and right query users' solutions, left defaulting to the requesting user
+func (h *APIHandler) compareGallerySolutions(r *http.Request, challenge *models.TrackChallenge) (*models.SolutionComparison, int, string, error) {
+	requester := requestUsername(r)
+	if !services.ValidUsername(requester) {
+		return nil, http.StatusBadRequest, ErrorCodeInvalidRequest, errors.New("a valid username is required")
+	}
+	left := r.URL.Query().Get("left")
+	if left == "" {
+		left = requester
+	}
+	right := r.URL.Query().Get("right")
+	if right == "" {
+		return nil, http.StatusBadRequest, ErrorCodeInvalidRequest, errors.New("the right query parameter is required")
+	}
+
+	comparison, err := h.galleryService.Compare(challenge, requester, left, right)
+	if err != nil {
+		status, code := galleryErrorStatus(err)
+		return nil, status, code, err
+	}
+	return comparison, http.StatusOK, "", nil
+}
+
+// galleryErrorStatus maps a gallery error to an HTTP status and error code
+func galleryErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrGalleryLocked):
+		return http.StatusForbidden, ErrorCodeForbidden
+	case errors.Is(err, services.ErrSolutionNotFound):
+		return http.StatusNotFound, ErrorCodeNotFound
+	}
+	return http.StatusInternalServerError, ErrorCodeInternal
+}
+
+// getChallengeSolutions serves GET /api/challenges/{id}/solutions
+func (h *APIHandler) getChallengeSolutions(w http.ResponseWriter, r *http.Request, challenge *models.TrackChallenge) {
+	gallery, status, _, err := h.gallerySolutions(r, challenge)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	writeJSON(w, gallery)
+}
+
+// compareChallengeSolutions serves GET /api/challenges/{id}/solutions/compare
+func (h *APIHandler) compareChallengeSolutions(w http.ResponseWriter, r *http.Request, challenge *models.TrackChallenge) {
+	comparison, status, _, err := h.compareGallerySolutions(r, challenge)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	writeJSON(w, comparison)
+}
+
+// listSolutions returns the other users' solutions to a challenge, once the requesting user has passed it
+func (h *V1Handler) listSolutions(w http.ResponseWriter, r *http.Request, challenge *models.TrackChallenge) {
+	gallery, status, code, err := h.api.gallerySolutions(r, challenge)
+	if err != nil {
+		writeAPIError(w, status, code, fmt.Sprintf("%s: %v", challenge.Ref, err))
+		return
+	}
+	writeJSON(w, gallery)
+}
+
+// compareSolutions returns a diff by function declaration between two users' solutions to a challenge
+func (h *V1Handler) compareSolutions(w http.ResponseWriter, r *http.Request, challenge *models.TrackChallenge) {
+	comparison, status, code, err := h.api.compareGallerySolutions(r, challenge)
+	if err != nil {
+		writeAPIError(w, status, code, fmt.Sprintf("%s: %v", challenge.Ref, err))
+		return
+	}
+	writeJSON(w, comparison)
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"web-ui/internal/models"
+)
+
+// galleryUsers returns two users who passed classic/2 on its scoreboard and have a saved solution to it
+func galleryUsers(t *testing.T, v1 *V1Handler) (passed, other string) {
+	t.Helper()
+	entries, _ := v1.api.scoreboardService.Scoreboard("classic/2")
+	challenge, err := v1.api.resolveChallenge("classic/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if !v1.api.scoreboardService.HasPassed("classic/2", entry.Username) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(challenge.SubmissionDir(entry.Username), "solution-template.go")); err != nil {
+			continue
+		}
+		if passed == "" {
+			passed = entry.Username
+		} else if other == "" {
+			other = entry.Username
+		}
+	}
+	if other == "" {
+		t.Skip("classic/2 needs two saved solutions that passed on its scoreboard")
+	}
+	return passed, other
+}
+
+func TestV1SolutionGallery(t *testing.T) {
+	server, v1 := newV1TestServer(t)
+	spec := fetchSpec(t, server)
+	passed, other := galleryUsers(t, v1)
+
+	pattern := "/api/v1/challenges/{id}/solutions"
+	status, body := doV1Request(t, server, "GET", "/api/v1/challenges/2/solutions?username="+passed, "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d: %s", status, body)
+	}
+	checkResponseContract(t, spec, "GET", pattern, status, body)
+
+	var gallery models.SolutionGallery
+	if err := json.Unmarshal(body, &gallery); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, solution := range gallery.Solutions {
+		if solution.Username == passed {
+			t.Errorf("the gallery lists the requester's own solution")
+		}
+		if solution.Username == other {
+			found = true
+			if solution.Error == "" && (solution.Metrics.Lines == 0 || solution.Metrics.Complexity < solution.Metrics.Functions) {
+				t.Errorf("metrics for %s = %+v", other, solution.Metrics)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("the gallery is missing %s", other)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"without username", "/api/v1/challenges/2/solutions", 400},
+		{"before passing", "/api/v1/challenges/2/solutions?username=gallery-newcomer", 403},
+		{"missing challenge", "/api/v1/challenges/9999/solutions?username=" + passed, 404},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := doV1Request(t, server, "GET", tt.path, "")
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d: %s", status, tt.status, body)
+			}
+			checkResponseContract(t, spec, "GET", pattern, status, body)
+		})
+	}
+}
+
+func TestV1CompareSolutions(t *testing.T) {
+	server, v1 := newV1TestServer(t)
+	spec := fetchSpec(t, server)
+	passed, other := galleryUsers(t, v1)
+
+	pattern := "/api/v1/challenges/{id}/solutions/compare"
+	status, body := doV1Request(t, server, "GET", "/api/v1/challenges/2/solutions/compare?username="+passed+"&right="+other, "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d: %s", status, body)
+	}
+	checkResponseContract(t, spec, "GET", pattern, status, body)
+
+	var comparison models.SolutionComparison
+	if err := json.Unmarshal(body, &comparison); err != nil {
+		t.Fatal(err)
+	}
+	if comparison.Left != passed || comparison.Right != other || len(comparison.Functions) == 0 {
+		t.Fatalf("comparison = %+v", comparison)
+	}
+	for _, fn := range comparison.Functions {
+		switch fn.Status {
+		case models.FunctionIdentical, models.FunctionEquivalent:
+			if fn.Left == nil || fn.Right == nil || len(fn.Diff) > 0 {
+				t.Errorf("%s is %s but has sides %v/%v and %d diff lines", fn.Name, fn.Status, fn.Left != nil, fn.Right != nil, len(fn.Diff))
+			}
+		case models.FunctionChanged:
+			if len(fn.Diff) == 0 {
+				t.Errorf("%s changed without a diff", fn.Name)
+			}
+		case models.FunctionAdded:
+			if fn.Left != nil || fn.Right == nil {
+				t.Errorf("added %s has the wrong sides", fn.Name)
+			}
+		case models.FunctionRemoved:
+			if fn.Left == nil || fn.Right != nil {
+				t.Errorf("removed %s has the wrong sides", fn.Name)
+			}
+		default:
+			t.Errorf("%s has unknown status %q", fn.Name, fn.Status)
+		}
+	}
+
+	// A solution compared with itself is identical throughout
+	status, body = doV1Request(t, server, "GET", "/api/v1/challenges/2/solutions/compare?username="+passed+"&left="+other+"&right="+other, "")
+	if status != http.StatusOK {
+		t.Fatalf("self comparison status = %d: %s", status, body)
+	}
+	if err := json.Unmarshal(body, &comparison); err != nil {
+		t.Fatal(err)
+	}
+	for _, fn := range comparison.Functions {
+		if fn.Status != models.FunctionIdentical {
+			t.Errorf("%s compared with itself is %s", fn.Name, fn.Status)
+		}
+	}
+
+	for _, tt := range []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"without right", "username=" + passed, 400},
+		{"before passing", "username=gallery-newcomer&left=" + passed + "&right=" + other, 403},
+		{"missing solution", "username=" + passed + "&right=gallery-nobody", 404},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := doV1Request(t, server, "GET", "/api/v1/challenges/2/solutions/compare?"+tt.query, "")
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d: %s", status, tt.status, body)
+			}
+			checkResponseContract(t, spec, "GET", pattern, status, body)
+		})
+	}
+}
+
+func TestLegacySolutionGallery(t *testing.T) {
+	_, v1 := newV1TestServer(t)
+	passed, _ := galleryUsers(t, v1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/challenges/", v1.api.GetChallengeByID)
+	for _, tt := range []struct {
+		path   string
+		status int
+	}{
+		{"/api/challenges/2", 200},
+		{"/api/challenges/2/solutions?username=" + passed, 200},
+		{"/api/challenges/2/solutions?username=gallery-newcomer", 403},
+		{"/api/challenges/2/solutions/compare?username=" + passed, 400},
+		{"/api/challenges/2/nope", 404},
+	} {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+		if recorder.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", tt.path, recorder.Code, tt.status, strings.TrimSpace(recorder.Body.String()))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+func TestCommitSubmissionResponse(t *testing.T) {
+	// Without a git identity every commit fails, which must not fail the save
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	challenge := &models.TrackChallenge{Ref: "classic/1", Track: "classic", ID: "1", SolutionFile: "solution-template.go"}
+
+	tests := []struct {
+		name         string
+		saved        bool
+		wantError    bool
+		wantCommands bool
+	}{
+		{"failed save is left alone", false, false, false},
+		{"failed commit leaves manual commands", true, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &APIHandler{gitService: services.NewGitService(t.TempDir(), services.GitOptions{})}
+			response := services.SaveSubmissionResponse{Success: tt.saved, GitPath: "challenge-1/submissions/alice/solution-template.go"}
+			h.commitSubmission(challenge, "alice", &response)
+
+			encoded, err := json.Marshal(response)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(encoded, &body); err != nil {
+				t.Fatal(err)
+			}
+			if _, exists := body["git"]; exists {
+				t.Errorf("response has a commit after a failed commit: %s", encoded)
+			}
+			if gitError, _ := body["gitError"].(string); (gitError != "") != tt.wantError {
+				t.Errorf("gitError = %q, want an error %v", gitError, tt.wantError)
+			}
+			commands, _ := body["gitCommands"].([]interface{})
+			if (len(commands) > 0) != tt.wantCommands {
+				t.Fatalf("gitCommands = %v, want commands %v", commands, tt.wantCommands)
+			}
+			if tt.wantCommands && !strings.HasPrefix(commands[len(commands)-1].(string), "git push -u origin submission/alice/classic-1-") {
+				t.Errorf("last command = %q, want the branch pushed", commands[len(commands)-1])
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+// InterviewHandler handles the timed interview session endpoints
+type InterviewHandler struct {
+	challengeService *services.ChallengeService
+	executionService *services.ExecutionService
+	interviewService *services.InterviewService
+}
+
+// NewInterviewHandler creates a new interview handler
+func NewInterviewHandler(
+	challengeService *services.ChallengeService,
+	executionService *services.ExecutionService,
+	interviewService *services.InterviewService,
+) *InterviewHandler {
+	return &InterviewHandler{
+		challengeService: challengeService,
+		executionService: executionService,
+		interviewService: interviewService,
+	}
+}
+
+// interviewResponse is a session plus the time left on its countdown
+type interviewResponse struct {
+	*models.InterviewSession
+	RemainingSeconds int `json:"remainingSeconds"`
+}
+
+// HandleInterviews lists sessions or starts a new one
+func (h *InterviewHandler) HandleInterviews(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		sessions := h.interviewService.ListSessions(r.URL.Query().Get("username"))
+		response := make([]interviewResponse, 0, len(sessions))
+		for _, session := range sessions {
+			response = append(response, newInterviewResponse(session))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	case "POST":
+		var request services.InterviewRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid request data", http.StatusBadRequest)
+			return
+		}
+
+		session, err := h.interviewService.StartSession(request, h.challengeService.GetChallenges())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(newInterviewResponse(session))
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleInterview routes /api/interviews/{id} and its sub-resources
+func (h *InterviewHandler) HandleInterview(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/interviews/"), "/"), "/")
+	if len(parts) == 0 || parts[0] == "" || len(parts) > 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	id := parts[0]
+	action := ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+
+	switch action {
+	case "":
+		h.getInterview(w, r, id)
+	case "snapshots":
+		h.saveSnapshot(w, r, id)
+	case "run":
+		h.runInterviewCode(w, r, id)
+	case "end":
+		h.endInterview(w, r, id)
+	case "report":
+		h.getInterviewReport(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// getInterview returns a session
+func (h *InterviewHandler) getInterview(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	session, exists := h.interviewService.GetSession(id)
+	if !exists {
+		http.Error(w, "Interview session not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newInterviewResponse(session))
+}
+
+// saveSnapshot records an autosaved copy of the editor
+func (h *InterviewHandler) saveSnapshot(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		ChallengeID int    `json:"challengeId"`
+		Code        string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request data", http.StatusBadRequest)
+		return
+	}
+
+	saved, err := h.interviewService.SaveSnapshot(id, request.ChallengeID, request.Code)
+	if err != nil {
+		writeInterviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"saved": saved,
+	})
+}
+
+// runInterviewCode runs the tests for a session challenge and records the run
+func (h *InterviewHandler) runInterviewCode(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	startedAt := time.Now()
+
+	var request struct {
+		ChallengeID int    `json:"challengeId"`
+		Code        string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request data", http.StatusBadRequest)
+		return
+	}
+
+	// Reject runs on locked sessions before spending time on the tests
+	if err := h.interviewService.CheckActive(id, request.ChallengeID); err != nil {
+		writeInterviewError(w, err)
+		return
+	}
+
+	challenge, exists := h.challengeService.GetChallenge(request.ChallengeID)
+	if !exists {
+		http.Error(w, "Challenge not found", http.StatusNotFound)
+		return
+	}
+
+	files := submissionFiles(request.Code, nil, services.ClassicChallenge(challenge).SolutionFile)
+	if err := services.ValidateSubmissionFiles(files); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := h.executionService.RunFiles(files, services.ClassicChallenge(challenge))
+	if err := h.interviewService.RecordRun(id, request.ChallengeID, request.Code, result.Passed, startedAt); err != nil {
+		writeInterviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// endInterview locks a session and returns its report
+func (h *InterviewHandler) endInterview(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, err := h.interviewService.EndSession(id); err != nil {
+		writeInterviewError(w, err)
+		return
+	}
+	h.writeReport(w, id)
+}
+
+// getInterviewReport returns the report of an ended session
+func (h *InterviewHandler) getInterviewReport(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.writeReport(w, id)
+}
+
+// writeReport writes the report of an ended session
+func (h *InterviewHandler) writeReport(w http.ResponseWriter, id string) {
+	report, err := h.interviewService.Report(id, h.challengeService.GetChallenges())
+	if err != nil {
+		writeInterviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// newInterviewResponse adds the remaining time to a session
+func newInterviewResponse(session *models.InterviewSession) interviewResponse {
+	remaining := 0
+	if session.Status == models.InterviewActive {
+		remaining = int(time.Until(session.EndsAt).Seconds())
+		if remaining < 0 {
+			remaining = 0
+		}
+	}
+	return interviewResponse{InterviewSession: session, RemainingSeconds: remaining}
+}
+
+// writeInterviewError maps interview service errors to HTTP status codes
+func writeInterviewError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInterviewNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrInterviewEnded), errors.Is(err, services.ErrInterviewActive):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrChallengeNotInSession):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+	"web-ui/internal/utils"
+)
+
+// LiveHandler handles live interviewer/candidate sessions
+type LiveHandler struct {
+	challengeService *services.ChallengeService
+	liveService      *services.LiveService
+}
+
+// NewLiveHandler creates a new live session handler
+func NewLiveHandler(challengeService *services.ChallengeService, liveService *services.LiveService) *LiveHandler {
+	return &LiveHandler{
+		challengeService: challengeService,
+		liveService:      liveService,
+	}
+}
+
+// CreateLiveSession starts a live session and returns the links for the candidate and the interviewer
+func (h *LiveHandler) CreateLiveSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		ChallengeID int    `json:"challengeId"`
+		Candidate   string `json:"candidate"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request data", http.StatusBadRequest)
+		return
+	}
+
+	if _, exists := h.challengeService.GetChallenge(request.ChallengeID); !exists {
+		http.Error(w, "Challenge not found", http.StatusNotFound)
+		return
+	}
+
+	session, err := h.liveService.CreateSession(request.ChallengeID, request.Candidate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":               session.ID,
+		"challengeId":      session.ChallengeID,
+		"candidate":        session.Candidate,
+		"candidateToken":   session.CandidateToken,
+		"interviewerToken": session.InterviewerToken,
+		"candidateUrl":     liveSessionURL(session, session.CandidateToken),
+		"interviewerUrl":   liveSessionURL(session, session.InterviewerToken),
+	})
+}
+
+// HandleLiveSession routes /api/live/{id}/ws and /api/live/{id}/transcript
+func (h *LiveHandler) HandleLiveSession(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/live/"), "/"), "/")
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch parts[1] {
+	case "ws":
+		h.serveWebSocket(w, r, parts[0])
+	case "transcript":
+		h.getTranscript(w, r, parts[0])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveWebSocket relays messages between a client and its session until either side disconnects
+func (h *LiveHandler) serveWebSocket(w http.ResponseWriter, r *http.Request, id string) {
+	client, err := h.liveService.Join(id, r.URL.Query().Get("token"), r.URL.Query().Get("name"))
+	if err != nil {
+		writeLiveError(w, err)
+		return
+	}
+
+	conn, err := utils.UpgradeWebSocket(w, r)
+	if err != nil {
+		h.liveService.Leave(client)
+		return
+	}
+
+	go func() {
+		for message := range client.Send {
+			if err := conn.WriteMessage(utils.TextMessage, message); err != nil {
+				break
+			}
+		}
+		conn.Close()
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		h.liveService.HandleMessage(client, data)
+	}
+	h.liveService.Leave(client)
+}
+
+// getTranscript returns the recorded events of a session to its interviewers
+func (h *LiveHandler) getTranscript(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	role, err := h.liveService.Role(id, r.URL.Query().Get("token"))
+	if err != nil {
+		writeLiveError(w, err)
+		return
+	}
+	if role != models.LiveInterviewer {
+		http.Error(w, "The transcript is only available to interviewers", http.StatusForbidden)
+		return
+	}
+
+	events, err := h.liveService.Transcript(id)
+	if err != nil {
+		writeLiveError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// liveSessionURL returns the challenge page link that joins a session with a token
+func liveSessionURL(session *models.LiveSession, token string) string {
+	return fmt.Sprintf("/challenge/%d?live=%s&token=%s", session.ChallengeID, session.ID, token)
+}
+
+// writeLiveError maps live session service errors to HTTP status codes
+func writeLiveError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrLiveSessionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrLiveInvalidToken):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
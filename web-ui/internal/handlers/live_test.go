@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+	"web-ui/internal/utils"
+)
+
+// liveTestClient is a WebSocket client that mirrors the session code from the messages it receives
+type liveTestClient struct {
+	t       *testing.T
+	conn    *utils.WebSocketConn
+	code    []uint16
+	version int
+}
+
+func newLiveTestServer(t *testing.T, dir string) (*httptest.Server, *services.LiveService) {
+	t.Helper()
+	liveService := services.NewLiveService(dir)
+	if err := liveService.LoadSessions(); err != nil {
+		t.Fatalf("LoadSessions: %v", err)
+	}
+	handler := NewLiveHandler(services.NewChallengeService(), liveService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/live/", handler.HandleLiveSession)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, liveService
+}
+
+func dialLive(t *testing.T, server *httptest.Server, session *models.LiveSession, token string) *liveTestClient {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/live/" + session.ID + "/ws?token=" + token
+	conn, err := utils.DialWebSocket(url)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	client := &liveTestClient{t: t, conn: conn}
+	client.expect("state")
+	return client
+}
+
+// next returns the next message, applying states and deltas to the mirrored code
+func (c *liveTestClient) next() map[string]json.RawMessage {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := c.conn.ReadMessage()
+	if err != nil {
+		c.t.Fatalf("read: %v", err)
+	}
+
+	var message map[string]json.RawMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		c.t.Fatalf("invalid message %s: %v", data, err)
+	}
+
+	switch messageType(message) {
+	case "state":
+		var state struct {
+			Code    string `json:"code"`
+			Version int    `json:"version"`
+		}
+		json.Unmarshal(data, &state)
+		c.code = utf16.Encode([]rune(state.Code))
+		c.version = state.Version
+	case "delta":
+		var event models.LiveEvent
+		json.Unmarshal(data, &event)
+		d := event.Delta
+		text := utf16.Encode([]rune(d.Text))
+		c.code = append(append(append([]uint16{}, c.code[:d.From]...), text...), c.code[d.To:]...)
+		c.version = event.Version
+	case "ack":
+		json.Unmarshal(message["version"], &c.version)
+	}
+	return message
+}
+
+// expect skips presence updates until a message of the given type arrives
+func (c *liveTestClient) expect(want string) map[string]json.RawMessage {
+	c.t.Helper()
+	for {
+		message := c.next()
+		got := messageType(message)
+		if got == want {
+			return message
+		}
+		if got != "presence" {
+			c.t.Fatalf("expected %q message, got %q", want, got)
+		}
+	}
+}
+
+// edit applies a delta locally and sends it against the client's version
+func (c *liveTestClient) edit(from, to int, text string) {
+	c.t.Helper()
+	c.sendDelta(c.version, from, to, text)
+	encoded := utf16.Encode([]rune(text))
+	c.code = append(append(append([]uint16{}, c.code[:from]...), encoded...), c.code[to:]...)
+}
+
+func (c *liveTestClient) sendDelta(baseVersion, from, to int, text string) {
+	c.t.Helper()
+	c.send(map[string]interface{}{
+		"type":        "delta",
+		"baseVersion": baseVersion,
+		"delta":       models.CodeDelta{From: from, To: to, Text: text},
+	})
+}
+
+func (c *liveTestClient) send(message interface{}) {
+	c.t.Helper()
+	data, _ := json.Marshal(message)
+	if err := c.conn.WriteMessage(utils.TextMessage, data); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+}
+
+func (c *liveTestClient) text() string {
+	return string(utf16.Decode(c.code))
+}
+
+func messageType(message map[string]json.RawMessage) string {
+	var t string
+	json.Unmarshal(message["type"], &t)
+	return t
+}
+
+func TestLiveSessionBroadcastsEditsAndRuns(t *testing.T) {
+	server, liveService := newLiveTestServer(t, t.TempDir())
+	session, err := liveService.CreateSession(1, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	candidate := dialLive(t, server, session, session.CandidateToken)
+	first := dialLive(t, server, session, session.InterviewerToken)
+	second := dialLive(t, server, session, session.InterviewerToken)
+
+	candidate.edit(0, 0, "package main\n")
+	candidate.expect("ack")
+	candidate.edit(13, 13, "func Sum() {} // π\n")
+	candidate.expect("ack")
+	candidate.edit(5, 7, "X")
+	candidate.expect("ack")
+
+	for _, interviewer := range []*liveTestClient{first, second} {
+		for i := 0; i < 3; i++ {
+			interviewer.expect("delta")
+		}
+		if interviewer.text() != candidate.text() {
+			t.Errorf("interviewer code %q, candidate code %q", interviewer.text(), candidate.text())
+		}
+		if interviewer.version != 3 {
+			t.Errorf("interviewer version = %d, want 3", interviewer.version)
+		}
+	}
+
+	candidate.send(map[string]interface{}{"type": "run", "result": map[string]interface{}{"passed": true}})
+	for _, client := range []*liveTestClient{candidate, first, second} {
+		message := client.expect("run")
+		if !strings.Contains(string(message["result"]), `"passed":true`) {
+			t.Errorf("run result = %s", message["result"])
+		}
+	}
+
+	// Interviewers cannot edit
+	first.sendDelta(first.version, 0, 0, "x")
+	first.expect("error")
+}
+
+func TestLiveSessionConcurrentEditsConverge(t *testing.T) {
+	server, liveService := newLiveTestServer(t, t.TempDir())
+	session, err := liveService.CreateSession(1, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tab1 := dialLive(t, server, session, session.CandidateToken)
+	tab2 := dialLive(t, server, session, session.CandidateToken)
+	interviewer := dialLive(t, server, session, session.InterviewerToken)
+
+	tab1.edit(0, 0, "hello world")
+	tab1.expect("ack")
+	tab2.expect("delta")
+	interviewer.expect("delta")
+
+	// Both tabs edit version 1 without seeing each other's change
+	base := tab1.version
+	tab1.edit(0, 5, "Hi")
+	tab1.expect("ack")
+	tab2.sendDelta(base, 6, 11, "there")
+	tab2.expect("delta")
+	tab2.expect("ack")
+	// tab2's edit was transformed, so it is resynchronized
+	tab2.expect("state")
+	tab1.expect("delta")
+	interviewer.expect("delta")
+	interviewer.expect("delta")
+
+	want := "Hi there"
+	for name, client := range map[string]*liveTestClient{"tab1": tab1, "tab2": tab2, "interviewer": interviewer} {
+		if client.text() != want {
+			t.Errorf("%s code = %q, want %q", name, client.text(), want)
+		}
+	}
+
+	// Overlapping edits resolve to the last writer, whose text replaces both ranges
+	base = tab1.version
+	tab1.edit(0, 2, "Howdy")
+	tab1.expect("ack")
+	tab2.expect("delta")
+	tab2.sendDelta(base, 1, 5, "y")
+	tab2.expect("ack")
+	tab2.expect("state")
+	tab1.expect("delta")
+	interviewer.expect("delta")
+	interviewer.expect("delta")
+
+	want = "yere"
+	for name, client := range map[string]*liveTestClient{"tab1": tab1, "tab2": tab2, "interviewer": interviewer} {
+		if client.text() != want {
+			t.Errorf("%s code = %q, want %q", name, client.text(), want)
+		}
+	}
+}
+
+func TestLiveSessionNotesAreInterviewerOnly(t *testing.T) {
+	dir := t.TempDir()
+	server, liveService := newLiveTestServer(t, dir)
+	session, err := liveService.CreateSession(1, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	candidate := dialLive(t, server, session, session.CandidateToken)
+	first := dialLive(t, server, session, session.InterviewerToken)
+	second := dialLive(t, server, session, session.InterviewerToken)
+
+	candidate.send(map[string]interface{}{"type": "note", "text": "I am great"})
+	candidate.expect("error")
+
+	first.send(map[string]interface{}{"type": "note", "text": "Good use of maps"})
+	first.expect("note")
+	second.expect("note")
+
+	candidate.edit(0, 0, "package main")
+	candidate.expect("ack")
+	// The candidate's next message is its ack, not the note
+	first.expect("delta")
+
+	// The transcript survives a restart
+	restarted := services.NewLiveService(dir)
+	if err := restarted.LoadSessions(); err != nil {
+		t.Fatal(err)
+	}
+	events, err := restarted.Transcript(session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notes, deltas int
+	for _, event := range events {
+		switch event.Type {
+		case models.LiveEventNote:
+			notes++
+		case models.LiveEventDelta:
+			deltas++
+		}
+	}
+	if notes != 1 || deltas != 1 {
+		t.Errorf("transcript has %d notes and %d deltas, want 1 and 1", notes, deltas)
+	}
+
+	rejoined, err := restarted.Join(session.ID, session.InterviewerToken, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state struct {
+		Code  string             `json:"code"`
+		Notes []models.LiveEvent `json:"notes"`
+	}
+	json.Unmarshal(<-rejoined.Send, &state)
+	if state.Code != "package main" || len(state.Notes) != 1 {
+		t.Errorf("restored state = %+v", state)
+	}
+
+	if _, err := restarted.Join(session.ID, "wrong", ""); err != services.ErrLiveInvalidToken {
+		t.Errorf("Join with a wrong token = %v, want ErrLiveInvalidToken", err)
+	}
+}
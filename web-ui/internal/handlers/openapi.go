@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// openAPIPathParam matches the {name} path values of a route pattern
+var openAPIPathParam = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// openAPIDocument generates an OpenAPI 3.0 document describing the routes, with schemas
+// reflected from the request and response types
+func openAPIDocument(routes []v1Route) map[string]interface{} {
+	schemas := &openAPISchemas{components: map[string]interface{}{}, names: map[reflect.Type]string{}}
+	errorSchema := schemas.schema(reflect.TypeOf(ErrorEnvelope{}))
+
+	paths := map[string]interface{}{}
+	for _, route := range routes {
+		operations, _ := paths[route.Pattern].(map[string]interface{})
+		if operations == nil {
+			operations = map[string]interface{}{}
+			paths[route.Pattern] = operations
+		}
+
+		parameters := []interface{}{}
+		for _, match := range openAPIPathParam.FindAllStringSubmatch(route.Pattern, -1) {
+			parameters = append(parameters, map[string]interface{}{
+				"name": match[1], "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"},
+			})
+		}
+		for _, name := range route.Query {
+			parameters = append(parameters, map[string]interface{}{
+				"name": name, "in": "query", "required": false, "schema": map[string]interface{}{"type": "string"},
+			})
+		}
+
+		responses := map[string]interface{}{}
+		switch {
+		case route.Status == http.StatusSwitchingProtocols:
+			responses["101"] = map[string]interface{}{"description": http.StatusText(http.StatusSwitchingProtocols)}
+		case route.ResponseType != "":
+			responses["200"] = openAPIContent("OK", route.ResponseType, openAPIBinary)
+		default:
+			responses["200"] = openAPIResponse("OK", schemas.schema(reflect.TypeOf(route.Response)))
+		}
+		for _, status := range route.Errors {
+			responses[strconv.Itoa(status)] = openAPIResponse(http.StatusText(status), errorSchema)
+		}
+
+		operation := map[string]interface{}{
+			"operationId": route.Operation,
+			"summary":     route.Summary,
+			"parameters":  parameters,
+			"responses":   responses,
+		}
+		if route.Request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": schemas.schema(reflect.TypeOf(route.Request))},
+				},
+			}
+		} else if route.RequestType != "" {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  map[string]interface{}{route.RequestType: map[string]interface{}{"schema": openAPIBinary}},
+			}
+		}
+		operations[strings.ToLower(route.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Go Interview Practice API",
+			"version": "1",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas.components},
+	}
+}
+
+// openAPIBinary is the schema of a body that is not JSON
+var openAPIBinary = map[string]interface{}{"type": "string", "format": "binary"}
+
+// openAPIResponse describes a JSON response
+func openAPIResponse(description string, schema map[string]interface{}) map[string]interface{} {
+	return openAPIContent(description, "application/json", schema)
+}
+
+// openAPIContent describes a response of a media type
+func openAPIContent(description, mediaType string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			mediaType: map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+// openAPISchemas reflects Go types into schemas, collecting named structs as components
+type openAPISchemas struct {
+	components map[string]interface{}
+	names      map[reflect.Type]string
+}
+
+// schema returns the schema of a type as encoding/json encodes it
+func (s *openAPISchemas) schema(t reflect.Type) map[string]interface{} {
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := s.schema(t.Elem())
+		if _, isRef := schema["$ref"]; isRef {
+			return map[string]interface{}{"allOf": []interface{}{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		name, exists := s.names[t]
+		if !exists {
+			name = s.componentName(t)
+			s.names[t] = name
+			s.components[name] = map[string]interface{}{} // Placeholder for recursive types
+			s.components[name] = s.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": s.schema(t.Elem()), "nullable": true}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.schema(t.Elem()), "nullable": true}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{} // Interfaces hold any value
+}
+
+// object returns the schema of a struct's JSON fields, flattening embedded structs
+func (s *openAPISchemas) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	s.addFields(t, properties, &required)
+	sort.Strings(required)
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addFields adds the JSON fields of a struct to an object schema
+func (s *openAPISchemas) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			s.addFields(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = s.schema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// componentName names a struct's component, qualifying it with its package if the name is taken
+func (s *openAPISchemas) componentName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := s.components[name]; taken {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+	return name
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+// maxProgressArchiveBytes bounds the size of an uploaded progress archive
+const maxProgressArchiveBytes = 64 << 20
+
+// ProgressHandler handles hint reveals and the export and import of user progress
+type ProgressHandler struct {
+	challengeService   *services.ChallengeService
+	packageService     *services.PackageService
+	userService        *services.UserService
+	progressService    *services.ProgressService
+	achievementService *services.AchievementService
+	leaderboardService *services.LeaderboardService
+}
+
+// NewProgressHandler creates a new progress handler
+func NewProgressHandler(
+	challengeService *services.ChallengeService,
+	packageService *services.PackageService,
+	userService *services.UserService,
+	progressService *services.ProgressService,
+	achievementService *services.AchievementService,
+	leaderboardService *services.LeaderboardService,
+) *ProgressHandler {
+	return &ProgressHandler{
+		challengeService:   challengeService,
+		packageService:     packageService,
+		userService:        userService,
+		progressService:    progressService,
+		achievementService: achievementService,
+		leaderboardService: leaderboardService,
+	}
+}
+
+// HintsRequest is the body of a hint reveal
+type HintsRequest struct {
+	Challenge string `json:"challenge"` // Challenge ref
+	Revealed  int    `json:"revealed"`
+}
+
+// HintsResponse is how many hints of a challenge the user has revealed
+type HintsResponse struct {
+	Challenge string `json:"challenge"`
+	Revealed  int    `json:"revealed"`
+}
+
+// v1Routes serves hint reveals and the export and import of user progress under /api/v1/users
+func (h *ProgressHandler) v1Routes() []v1Route {
+	return []v1Route{
+		{Method: "POST", Pattern: "/api/v1/users/{username}/hints", Summary: "Record how many hints of a challenge a user has revealed",
+			Request: HintsRequest{}, Response: HintsResponse{}, Errors: []int{400, 404}, Operation: "revealHints", handle: h.revealHints},
+		{Method: "GET", Pattern: "/api/v1/users/{username}/export", Summary: "Download a zip archive of a user's progress",
+			ResponseType: "application/zip", Errors: []int{400, 500}, Operation: "exportProgress", handle: h.exportProgress},
+		{Method: "POST", Pattern: "/api/v1/users/import", Summary: "Restore an exported progress archive, uploaded as the multipart field archive or as the body",
+			Query: []string{"username", "overwrite", "dry_run"}, RequestType: "application/zip", Response: models.ImportReport{}, Errors: []int{400, 422, 500},
+			Operation: "importProgress", handle: h.importProgress},
+	}
+}
+
+// revealHints records how many hints of a challenge a user has revealed
+func (h *ProgressHandler) revealHints(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	if !services.ValidUsername(username) {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("Invalid username %q", username))
+		return
+	}
+	var request HintsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "Invalid request data")
+		return
+	}
+	if _, ok := services.LocateChallenge(request.Challenge, h.challengeService.GetChallenges(), h.packageService.GetPackages()); !ok {
+		writeAPIError(w, http.StatusNotFound, ErrorCodeNotFound, fmt.Sprintf("Challenge %s not found", request.Challenge))
+		return
+	}
+
+	revealed, err := h.progressService.RevealHints(username, request.Challenge, request.Revealed)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	writeJSON(w, HintsResponse{Challenge: request.Challenge, Revealed: revealed})
+}
+
+// exportProgress writes a zip archive with a user's solutions, submission history, hint reveals and achievements
+func (h *ProgressHandler) exportProgress(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	if !services.ValidUsername(username) {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("Invalid username %q", username))
+		return
+	}
+
+	challenges := h.challengeService.GetChallenges()
+	packages := h.packageService.GetPackages()
+
+	state, err := h.progressService.State(username)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	solutions, err := services.UserSolutions(username, challenges, packages)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrorCodeInternal, err.Error())
+		return
+	}
+	achievements, err := h.achievementService.Export(username)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrorCodeInternal, err.Error())
+		return
+	}
+
+	var archive bytes.Buffer
+	err = services.WriteProgressArchive(&archive, &models.ProgressExport{
+		Username:     username,
+		ExportedAt:   time.Now(),
+		Solutions:    solutions,
+		History:      state.History,
+		Hints:        state.Hints,
+		Achievements: achievements,
+	})
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrorCodeInternal, fmt.Sprintf("Failed to create archive: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", username+"-progress.zip"))
+	w.Write(archive.Bytes())
+}
+
+// importProgress restores an exported archive. The archive is uploaded as the multipart field "archive",
+// or as the request body with the options in the query string. Solutions for unknown challenges or with
+// disallowed files reject the whole import; solutions that differ from saved ones are reported as conflicts
+// and only replaced when overwrite is set.
+func (h *ProgressHandler) importProgress(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxProgressArchiveBytes)
+
+	var data []byte
+	var err error
+	options := r.URL.Query()
+	if isMultipartRequest(r) {
+		if err := r.ParseMultipartForm(maxProgressArchiveBytes); err != nil {
+			writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("Invalid upload: %v", err))
+			return
+		}
+		file, _, err := r.FormFile("archive")
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "archive file is required")
+			return
+		}
+		defer file.Close()
+		data, err = io.ReadAll(file)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("Failed to read archive: %v", err))
+			return
+		}
+		for name, values := range r.MultipartForm.Value {
+			options[name] = values
+		}
+	} else {
+		data, err = io.ReadAll(r.Body)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "Failed to read request body")
+			return
+		}
+	}
+
+	export, err := services.ReadProgressArchive(data)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+
+	username := options.Get("username")
+	if username == "" {
+		username = export.Username
+	}
+	if !services.ValidUsername(username) {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("Invalid username %q", username))
+		return
+	}
+	report := &models.ImportReport{
+		Username:  username,
+		DryRun:    options.Get("dry_run") == "true",
+		Imported:  []string{},
+		Unchanged: []string{},
+		Conflicts: []models.ImportConflict{},
+		Unknown:   []string{},
+		Invalid:   []string{},
+	}
+	overwrite := options.Get("overwrite") == "true"
+
+	challenges := h.challengeService.GetChallenges()
+	packages := h.packageService.GetPackages()
+
+	// Validate every solution before writing any of them
+	refs := make([]string, 0, len(export.Solutions))
+	for ref := range export.Solutions {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	dirs := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		challenge, ok := services.LocateChallenge(ref, challenges, packages)
+		if !ok {
+			report.Unknown = append(report.Unknown, ref)
+			continue
+		}
+		if err := services.ValidateSubmissionFiles(export.Solutions[ref]); err != nil {
+			report.Invalid = append(report.Invalid, fmt.Sprintf("%s: %v", ref, err))
+			continue
+		}
+		dirs[ref] = challenge.SubmissionDir(username)
+	}
+	for ref := range export.Hints {
+		_, isSolution := export.Solutions[ref]
+		if _, ok := services.LocateChallenge(ref, challenges, packages); !ok && !isSolution {
+			report.Unknown = append(report.Unknown, ref)
+		}
+	}
+	if len(report.Unknown) > 0 || len(report.Invalid) > 0 {
+		sort.Strings(report.Unknown)
+		var problems []string
+		if len(report.Unknown) > 0 {
+			problems = append(problems, "unknown challenges: "+strings.Join(report.Unknown, ", "))
+		}
+		problems = append(problems, report.Invalid...)
+		writeAPIError(w, http.StatusUnprocessableEntity, ErrorCodeInvalidRequest, "Nothing was imported; "+strings.Join(problems, "; "))
+		return
+	}
+
+	// Completions are dated by the archive's history, so an old archive does not rank in recent windows.
+	// Solutions without a passing submission in the history are undated.
+	firstPasses := services.FirstPasses(export.History)
+
+	for _, ref := range refs {
+		files := export.Solutions[ref]
+		existing, err := services.ReadSolutionFiles(dirs[ref])
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, ErrorCodeInternal, err.Error())
+			return
+		}
+		if len(existing) > 0 {
+			differ := services.DiffSolutionFiles(files, existing)
+			if len(differ) == 0 {
+				report.Unchanged = append(report.Unchanged, ref)
+				continue
+			}
+			report.Conflicts = append(report.Conflicts, models.ImportConflict{Ref: ref, Files: differ})
+			if !overwrite {
+				continue
+			}
+		}
+		if !report.DryRun {
+			if err := services.ReplaceSubmissionFiles(dirs[ref], files); err != nil {
+				writeAPIError(w, http.StatusInternalServerError, ErrorCodeInternal, fmt.Sprintf("Failed to write %s: %v", ref, err))
+				return
+			}
+			if !strings.HasPrefix(ref, services.ClassicTrack+"/") {
+				if err := h.leaderboardService.Record(username, ref, firstPasses[ref]); err != nil {
+					log.Printf("Failed to update leaderboard: %v", err)
+				}
+			}
+		}
+		report.Imported = append(report.Imported, ref)
+	}
+
+	report.History, report.Hints, err = h.progressService.Merge(username, export.History, export.Hints, report.DryRun)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if export.Achievements != nil {
+		report.Achievements, err = h.achievementService.Import(username, export.Achievements, report.DryRun)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, err.Error())
+			return
+		}
+	}
+
+	if !report.DryRun {
+		h.userService.RefreshUserAttempts(username, challenges)
+	}
+
+	writeJSON(w, report)
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+func TestRevealHints(t *testing.T) {
+	server, _ := newV1TestServer(t)
+
+	tests := []struct {
+		name     string
+		username string
+		body     string
+		status   int
+		code     string
+		revealed int
+	}{
+		{"reveal", "alice", `{"challenge": "classic/1", "revealed": 2}`, http.StatusOK, "", 2},
+		{"never lowered", "alice", `{"challenge": "classic/1", "revealed": 1}`, http.StatusOK, "", 2},
+		{"invalid username", "bad%20name", `{"challenge": "classic/1", "revealed": 1}`, http.StatusBadRequest, ErrorCodeInvalidRequest, 0},
+		{"unknown challenge", "alice", `{"challenge": "classic/9999", "revealed": 1}`, http.StatusNotFound, ErrorCodeNotFound, 0},
+		{"invalid body", "alice", `{`, http.StatusBadRequest, ErrorCodeInvalidRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+"/api/v1/users/"+tt.username+"/hints", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+
+			if tt.code != "" {
+				var envelope ErrorEnvelope
+				if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil || envelope.Error.Code != tt.code {
+					t.Errorf("envelope = %+v (%v), want code %s", envelope, err, tt.code)
+				}
+				return
+			}
+			var hints HintsResponse
+			if err := json.NewDecoder(resp.Body).Decode(&hints); err != nil {
+				t.Fatal(err)
+			}
+			if hints.Challenge != "classic/1" || hints.Revealed != tt.revealed {
+				t.Errorf("response = %+v, want %d revealed", hints, tt.revealed)
+			}
+		})
+	}
+}
+
+func TestProgressExportImport(t *testing.T) {
+	server, _ := newV1TestServer(t)
+
+	resp, err := http.Post(server.URL+"/api/v1/users/alice/hints", "application/json", strings.NewReader(`{"challenge": "classic/1", "revealed": 1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	resp, err = http.Get(server.URL + "/api/v1/users/alice/export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/zip" {
+		t.Fatalf("export: status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		url := server.URL + "/api/v1/users/import?username=bob"
+		if dryRun {
+			url += "&dry_run=true"
+		}
+		resp, err := http.Post(url, "application/zip", bytes.NewReader(archive))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var report models.ImportReport
+		err = json.NewDecoder(resp.Body).Decode(&report)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || err != nil {
+			t.Fatalf("import: status %d, %v", resp.StatusCode, err)
+		}
+		if report.Username != "bob" || report.DryRun != dryRun || report.Hints != 1 {
+			t.Errorf("import report = %+v, want one hint merged into bob", report)
+		}
+	}
+
+	// The import only wrote once, so importing again changes nothing
+	resp, err = http.Post(server.URL+"/api/v1/users/import?username=bob", "application/zip", bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var report models.ImportReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil || report.Hints != 0 {
+		t.Errorf("second import = %+v (%v), want nothing merged", report, err)
+	}
+
+	resp, err = http.Post(server.URL+"/api/v1/users/import", "application/zip", strings.NewReader("not a zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var envelope ErrorEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil || resp.StatusCode != http.StatusBadRequest || envelope.Error.Code != ErrorCodeInvalidRequest {
+		t.Errorf("invalid archive: status %d, envelope %+v", resp.StatusCode, envelope)
+	}
+}
+
+// postArchive imports a progress archive with the given query string
+func postArchive(t *testing.T, serverURL, query string, export *models.ProgressExport) *http.Response {
+	t.Helper()
+	var archive bytes.Buffer
+	if err := services.WriteProgressArchive(&archive, export); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(serverURL+"/api/v1/users/import?"+query, "application/zip", &archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestProgressImportRejectsUnknownChallenges(t *testing.T) {
+	server, _ := newV1TestServer(t)
+
+	tests := []struct {
+		name      string
+		solutions map[string]models.SubmissionFiles
+		wantMsg   string
+	}{
+		{"unknown challenge", map[string]models.SubmissionFiles{"gin/challenge-99-missing": {"solution.go": "package main"}}, "unknown challenges: gin/challenge-99-missing"},
+		{"disallowed file", map[string]models.SubmissionFiles{"classic/1": {"solution-template.go": "package main", "run.sh": ""}}, "classic/1: file type not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postArchive(t, server.URL, "username=importer", &models.ProgressExport{Username: "importer", Solutions: tt.solutions})
+			var envelope ErrorEnvelope
+			if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != http.StatusUnprocessableEntity || envelope.Error.Status != http.StatusUnprocessableEntity ||
+				envelope.Error.Code != ErrorCodeInvalidRequest || !strings.Contains(envelope.Error.Message, tt.wantMsg) {
+				t.Errorf("status %d, envelope %+v, want 422 mentioning %q", resp.StatusCode, envelope, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestProgressImportOverwriteAndLeaderboard(t *testing.T) {
+	server, _ := newV1TestServer(t)
+	const username = "progress-importer"
+	ref := "gin/challenge-1-basic-routing"
+	submissionDir := filepath.Join("..", "packages", "gin", "challenge-1-basic-routing", "submissions", username)
+	t.Cleanup(func() { os.RemoveAll(submissionDir) })
+
+	// A solution saved earlier with a file the archive does not have
+	if err := services.WriteSubmissionFiles(submissionDir, models.SubmissionFiles{"solution.go": "package main // old", "handlers/old.go": "package handlers"}); err != nil {
+		t.Fatal(err)
+	}
+
+	solvedAt := time.Now().AddDate(-1, 0, 0).UTC().Truncate(time.Second)
+	export := &models.ProgressExport{
+		Username:  username,
+		Solutions: map[string]models.SubmissionFiles{ref: {"solution.go": "package main // new"}},
+		History: []models.SubmissionRecord{
+			{Ref: ref, Passed: false, SubmittedAt: solvedAt.Add(-time.Hour)},
+			{Ref: ref, Passed: true, SubmittedAt: solvedAt},
+			{Ref: ref, Passed: true, SubmittedAt: solvedAt.Add(time.Hour)},
+		},
+	}
+	resp := postArchive(t, server.URL, "overwrite=true", export)
+	var report models.ImportReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil || resp.StatusCode != http.StatusOK || len(report.Imported) != 1 {
+		t.Fatalf("import: status %d, report %+v (%v)", resp.StatusCode, report, err)
+	}
+
+	files, err := services.ReadSolutionFiles(submissionDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(files, export.Solutions[ref]) {
+		t.Errorf("saved files = %v, want only the imported solution", files)
+	}
+
+	// The completion is dated by the archive's first pass, so it only ranks all-time
+	for window, wantRanked := range map[string]bool{"week": false, "month": false, "all": true} {
+		resp, err := http.Get(server.URL + "/api/v1/leaderboard?track=gin&window=" + window + "&username=" + username)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var page models.LeaderboardPage
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (page.Me != nil) != wantRanked {
+			t.Errorf("%s window: me = %+v, want ranked %v", window, page.Me, wantRanked)
+		} else if page.Me != nil && !page.Me.LastCompletedAt.Equal(solvedAt) {
+			t.Errorf("%s window: completed at %v, want %v", window, page.Me.LastCompletedAt, solvedAt)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+func TestV1RunReportsQuality(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	server, v1 := newV1TestServer(t)
+	spec := fetchSpec(t, server)
+
+	// Any passing solution of classic/1 will do
+	challenge, err := v1.api.resolveChallenge("classic/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := v1.api.scoreboardService.Scoreboard("classic/1")
+	var code []byte
+	for _, entry := range entries {
+		if code, err = os.ReadFile(filepath.Join(challenge.SubmissionDir(entry.Username), "solution-template.go")); err == nil {
+			break
+		}
+	}
+	if code == nil {
+		t.Skip("classic/1 has no saved solution on its scoreboard")
+	}
+
+	request, _ := json.Marshal(RunRequest{Code: string(code)})
+	status, body := doV1Request(t, server, "POST", "/api/v1/challenges/1/run", string(request))
+	if status != 200 {
+		t.Fatalf("status = %d: %s", status, body)
+	}
+	checkResponseContract(t, spec, "POST", "/api/v1/challenges/{id}/run", status, body)
+
+	var response RunResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatal(err)
+	}
+	if !response.Passed {
+		t.Skipf("the saved solution does not pass: %s", response.Output)
+	}
+	if response.Quality == nil {
+		t.Fatal("a passing run has no quality score")
+	}
+	if response.Quality.Score <= 0 || response.Quality.Score > 100 {
+		t.Errorf("score = %v, want (0, 100]", response.Quality.Score)
+	}
+
+	totalWeight := 0.0
+	for _, component := range response.Quality.Components {
+		totalWeight += component.Weight
+		if component.Name == models.QualityAllocations && (component.Measured || component.Weight != 0) {
+			t.Errorf("allocations were scored without a bench run: %+v", component)
+		}
+	}
+	if len(response.Quality.Components) != 5 || totalWeight < 0.99 || totalWeight > 1.01 {
+		t.Errorf("components = %+v, weights sum to %v", response.Quality.Components, totalWeight)
+	}
+}
+
+func TestLeaderboardQualityOrder(t *testing.T) {
+	_, v1 := newV1TestServer(t)
+	leaderboard := v1.api.leaderboardService
+
+	now := time.Now()
+	for _, score := range []struct {
+		username string
+		ref      string
+		score    float64
+	}{
+		{"quality-alice", "classic/1", 70},
+		{"quality-alice", "classic/1", 90}, // The best score per challenge counts
+		{"quality-alice", "classic/2", 80},
+		{"quality-bob", "classic/1", 99},
+	} {
+		if err := leaderboard.RecordQuality(score.username, score.ref, score.score, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := leaderboard.RecordQuality("quality-alice", "classic/9999", 50, now); err == nil {
+		t.Error("RecordQuality accepted an unknown challenge")
+	}
+
+	page, err := leaderboard.Query(services.LeaderboardQuery{Order: services.LeaderboardOrderQuality, Username: "quality-alice", Limit: 200})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Order != services.LeaderboardOrderQuality || page.Me == nil {
+		t.Fatalf("page = %+v", page)
+	}
+	if page.Me.QualityScore != 85 || page.Me.ScoredCount != 2 {
+		t.Errorf("alice = %+v, want a mean of 85 over 2 challenges", *page.Me)
+	}
+	ranks := map[string]int{}
+	for _, entry := range page.Entries {
+		ranks[entry.Username] = entry.Rank
+	}
+	if ranks["quality-bob"] == 0 || ranks["quality-bob"] > ranks["quality-alice"] {
+		t.Errorf("ranks = %v, want bob above alice", ranks)
+	}
+
+	// The completions order is unchanged by quality scores
+	page, err = leaderboard.Query(services.LeaderboardQuery{Username: "quality-alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Order != services.LeaderboardOrderCompletions || page.Me != nil {
+		t.Errorf("completions order = %+v", page)
+	}
+}
@@ -0,0 +1,573 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"web-ui/internal/models"
+	"web-ui/internal/services"
+)
+
+// Error codes of the /api/v1 error envelope
+const (
+	ErrorCodeInvalidRequest       = "invalid_request"
+	ErrorCodeNotFound             = "not_found"
+	ErrorCodeMethodNotAllowed     = "method_not_allowed"
+	ErrorCodePrerequisitesMissing = "prerequisites_missing"
+	ErrorCodeRateLimited          = "rate_limited"
+	ErrorCodeUnavailable          = "unavailable"
+)
+
+// APIError describes a failed /api/v1 request
+type APIError struct {
+	Code    string `json:"code"` // Stable machine-readable code, e.g. "not_found"
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+// ErrorEnvelope is the body of every /api/v1 error response
+type ErrorEnvelope struct {
+	Error APIError `json:"error"`
+}
+
+// ChallengeSummary describes a classic or package challenge in listings
+type ChallengeSummary struct {
+	Ref        string   `json:"ref"`   // "classic/7" or "gin/challenge-2-middleware"
+	Track      string   `json:"track"` // "classic" or the package name
+	ID         string   `json:"id"`    // "7" or "challenge-2-middleware"
+	Title      string   `json:"title"`
+	Difficulty string   `json:"difficulty"`
+	Tags       []string `json:"tags"`
+}
+
+// ChallengeDetail is a challenge with everything needed to attempt it
+type ChallengeDetail struct {
+	ChallengeSummary
+	Description       string `json:"description"`
+	Template          string `json:"template"`
+	TestFile          string `json:"testFile"`
+	LearningMaterials string `json:"learningMaterials"`
+	Hints             string `json:"hints"`
+}
+
+// PackageSummary describes a package track and its challenges in learning-path order
+type PackageSummary struct {
+	Name        string             `json:"name"`
+	DisplayName string             `json:"displayName"`
+	Description string             `json:"description"`
+	Category    string             `json:"category"`
+	Difficulty  string             `json:"difficulty"`
+	Tags        []string           `json:"tags"`
+	Challenges  []ChallengeSummary `json:"challenges"`
+}
+
+// RunRequest is the body of run and submission requests for either track
+type RunRequest struct {
+	Code     string                 `json:"code,omitempty"`  // Single-file solution
+	Files    models.SubmissionFiles `json:"files,omitempty"` // Multi-file solution, keyed by relative path
+	Mode     string                 `json:"mode,omitempty"`  // "", "race", "cover", "short", "bench" or "fuzz"
+	Count    int                    `json:"count,omitempty"` // Benchmark samples for bench runs
+	Username string                 `json:"username,omitempty"`
+}
+
+// RunResponse is the result of a run or submission for either track
+type RunResponse struct {
+	Ref            string                       `json:"ref"`
+	Passed         bool                         `json:"passed"`
+	Output         string                       `json:"output"`
+	ExecutionMs    int64                        `json:"executionMs"`
+	Diagnostics    []models.Diagnostic          `json:"diagnostics"`
+	Mode           string                       `json:"mode"`
+	Race           bool                         `json:"race"`
+	RaceDetected   bool                         `json:"raceDetected"`
+	TestsPassed    int                          `json:"testsPassed"`
+	TestsTotal     int                          `json:"testsTotal"`
+	HiddenTests    []models.TestOutcome         `json:"hiddenTests,omitempty"`
+	IncludesHidden bool                         `json:"includesHidden"`
+	Coverage       *services.CoverageReport     `json:"coverage,omitempty"`
+	Benchmark      *services.BenchmarkReport    `json:"benchmark,omitempty"`
+	Fuzz           *services.FuzzReport         `json:"fuzz,omitempty"`
+	Differential   *services.DifferentialReport `json:"differential,omitempty"`
+	Achievements   []models.Achievement         `json:"achievements,omitempty"` // Only set on submissions
+	Quality        *models.QualityReport        `json:"quality,omitempty"`      // Only set for passing runs
+}
+
+// v1Route is a /api/v1 endpoint with what the OpenAPI document says about it
+type v1Route struct {
+	Method    string
+	Pattern   string // Path pattern, e.g. "/api/v1/challenges/{id}"
+	Summary   string
+	Operation string      // OpenAPI operationId
+	Query     []string    // Query parameters
+	Request   interface{} // Request body type, nil if there is none
+	Response  interface{} // Type of the 200 response body
+	Errors    []int       // Statuses of the error envelopes the route returns
+
+	RequestType  string // Media type of a request body that is not JSON, e.g. "application/zip"
+	ResponseType string // Media type of a response body that is not JSON
+	Status       int    // Status of a successful response if it is not 200, e.g. 101 for WebSocket upgrades
+
+	handle http.HandlerFunc
+}
+
+// V1Routes is implemented by handlers that serve part of /api/v1 next to the V1Handler's own routes
+type V1Routes interface {
+	v1Routes() []v1Route
+}
+
+// V1Handler serves the versioned /api/v1 API over the same services as the classic endpoints
+type V1Handler struct {
+	api    *APIHandler
+	mux    *http.ServeMux
+	routes []v1Route
+}
+
+// NewV1Handler creates the /api/v1 router, also serving the routes of the given handlers
+func NewV1Handler(api *APIHandler, handlers ...V1Routes) *V1Handler {
+	h := &V1Handler{api: api, mux: http.NewServeMux()}
+	h.routes = []v1Route{
+		{Method: "GET", Pattern: "/api/v1/challenges", Summary: "List classic and package challenges",
+			Query: []string{"track", "difficulty"}, Response: []ChallengeSummary{}, Errors: []int{400}, Operation: "listChallenges", handle: h.listChallenges},
+		{Method: "GET", Pattern: "/api/v1/challenges/{id}", Summary: "Get a classic challenge",
+			Response: ChallengeDetail{}, Errors: []int{400, 404}, Operation: "getChallenge", handle: h.classic(h.getChallenge)},
+		{Method: "POST", Pattern: "/api/v1/challenges/{id}/run", Summary: "Run a solution against a classic challenge's tests",
+			Request: RunRequest{}, Response: RunResponse{}, Errors: []int{400, 403, 404}, Operation: "runChallenge", handle: h.classic(h.runChallenge)},
+		{Method: "POST", Pattern: "/api/v1/challenges/{id}/submissions", Summary: "Submit a solution to a classic challenge",
+			Request: RunRequest{}, Response: RunResponse{}, Errors: []int{400, 403, 404}, Operation: "submitChallenge", handle: h.classic(h.submitChallenge)},
+		{Method: "GET", Pattern: "/api/v1/challenges/{id}/scoreboard", Summary: "Get a classic challenge's scoreboard",
+			Response: []models.ScoreboardEntry{}, Errors: []int{400, 404}, Operation: "getScoreboard", handle: h.classic(h.getScoreboard)},
+		{Method: "GET", Pattern: "/api/v1/challenges/{id}/solutions", Summary: "List other users' solutions to a classic challenge the user has passed",
+			Query: []string{"username"}, Response: models.SolutionGallery{}, Errors: []int{400, 403, 404}, Operation: "listSolutions", handle: h.classic(h.listSolutions)},
+		{Method: "GET", Pattern: "/api/v1/challenges/{id}/solutions/compare", Summary: "Diff two solutions to a classic challenge by function",
+			Query: []string{"username", "left", "right"}, Response: models.SolutionComparison{}, Errors: []int{400, 403, 404}, Operation: "compareSolutions", handle: h.classic(h.compareSolutions)},
+		{Method: "GET", Pattern: "/api/v1/packages", Summary: "List package tracks",
+			Response: []PackageSummary{}, Operation: "listPackages", handle: h.listPackages},
+		{Method: "GET", Pattern: "/api/v1/packages/{package}", Summary: "Get a package track",
+			Response: PackageSummary{}, Errors: []int{404}, Operation: "getPackage", handle: h.getPackage},
+		{Method: "GET", Pattern: "/api/v1/packages/{package}/challenges/{challenge}", Summary: "Get a package challenge",
+			Response: ChallengeDetail{}, Errors: []int{404}, Operation: "getPackageChallenge", handle: h.inPackage(h.getChallenge)},
+		{Method: "POST", Pattern: "/api/v1/packages/{package}/challenges/{challenge}/run", Summary: "Run a solution against a package challenge's tests",
+			Request: RunRequest{}, Response: RunResponse{}, Errors: []int{400, 403, 404}, Operation: "runPackageChallenge", handle: h.inPackage(h.runChallenge)},
+		{Method: "POST", Pattern: "/api/v1/packages/{package}/challenges/{challenge}/submissions", Summary: "Submit a solution to a package challenge",
+			Request: RunRequest{}, Response: RunResponse{}, Errors: []int{400, 403, 404}, Operation: "submitPackageChallenge", handle: h.inPackage(h.submitChallenge)},
+		{Method: "GET", Pattern: "/api/v1/packages/{package}/challenges/{challenge}/scoreboard", Summary: "Get a package challenge's scoreboard",
+			Response: []models.ScoreboardEntry{}, Errors: []int{404}, Operation: "getPackageScoreboard", handle: h.inPackage(h.getScoreboard)},
+		{Method: "GET", Pattern: "/api/v1/packages/{package}/challenges/{challenge}/solutions", Summary: "List other users' solutions to a package challenge the user has passed",
+			Query: []string{"username"}, Response: models.SolutionGallery{}, Errors: []int{400, 403, 404}, Operation: "listPackageSolutions", handle: h.inPackage(h.listSolutions)},
+		{Method: "GET", Pattern: "/api/v1/packages/{package}/challenges/{challenge}/solutions/compare", Summary: "Diff two solutions to a package challenge by function",
+			Query: []string{"username", "left", "right"}, Response: models.SolutionComparison{}, Errors: []int{400, 403, 404}, Operation: "comparePackageSolutions", handle: h.inPackage(h.compareSolutions)},
+		{Method: "GET", Pattern: "/api/v1/leaderboard", Summary: "Get a page of the leaderboard",
+			Query: []string{"track", "window", "difficulty", "order", "page", "limit", "username"}, Response: models.LeaderboardPage{}, Errors: []int{400}, Operation: "getLeaderboard", handle: h.getLeaderboard},
+		{Method: "GET", Pattern: "/api/v1/users/{username}/achievements", Summary: "Get a user's achievements and package progress",
+			Response: AchievementsResponse{}, Errors: []int{400}, Operation: "getAchievements", handle: h.getAchievements},
+		{Method: "GET", Pattern: "/api/v1/users/{username}/path", Summary: "Get a user's learning plan",
+			Response: models.LearningPlan{}, Errors: []int{400}, Operation: "getLearningPath", handle: h.getLearningPath},
+		{Method: "GET", Pattern: "/api/v1/openapi.json", Summary: "Get this OpenAPI document",
+			Response: map[string]interface{}{}, Operation: "getOpenAPI", handle: h.getOpenAPI},
+	}
+	for _, handler := range handlers {
+		h.routes = append(h.routes, handler.v1Routes()...)
+	}
+	for _, route := range h.routes {
+		h.mux.HandleFunc(route.Method+" "+route.Pattern, route.handle)
+	}
+	return h
+}
+
+// ServeHTTP routes a request, answering unknown paths and methods with an error envelope
+func (h *V1Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, pattern := h.mux.Handler(r); pattern == "" {
+		w = &envelopeWriter{ResponseWriter: w}
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// envelopeWriter replaces the plain-text 404 and 405 responses of the mux with error envelopes
+type envelopeWriter struct {
+	http.ResponseWriter
+	replaced bool
+}
+
+func (w *envelopeWriter) WriteHeader(status int) {
+	code := ErrorCodeNotFound
+	if status == http.StatusMethodNotAllowed {
+		code = ErrorCodeMethodNotAllowed
+	}
+	w.replaced = true
+	writeAPIError(w.ResponseWriter, status, code, http.StatusText(status))
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	if w.replaced {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// writeAPIError writes an error envelope
+func writeAPIError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Del("X-Content-Type-Options")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorEnvelope{Error: APIError{Code: code, Message: message, Status: status}})
+}
+
+// writeJSON writes a successful response
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+// listChallenges returns every challenge, classic first, optionally filtered by track and difficulty
+func (h *V1Handler) listChallenges(w http.ResponseWriter, r *http.Request) {
+	track := r.URL.Query().Get("track")
+	difficulty := r.URL.Query().Get("difficulty")
+
+	packages := h.api.packageService.GetPackages()
+	if _, exists := packages[track]; track != "" && track != services.ClassicTrack && !exists {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("Unknown track %q", track))
+		return
+	}
+
+	summaries := []ChallengeSummary{}
+	if track == "" || track == services.ClassicTrack {
+		challenges := h.api.challengeService.GetChallenges()
+		ids := make([]int, 0, len(challenges))
+		for id := range challenges {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			summaries = append(summaries, trackSummary(services.ClassicChallenge(challenges[id])))
+		}
+	}
+
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		if track == "" || track == name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		summaries = append(summaries, packageChallengeSummaries(packages[name])...)
+	}
+
+	if difficulty != "" {
+		filtered := []ChallengeSummary{}
+		for _, summary := range summaries {
+			if strings.EqualFold(summary.Difficulty, difficulty) {
+				filtered = append(filtered, summary)
+			}
+		}
+		summaries = filtered
+	}
+	writeJSON(w, summaries)
+}
+
+// getChallenge returns a challenge
+func (h *V1Handler) getChallenge(w http.ResponseWriter, r *http.Request, challenge *models.TrackChallenge) {
+	writeJSON(w, ChallengeDetail{
+		ChallengeSummary:  trackSummary(challenge),
+		Description:       challenge.Description,
+		Template:          challenge.Template,
+		TestFile:          challenge.TestFile,
+		LearningMaterials: challenge.LearningMaterials,
+		Hints:             challenge.Hints,
+	})
+}
+
+// runChallenge runs a solution against a challenge's visible tests
+func (h *V1Handler) runChallenge(w http.ResponseWriter, r *http.Request, challenge *models.TrackChallenge) {
+	request, files, ok := readRunRequest(w, r, challenge.SolutionFile)
+	if !ok {
+		return
+	}
+
+	result, err := h.api.runChallenge(challenge, request.Username, files, services.RunOptions{Mode: request.Mode, Count: request.Count})
+	if err != nil {
+		writePrerequisitesError(w, err)
+		return
+	}
+	writeJSON(w, h.runResponse(challenge.Ref, result, nil))
+}
+
+// submitChallenge judges a solution to a challenge, hidden tests included
+func (h *V1Handler) submitChallenge(w http.ResponseWriter, r *http.Request, challenge *models.TrackChallenge) {
+	request, files, ok := readRunRequest(w, r, challenge.SolutionFile)
+	if !ok {
+		return
+	}
+
+	result, earned, err := h.api.submitChallenge(challenge, request.Username, files, request.Mode, time.Now())
+	if err != nil {
+		writePrerequisitesError(w, err)
+		return
+	}
+	writeJSON(w, h.runResponse(challenge.Ref, result, earned))
+}
+
+// writePrerequisitesError writes the error envelope of a run or submission refused by its challenge's prerequisites
+func writePrerequisitesError(w http.ResponseWriter, err error) {
+	writeAPIError(w, http.StatusForbidden, ErrorCodePrerequisitesMissing, err.Error())
+}
+
+// getScoreboard returns a challenge's scoreboard
+func (h *V1Handler) getScoreboard(w http.ResponseWriter, r *http.Request, challenge *models.TrackChallenge) {
+	scoreboard, exists := h.api.scoreboardService.Scoreboard(challenge.Ref)
+	if !exists {
+		scoreboard = []models.ScoreboardEntry{}
+	}
+	writeJSON(w, scoreboard)
+}
+
+// listPackages returns every package track
+func (h *V1Handler) listPackages(w http.ResponseWriter, r *http.Request) {
+	packages := h.api.packageService.GetPackages()
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	summaries := make([]PackageSummary, 0, len(names))
+	for _, name := range names {
+		summaries = append(summaries, packageSummary(packages[name]))
+	}
+	writeJSON(w, summaries)
+}
+
+// getPackage returns a package track
+func (h *V1Handler) getPackage(w http.ResponseWriter, r *http.Request) {
+	pkg, exists := h.api.packageService.GetPackages()[r.PathValue("package")]
+	if !exists {
+		writeAPIError(w, http.StatusNotFound, ErrorCodeNotFound, fmt.Sprintf("Package %q not found", r.PathValue("package")))
+		return
+	}
+	writeJSON(w, packageSummary(pkg))
+}
+
+// getLeaderboard returns a page of the leaderboard
+func (h *V1Handler) getLeaderboard(w http.ResponseWriter, r *http.Request) {
+	request, err := leaderboardQuery(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	page, err := h.api.leaderboardService.Query(request)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	writeJSON(w, page)
+}
+
+// getAchievements returns a user's achievements and package progress
+func (h *V1Handler) getAchievements(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	if !services.ValidUsername(username) {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("Invalid username %q", username))
+		return
+	}
+	response, err := h.api.userAchievements(username)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	writeJSON(w, response)
+}
+
+// getLearningPath returns a user's learning plan
+func (h *V1Handler) getLearningPath(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	if !services.ValidUsername(username) {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("Invalid username %q", username))
+		return
+	}
+	challenges := h.api.challengeService.GetChallenges()
+	packages := h.api.packageService.GetPackages()
+	completed := h.api.userService.GetCompletedRefs(username, challenges, packages)
+	writeJSON(w, h.api.pathService.Plan(username, challenges, packages, completed))
+}
+
+// getOpenAPI returns the OpenAPI document generated from the routes
+func (h *V1Handler) getOpenAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, openAPIDocument(h.routes))
+}
+
+// challengeHandler handles a request for the challenge it was routed to
+type challengeHandler func(w http.ResponseWriter, r *http.Request, challenge *models.TrackChallenge)
+
+// classic looks up the classic challenge named by the {id} path value before handling a request
+func (h *V1Handler) classic(handle challengeHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "Invalid challenge ID")
+			return
+		}
+		h.resolve(w, r, services.ClassicRef(id), handle)
+	}
+}
+
+// inPackage looks up the package challenge named by the {package} and {challenge} path values before handling a request
+func (h *V1Handler) inPackage(handle challengeHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.resolve(w, r, services.PackageRef(r.PathValue("package"), r.PathValue("challenge")), handle)
+	}
+}
+
+// resolve handles a request for the challenge behind a ref, writing an error if there is none
+func (h *V1Handler) resolve(w http.ResponseWriter, r *http.Request, ref string, handle challengeHandler) {
+	challenge, err := h.api.resolveChallenge(ref)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, ErrorCodeNotFound, fmt.Sprintf("Challenge %s not found", ref))
+		return
+	}
+	handle(w, r, challenge)
+}
+
+// routedChallenge resolves the challenge named by the {track} and {challenge} path values, writing an error if there is none
+func routedChallenge(w http.ResponseWriter, r *http.Request, challengeService *services.ChallengeService, packageService *services.PackageService) (*models.TrackChallenge, bool) {
+	ref := r.PathValue("track") + "/" + r.PathValue("challenge")
+	challenge, err := services.ResolveChallenge(ref, challengeService.GetChallenges(), packageService)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, ErrorCodeNotFound, fmt.Sprintf("Challenge %s not found", ref))
+		return nil, false
+	}
+	return challenge, true
+}
+
+// routedUsername returns the requesting user, from the query string or cookie, writing an error if it is invalid
+func routedUsername(w http.ResponseWriter, r *http.Request) (string, bool) {
+	username := requestUsername(r)
+	if !services.ValidUsername(username) {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "A valid username is required")
+		return "", false
+	}
+	return username, true
+}
+
+// readRunRequest decodes and validates a run or submission body, writing an error if it is invalid
+func readRunRequest(w http.ResponseWriter, r *http.Request, defaultName string) (RunRequest, models.SubmissionFiles, bool) {
+	var request RunRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return request, nil, false
+	}
+	if request.Code == "" && len(request.Files) == 0 {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "Code or files are required")
+		return request, nil, false
+	}
+	if request.Username != "" && !services.ValidUsername(request.Username) {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("Invalid username %q", request.Username))
+		return request, nil, false
+	}
+	if !services.ValidRunMode(request.Mode) {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "Invalid mode. Must be one of 'race', 'cover', 'short', 'bench' or 'fuzz'")
+		return request, nil, false
+	}
+
+	files := submissionFiles(request.Code, request.Files, defaultName)
+	if err := services.ValidateSubmissionFiles(files); err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, err.Error())
+		return request, nil, false
+	}
+	return request, files, true
+}
+
+// runResponse converts an execution result for either track
+func (h *V1Handler) runResponse(ref string, result services.ExecutionResult, achievements []models.Achievement) RunResponse {
+	testsPassed, testsTotal := h.api.parseTestResults(result.Output)
+	for _, hidden := range result.HiddenTests {
+		testsTotal++
+		if hidden.Passed {
+			testsPassed++
+		}
+	}
+	diagnostics := result.Diagnostics
+	if diagnostics == nil {
+		diagnostics = []models.Diagnostic{}
+	}
+
+	return RunResponse{
+		Ref:            ref,
+		Passed:         result.Passed,
+		Output:         result.Output,
+		ExecutionMs:    result.ExecutionMs,
+		Diagnostics:    diagnostics,
+		Mode:           result.Mode,
+		Race:           result.Race,
+		RaceDetected:   result.RaceDetected,
+		TestsPassed:    testsPassed,
+		TestsTotal:     testsTotal,
+		HiddenTests:    result.HiddenTests,
+		IncludesHidden: result.IncludesHidden,
+		Coverage:       result.Coverage,
+		Benchmark:      result.Benchmark,
+		Fuzz:           result.Fuzz,
+		Differential:   result.Differential,
+		Achievements:   achievements,
+		Quality:        result.Quality,
+	}
+}
+
+// trackSummary describes a challenge of either track
+func trackSummary(challenge *models.TrackChallenge) ChallengeSummary {
+	return ChallengeSummary{
+		Ref:        challenge.Ref,
+		Track:      challenge.Track,
+		ID:         challenge.ID,
+		Title:      challenge.Title,
+		Difficulty: challenge.Difficulty,
+		Tags:       nonNilStrings(challenge.Tags),
+	}
+}
+
+// packageSummary describes a package track
+func packageSummary(pkg *models.Package) PackageSummary {
+	return PackageSummary{
+		Name:        pkg.Name,
+		DisplayName: pkg.DisplayName,
+		Description: pkg.Description,
+		Category:    pkg.Category,
+		Difficulty:  pkg.Difficulty,
+		Tags:        nonNilStrings(pkg.Tags),
+		Challenges:  packageChallengeSummaries(pkg),
+	}
+}
+
+// packageChallengeSummaries describes a package's challenges in learning-path order
+func packageChallengeSummaries(pkg *models.Package) []ChallengeSummary {
+	summaries := []ChallengeSummary{}
+	for _, challengeID := range pkg.LearningPath {
+		summary := ChallengeSummary{
+			Ref:   services.PackageRef(pkg.Name, challengeID),
+			Track: pkg.Name,
+			ID:    challengeID,
+			Tags:  []string{},
+		}
+		if info := pkg.ChallengeDetails[challengeID]; info != nil {
+			summary.Title = info.Title
+			summary.Difficulty = info.Difficulty
+			summary.Tags = nonNilStrings(info.Tags)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// nonNilStrings returns an empty slice for nil, so lists encode as [] rather than null
+func nonNilStrings(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
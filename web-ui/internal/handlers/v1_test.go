@@ -0,0 +1,471 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"web-ui/internal/services"
+)
+
+// newV1TestServer serves /api/v1 over services loaded from the repository, with state in a temporary data directory
+func newV1TestServer(t *testing.T) (*httptest.Server, *V1Handler) {
+	t.Helper()
+
+	// Services read challenges relative to the web-ui directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join("..", "..")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	dataDir := t.TempDir()
+	t.Setenv("GIP_DATA_DIR", dataDir)
+
+	challengeService := services.NewChallengeService()
+	if err := challengeService.LoadChallenges(); err != nil {
+		t.Fatalf("LoadChallenges: %v", err)
+	}
+	packageService := services.NewPackageService()
+	challenges := challengeService.GetChallenges()
+	packages := packageService.GetPackages()
+
+	scoreboardService := services.NewScoreboardService()
+	if err := scoreboardService.LoadScoreboards(challenges, packages); err != nil {
+		t.Fatalf("LoadScoreboards: %v", err)
+	}
+	achievementService := services.NewAchievementService(filepath.Join(dataDir, "achievements"), "../achievements.json")
+	if err := achievementService.LoadRules(challenges, packages); err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+	leaderboardService := services.NewLeaderboardService(scoreboardService)
+	if err := leaderboardService.Load(challenges, packages, nil); err != nil {
+		t.Fatalf("Load leaderboard: %v", err)
+	}
+
+	api := NewAPIHandler(
+		challengeService,
+		scoreboardService,
+		services.NewUserService(),
+		services.NewExecutionService(),
+		packageService,
+		services.NewPracticeService(filepath.Join(dataDir, "practice")),
+		services.NewPathService(false),
+		achievementService,
+		leaderboardService,
+		services.NewProgressService(filepath.Join(dataDir, "progress")),
+		nil,
+	)
+	v1 := NewV1Handler(api,
+		NewProgressHandler(challengeService, packageService, api.userService, api.progressService, achievementService, leaderboardService),
+		NewEditorHandler(challengeService, packageService, services.NewEditorService(api.executionService, "gopls-not-installed", services.DefaultEditorLimits)),
+		NewDraftHandler(challengeService, packageService, services.NewDraftService(filepath.Join(dataDir, "drafts"))),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/v1/", v1)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, v1
+}
+
+// fetchSpec returns the served OpenAPI document
+func fetchSpec(t *testing.T, server *httptest.Server) map[string]interface{} {
+	t.Helper()
+	resp, err := http.Get(server.URL + "/api/v1/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var spec map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatalf("invalid spec: %v", err)
+	}
+	return spec
+}
+
+func TestV1SpecCoversRoutes(t *testing.T) {
+	server, v1 := newV1TestServer(t)
+	spec := fetchSpec(t, server)
+	paths := spec["paths"].(map[string]interface{})
+
+	routes := map[string]bool{}
+	for _, route := range v1.routes {
+		key := strings.ToLower(route.Method) + " " + route.Pattern
+		routes[key] = true
+		operations, _ := paths[route.Pattern].(map[string]interface{})
+		if _, exists := operations[strings.ToLower(route.Method)]; !exists {
+			t.Errorf("%s is not in the spec", key)
+		}
+	}
+	for path, operations := range paths {
+		for method := range operations.(map[string]interface{}) {
+			if !routes[method+" "+path] {
+				t.Errorf("spec declares %s %s, which is not routed", method, path)
+			}
+		}
+	}
+	if _, exists := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})["ErrorEnvelope"]; !exists {
+		t.Error("spec has no ErrorEnvelope schema")
+	}
+}
+
+func TestV1Contract(t *testing.T) {
+	server, _ := newV1TestServer(t)
+	spec := fetchSpec(t, server)
+
+	packageChallenge := firstPackageChallenge(t)
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		pattern string // Routed pattern, empty for requests the router rejects
+		body    string
+		status  int
+	}{
+		{"list challenges", "GET", "/api/v1/challenges", "/api/v1/challenges", "", 200},
+		{"list classic beginner challenges", "GET", "/api/v1/challenges?track=classic&difficulty=beginner", "/api/v1/challenges", "", 200},
+		{"list challenges of unknown track", "GET", "/api/v1/challenges?track=nope", "/api/v1/challenges", "", 400},
+		{"get challenge", "GET", "/api/v1/challenges/1", "/api/v1/challenges/{id}", "", 200},
+		{"get challenge with invalid id", "GET", "/api/v1/challenges/abc", "/api/v1/challenges/{id}", "", 400},
+		{"get missing challenge", "GET", "/api/v1/challenges/9999", "/api/v1/challenges/{id}", "", 404},
+		{"get scoreboard", "GET", "/api/v1/challenges/1/scoreboard", "/api/v1/challenges/{id}/scoreboard", "", 200},
+		{"run with invalid body", "POST", "/api/v1/challenges/1/run", "/api/v1/challenges/{id}/run", `{"code":`, 400},
+		{"run with unknown field", "POST", "/api/v1/challenges/1/run", "/api/v1/challenges/{id}/run", `{"code":"package main","challengeId":1}`, 400},
+		{"run with invalid mode", "POST", "/api/v1/challenges/1/run", "/api/v1/challenges/{id}/run", `{"code":"package main","mode":"turbo"}`, 400},
+		{"run missing challenge", "POST", "/api/v1/challenges/9999/run", "/api/v1/challenges/{id}/run", `{"code":"package main"}`, 404},
+		{"submit with invalid username", "POST", "/api/v1/challenges/1/submissions", "/api/v1/challenges/{id}/submissions", `{"code":"package main","username":"../x"}`, 400},
+		{"list packages", "GET", "/api/v1/packages", "/api/v1/packages", "", 200},
+		{"get package", "GET", "/api/v1/packages/" + packageChallenge[0], "/api/v1/packages/{package}", "", 200},
+		{"get missing package", "GET", "/api/v1/packages/nope", "/api/v1/packages/{package}", "", 404},
+		{"get package challenge", "GET", "/api/v1/packages/" + packageChallenge[0] + "/challenges/" + packageChallenge[1], "/api/v1/packages/{package}/challenges/{challenge}", "", 200},
+		{"get missing package challenge", "GET", "/api/v1/packages/" + packageChallenge[0] + "/challenges/nope", "/api/v1/packages/{package}/challenges/{challenge}", "", 404},
+		{"get package challenge scoreboard", "GET", "/api/v1/packages/" + packageChallenge[0] + "/challenges/" + packageChallenge[1] + "/scoreboard", "/api/v1/packages/{package}/challenges/{challenge}/scoreboard", "", 200},
+		{"run package challenge without code", "POST", "/api/v1/packages/" + packageChallenge[0] + "/challenges/" + packageChallenge[1] + "/run", "/api/v1/packages/{package}/challenges/{challenge}/run", `{}`, 400},
+		{"submit missing package challenge", "POST", "/api/v1/packages/nope/challenges/nope/submissions", "/api/v1/packages/{package}/challenges/{challenge}/submissions", `{"code":"package main"}`, 404},
+		{"get leaderboard", "GET", "/api/v1/leaderboard?track=classic&window=month", "/api/v1/leaderboard", "", 200},
+		{"get leaderboard with invalid window", "GET", "/api/v1/leaderboard?window=decade", "/api/v1/leaderboard", "", 400},
+		{"get quality leaderboard", "GET", "/api/v1/leaderboard?order=quality", "/api/v1/leaderboard", "", 200},
+		{"get leaderboard with invalid order", "GET", "/api/v1/leaderboard?order=speed", "/api/v1/leaderboard", "", 400},
+		{"get achievements", "GET", "/api/v1/users/contract-user/achievements", "/api/v1/users/{username}/achievements", "", 200},
+		{"get achievements of invalid user", "GET", "/api/v1/users/a%20b/achievements", "/api/v1/users/{username}/achievements", "", 400},
+		{"get learning path", "GET", "/api/v1/users/contract-user/path", "/api/v1/users/{username}/path", "", 200},
+		{"list solutions without username", "GET", "/api/v1/challenges/2/solutions", "/api/v1/challenges/{id}/solutions", "", 400},
+		{"list solutions before passing", "GET", "/api/v1/challenges/2/solutions?username=contract-user", "/api/v1/challenges/{id}/solutions", "", 403},
+		{"list solutions of missing challenge", "GET", "/api/v1/challenges/9999/solutions?username=contract-user", "/api/v1/challenges/{id}/solutions", "", 404},
+		{"compare solutions before passing", "GET", "/api/v1/challenges/2/solutions/compare?username=contract-user&right=other", "/api/v1/challenges/{id}/solutions/compare", "", 403},
+		{"list package solutions before passing", "GET", "/api/v1/packages/" + packageChallenge[0] + "/challenges/" + packageChallenge[1] + "/solutions?username=contract-user", "/api/v1/packages/{package}/challenges/{challenge}/solutions", "", 403},
+		{"compare solutions of missing package challenge", "GET", "/api/v1/packages/nope/challenges/nope/solutions/compare?username=contract-user", "/api/v1/packages/{package}/challenges/{challenge}/solutions/compare", "", 404},
+		{"reveal hints", "POST", "/api/v1/users/contract-user/hints", "/api/v1/users/{username}/hints", `{"challenge":"classic/1","revealed":1}`, 200},
+		{"reveal hints of missing challenge", "POST", "/api/v1/users/contract-user/hints", "/api/v1/users/{username}/hints", `{"challenge":"classic/9999","revealed":1}`, 404},
+		{"reveal hints of invalid user", "POST", "/api/v1/users/a%20b/hints", "/api/v1/users/{username}/hints", `{"challenge":"classic/1","revealed":1}`, 400},
+		{"export progress of invalid user", "GET", "/api/v1/users/a%20b/export", "/api/v1/users/{username}/export", "", 400},
+		{"import invalid archive", "POST", "/api/v1/users/import?username=contract-user", "/api/v1/users/import", "not a zip", 400},
+		{"open editor of missing challenge", "GET", "/api/v1/editor/classic/9999?username=contract-user", "/api/v1/editor/{track}/{challenge}", "", 404},
+		{"open editor with invalid username", "GET", "/api/v1/editor/classic/1?username=a%20b", "/api/v1/editor/{track}/{challenge}", "", 400},
+		{"get missing draft", "GET", "/api/v1/drafts/classic/1?username=contract-user", "/api/v1/drafts/{track}/{challenge}", "", 404},
+		{"save draft", "PUT", "/api/v1/drafts/classic/1?username=contract-user", "/api/v1/drafts/{track}/{challenge}", `{"code":"package main"}`, 200},
+		{"save draft with invalid body", "PUT", "/api/v1/drafts/classic/1?username=contract-user", "/api/v1/drafts/{track}/{challenge}", `{"code":`, 400},
+		{"get draft", "GET", "/api/v1/drafts/classic/1?username=contract-user", "/api/v1/drafts/{track}/{challenge}", "", 200},
+		{"get draft history", "GET", "/api/v1/drafts/classic/1/history?username=contract-user", "/api/v1/drafts/{track}/{challenge}/history", "", 200},
+		{"get draft version", "GET", "/api/v1/drafts/classic/1/versions/1?username=contract-user", "/api/v1/drafts/{track}/{challenge}/versions/{version}", "", 200},
+		{"get missing draft version", "GET", "/api/v1/drafts/classic/1/versions/99?username=contract-user", "/api/v1/drafts/{track}/{challenge}/versions/{version}", "", 404},
+		{"restore draft", "POST", "/api/v1/drafts/classic/1/restore?username=contract-user", "/api/v1/drafts/{track}/{challenge}/restore", `{"version":1}`, 200},
+		{"get draft of missing challenge", "GET", "/api/v1/drafts/classic/9999?username=contract-user", "/api/v1/drafts/{track}/{challenge}", "", 404},
+		{"get draft with invalid username", "GET", "/api/v1/drafts/classic/1?username=a%20b", "/api/v1/drafts/{track}/{challenge}", "", 400},
+		{"get spec", "GET", "/api/v1/openapi.json", "/api/v1/openapi.json", "", 200},
+		{"unknown path", "GET", "/api/v1/nope", "", "", 404},
+		{"unsupported method", "DELETE", "/api/v1/challenges", "", "", 405},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := doV1Request(t, server, tt.method, tt.path, tt.body)
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d: %s", status, tt.status, body)
+			}
+			checkResponseContract(t, spec, tt.method, tt.pattern, status, body)
+		})
+	}
+}
+
+func TestV1RunClassicChallenge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	server, _ := newV1TestServer(t)
+	spec := fetchSpec(t, server)
+
+	template, err := os.ReadFile(filepath.Join("..", "challenge-1", "solution-template.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request, _ := json.Marshal(RunRequest{Code: string(template)})
+	status, body := doV1Request(t, server, "POST", "/api/v1/challenges/1/run", string(request))
+	if status != 200 {
+		t.Fatalf("status = %d: %s", status, body)
+	}
+	checkResponseContract(t, spec, "POST", "/api/v1/challenges/{id}/run", status, body)
+
+	var response RunResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Ref != "classic/1" {
+		t.Errorf("ref = %q, want classic/1", response.Ref)
+	}
+}
+
+// firstPackageChallenge returns the package and ID of the first challenge in any package's learning path
+func firstPackageChallenge(t *testing.T) [2]string {
+	t.Helper()
+	packages := services.NewPackageService().GetPackages()
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if path := packages[name].LearningPath; len(path) > 0 {
+			return [2]string{name, path[0]}
+		}
+	}
+	t.Skip("no package challenges")
+	return [2]string{}
+}
+
+func doV1Request(t *testing.T, server *httptest.Server, method, path, body string) (int, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+	return resp.StatusCode, data
+}
+
+// checkResponseContract checks a response against the schema the spec declares for its status,
+// or against the error envelope for requests the router rejects
+func checkResponseContract(t *testing.T, spec map[string]interface{}, method, pattern string, status int, body []byte) {
+	t.Helper()
+	var schema interface{} = map[string]interface{}{"$ref": "#/components/schemas/ErrorEnvelope"}
+	if pattern != "" {
+		operation, _ := lookup(spec, "paths", pattern, strings.ToLower(method)).(map[string]interface{})
+		if operation == nil {
+			t.Fatalf("spec has no operation %s %s", method, pattern)
+		}
+		schema = lookup(operation, "responses", strconv.Itoa(status), "content", "application/json", "schema")
+		if schema == nil {
+			t.Fatalf("spec does not declare status %d for %s %s", status, method, pattern)
+		}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		t.Fatalf("invalid JSON %s: %v", body, err)
+	}
+	if err := validateSchema(spec, schema.(map[string]interface{}), value, "$"); err != nil {
+		t.Fatalf("response does not match spec: %v\n%s", err, body)
+	}
+	if status >= 400 {
+		envelope := value.(map[string]interface{})["error"].(map[string]interface{})
+		if envelope["status"] != float64(status) {
+			t.Errorf("envelope status = %v, want %d", envelope["status"], status)
+		}
+	}
+}
+
+// lookup follows keys through nested JSON objects
+func lookup(value interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+// validateSchema checks a decoded JSON value against the subset of OpenAPI 3.0 schemas the generator emits
+func validateSchema(spec, schema map[string]interface{}, value interface{}, at string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, _ := lookup(spec, strings.Split(strings.TrimPrefix(ref, "#/"), "/")...).(map[string]interface{})
+		if resolved == nil {
+			return fmt.Errorf("%s: unresolved $ref %s", at, ref)
+		}
+		return validateSchema(spec, resolved, value, at)
+	}
+	if value == nil {
+		if schema["nullable"] == true {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not nullable", at)
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, part := range allOf {
+			if err := validateSchema(spec, part.(map[string]interface{}), value, at); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %T is not an object", at, value)
+		}
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, exists := object[name.(string)]; !exists {
+				return fmt.Errorf("%s: missing required property %q", at, name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range object {
+			propertySchema, declared := properties[name].(map[string]interface{})
+			if !declared {
+				switch additional := schema["additionalProperties"].(type) {
+				case bool:
+					if !additional {
+						return fmt.Errorf("%s: undeclared property %q", at, name)
+					}
+					continue
+				case map[string]interface{}:
+					propertySchema = additional
+				default:
+					continue
+				}
+			}
+			if err := validateSchema(spec, propertySchema, property, at+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %T is not an array", at, value)
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range array {
+			if err := validateSchema(spec, items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: %T is not a string", at, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: %T is not a boolean", at, value)
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%s: %v is not an integer", at, value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: %T is not a number", at, value)
+		}
+	}
+	return nil
+}
+
+func TestPrerequisitesLockRunAndSubmit(t *testing.T) {
+	server, v1 := newV1TestServer(t)
+	v1.api.pathService = services.NewPathService(true)
+	spec := fetchSpec(t, server)
+
+	// Challenge 10 needs challenge 3, and every package challenge after the first needs the one before
+	var locked [2]string
+	for name, pkg := range v1.api.packageService.GetPackages() {
+		if len(pkg.LearningPath) > 1 {
+			locked = [2]string{name, pkg.LearningPath[1]}
+			break
+		}
+	}
+	if locked[0] == "" {
+		t.Skip("no package with two challenges")
+	}
+	body := `{"code":"package main","username":"prerequisite-user"}`
+
+	tests := []struct {
+		name    string
+		path    string
+		pattern string
+	}{
+		{"run classic", "/api/v1/challenges/10/run", "/api/v1/challenges/{id}/run"},
+		{"submit classic", "/api/v1/challenges/10/submissions", "/api/v1/challenges/{id}/submissions"},
+		{"run package", "/api/v1/packages/" + locked[0] + "/challenges/" + locked[1] + "/run", "/api/v1/packages/{package}/challenges/{challenge}/run"},
+		{"submit package", "/api/v1/packages/" + locked[0] + "/challenges/" + locked[1] + "/submissions", "/api/v1/packages/{package}/challenges/{challenge}/submissions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, data := doV1Request(t, server, "POST", tt.path, body)
+			if status != http.StatusForbidden {
+				t.Fatalf("status = %d, want 403: %s", status, data)
+			}
+			checkResponseContract(t, spec, "POST", tt.pattern, status, data)
+			var envelope ErrorEnvelope
+			if err := json.Unmarshal(data, &envelope); err != nil || envelope.Error.Code != ErrorCodePrerequisitesMissing {
+				t.Errorf("error = %s, want %s", data, ErrorCodePrerequisitesMissing)
+			}
+		})
+	}
+
+	// The classic endpoints the pages post to are locked the same way
+	legacy := []struct {
+		name   string
+		handle http.HandlerFunc
+		path   string
+		body   string
+	}{
+		{"legacy run", v1.api.RunCode, "/api/run", `{"challengeId":10,"code":"package main"}`},
+		{"legacy submission", v1.api.HandleSubmissions, "/api/submissions", `{"challengeId":10,"username":"prerequisite-user","code":"package main"}`},
+		{"legacy package test", v1.api.HandlePackageChallenge, "/api/packages/" + locked[0] + "/" + locked[1] + "/test", body},
+		{"legacy package submit", v1.api.HandlePackageChallenge, "/api/packages/" + locked[0] + "/" + locked[1] + "/submit", body},
+	}
+	for _, tt := range legacy {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			req.AddCookie(&http.Cookie{Name: "username", Value: "prerequisite-user"})
+			rec := httptest.NewRecorder()
+			tt.handle(rec, req)
+			if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "Challenge locked") {
+				t.Errorf("status = %d, want 403: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
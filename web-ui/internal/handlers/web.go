@@ -25,6 +25,9 @@ type WebHandler struct {
 	scoreboardService *services.ScoreboardService
 	userService       *services.UserService
 	packageService    *services.PackageService
+	practiceService   *services.PracticeService
+	pathService       *services.PathService
+	draftService      *services.DraftService
 }
 
 // NewWebHandler creates a new web handler
@@ -34,6 +37,9 @@ func NewWebHandler(
 	scoreboardService *services.ScoreboardService,
 	userService *services.UserService,
 	packageService *services.PackageService,
+	practiceService *services.PracticeService,
+	pathService *services.PathService,
+	draftService *services.DraftService,
 ) *WebHandler {
 	return &WebHandler{
 		content:           content,
@@ -41,6 +47,9 @@ func NewWebHandler(
 		scoreboardService: scoreboardService,
 		userService:       userService,
 		packageService:    packageService,
+		practiceService:   practiceService,
+		pathService:       pathService,
+		draftService:      draftService,
 	}
 }
 
@@ -105,8 +114,8 @@ func (h *WebHandler) HomePage(w http.ResponseWriter, r *http.Request) {
 		// Add package challenge attempts to userAttempt for UI consistency
 		for packageName, pkg := range packages {
 			for i, challengeID := range pkg.LearningPath {
-				attempted := h.hasUserAttemptedPackageChallenge(username, packageName, challengeID)
-				if attempted {
+				challenge, ok := services.LocateChallenge(services.PackageRef(packageName, challengeID), h.challengeService.GetChallenges(), packages)
+				if ok && h.hasUserAttemptedPackageChallenge(username, challenge) {
 					// Use negative IDs for package challenges to avoid conflicts with classic challenges
 					// Create unique negative ID based on package and challenge index
 					packageChallengeID := -(1000 + i*10 + len(packageName)) // Ensure unique negative IDs
@@ -116,18 +125,31 @@ func (h *WebHandler) HomePage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Solved challenges due for another attempt today
+	var dueToday []models.PracticeItem
+	if username != "" {
+		challenges := h.challengeService.GetChallenges()
+		if err := h.practiceService.Seed(username, h.userService.GetSubmissionTimes(username, challenges)); err != nil {
+			log.Printf("Failed to schedule practice for %s: %v", username, err)
+		} else if dueToday, err = h.practiceService.Due(username, challenges, time.Now()); err != nil {
+			log.Printf("Failed to load practice schedule for %s: %v", username, err)
+		}
+	}
+
 	data := struct {
 		Challenges   []*models.Challenge
 		Username     string
 		UserAttempts *models.UserAttemptedChallenges
 		Packages     map[string]*models.Package
 		PackagesList []*PackageWithName
+		DueToday     []models.PracticeItem
 	}{
 		Challenges:   challengeList,
 		Username:     username,
 		UserAttempts: userAttempt,
 		Packages:     packages,
 		PackagesList: packagesList,
+		DueToday:     dueToday,
 	}
 
 	err = tmpl.ExecuteTemplate(w, "base", data)
@@ -166,11 +188,19 @@ func (h *WebHandler) ChallengePage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	track := services.ClassicChallenge(challenge)
+	if err := h.checkPrerequisites(username, track.Ref); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	existingSolution := ""
 	hasAttempted := false
 
 	if username != "" {
-		existingSolution = h.userService.GetExistingSolution(username, id)
+		existingSolution = h.draftSolution(username, track.Ref, track.SolutionFile, func() string {
+			return h.userService.GetExistingSolution(username, challenge)
+		})
 		// Check if user has attempted this challenge
 		userAttempts := h.userService.GetUserAttempts(username, h.challengeService.GetChallenges())
 		hasAttempted = userAttempts.AttemptedIDs[id]
@@ -351,7 +381,7 @@ func (h *WebHandler) PackageDetailPage(w http.ResponseWriter, r *http.Request) {
 	completedCount := 0
 	if username != "" {
 		for _, challenge := range challenges {
-			attempted := h.hasUserAttemptedPackageChallenge(username, packageName, challenge.ID)
+			attempted := h.hasUserAttemptedPackageChallenge(username, services.PackageTrackChallenge(challenge))
 			packageAttempts[challenge.ID] = attempted
 			if attempted {
 				completedCount++
@@ -371,7 +401,7 @@ func (h *WebHandler) PackageDetailPage(w http.ResponseWriter, r *http.Request) {
 	// Create submission counts map for each challenge
 	submissionCounts := make(map[string]int)
 	for _, challenge := range challenges {
-		submissionCounts[challenge.ID] = h.countPackageChallengeSubmissions(packageName, challenge.ID)
+		submissionCounts[challenge.ID] = h.countPackageChallengeSubmissions(services.PackageTrackChallenge(challenge))
 	}
 
 	// Create actual leaderboard using submission data
@@ -452,12 +482,20 @@ func (h *WebHandler) PackageChallengePage(w http.ResponseWriter, r *http.Request
 		}
 	}
 
+	track := services.PackageTrackChallenge(challenge)
+	if err := h.checkPrerequisites(username, track.Ref); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	// Check if user has attempted this challenge
 	hasAttempted := false
 	existingSolution := ""
 	if username != "" {
-		hasAttempted = h.hasUserAttemptedPackageChallenge(username, packageName, challengeID)
-		existingSolution = h.getUserPackageChallengeSolution(username, packageName, challengeID)
+		hasAttempted = h.hasUserAttemptedPackageChallenge(username, track)
+		existingSolution = h.draftSolution(username, track.Ref, track.SolutionFile, func() string {
+			return h.getUserPackageChallengeSolution(username, track)
+		})
 	}
 
 	data := struct {
@@ -484,73 +522,55 @@ func (h *WebHandler) PackageChallengePage(w http.ResponseWriter, r *http.Request
 }
 
 // hasUserAttemptedPackageChallenge checks if a user has attempted a package challenge
-func (h *WebHandler) hasUserAttemptedPackageChallenge(username, packageName, challengeID string) bool {
-	// Check if submission file exists in ../packages/{packageName}/{challengeID}/submissions/{username}/solution.go
-	submissionPath := filepath.Join("..", "packages", packageName, challengeID, "submissions", username, "solution.go")
-	if _, err := os.Stat(submissionPath); err == nil {
-		return true
-	}
+func (h *WebHandler) hasUserAttemptedPackageChallenge(username string, challenge *models.TrackChallenge) bool {
+	_, err := os.Stat(filepath.Join(challenge.SubmissionDir(username), challenge.SolutionFile))
+	return err == nil
+}
 
-	// Try alternative path in case of different file naming
-	altSubmissionPath := filepath.Join("..", "packages", packageName, challengeID, "submissions", username, "solution-template.go")
-	if _, err := os.Stat(altSubmissionPath); err == nil {
-		return true
-	}
+// checkPrerequisites returns a *services.PrerequisitesError when a challenge is locked for a user
+func (h *WebHandler) checkPrerequisites(username, ref string) error {
+	return h.pathService.CheckPrerequisites(username, ref, h.challengeService.GetChallenges(), h.packageService.GetPackages(), h.userService)
+}
 
-	return false
+// draftSolution returns the user's latest autosaved draft of a challenge's solution file,
+// falling back to their submitted solution when they have no draft
+func (h *WebHandler) draftSolution(username, ref, solutionFile string, submitted func() string) string {
+	if h.draftService != nil && services.ValidUsername(username) {
+		if draft, err := h.draftService.Latest(username, ref); err == nil {
+			if code, ok := draft.Files[solutionFile]; ok {
+				return code
+			}
+		}
+	}
+	return submitted()
 }
 
 // getUserPackageChallengeSolution retrieves a user's existing solution for a package challenge
-func (h *WebHandler) getUserPackageChallengeSolution(username, packageName, challengeID string) string {
+func (h *WebHandler) getUserPackageChallengeSolution(username string, challenge *models.TrackChallenge) string {
 	if username == "" {
 		return ""
 	}
 
-	// Try solution.go first
-	submissionPath := filepath.Join("..", "packages", packageName, challengeID, "submissions", username, "solution.go")
-	content, err := ioutil.ReadFile(submissionPath)
-	if err == nil {
-		return string(content)
-	}
-
-	// Try solution-template.go as fallback
-	altSubmissionPath := filepath.Join("..", "packages", packageName, challengeID, "submissions", username, "solution-template.go")
-	content, err = ioutil.ReadFile(altSubmissionPath)
-	if err == nil {
-		return string(content)
+	content, err := ioutil.ReadFile(filepath.Join(challenge.SubmissionDir(username), challenge.SolutionFile))
+	if err != nil {
+		return ""
 	}
-
-	return ""
+	return string(content)
 }
 
 // countPackageChallengeSubmissions counts the number of submissions for a package challenge
-func (h *WebHandler) countPackageChallengeSubmissions(packageName, challengeID string) int {
-	submissionsDir := filepath.Join("..", "packages", packageName, challengeID, "submissions")
-
-	// Check if submissions directory exists
-	if _, err := os.Stat(submissionsDir); os.IsNotExist(err) {
-		return 0
-	}
-
+func (h *WebHandler) countPackageChallengeSubmissions(challenge *models.TrackChallenge) int {
 	// Read the submissions directory
-	entries, err := ioutil.ReadDir(submissionsDir)
+	entries, err := ioutil.ReadDir(filepath.Join(challenge.Dir, "submissions"))
 	if err != nil {
 		return 0
 	}
 
 	count := 0
 	for _, entry := range entries {
-		if entry.IsDir() {
-			// Check if this user directory has a solution file
-			userDir := filepath.Join(submissionsDir, entry.Name())
-			solutionPath := filepath.Join(userDir, "solution.go")
-			altSolutionPath := filepath.Join(userDir, "solution-template.go")
-
-			if _, err := os.Stat(solutionPath); err == nil {
-				count++
-			} else if _, err := os.Stat(altSolutionPath); err == nil {
-				count++
-			}
+		// Check if this user directory has a solution file
+		if entry.IsDir() && h.hasUserAttemptedPackageChallenge(entry.Name(), challenge) {
+			count++
 		}
 	}
 
@@ -564,7 +584,8 @@ func (h *WebHandler) createPackageLeaderboard(packageName string, challenges []*
 
 	// Collect submission data for each challenge
 	for _, challenge := range challenges {
-		submissionsDir := filepath.Join("..", "packages", packageName, challenge.ID, "submissions")
+		track := services.PackageTrackChallenge(challenge)
+		submissionsDir := filepath.Join(track.Dir, "submissions")
 
 		// Check if submissions directory exists
 		if _, err := os.Stat(submissionsDir); os.IsNotExist(err) {
@@ -580,8 +601,7 @@ func (h *WebHandler) createPackageLeaderboard(packageName string, challenges []*
 		for _, entry := range entries {
 			if entry.IsDir() {
 				username := entry.Name()
-				userDir := filepath.Join(submissionsDir, username)
-				solutionPath := filepath.Join(userDir, "solution.go")
+				solutionPath := filepath.Join(track.SubmissionDir(username), track.SolutionFile)
 
 				// Check if user has a solution file
 				if stat, err := os.Stat(solutionPath); err == nil {
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// Achievement condition types
+const (
+	ConditionCompleted = "completed" // Challenges completed, filtered by track, tag or refs
+	ConditionSpeedup   = "speedup"   // A passing bench submission reached a geometric mean speedup
+	ConditionStreak    = "streak"    // Passing submissions on consecutive days
+	ConditionAll       = "all"       // Every nested condition holds
+	ConditionAny       = "any"       // At least one nested condition holds
+)
+
+// AchievementRule is a declarative achievement loaded from achievements.json
+type AchievementRule struct {
+	ID          string               `json:"id"`
+	Name        string               `json:"name"`
+	Icon        string               `json:"icon"` // Emoji shown next to the name
+	Description string               `json:"description"`
+	Rank        int                  `json:"rank,omitempty"` // Rules with a rank are titles; the highest earned one is shown on the leaderboard
+	Condition   AchievementCondition `json:"condition"`
+}
+
+// AchievementCondition is the condition of a rule. Which fields apply depends on the type.
+type AchievementCondition struct {
+	Type       string                 `json:"type"`
+	Track      string                 `json:"track,omitempty"`       // completed: "classic" or a package name
+	Tag        string                 `json:"tag,omitempty"`         // completed: only challenges with this tag
+	Challenges []string               `json:"challenges,omitempty"`  // completed: only these refs
+	Min        int                    `json:"min,omitempty"`         // completed: challenges needed; 0 means every matching challenge
+	Challenge  string                 `json:"challenge,omitempty"`   // speedup: challenge ref, e.g. "classic/16"
+	MinSpeedup float64                `json:"min_speedup,omitempty"` // speedup: required geometric mean speedup
+	Days       int                    `json:"days,omitempty"`        // streak: consecutive days
+	Conditions []AchievementCondition `json:"conditions,omitempty"`  // all, any: nested conditions
+}
+
+// Achievement is an achievement a user has earned
+type Achievement struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Icon        string    `json:"icon"`
+	Description string    `json:"description"`
+	Rank        int       `json:"rank,omitempty"`
+	Track       string    `json:"track,omitempty"` // Track the rule is scoped to, if any
+	EarnedAt    time.Time `json:"earnedAt"`
+}
+
+// AchievementState is the activity and earned achievements of one user
+type AchievementState struct {
+	Username    string               `json:"username"`
+	Earned      map[string]time.Time `json:"earned"`      // Rule ID -> when it was earned; earned achievements are never revoked
+	Passed      map[string]time.Time `json:"passed"`      // Challenge ref -> first passing submission
+	PassDays    []string             `json:"passDays"`    // Days with a passing submission, "2006-01-02", sorted
+	BestSpeedup map[string]float64   `json:"bestSpeedup"` // Challenge ref -> best geometric mean speedup of a passing bench submission
+}
@@ -1,36 +1,93 @@
 package models
 
 import (
+	"sort"
+	"strings"
 	"time"
 )
 
 // Challenge represents a coding challenge
 type Challenge struct {
-	ID                int    `json:"id"`
-	Title             string `json:"title"`
-	Description       string `json:"description"`
-	Difficulty        string `json:"difficulty"`
-	Template          string `json:"template"`
-	TestFile          string `json:"testFile"`
-	LearningMaterials string `json:"learningMaterials"`
-	Hints             string `json:"hints"`
+	ID                int             `json:"id"`
+	Title             string          `json:"title"`
+	Description       string          `json:"description"`
+	Difficulty        string          `json:"difficulty"`
+	Tags              []string        `json:"tags,omitempty"`          // Topics from metadata.json, e.g. "concurrency"
+	Prerequisites     []string        `json:"prerequisites,omitempty"` // Challenges or concepts to know first, from metadata.json
+	Template          string          `json:"template"`
+	TestFile          string          `json:"testFile"`
+	HiddenTestFile    string          `json:"-"` // Tests run only on submissions, never sent to the browser
+	FuzzTestFile      string          `json:"-"` // Fuzz targets and their oracles, only used by fuzz runs
+	LearningMaterials string          `json:"learningMaterials"`
+	Hints             string          `json:"hints"`
+	SupportFiles      []string        `json:"supportFiles,omitempty"` // Fixture and support files copied into each run
+	Execution         ExecutionPolicy `json:"execution"`              // Extra requirements for passing runs
+	Dependencies      Dependencies    `json:"-"`                      // Modules needed to run the challenge
+	Dir               string          `json:"-"`                      // Challenge directory on disk
+}
+
+// ExecutionPolicy holds per-challenge execution requirements loaded from metadata.json
+type ExecutionPolicy struct {
+	RequireCleanVet bool                `json:"require_clean_vet,omitempty"` // Fail runs with go vet or analyzer findings
+	Analyzers       []string            `json:"analyzers,omitempty"`         // Extra analyzers such as "staticcheck"
+	RequireRace     bool                `json:"require_race,omitempty"`      // Always run with the race detector
+	Benchmark       *BenchmarkPolicy    `json:"benchmark,omitempty"`         // Benchmark settings for bench runs
+	Fuzz            *FuzzPolicy         `json:"fuzz,omitempty"`              // Fuzz settings for fuzz runs
+	Differential    *DifferentialPolicy `json:"differential,omitempty"`      // Settings for testing against the reference solution
+	Quality         *QualityPolicy      `json:"quality,omitempty"`           // Weights and targets of the quality score
+}
+
+// DifferentialPolicy configures the comparison of submissions with a challenge's reference solution
+type DifferentialPolicy struct {
+	Iterations int   `json:"iterations,omitempty"` // Random inputs per function, defaults to 500
+	Seed       int64 `json:"seed,omitempty"`       // Fixed generator seed, random if zero
+}
+
+// FuzzPolicy configures fuzz runs of the FuzzXxx targets in a challenge's test file
+type FuzzPolicy struct {
+	Targets  []string `json:"targets,omitempty"`  // Targets to fuzz, all declared targets if empty
+	FuzzTime string   `json:"fuzztime,omitempty"` // Time per target, e.g. "10s"
+}
+
+// BenchmarkPolicy configures bench runs and the speedup a solution needs to pass
+type BenchmarkPolicy struct {
+	Count        int               `json:"count,omitempty"`         // Samples per benchmark (-count), defaults to 5
+	Benchtime    string            `json:"benchtime,omitempty"`     // Passed to -benchtime, e.g. "200ms"
+	Baseline     string            `json:"baseline,omitempty"`      // Stored go test -bench output, relative to the challenge directory
+	BaselineCode []string          `json:"baseline_code,omitempty"` // Challenge-owned files benchmarked as the baseline, relative to the challenge directory
+	Comparisons  map[string]string `json:"comparisons,omitempty"`   // Solution benchmark -> baseline benchmark name
+	MinSpeedup   float64           `json:"min_speedup,omitempty"`   // Required geometric mean speedup over the baseline
 }
 
 // Submission represents a user's submitted solution
 type Submission struct {
-	Username    string    `json:"username"`
-	ChallengeID int       `json:"challengeId"`
-	Code        string    `json:"code"`
-	SubmittedAt time.Time `json:"submittedAt"`
-	Passed      bool      `json:"passed"`
-	TestOutput  string    `json:"testOutput"`
-	ExecutionMs int64     `json:"executionMs"`
+	Username    string          `json:"username"`
+	ChallengeID int             `json:"challengeId"`
+	Code        string          `json:"code"`
+	Files       SubmissionFiles `json:"files,omitempty"` // Multi-file submissions, keyed by relative path
+	SubmittedAt time.Time       `json:"submittedAt"`
+	Passed      bool            `json:"passed"`
+	TestOutput  string          `json:"testOutput"`
+	ExecutionMs int64           `json:"executionMs"`
+	Diagnostics []Diagnostic    `json:"diagnostics,omitempty"`
+	HiddenTests []TestOutcome   `json:"hiddenTests,omitempty"` // Hidden test outcomes, by name only
+	// IncludesHidden is set when the run included the challenge's hidden tests; only such runs reach the scoreboard
+	IncludesHidden bool           `json:"includesHidden"`
+	Achievements   []Achievement  `json:"achievements,omitempty"` // Achievements this submission earned
+	Quality        *QualityReport `json:"quality,omitempty"`      // Only set for passing submissions
+}
+
+// TestOutcome is the result of a single test reported without its source or output
+type TestOutcome struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
 }
 
 // ScoreboardEntry represents an entry in the scoreboard
 type ScoreboardEntry struct {
 	Username    string    `json:"username"`
-	ChallengeID int       `json:"challengeId"`
+	Ref         string    `json:"ref"`         // "classic/7" or "gin/challenge-2-middleware"
+	ChallengeID int       `json:"challengeId"` // Zero for package challenges
 	SubmittedAt time.Time `json:"submittedAt"`
 }
 
@@ -49,3 +106,45 @@ type ScoreboardMap map[int][]ScoreboardEntry
 
 // UserAttemptsMap is a type alias for user attempts tracking
 type UserAttemptsMap map[string]*UserAttemptedChallenges
+
+// SubmissionFiles maps a relative file path to its content
+type SubmissionFiles map[string]string
+
+// Names returns the file paths in sorted order
+func (f SubmissionFiles) Names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GoSource returns the concatenated content of all Go files
+func (f SubmissionFiles) GoSource() string {
+	var sb strings.Builder
+	for _, name := range f.Names() {
+		if strings.HasSuffix(name, ".go") {
+			sb.WriteString(f[name])
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// Diagnostic severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Diagnostic is a single finding reported by gofmt, go vet or an analyzer
+type Diagnostic struct {
+	Tool     string `json:"tool"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// DraftVersion is one autosaved state of a user's code for a challenge
+type DraftVersion struct {
+	Version      int             `json:"version"`
+	Files        SubmissionFiles `json:"files,omitempty"` // Left out of history listings
+	Bytes        int             `json:"bytes"`
+	CreatedAt    time.Time       `json:"createdAt"`              // When the version was started
+	SavedAt      time.Time       `json:"savedAt"`                // Last autosave folded into the version
+	RestoredFrom int             `json:"restoredFrom,omitempty"` // Version this one restored
+}
+
+// Draft is a user's autosaved code for a challenge, oldest version first
+type Draft struct {
+	Username string         `json:"username"`
+	Ref      string         `json:"ref"` // "classic/7" or "gin/challenge-2-middleware"
+	Versions []DraftVersion `json:"versions"`
+}
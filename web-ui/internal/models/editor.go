@@ -0,0 +1,34 @@
+package models
+
+// EditorCompletion is a completion suggested by gopls at a position of the editor
+type EditorCompletion struct {
+	Label         string `json:"label"`
+	Kind          string `json:"kind,omitempty"` // "function", "method", "variable", "field", "type", ...
+	Detail        string `json:"detail,omitempty"`
+	Documentation string `json:"documentation,omitempty"`
+	InsertText    string `json:"insertText"`
+}
+
+// EditorHover is the documentation of the identifier under a position of the editor
+type EditorHover struct {
+	Contents  string `json:"contents"` // Plain text
+	Line      int    `json:"line,omitempty"`
+	Column    int    `json:"column,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	EndColumn int    `json:"endColumn,omitempty"`
+}
+
+// EditorLocation is where an identifier is defined
+type EditorLocation struct {
+	File     string `json:"file"` // Workspace file, or a path in the standard library or module cache if External
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	External bool   `json:"external"`
+}
+
+// EditorDiagnostics are the current gopls diagnostics of one file of an editing session
+type EditorDiagnostics struct {
+	File        string       `json:"file"`
+	Version     int          `json:"version"` // Version of the file the diagnostics are for
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
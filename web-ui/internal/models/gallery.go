@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Function diff statuses, from the left solution to the right one
+const (
+	FunctionIdentical  = "identical"  // Same code once comments and formatting are dropped
+	FunctionEquivalent = "equivalent" // Same code up to the names of identifiers
+	FunctionChanged    = "changed"
+	FunctionAdded      = "added"   // Only in the right solution
+	FunctionRemoved    = "removed" // Only in the left solution
+)
+
+// SolutionMetrics measures the non-test Go code of a solution
+type SolutionMetrics struct {
+	Lines         int `json:"lines"`         // Lines of code, without blank and comment-only lines
+	Functions     int `json:"functions"`     // Function and method declarations
+	Complexity    int `json:"complexity"`    // Sum of the functions' cyclomatic complexities
+	MaxComplexity int `json:"maxComplexity"` // Cyclomatic complexity of the most complex function
+}
+
+// BenchmarkResult is the median result of one benchmark of a passing submission
+type BenchmarkResult struct {
+	Name        string  `json:"name"`
+	NsPerOp     float64 `json:"nsPerOp"`
+	BytesPerOp  float64 `json:"bytesPerOp"`
+	AllocsPerOp float64 `json:"allocsPerOp"`
+}
+
+// GallerySolution is another user's saved solution with its metrics
+type GallerySolution struct {
+	Username     string            `json:"username"`
+	Files        []string          `json:"files"`
+	Metrics      SolutionMetrics   `json:"metrics"`
+	OnScoreboard bool              `json:"onScoreboard"`         // Passed every test on the challenge's scoreboard
+	Benchmarks   []BenchmarkResult `json:"benchmarks,omitempty"` // From the user's latest passing bench run, if any
+	BenchmarkAt  *time.Time        `json:"benchmarkAt,omitempty"`
+	Error        string            `json:"error,omitempty"` // Why the metrics could not be computed
+}
+
+// SolutionGallery lists the solutions of other users for a challenge
+type SolutionGallery struct {
+	Ref       string            `json:"ref"`
+	Solutions []GallerySolution `json:"solutions"`
+}
+
+// FunctionSide is one solution's version of a function
+type FunctionSide struct {
+	Signature  string `json:"signature"`
+	Source     string `json:"source"` // Printed without comments
+	Lines      int    `json:"lines"`
+	Complexity int    `json:"complexity"`
+}
+
+// DiffLine is a line of a function diff, with Op "+", "-" or " "
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// FunctionDiff compares the declarations of one function, keyed by name ("Solve" or "Stack.Push")
+type FunctionDiff struct {
+	Name   string        `json:"name"`
+	Status string        `json:"status"`
+	Left   *FunctionSide `json:"left,omitempty"`
+	Right  *FunctionSide `json:"right,omitempty"`
+	Diff   []DiffLine    `json:"diff,omitempty"` // Only for changed functions
+}
+
+// SolutionComparison is a semantic diff between two users' solutions to a challenge
+type SolutionComparison struct {
+	Ref          string          `json:"ref"`
+	Left         string          `json:"left"`
+	Right        string          `json:"right"`
+	LeftMetrics  SolutionMetrics `json:"leftMetrics"`
+	RightMetrics SolutionMetrics `json:"rightMetrics"`
+	Functions    []FunctionDiff  `json:"functions"` // In the left solution's order, then the added ones
+}
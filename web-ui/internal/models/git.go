@@ -0,0 +1,12 @@
+package models
+
+// GitCommit is the branch and commit a saved submission was recorded in
+type GitCommit struct {
+	Branch  string `json:"branch"` // e.g. "submission/alice/classic-7-20240102-150405"
+	SHA     string `json:"sha"`
+	Message string `json:"message"`
+	Author  string `json:"author"` // "Name <email>" from the repository's git config
+	Remote  string `json:"remote,omitempty"`
+	Pushed  bool   `json:"pushed"`
+	DryRun  bool   `json:"dryRun,omitempty"` // The branch was not created and the push only checked
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+)
+
+// Interview session states
+const (
+	InterviewActive = "active"
+	InterviewEnded  = "ended"
+)
+
+// Code snapshot sources
+const (
+	SnapshotAutosave = "autosave"
+	SnapshotRun      = "run"
+)
+
+// InterviewSession is a timed set of challenges solved under interview conditions
+type InterviewSession struct {
+	ID              string                     `json:"id"`
+	Username        string                     `json:"username"`
+	ChallengeIDs    []int                      `json:"challengeIds"`
+	Difficulty      string                     `json:"difficulty,omitempty"` // Filter used to pick the challenges
+	Tags            []string                   `json:"tags,omitempty"`       // Filter used to pick the challenges
+	DurationSeconds int                        `json:"durationSeconds"`
+	AutosaveSeconds int                        `json:"autosaveSeconds"` // How often the editor sends a snapshot
+	StartedAt       time.Time                  `json:"startedAt"`
+	EndsAt          time.Time                  `json:"endsAt"`
+	EndedAt         *time.Time                 `json:"endedAt,omitempty"`
+	Status          string                     `json:"status"`
+	Challenges      map[int]*InterviewProgress `json:"challenges"`
+}
+
+// InterviewProgress is the work done on one challenge during a session
+type InterviewProgress struct {
+	ChallengeID  int            `json:"challengeId"`
+	Runs         int            `json:"runs"`
+	PassedRuns   int            `json:"passedRuns"`
+	FirstGreenAt *time.Time     `json:"firstGreenAt,omitempty"`
+	Snapshots    []CodeSnapshot `json:"snapshots"`
+}
+
+// CodeSnapshot is the editor contents at a point in time
+type CodeSnapshot struct {
+	At     time.Time `json:"at"`
+	Source string    `json:"source"` // "autosave" or "run"
+	Code   string    `json:"code"`
+	Passed *bool     `json:"passed,omitempty"` // Run result, for run snapshots
+}
+
+// InterviewReport summarizes a finished session
+type InterviewReport struct {
+	SessionID       string                     `json:"sessionId"`
+	Username        string                     `json:"username"`
+	StartedAt       time.Time                  `json:"startedAt"`
+	EndedAt         time.Time                  `json:"endedAt"`
+	DurationSeconds int                        `json:"durationSeconds"` // Time actually spent
+	Solved          int                        `json:"solved"`
+	Challenges      []InterviewChallengeReport `json:"challenges"`
+}
+
+// InterviewChallengeReport summarizes the work on one challenge of a session
+type InterviewChallengeReport struct {
+	ChallengeID             int            `json:"challengeId"`
+	Title                   string         `json:"title"`
+	Runs                    int            `json:"runs"`
+	PassRate                float64        `json:"passRate"`                          // Passing runs divided by runs
+	TimeToFirstGreenSeconds *int           `json:"timeToFirstGreenSeconds,omitempty"` // Unset if no run passed
+	Timeline                []CodeSnapshot `json:"timeline"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// LeaderboardEntry is a user's position in a leaderboard ranking
+type LeaderboardEntry struct {
+	Rank            int       `json:"rank"`
+	Username        string    `json:"username"`
+	CompletedCount  int       `json:"completedCount"`
+	LastCompletedAt time.Time `json:"lastCompletedAt"`        // When the user reached their count, zero if undated; earlier ranks higher on ties
+	QualityScore    float64   `json:"qualityScore,omitempty"` // Mean best quality score, only in the quality order
+	ScoredCount     int       `json:"scoredCount,omitempty"`  // Challenges with a quality score, only in the quality order
+}
+
+// LeaderboardPage is one page of a filtered leaderboard with the caller's neighbourhood
+type LeaderboardPage struct {
+	Track         string             `json:"track"`
+	Window        string             `json:"window"`
+	Difficulty    string             `json:"difficulty,omitempty"`
+	Order         string             `json:"order"` // "completions" or "quality"
+	Page          int                `json:"page"`
+	Limit         int                `json:"limit"`
+	Total         int                `json:"total"` // Ranked users across all pages
+	Entries       []LeaderboardEntry `json:"entries"`
+	Me            *LeaderboardEntry  `json:"me,omitempty"`            // The caller's entry, if ranked
+	Neighbourhood []LeaderboardEntry `json:"neighbourhood,omitempty"` // Up to five users either side of the caller
+	UpdatedAt     time.Time          `json:"updatedAt"`               // When the index was last rebuilt
+}
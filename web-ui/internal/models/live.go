@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Live session roles
+const (
+	LiveCandidate   = "candidate"
+	LiveInterviewer = "interviewer"
+)
+
+// Live session event types
+const (
+	LiveEventDelta = "delta"
+	LiveEventRun   = "run"
+	LiveEventNote  = "note"
+	LiveEventJoin  = "join"
+	LiveEventLeave = "leave"
+)
+
+// LiveSession pairs a candidate's editor with interviewers watching it
+type LiveSession struct {
+	ID               string    `json:"id"`
+	ChallengeID      int       `json:"challengeId"`
+	Candidate        string    `json:"candidate"`
+	CandidateToken   string    `json:"candidateToken"`   // Grants the candidate role
+	InterviewerToken string    `json:"interviewerToken"` // Grants the interviewer role
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+// CodeDelta replaces the code between From and To with Text.
+// Offsets are in UTF-16 code units, as used by the browser editor.
+type CodeDelta struct {
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	Text string `json:"text"`
+}
+
+// LiveEvent is one entry of a live session transcript
+type LiveEvent struct {
+	Seq     int             `json:"seq"`
+	At      time.Time       `json:"at"`
+	Type    string          `json:"type"`
+	Role    string          `json:"role"`
+	Name    string          `json:"name,omitempty"`
+	Version int             `json:"version,omitempty"` // Code version after a delta
+	Delta   *CodeDelta      `json:"delta,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"` // Run result as sent by the candidate
+	Note    string          `json:"note,omitempty"`
+}
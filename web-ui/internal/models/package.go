@@ -20,6 +20,7 @@ type Package struct {
 	Tags             []string                  `json:"tags"`
 	EstimatedTime    string                    `json:"estimated_time"`
 	RealWorldUsage   []string                  `json:"real_world_usage"`
+	Icon             string                    `json:"icon,omitempty"`              // Bootstrap icon class of the track
 	ChallengeDetails map[string]*ChallengeInfo `json:"challenge_details,omitempty"` // Dynamic challenge metadata
 }
 
@@ -41,43 +42,52 @@ type ChallengeInfo struct {
 
 // ChallengeMetadata represents metadata that can be loaded from challenge directories
 type ChallengeMetadata struct {
-	Title               string   `json:"title"`
-	Description         string   `json:"description"`
-	ShortDescription    string   `json:"short_description"` // Brief description for cards
-	Difficulty          string   `json:"difficulty"`
-	EstimatedTime       string   `json:"estimated_time"`
-	LearningObjectives  []string `json:"learning_objectives"`
-	Prerequisites       []string `json:"prerequisites"`
-	Tags                []string `json:"tags"`
-	RealWorldConnection string   `json:"real_world_connection"`
-	Requirements        []string `json:"requirements"`
-	BonusPoints         []string `json:"bonus_points"`
-	Icon                string   `json:"icon,omitempty"`
-	Order               int      `json:"order"`
+	Title               string          `json:"title"`
+	Description         string          `json:"description"`
+	ShortDescription    string          `json:"short_description"` // Brief description for cards
+	Difficulty          string          `json:"difficulty"`
+	EstimatedTime       string          `json:"estimated_time"`
+	LearningObjectives  []string        `json:"learning_objectives"`
+	Prerequisites       []string        `json:"prerequisites"`
+	Tags                []string        `json:"tags"`
+	RealWorldConnection string          `json:"real_world_connection"`
+	Requirements        []string        `json:"requirements"`
+	BonusPoints         []string        `json:"bonus_points"`
+	Icon                string          `json:"icon,omitempty"`
+	Order               int             `json:"order"`
+	SupportFiles        []string        `json:"support_files,omitempty"` // Fixture and support files copied into each run
+	Execution           ExecutionPolicy `json:"execution,omitempty"`     // Extra requirements for passing runs
+	Dependencies        Dependencies    `json:"dependencies,omitempty"`  // Modules needed on top of the track's
 }
 
 // PackageChallenge represents a challenge specific to a package
 type PackageChallenge struct {
-	ID                  string   `json:"id"`           // e.g., "challenge-1-basic-routing"
-	PackageName         string   `json:"package_name"` // e.g., "gin"
-	Title               string   `json:"title"`
-	Description         string   `json:"description"`
-	ShortDescription    string   `json:"short_description"` // Brief description for cards
-	Difficulty          string   `json:"difficulty"`
-	LearningObjectives  []string `json:"learning_objectives"`
-	Template            string   `json:"template"`
-	TestFile            string   `json:"testFile"`
-	LearningMaterials   string   `json:"learningMaterials"`
-	Hints               string   `json:"hints"`
-	Requirements        []string `json:"requirements"`
-	BonusPoints         []string `json:"bonus_points"`
-	RealWorldConnection string   `json:"real_world_connection"`
-	EstimatedTime       string   `json:"estimated_time"`
-	Tags                []string `json:"tags"`
-	Prerequisites       []string `json:"prerequisites"`
-	Icon                string   `json:"icon,omitempty"`
-	Order               int      `json:"order"`
-	Status              string   `json:"status,omitempty"` // "available", "coming-soon", etc.
+	ID                  string          `json:"id"`           // e.g., "challenge-1-basic-routing"
+	PackageName         string          `json:"package_name"` // e.g., "gin"
+	Title               string          `json:"title"`
+	Description         string          `json:"description"`
+	ShortDescription    string          `json:"short_description"` // Brief description for cards
+	Difficulty          string          `json:"difficulty"`
+	LearningObjectives  []string        `json:"learning_objectives"`
+	Template            string          `json:"template"`
+	TestFile            string          `json:"testFile"`
+	HiddenTestFile      string          `json:"-"` // Tests run only on submit, never sent to the browser
+	FuzzTestFile        string          `json:"-"` // Fuzz targets and their oracles, only used by fuzz runs
+	LearningMaterials   string          `json:"learningMaterials"`
+	Hints               string          `json:"hints"`
+	Requirements        []string        `json:"requirements"`
+	BonusPoints         []string        `json:"bonus_points"`
+	RealWorldConnection string          `json:"real_world_connection"`
+	EstimatedTime       string          `json:"estimated_time"`
+	Tags                []string        `json:"tags"`
+	Prerequisites       []string        `json:"prerequisites"`
+	Icon                string          `json:"icon,omitempty"`
+	Order               int             `json:"order"`
+	Status              string          `json:"status,omitempty"` // "available", "coming-soon", etc.
+	SupportFiles        []string        `json:"support_files,omitempty"`
+	Execution           ExecutionPolicy `json:"execution"`
+	Dependencies        Dependencies    `json:"-"` // Track and challenge dependencies merged
+	Dir                 string          `json:"-"` // Challenge directory on disk
 }
 
 // PackageSubmission represents a user's submitted solution for a package challenge
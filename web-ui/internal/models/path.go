@@ -0,0 +1,25 @@
+package models
+
+// PathNode is a challenge in the learning-path graph
+type PathNode struct {
+	Ref           string   `json:"ref"`   // "classic/7" or "gin/challenge-2-middleware"
+	Track         string   `json:"track"` // "classic" or the package name
+	Title         string   `json:"title"`
+	Difficulty    string   `json:"difficulty"`
+	Prerequisites []string `json:"prerequisites"`      // Refs of the challenges to complete first
+	Concepts      []string `json:"concepts,omitempty"` // Prerequisites that are not challenges, e.g. "Basic Go syntax"
+	Completed     bool     `json:"completed"`
+	Available     bool     `json:"available"` // Every prerequisite is completed
+	Locked        bool     `json:"locked"`    // Unavailable while prerequisites are enforced
+}
+
+// LearningPlan is a user's progress through the learning-path graph
+type LearningPlan struct {
+	Username    string     `json:"username"`
+	Recommended *PathNode  `json:"recommended"` // Next challenge to attempt; unset when everything is completed
+	Completed   int        `json:"completed"`
+	Total       int        `json:"total"`
+	Enforced    bool       `json:"enforced"` // Whether challenges with missing prerequisites are locked
+	Plan        []PathNode `json:"plan"`     // Every challenge, prerequisites first
+	Cycles      [][]string `json:"cycles,omitempty"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// PracticeCard schedules re-attempts of a solved challenge using SM-2
+type PracticeCard struct {
+	ChallengeID  int              `json:"challengeId"`
+	Repetitions  int              `json:"repetitions"` // Successful reviews in a row
+	EaseFactor   float64          `json:"easeFactor"`
+	IntervalDays int              `json:"intervalDays"`
+	DueAt        time.Time        `json:"dueAt"`
+	Reviews      []PracticeReview `json:"reviews"`
+}
+
+// PracticeReview is the outcome of one solve of a challenge
+type PracticeReview struct {
+	At           time.Time `json:"at"`
+	Quality      int       `json:"quality"` // SM-2 grade from 0 to 5; below 3 resets the interval
+	FailedRuns   int       `json:"failedRuns"`
+	SolveSeconds int       `json:"solveSeconds"`
+	Seeded       bool      `json:"seeded,omitempty"` // Created from a solution solved before scheduling started
+}
+
+// PracticeAttempt tracks the runs of a challenge since it was last solved
+type PracticeAttempt struct {
+	StartedAt  time.Time `json:"startedAt"`
+	LastRunAt  time.Time `json:"lastRunAt"`
+	FailedRuns int       `json:"failedRuns"`
+}
+
+// PracticeState is the practice schedule of one user
+type PracticeState struct {
+	Username string                   `json:"username"`
+	Cards    map[int]*PracticeCard    `json:"cards"`
+	Attempts map[int]*PracticeAttempt `json:"attempts"`
+}
+
+// PracticeItem is a scheduled challenge as listed to the user
+type PracticeItem struct {
+	ChallengeID  int       `json:"challengeId"`
+	Title        string    `json:"title"`
+	Difficulty   string    `json:"difficulty"`
+	DueAt        time.Time `json:"dueAt"`
+	OverdueDays  int       `json:"overdueDays"`
+	IntervalDays int       `json:"intervalDays"`
+	Repetitions  int       `json:"repetitions"`
+	EaseFactor   float64   `json:"easeFactor"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// SubmissionRecord is one entry of a user's submission history
+type SubmissionRecord struct {
+	Ref         string            `json:"ref"` // "classic/7" or "gin/challenge-2-middleware"
+	Passed      bool              `json:"passed"`
+	SubmittedAt time.Time         `json:"submittedAt"`
+	ExecutionMs int64             `json:"executionMs"`
+	Benchmarks  []BenchmarkResult `json:"benchmarks,omitempty"` // Only for bench runs
+	Quality     float64           `json:"quality,omitempty"`    // Quality score of a passing submission
+}
+
+// ProgressState is the submission history and hint reveals of one user
+type ProgressState struct {
+	Username string             `json:"username"`
+	History  []SubmissionRecord `json:"history"`
+	Hints    map[string]int     `json:"hints"` // Challenge ref -> hints revealed
+}
+
+// ProgressExport is everything exported for a user, as read from or written to an archive
+type ProgressExport struct {
+	Version      int                        `json:"version"`
+	Username     string                     `json:"username"`
+	ExportedAt   time.Time                  `json:"exportedAt"`
+	Solutions    map[string]SubmissionFiles `json:"-"` // Challenge ref -> saved solution files
+	History      []SubmissionRecord         `json:"-"`
+	Hints        map[string]int             `json:"-"`
+	Achievements *AchievementState          `json:"-"`
+}
+
+// ImportConflict is a solution in an import that differs from the one already saved
+type ImportConflict struct {
+	Ref   string   `json:"ref"`
+	Files []string `json:"files"` // Files whose content differs or that exist only on one side
+}
+
+// ImportReport describes what an import did, or would do on a dry run
+type ImportReport struct {
+	Username     string           `json:"username"`
+	DryRun       bool             `json:"dryRun"`
+	Imported     []string         `json:"imported"`  // Solutions written
+	Unchanged    []string         `json:"unchanged"` // Solutions identical to the saved ones
+	Conflicts    []ImportConflict `json:"conflicts"` // Solutions left as they are; overwritten when overwrite is set
+	Unknown      []string         `json:"unknown"`   // Challenge refs not in the current catalog
+	Invalid      []string         `json:"invalid"`   // Solutions with files that are not allowed
+	History      int              `json:"history"`   // History entries added
+	Hints        int              `json:"hints"`     // Challenges with more hints revealed
+	Achievements int              `json:"achievements"`
+}
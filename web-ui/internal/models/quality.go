@@ -0,0 +1,44 @@
+package models
+
+// Quality score components
+const (
+	QualityComplexity     = "complexity"      // Cyclomatic complexity of the most complex function
+	QualityFunctionLength = "function_length" // Lines of code of the longest function
+	QualityFindings       = "findings"        // go vet and analyzer findings
+	QualityAllocations    = "allocations"     // Mean allocations per op of a bench run
+	QualityRuntime        = "runtime"         // Test runtime in milliseconds
+)
+
+// QualityPolicy configures the quality score of passing runs, under "quality" in a challenge's execution policy
+type QualityPolicy struct {
+	Weights          *QualityWeights `json:"weights,omitempty"`            // Replaces the default weights; omitted components get no weight
+	MaxComplexity    float64         `json:"max_complexity,omitempty"`     // Full marks up to this complexity, defaults to 10
+	MaxFunctionLines float64         `json:"max_function_lines,omitempty"` // Full marks up to this function length, defaults to 40
+	MaxAllocs        float64         `json:"max_allocs,omitempty"`         // Full marks up to this many allocs/op, defaults to the baseline's or 0
+	MaxTestMs        float64         `json:"max_test_ms,omitempty"`        // Full marks up to this test runtime, defaults to 1000
+}
+
+// QualityWeights weighs the components of the quality score against each other
+type QualityWeights struct {
+	Complexity     float64 `json:"complexity"`
+	FunctionLength float64 `json:"function_length"`
+	Findings       float64 `json:"findings"`
+	Allocations    float64 `json:"allocations"`
+	Runtime        float64 `json:"runtime"`
+}
+
+// QualityComponent is one measured part of a quality score
+type QualityComponent struct {
+	Name     string  `json:"name"`
+	Value    float64 `json:"value"`    // The measurement, e.g. the highest complexity
+	Target   float64 `json:"target"`   // Values up to the target score 100
+	Score    float64 `json:"score"`    // 0 to 100
+	Weight   float64 `json:"weight"`   // Share of the total score, 0 if not measured
+	Measured bool    `json:"measured"` // Allocations are only measured on bench runs
+}
+
+// QualityReport is the quality score of a passing run with its breakdown
+type QualityReport struct {
+	Score      float64            `json:"score"` // 0 to 100, the weighted mean of the measured components
+	Components []QualityComponent `json:"components"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// SimilarityReport lists the submissions of a challenge that are suspiciously alike
+type SimilarityReport struct {
+	Ref         string              `json:"ref"`
+	Threshold   float64             `json:"threshold"`   // Minimum score reported
+	Submissions int                 `json:"submissions"` // Submissions compared
+	Pairs       []SimilarityPair    `json:"pairs"`       // Pairs at or above the threshold, most similar first
+	Clusters    []SimilarityCluster `json:"clusters"`    // Groups of users connected by those pairs
+	Skipped     []string            `json:"skipped"`     // Users whose submission could not be parsed
+	TooShort    []string            `json:"tooShort"`    // Users whose submission adds too little to the template to compare
+}
+
+// SimilarityPair is the similarity of two users' submissions, from 0 to 1
+type SimilarityPair struct {
+	Users [2]string `json:"users"`
+	Score float64   `json:"score"`
+}
+
+// SimilarityCluster is a group of users whose submissions are pairwise connected by similar pairs
+type SimilarityCluster struct {
+	Users    []string `json:"users"`
+	MaxScore float64  `json:"maxScore"`
+}
+
+// SimilarityFlag hides a user's entry for a challenge from the scoreboard
+type SimilarityFlag struct {
+	Ref       string    `json:"ref"`
+	Username  string    `json:"username"`
+	Reason    string    `json:"reason,omitempty"`
+	FlaggedAt time.Time `json:"flaggedAt"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// TrackChallenge is a classic or package challenge addressed by a stable ref.
+// Execution, saving, scoreboards and progress work on it without knowing which track it is from.
+type TrackChallenge struct {
+	Ref               string          `json:"ref"`   // "classic/7" or "gin/challenge-2-middleware"
+	Track             string          `json:"track"` // "classic" or the package name
+	ID                string          `json:"id"`    // "7" or "challenge-2-middleware"
+	Title             string          `json:"title"`
+	Description       string          `json:"description"`
+	Difficulty        string          `json:"difficulty"`
+	Tags              []string        `json:"tags"`
+	Template          string          `json:"template"`
+	TestFile          string          `json:"testFile"`
+	HiddenTestFile    string          `json:"-"`
+	FuzzTestFile      string          `json:"-"`
+	LearningMaterials string          `json:"learningMaterials"`
+	Hints             string          `json:"hints"`
+	SupportFiles      []string        `json:"supportFiles,omitempty"`
+	Execution         ExecutionPolicy `json:"execution"`
+	Dependencies      Dependencies    `json:"-"`
+	Dir               string          `json:"-"` // Challenge directory on disk
+	SolutionFile      string          `json:"-"` // File name of a saved single-file solution
+	Module            string          `json:"-"` // Module path of the run directory
+	Classic           *Challenge      `json:"-"` // Set for classic challenges
+}
+
+// SubmissionDir returns the directory a user's solution is saved in
+func (c *TrackChallenge) SubmissionDir(username string) string {
+	return filepath.Join(c.Dir, "submissions", username)
+}
+
+// RepoPath returns the challenge directory relative to the repository root
+func (c *TrackChallenge) RepoPath() string {
+	return strings.TrimPrefix(filepath.ToSlash(filepath.Clean(c.Dir)), "../")
+}
+
+// Dependencies declares the modules a track or challenge needs at run time, from package.json or metadata.json
+type Dependencies struct {
+	Modules []string            `json:"modules,omitempty"` // Modules fetched for every run, optionally pinned as "path@version"
+	Drivers map[string][]string `json:"drivers,omitempty"` // Import path -> extra modules it needs, e.g. a database driver
+}
+
+// Merge returns the union of two dependency declarations
+func (d Dependencies) Merge(other Dependencies) Dependencies {
+	merged := Dependencies{Modules: append(append([]string{}, d.Modules...), other.Modules...)}
+	for _, drivers := range []map[string][]string{d.Drivers, other.Drivers} {
+		for importPath, modules := range drivers {
+			if merged.Drivers == nil {
+				merged.Drivers = map[string][]string{}
+			}
+			merged.Drivers[importPath] = append(merged.Drivers[importPath], modules...)
+		}
+	}
+	return merged
+}
+
+// ModulePath returns a declared module without its version suffix
+func ModulePath(module string) string {
+	path, _, _ := strings.Cut(module, "@")
+	return path
+}
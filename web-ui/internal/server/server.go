@@ -13,12 +13,24 @@ import (
 
 // Server represents the web server with all its dependencies
 type Server struct {
-	content           embed.FS
-	challengeService  *services.ChallengeService
-	scoreboardService *services.ScoreboardService
-	userService       *services.UserService
-	executionService  *services.ExecutionService
-	packageService    *services.PackageService
+	content            embed.FS
+	challengeService   *services.ChallengeService
+	scoreboardService  *services.ScoreboardService
+	userService        *services.UserService
+	executionService   *services.ExecutionService
+	packageService     *services.PackageService
+	interviewService   *services.InterviewService
+	liveService        *services.LiveService
+	practiceService    *services.PracticeService
+	pathService        *services.PathService
+	achievementService *services.AchievementService
+	leaderboardService *services.LeaderboardService
+	progressService    *services.ProgressService
+	similarityService  *services.SimilarityService
+	editorService      *services.EditorService
+	draftService       *services.DraftService
+	gitService         *services.GitService
+	adminToken         string
 }
 
 // NewServer creates a new server instance
@@ -29,14 +41,38 @@ func NewServer(
 	userService *services.UserService,
 	executionService *services.ExecutionService,
 	packageService *services.PackageService,
+	interviewService *services.InterviewService,
+	liveService *services.LiveService,
+	practiceService *services.PracticeService,
+	pathService *services.PathService,
+	achievementService *services.AchievementService,
+	leaderboardService *services.LeaderboardService,
+	progressService *services.ProgressService,
+	similarityService *services.SimilarityService,
+	editorService *services.EditorService,
+	draftService *services.DraftService,
+	gitService *services.GitService,
+	adminToken string,
 ) *Server {
 	return &Server{
-		content:           content,
-		challengeService:  challengeService,
-		scoreboardService: scoreboardService,
-		userService:       userService,
-		executionService:  executionService,
-		packageService:    packageService,
+		content:            content,
+		challengeService:   challengeService,
+		scoreboardService:  scoreboardService,
+		userService:        userService,
+		executionService:   executionService,
+		packageService:     packageService,
+		interviewService:   interviewService,
+		liveService:        liveService,
+		practiceService:    practiceService,
+		pathService:        pathService,
+		achievementService: achievementService,
+		leaderboardService: leaderboardService,
+		progressService:    progressService,
+		similarityService:  similarityService,
+		editorService:      editorService,
+		draftService:       draftService,
+		gitService:         gitService,
+		adminToken:         adminToken,
 	}
 }
 
@@ -54,6 +90,43 @@ func (s *Server) SetupRoutes() *http.ServeMux {
 		s.userService,
 		s.executionService,
 		s.packageService,
+		s.practiceService,
+		s.pathService,
+		s.achievementService,
+		s.leaderboardService,
+		s.progressService,
+		s.gitService,
+	)
+
+	progressHandler := handlers.NewProgressHandler(
+		s.challengeService,
+		s.packageService,
+		s.userService,
+		s.progressService,
+		s.achievementService,
+		s.leaderboardService,
+	)
+
+	interviewHandler := handlers.NewInterviewHandler(
+		s.challengeService,
+		s.executionService,
+		s.interviewService,
+	)
+
+	liveHandler := handlers.NewLiveHandler(s.challengeService, s.liveService)
+
+	editorHandler := handlers.NewEditorHandler(s.challengeService, s.packageService, s.editorService)
+
+	draftHandler := handlers.NewDraftHandler(s.challengeService, s.packageService, s.draftService)
+
+	v1Handler := handlers.NewV1Handler(apiHandler, progressHandler, editorHandler, draftHandler)
+
+	adminHandler := handlers.NewAdminHandler(
+		s.challengeService,
+		s.packageService,
+		s.scoreboardService,
+		s.similarityService,
+		s.adminToken,
 	)
 
 	webHandler := handlers.NewWebHandler(
@@ -62,6 +135,9 @@ func (s *Server) SetupRoutes() *http.ServeMux {
 		s.scoreboardService,
 		s.userService,
 		s.packageService,
+		s.practiceService,
+		s.pathService,
+		s.draftService,
 	)
 
 	// API routes
@@ -75,11 +151,30 @@ func (s *Server) SetupRoutes() *http.ServeMux {
 	mux.HandleFunc("/api/git-username", apiHandler.GetGitUsername)
 	mux.HandleFunc("/api/main-scoreboard-rank", apiHandler.GetMainScoreboardRank)
 	mux.HandleFunc("/api/main-leaderboard", apiHandler.GetMainLeaderboard)
+	mux.HandleFunc("/api/leaderboard", apiHandler.GetLeaderboard)
+	mux.HandleFunc("/api/practice/next", apiHandler.GetPracticeNext)
+	mux.HandleFunc("/api/practice/reviews", apiHandler.GetPracticeReviews)
+	mux.HandleFunc("/api/paths/", apiHandler.GetLearningPath)
+	mux.HandleFunc("/api/achievements/", apiHandler.GetAchievements)
+
+	// Versioned API, described by /api/v1/openapi.json, with user progress, editor sessions and drafts
+	mux.Handle("/api/v1/", v1Handler)
 
 	// Package challenge API routes
 	mux.HandleFunc("/api/packages/", apiHandler.HandlePackageChallenge)
 	mux.HandleFunc("/api/packages-save-to-filesystem", apiHandler.SavePackageChallengeToFilesystem)
 
+	// Interview session API routes
+	mux.HandleFunc("/api/interviews", interviewHandler.HandleInterviews)
+	mux.HandleFunc("/api/interviews/", interviewHandler.HandleInterview)
+
+	// Live session routes
+	mux.HandleFunc("/api/live", liveHandler.CreateLiveSession)
+	mux.HandleFunc("/api/live/", liveHandler.HandleLiveSession)
+
+	// Admin routes, enabled by GIP_ADMIN_TOKEN
+	mux.Handle("/api/admin/", adminHandler)
+
 	// Web routes
 	mux.HandleFunc("/", webHandler.HomePage)
 	mux.HandleFunc("/challenge/", webHandler.ChallengePage)
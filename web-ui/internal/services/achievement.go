@@ -0,0 +1,458 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+// passDayLayout is the layout of the days in AchievementState.PassDays
+const passDayLayout = "2006-01-02"
+
+// AchievementSubmission is a submission as seen by the achievement rules
+type AchievementSubmission struct {
+	Ref     string    // Challenge ref, e.g. "classic/16" or "gin/challenge-2-middleware"
+	Passed  bool      // Whether every test passed
+	Speedup float64   // Geometric mean speedup of a bench run, 0 if none
+	At      time.Time // When it was submitted
+}
+
+// achievementChallenge is what the rules know about a challenge
+type achievementChallenge struct {
+	track string
+	tags  []string
+}
+
+// AchievementService evaluates the declarative achievement rules against each user's activity.
+// Earned achievements are stored per user and never revoked.
+type AchievementService struct {
+	mu        sync.Mutex
+	dir       string
+	rulesPath string
+	rules     []models.AchievementRule
+	states    map[string]*models.AchievementState
+}
+
+// NewAchievementService creates an achievement service reading rules from rulesPath and storing one JSON file per user in dir
+func NewAchievementService(dir, rulesPath string) *AchievementService {
+	return &AchievementService{
+		dir:       dir,
+		rulesPath: rulesPath,
+		states:    make(map[string]*models.AchievementState),
+	}
+}
+
+// LoadRules reads and validates the rules against the known challenges and tracks
+func (as *AchievementService) LoadRules(challenges models.ChallengeMap, packages models.PackageMap) error {
+	content, err := os.ReadFile(as.rulesPath)
+	if err != nil {
+		return fmt.Errorf("failed to read achievement rules: %v", err)
+	}
+
+	var rules []models.AchievementRule
+	if err := json.Unmarshal(content, &rules); err != nil {
+		return fmt.Errorf("failed to parse achievement rules: %v", err)
+	}
+
+	catalog := buildAchievementCatalog(challenges, packages)
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.ID == "" || rule.Name == "" {
+			return fmt.Errorf("achievement rule %q needs an id and a name", rule.ID)
+		}
+		if seen[rule.ID] {
+			return fmt.Errorf("duplicate achievement rule %q", rule.ID)
+		}
+		seen[rule.ID] = true
+		if err := validateCondition(rule.Condition, catalog, packages); err != nil {
+			return fmt.Errorf("achievement rule %q: %v", rule.ID, err)
+		}
+	}
+
+	as.mu.Lock()
+	as.rules = rules
+	as.mu.Unlock()
+	return nil
+}
+
+// Rules returns the loaded rules
+func (as *AchievementService) Rules() []models.AchievementRule {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	return as.rules
+}
+
+// RecordSubmission records a submission and evaluates the rules, returning the achievements it earned
+func (as *AchievementService) RecordSubmission(username string, submission AchievementSubmission, challenges models.ChallengeMap, packages models.PackageMap, completed map[string]bool) ([]models.Achievement, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	state, err := as.load(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission.Passed {
+		if _, exists := state.Passed[submission.Ref]; !exists {
+			state.Passed[submission.Ref] = submission.At
+		}
+		day := submission.At.Format(passDayLayout)
+		if i := sort.SearchStrings(state.PassDays, day); i == len(state.PassDays) || state.PassDays[i] != day {
+			state.PassDays = append(state.PassDays, "")
+			copy(state.PassDays[i+1:], state.PassDays[i:])
+			state.PassDays[i] = day
+		}
+		if submission.Speedup > state.BestSpeedup[submission.Ref] {
+			state.BestSpeedup[submission.Ref] = submission.Speedup
+		}
+	}
+
+	earned := as.evaluate(state, buildAchievementCatalog(challenges, packages), completed, submission.At)
+	if err := as.save(state); err != nil {
+		return nil, err
+	}
+	if len(earned) == 0 {
+		return []models.Achievement{}, nil
+	}
+	return as.achievements(state, earned), nil
+}
+
+// Evaluate evaluates the rules against a user's activity and the given completions, returning every achievement earned so far
+func (as *AchievementService) Evaluate(username string, challenges models.ChallengeMap, packages models.PackageMap, completed map[string]bool) ([]models.Achievement, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	state, err := as.load(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if earned := as.evaluate(state, buildAchievementCatalog(challenges, packages), completed, time.Now()); len(earned) > 0 {
+		if err := as.save(state); err != nil {
+			return nil, err
+		}
+	}
+	return as.achievements(state, nil), nil
+}
+
+// Earned returns the achievements a user has earned so far, as stored; it evaluates no rules and writes nothing
+func (as *AchievementService) Earned(username string) ([]models.Achievement, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	state, err := as.load(username)
+	if err != nil {
+		return nil, err
+	}
+	return as.achievements(state, nil), nil
+}
+
+// Export returns a copy of a user's achievement state
+func (as *AchievementService) Export(username string) (*models.AchievementState, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	state, err := as.load(username)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	copied := &models.AchievementState{}
+	return copied, json.Unmarshal(content, copied)
+}
+
+// Import merges imported activity and earned achievements of known rules into a user's state,
+// returning how many achievements it adds. With dryRun set nothing is saved.
+func (as *AchievementService) Import(username string, imported *models.AchievementState, dryRun bool) (int, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	state, err := as.load(username)
+	if err != nil {
+		return 0, err
+	}
+
+	known := make(map[string]bool, len(as.rules))
+	for _, rule := range as.rules {
+		known[rule.ID] = true
+	}
+	added := 0
+	for id, earnedAt := range imported.Earned {
+		if previous, exists := state.Earned[id]; known[id] && (!exists || earnedAt.Before(previous)) {
+			if !exists {
+				added++
+			}
+			if !dryRun {
+				state.Earned[id] = earnedAt
+			}
+		}
+	}
+	if dryRun {
+		return added, nil
+	}
+
+	for ref, passedAt := range imported.Passed {
+		if previous, exists := state.Passed[ref]; !exists || passedAt.Before(previous) {
+			state.Passed[ref] = passedAt
+		}
+	}
+	for _, day := range imported.PassDays {
+		if !containsString(state.PassDays, day) {
+			state.PassDays = append(state.PassDays, day)
+		}
+	}
+	sort.Strings(state.PassDays)
+	for ref, speedup := range imported.BestSpeedup {
+		if speedup > state.BestSpeedup[ref] {
+			state.BestSpeedup[ref] = speedup
+		}
+	}
+	return added, as.save(state)
+}
+
+// TopTitle returns the highest ranked title among earned achievements, or nil if none is a title
+func TopTitle(achievements []models.Achievement) *models.Achievement {
+	var top *models.Achievement
+	for i := range achievements {
+		if achievements[i].Rank > 0 && (top == nil || achievements[i].Rank > top.Rank) {
+			top = &achievements[i]
+		}
+	}
+	return top
+}
+
+// evaluate marks the rules that now hold as earned and returns their IDs
+func (as *AchievementService) evaluate(state *models.AchievementState, catalog map[string]achievementChallenge, completed map[string]bool, at time.Time) []string {
+	done := make(map[string]bool, len(completed)+len(state.Passed))
+	for ref, ok := range completed {
+		done[ref] = ok
+	}
+	for ref := range state.Passed {
+		done[ref] = true
+	}
+
+	var earned []string
+	for _, rule := range as.rules {
+		if _, exists := state.Earned[rule.ID]; exists {
+			continue
+		}
+		if conditionHolds(rule.Condition, state, catalog, done) {
+			state.Earned[rule.ID] = at
+			earned = append(earned, rule.ID)
+		}
+	}
+	return earned
+}
+
+// achievements returns the earned achievements, limited to the given rule IDs if any, highest rank then earliest first
+func (as *AchievementService) achievements(state *models.AchievementState, only []string) []models.Achievement {
+	achievements := []models.Achievement{}
+	for _, rule := range as.rules {
+		earnedAt, exists := state.Earned[rule.ID]
+		if !exists || (only != nil && !containsString(only, rule.ID)) {
+			continue
+		}
+		achievement := models.Achievement{
+			ID:          rule.ID,
+			Name:        rule.Name,
+			Icon:        rule.Icon,
+			Description: rule.Description,
+			Rank:        rule.Rank,
+			EarnedAt:    earnedAt,
+		}
+		if rule.Condition.Type == models.ConditionCompleted {
+			achievement.Track = rule.Condition.Track
+		}
+		achievements = append(achievements, achievement)
+	}
+	sort.SliceStable(achievements, func(i, j int) bool {
+		if achievements[i].Rank != achievements[j].Rank {
+			return achievements[i].Rank > achievements[j].Rank
+		}
+		return achievements[i].EarnedAt.Before(achievements[j].EarnedAt)
+	})
+	return achievements
+}
+
+// conditionHolds evaluates a condition
+func conditionHolds(condition models.AchievementCondition, state *models.AchievementState, catalog map[string]achievementChallenge, done map[string]bool) bool {
+	switch condition.Type {
+	case models.ConditionCompleted:
+		matching, count := 0, 0
+		for ref, challenge := range catalog {
+			if !conditionMatches(condition, ref, challenge) {
+				continue
+			}
+			matching++
+			if done[ref] {
+				count++
+			}
+		}
+		if condition.Min > 0 {
+			return count >= condition.Min
+		}
+		return matching > 0 && count == matching
+	case models.ConditionSpeedup:
+		best, exists := state.BestSpeedup[condition.Challenge]
+		return exists && best >= condition.MinSpeedup
+	case models.ConditionStreak:
+		return longestStreak(state.PassDays) >= condition.Days
+	case models.ConditionAll:
+		for _, nested := range condition.Conditions {
+			if !conditionHolds(nested, state, catalog, done) {
+				return false
+			}
+		}
+		return true
+	case models.ConditionAny:
+		for _, nested := range condition.Conditions {
+			if conditionHolds(nested, state, catalog, done) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// conditionMatches reports whether a challenge is counted by a completed condition
+func conditionMatches(condition models.AchievementCondition, ref string, challenge achievementChallenge) bool {
+	if condition.Track != "" && challenge.track != condition.Track {
+		return false
+	}
+	if condition.Tag != "" && !containsString(challenge.tags, condition.Tag) {
+		return false
+	}
+	if len(condition.Challenges) > 0 && !containsString(condition.Challenges, ref) {
+		return false
+	}
+	return true
+}
+
+// longestStreak returns the longest run of consecutive days in sorted days
+func longestStreak(days []string) int {
+	longest, current := 0, 0
+	var previous time.Time
+	for _, day := range days {
+		t, err := time.Parse(passDayLayout, day)
+		if err != nil {
+			continue
+		}
+		if current > 0 && t.Equal(previous.AddDate(0, 0, 1)) {
+			current++
+		} else {
+			current = 1
+		}
+		previous = t
+		if current > longest {
+			longest = current
+		}
+	}
+	return longest
+}
+
+// validateCondition checks that a condition is well formed and names existing challenges and tracks
+func validateCondition(condition models.AchievementCondition, catalog map[string]achievementChallenge, packages models.PackageMap) error {
+	switch condition.Type {
+	case models.ConditionCompleted:
+		if condition.Track != "" && condition.Track != ClassicTrack && packages[condition.Track] == nil {
+			return fmt.Errorf("unknown track %q", condition.Track)
+		}
+		for _, ref := range condition.Challenges {
+			if _, exists := catalog[ref]; !exists {
+				return fmt.Errorf("unknown challenge %q", ref)
+			}
+		}
+		if condition.Min < 0 {
+			return fmt.Errorf("min must not be negative")
+		}
+	case models.ConditionSpeedup:
+		if _, exists := catalog[condition.Challenge]; !exists {
+			return fmt.Errorf("unknown challenge %q", condition.Challenge)
+		}
+		if condition.MinSpeedup <= 0 {
+			return fmt.Errorf("min_speedup must be positive")
+		}
+	case models.ConditionStreak:
+		if condition.Days <= 0 {
+			return fmt.Errorf("days must be positive")
+		}
+	case models.ConditionAll, models.ConditionAny:
+		if len(condition.Conditions) == 0 {
+			return fmt.Errorf("%s needs nested conditions", condition.Type)
+		}
+		for _, nested := range condition.Conditions {
+			if err := validateCondition(nested, catalog, packages); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown condition type %q", condition.Type)
+	}
+	return nil
+}
+
+// buildAchievementCatalog indexes the classic and package challenges by ref
+func buildAchievementCatalog(challenges models.ChallengeMap, packages models.PackageMap) map[string]achievementChallenge {
+	catalog := make(map[string]achievementChallenge)
+	for id, challenge := range challenges {
+		catalog[ClassicRef(id)] = achievementChallenge{track: ClassicTrack, tags: challenge.Tags}
+	}
+	for name, pkg := range packages {
+		for _, challengeID := range pkg.LearningPath {
+			challenge := achievementChallenge{track: name}
+			if info := pkg.ChallengeDetails[challengeID]; info != nil {
+				challenge.tags = info.Tags
+			}
+			catalog[PackageRef(name, challengeID)] = challenge
+		}
+	}
+	return catalog
+}
+
+// load returns a user's state, reading it from disk on first use
+func (as *AchievementService) load(username string) (*models.AchievementState, error) {
+	path, err := userStatePath(as.dir, username)
+	if err != nil {
+		return nil, err
+	}
+	if state, exists := as.states[username]; exists {
+		return state, nil
+	}
+
+	state := &models.AchievementState{Username: username}
+	if err := loadJSON(path, state); err != nil {
+		return nil, fmt.Errorf("failed to load achievements: %v", err)
+	}
+	if state.Earned == nil {
+		state.Earned = make(map[string]time.Time)
+	}
+	if state.Passed == nil {
+		state.Passed = make(map[string]time.Time)
+	}
+	if state.PassDays == nil {
+		state.PassDays = []string{}
+	}
+	if state.BestSpeedup == nil {
+		state.BestSpeedup = make(map[string]float64)
+	}
+
+	as.states[username] = state
+	return state, nil
+}
+
+// save writes a user's state to disk, replacing the previous file atomically
+func (as *AchievementService) save(state *models.AchievementState) error {
+	if err := writeJSONAtomic(filepath.Join(as.dir, state.Username+".json"), state); err != nil {
+		return fmt.Errorf("failed to save achievements: %v", err)
+	}
+	return nil
+}
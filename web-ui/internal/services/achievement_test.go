@@ -0,0 +1,191 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+// achievementTestCatalog returns classic challenges 1-3, of which 1 and 2 are tagged concurrency, and a one-challenge gin track
+func achievementTestCatalog() (models.ChallengeMap, models.PackageMap) {
+	challenges := models.ChallengeMap{
+		1: {ID: 1, Tags: []string{"concurrency"}},
+		2: {ID: 2, Tags: []string{"concurrency", "algorithms"}},
+		3: {ID: 3, Tags: []string{"algorithms"}},
+	}
+	packages := models.PackageMap{"gin": {LearningPath: []string{"challenge-1-routing"}}}
+	return challenges, packages
+}
+
+func TestConditionHolds(t *testing.T) {
+	challenges, packages := achievementTestCatalog()
+	catalog := buildAchievementCatalog(challenges, packages)
+	state := &models.AchievementState{
+		PassDays:    []string{"2026-01-01", "2026-01-02", "2026-01-03", "2026-01-05"},
+		BestSpeedup: map[string]float64{"classic/3": 2.5},
+	}
+	done := map[string]bool{"classic/1": true, "classic/2": true}
+
+	completed := func(track, tag string, min int) models.AchievementCondition {
+		return models.AchievementCondition{Type: models.ConditionCompleted, Track: track, Tag: tag, Min: min}
+	}
+	tests := []struct {
+		name      string
+		condition models.AchievementCondition
+		want      bool
+	}{
+		{"min reached", completed("classic", "", 2), true},
+		{"min not reached", completed("classic", "", 3), false},
+		{"every tagged challenge", completed("classic", "concurrency", 0), true},
+		{"not every tagged challenge", completed("classic", "algorithms", 0), false},
+		{"untouched track", completed("gin", "", 0), false},
+		{"named challenges", models.AchievementCondition{Type: models.ConditionCompleted, Challenges: []string{"classic/2"}}, true},
+		{"no matching challenge", completed("classic", "nope", 0), false},
+		{"speedup reached", models.AchievementCondition{Type: models.ConditionSpeedup, Challenge: "classic/3", MinSpeedup: 2}, true},
+		{"speedup not reached", models.AchievementCondition{Type: models.ConditionSpeedup, Challenge: "classic/3", MinSpeedup: 3}, false},
+		{"speedup never measured", models.AchievementCondition{Type: models.ConditionSpeedup, Challenge: "classic/1", MinSpeedup: 1}, false},
+		{"streak reached", models.AchievementCondition{Type: models.ConditionStreak, Days: 3}, true},
+		{"streak not reached", models.AchievementCondition{Type: models.ConditionStreak, Days: 4}, false},
+		{"all", models.AchievementCondition{Type: models.ConditionAll, Conditions: []models.AchievementCondition{completed("classic", "", 1), completed("gin", "", 0)}}, false},
+		{"any", models.AchievementCondition{Type: models.ConditionAny, Conditions: []models.AchievementCondition{completed("classic", "", 1), completed("gin", "", 0)}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conditionHolds(tt.condition, state, catalog, done); got != tt.want {
+				t.Errorf("conditionHolds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLongestStreak(t *testing.T) {
+	tests := []struct {
+		days []string
+		want int
+	}{
+		{nil, 0},
+		{[]string{"2026-03-01"}, 1},
+		{[]string{"2026-02-28", "2026-03-01", "2026-03-02"}, 3},
+		{[]string{"2026-03-01", "2026-03-03", "2026-03-04"}, 2},
+		{[]string{"2026-03-01", "garbage", "2026-03-02"}, 2},
+	}
+
+	for _, tt := range tests {
+		if got := longestStreak(tt.days); got != tt.want {
+			t.Errorf("longestStreak(%v) = %d, want %d", tt.days, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCondition(t *testing.T) {
+	challenges, packages := achievementTestCatalog()
+	catalog := buildAchievementCatalog(challenges, packages)
+
+	tests := []struct {
+		name      string
+		condition models.AchievementCondition
+		wantErr   bool
+	}{
+		{"completed package", models.AchievementCondition{Type: models.ConditionCompleted, Track: "gin"}, false},
+		{"unknown track", models.AchievementCondition{Type: models.ConditionCompleted, Track: "echo"}, true},
+		{"unknown challenge", models.AchievementCondition{Type: models.ConditionCompleted, Challenges: []string{"classic/9"}}, true},
+		{"negative min", models.AchievementCondition{Type: models.ConditionCompleted, Min: -1}, true},
+		{"speedup", models.AchievementCondition{Type: models.ConditionSpeedup, Challenge: "classic/1", MinSpeedup: 2}, false},
+		{"speedup without minimum", models.AchievementCondition{Type: models.ConditionSpeedup, Challenge: "classic/1"}, true},
+		{"empty streak", models.AchievementCondition{Type: models.ConditionStreak}, true},
+		{"empty all", models.AchievementCondition{Type: models.ConditionAll}, true},
+		{"invalid nested", models.AchievementCondition{Type: models.ConditionAny, Conditions: []models.AchievementCondition{{Type: "nope"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateCondition(tt.condition, catalog, packages); (err != nil) != tt.wantErr {
+				t.Errorf("validateCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newAchievementTestService returns a service with a first-challenge title and a two-day streak rule
+func newAchievementTestService(t *testing.T) (*AchievementService, string) {
+	t.Helper()
+	dir := t.TempDir()
+	rules := `[
+  {"id": "beginner", "name": "Beginner", "rank": 1, "condition": {"type": "completed", "track": "classic", "min": 1}},
+  {"id": "streak", "name": "Streak", "condition": {"type": "streak", "days": 2}}
+]`
+	rulesPath := filepath.Join(dir, "achievements.json")
+	if err := os.WriteFile(rulesPath, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	challenges, packages := achievementTestCatalog()
+	service := NewAchievementService(filepath.Join(dir, "achievements"), rulesPath)
+	if err := service.LoadRules(challenges, packages); err != nil {
+		t.Fatal(err)
+	}
+	return service, filepath.Join(dir, "achievements")
+}
+
+func TestAchievementsEarnedOnSubmission(t *testing.T) {
+	service, dir := newAchievementTestService(t)
+	challenges, packages := achievementTestCatalog()
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		submission AchievementSubmission
+		wantEarned []string
+	}{
+		{AchievementSubmission{Ref: "classic/1", Passed: false, At: day}, nil},
+		{AchievementSubmission{Ref: "classic/1", Passed: true, At: day}, []string{"beginner"}},
+		{AchievementSubmission{Ref: "classic/2", Passed: true, At: day.Add(time.Hour)}, nil},
+		{AchievementSubmission{Ref: "classic/3", Passed: true, At: day.AddDate(0, 0, 1)}, []string{"streak"}},
+	}
+
+	for i, step := range steps {
+		earned, err := service.RecordSubmission("alice", step.submission, challenges, packages, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, achievement := range earned {
+			ids = append(ids, achievement.ID)
+		}
+		if len(ids) != len(step.wantEarned) || (len(ids) > 0 && ids[0] != step.wantEarned[0]) {
+			t.Errorf("step %d earned %v, want %v", i, ids, step.wantEarned)
+		}
+	}
+
+	// A fresh service reads the stored state back, titles first
+	reloaded := NewAchievementService(dir, service.rulesPath)
+	reloaded.rules = service.Rules()
+	achievements, err := reloaded.Earned("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(achievements) != 2 || achievements[0].ID != "beginner" || !achievements[0].EarnedAt.Equal(day) {
+		t.Errorf("stored achievements = %+v", achievements)
+	}
+}
+
+func TestEarnedWritesNothing(t *testing.T) {
+	service, dir := newAchievementTestService(t)
+
+	achievements, err := service.Earned("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(achievements) != 0 {
+		t.Errorf("achievements = %+v, want none", achievements)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Earned wrote to %s", dir)
+	}
+	if _, err := service.Earned("../bob"); err == nil {
+		t.Error("Earned accepted an invalid username")
+	}
+}
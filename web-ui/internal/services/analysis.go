@@ -0,0 +1,159 @@
+package services
+
+import (
+	"fmt"
+	"go/format"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"web-ui/internal/models"
+)
+
+// analyzerCommands maps the analyzers that can be enabled to the command that runs them
+var analyzerCommands = map[string][]string{
+	"staticcheck": {"staticcheck", "./..."},
+	"errcheck":    {"errcheck", "./..."},
+	"ineffassign": {"ineffassign", "./..."},
+}
+
+// diagnosticLine matches "file.go:line:col: message" with an optional "vet: " prefix
+var diagnosticLine = regexp.MustCompile(`^(vet: )?(?:\./)?([^\s:]+\.go):(\d+):(?:(\d+):)?\s*(.*)$`)
+
+// analyzeSubmission runs gofmt, go vet and the configured analyzers in the run directory.
+// Only findings in submitted files are returned.
+func (es *ExecutionService) analyzeSubmission(runDir string, files models.SubmissionFiles, policy models.ExecutionPolicy) []models.Diagnostic {
+	diagnostics := checkFormatting(files)
+
+	vetOutput, _ := runTool(runDir, "go", "vet", "./...")
+	diagnostics = append(diagnostics, parseDiagnostics("vet", vetOutput, runDir, files, models.SeverityWarning)...)
+
+	for _, name := range es.analyzersFor(policy) {
+		command := analyzerCommands[name]
+		if _, err := exec.LookPath(command[0]); err != nil {
+			diagnostics = append(diagnostics, models.Diagnostic{
+				Tool:     name,
+				Message:  fmt.Sprintf("%s is not installed on the server, skipping", name),
+				Severity: models.SeverityInfo,
+			})
+			continue
+		}
+		output, _ := runTool(runDir, command[0], command[1:]...)
+		diagnostics = append(diagnostics, parseDiagnostics(name, output, runDir, files, models.SeverityWarning)...)
+	}
+
+	return diagnostics
+}
+
+// analyzersFor returns the server-wide analyzers plus those requested by the challenge
+func (es *ExecutionService) analyzersFor(policy models.ExecutionPolicy) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range append(append([]string{}, es.analyzers...), policy.Analyzers...) {
+		if _, known := analyzerCommands[name]; !known || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// SetAnalyzers configures the analyzers run on every submission in addition to go vet
+func (es *ExecutionService) SetAnalyzers(names []string) {
+	es.analyzers = names
+}
+
+// checkFormatting reports submitted Go files that are not gofmt-formatted
+func checkFormatting(files models.SubmissionFiles) []models.Diagnostic {
+	var diagnostics []models.Diagnostic
+	for _, name := range files.Names() {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		formatted, err := format.Source([]byte(files[name]))
+		if err != nil {
+			// Syntax errors are reported by go vet with a position
+			continue
+		}
+		if string(formatted) != files[name] {
+			diagnostics = append(diagnostics, models.Diagnostic{
+				Tool:     "gofmt",
+				File:     name,
+				Line:     firstDifferentLine(files[name], string(formatted)),
+				Column:   1,
+				Message:  "file is not gofmt-formatted",
+				Severity: models.SeverityInfo,
+			})
+		}
+	}
+	return diagnostics
+}
+
+// firstDifferentLine returns the first line number where two texts differ
+func firstDifferentLine(a, b string) int {
+	aLines := strings.Split(a, "\n")
+	bLines := strings.Split(b, "\n")
+	for i := range aLines {
+		if i >= len(bLines) || aLines[i] != bLines[i] {
+			return i + 1
+		}
+	}
+	return len(aLines)
+}
+
+// runTool runs a command in dir and returns its combined output
+func runTool(dir, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// parseDiagnostics extracts positioned findings for submitted files from tool output
+func parseDiagnostics(tool, output, runDir string, files models.SubmissionFiles, severity string) []models.Diagnostic {
+	var diagnostics []models.Diagnostic
+	for _, line := range strings.Split(output, "\n") {
+		match := diagnosticLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		file := filepath.ToSlash(match[2])
+		if rel, err := filepath.Rel(runDir, match[2]); err == nil && filepath.IsAbs(match[2]) {
+			file = filepath.ToSlash(rel)
+		}
+		if _, submitted := files[file]; !submitted {
+			continue
+		}
+
+		lineNum, _ := strconv.Atoi(match[3])
+		column, _ := strconv.Atoi(match[4])
+		diagnostic := models.Diagnostic{
+			Tool:     tool,
+			File:     file,
+			Line:     lineNum,
+			Column:   column,
+			Message:  match[5],
+			Severity: severity,
+		}
+		if match[1] != "" {
+			// "vet: " prefixed lines are type-checking failures
+			diagnostic.Severity = models.SeverityError
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// hasBlockingDiagnostics reports whether any vet or analyzer finding should fail a strict run
+func hasBlockingDiagnostics(diagnostics []models.Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == models.SeverityError || d.Severity == models.SeverityWarning {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"web-ui/internal/models"
+)
+
+func TestCheckFormatting(t *testing.T) {
+	tests := []struct {
+		name  string
+		files models.SubmissionFiles
+		want  []models.Diagnostic
+	}{
+		{"formatted", models.SubmissionFiles{"main.go": "package main\n\nfunc main() {}\n"}, nil},
+		{"unformatted", models.SubmissionFiles{"main.go": "package main\n\nfunc main()  {\n}\n"}, []models.Diagnostic{
+			{Tool: "gofmt", File: "main.go", Line: 3, Column: 1, Message: "file is not gofmt-formatted", Severity: models.SeverityInfo},
+		}},
+		// Syntax errors are left to go vet, which reports them with a position
+		{"syntax error", models.SubmissionFiles{"main.go": "package main\n\nfunc main( {\n"}, nil},
+		{"not go", models.SubmissionFiles{"schema.sql": "create  table t (id int);"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkFormatting(tt.files); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkFormatting() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDiagnostics(t *testing.T) {
+	runDir := filepath.Join(os.TempDir(), "run")
+	files := models.SubmissionFiles{"solution-template.go": "", "service/user.go": ""}
+
+	tests := []struct {
+		name   string
+		output string
+		want   []models.Diagnostic
+	}{
+		{"vet finding", "# example\n./solution-template.go:12:2: fmt.Printf format %d has arg s of wrong type string\n", []models.Diagnostic{
+			{Tool: "vet", File: "solution-template.go", Line: 12, Column: 2, Message: "fmt.Printf format %d has arg s of wrong type string", Severity: models.SeverityWarning},
+		}},
+		{"type error", "vet: ./solution-template.go:3:9: undefined: foo\n", []models.Diagnostic{
+			{Tool: "vet", File: "solution-template.go", Line: 3, Column: 9, Message: "undefined: foo", Severity: models.SeverityError},
+		}},
+		{"absolute path without column", filepath.Join(runDir, "service", "user.go") + ":7: unreachable code\n", []models.Diagnostic{
+			{Tool: "vet", File: "service/user.go", Line: 7, Message: "unreachable code", Severity: models.SeverityWarning},
+		}},
+		{"test file ignored", "./solution-template_test.go:4:1: unused variable\n", nil},
+		{"noise ignored", "ok  \texample\t0.01s\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseDiagnostics("vet", tt.output, runDir, files, models.SeverityWarning); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDiagnostics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasBlockingDiagnostics(t *testing.T) {
+	tests := []struct {
+		name       string
+		severities []string
+		want       bool
+	}{
+		{"none", nil, false},
+		{"info only", []string{models.SeverityInfo, models.SeverityInfo}, false},
+		{"warning", []string{models.SeverityInfo, models.SeverityWarning}, true},
+		{"error", []string{models.SeverityError}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diagnostics []models.Diagnostic
+			for _, severity := range tt.severities {
+				diagnostics = append(diagnostics, models.Diagnostic{Severity: severity})
+			}
+			if got := hasBlockingDiagnostics(diagnostics); got != tt.want {
+				t.Errorf("hasBlockingDiagnostics(%q) = %v, want %v", tt.severities, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzersFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		server    []string
+		challenge []string
+		want      []string
+	}{
+		{"none", nil, nil, nil},
+		{"server and challenge", []string{"staticcheck"}, []string{"errcheck"}, []string{"staticcheck", "errcheck"}},
+		{"deduplicated", []string{"errcheck"}, []string{"errcheck", "ineffassign"}, []string{"errcheck", "ineffassign"}},
+		{"unknown dropped", []string{"golint"}, []string{"rm -rf"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := NewExecutionService()
+			es.SetAnalyzers(tt.server)
+			if got := es.analyzersFor(models.ExecutionPolicy{Analyzers: tt.challenge}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("analyzersFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSubmissionRunsVet(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go vet")
+	}
+	runDir := t.TempDir()
+	files := models.SubmissionFiles{
+		"solution-template.go": "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Printf(\"%d\\n\", \"text\")\n}\n",
+	}
+	if err := WriteSubmissionFiles(runDir, files); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(runDir, "go.mod"), []byte("module example\n\ngo 1.22\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diagnostics := NewExecutionService().analyzeSubmission(runDir, files, models.ExecutionPolicy{})
+	if len(diagnostics) != 1 {
+		t.Fatalf("diagnostics = %+v, want the Printf mismatch", diagnostics)
+	}
+	if d := diagnostics[0]; d.Tool != "vet" || d.File != "solution-template.go" || d.Line != 6 || d.Severity != models.SeverityWarning {
+		t.Errorf("diagnostic = %+v", d)
+	}
+}
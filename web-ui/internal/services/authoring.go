@@ -0,0 +1,609 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"web-ui/internal/models"
+)
+
+// Difficulties accepted in challenge metadata
+var Difficulties = []string{"Beginner", "Intermediate", "Advanced"}
+
+// goIdentifier matches an exported Go identifier
+var goIdentifier = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+
+// AuthoringService scaffolds new challenges and validates challenge directories
+type AuthoringService struct {
+	challengeService *ChallengeService
+	packageService   *PackageService
+	executionService *ExecutionService
+}
+
+// NewAuthoringService creates a new authoring service
+func NewAuthoringService(challengeService *ChallengeService, packageService *PackageService, executionService *ExecutionService) *AuthoringService {
+	return &AuthoringService{
+		challengeService: challengeService,
+		packageService:   packageService,
+		executionService: executionService,
+	}
+}
+
+// ScaffoldOptions describes a challenge to generate
+type ScaffoldOptions struct {
+	Ref        string   // "classic/31" or "gin/challenge-5-file-uploads"
+	Title      string   // Shown in the README heading and on cards
+	Difficulty string   // One of Difficulties
+	Function   string   // Exported function the template asks for
+	Tags       []string // metadata.json tags
+}
+
+// scaffoldData is the data the scaffold templates are rendered with
+type scaffoldData struct {
+	ScaffoldOptions
+	Heading  string // README heading, "Challenge 31: Title" for classic challenges
+	Module   string // go.mod module path
+	Metadata string // Rendered metadata.json
+}
+
+// scaffoldFiles are the files a new challenge starts with, rendered from text templates
+var scaffoldFiles = map[string]string{
+	"README.md": `# {{.Heading}}
+
+## Problem Statement
+
+TODO: Describe the problem. Ask for a function ` + "`{{.Function}}`" + ` and explain what it must return.
+
+## Function Signature
+
+` + "```go" + `
+func {{.Function}}(input int) int
+` + "```" + `
+
+## Sample Input and Output
+
+TODO: Add at least two examples.
+
+## Testing Your Solution Locally
+
+Run the following command in the challenge directory:
+
+` + "```bash" + `
+go test -v
+` + "```" + `
+`,
+	"solution-template.go": `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println({{.Function}}(1))
+}
+
+// {{.Function}} TODO: describe what the function returns.
+func {{.Function}}(input int) int {
+	// TODO: Implement the function
+	return 0
+}
+`,
+	"solution-template_test.go": `package main
+
+import "testing"
+
+func Test{{.Function}}(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    int
+		expected int
+	}{
+		// TODO: Replace with real cases. The template must fail at least one of them.
+		{"Identity", 1, 1},
+		{"Larger input", 42, 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := {{.Function}}(tt.input); got != tt.expected {
+				t.Errorf("{{.Function}}(%d) = %d, want %d", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+`,
+	filepath.Join(referenceDirName, "reference.go"): `// Package reference is the hidden reference solution used for validation and differential testing
+package reference
+
+// {{.Function}} TODO: replace with a correct implementation.
+func {{.Function}}(input int) int {
+	return input
+}
+`,
+	"hints.md": `# Hints for {{.Title}}
+
+## Hint 1: Getting Started
+TODO: Point at the first step without giving the answer away.
+
+## Hint 2: Edge Cases
+TODO: Mention the inputs that are easy to get wrong.
+`,
+	"learning.md": `# Learning Materials for {{.Title}}
+
+## Key Concepts
+
+TODO: Explain the Go concepts the challenge practises, with short examples.
+
+## Further Reading
+
+- [Effective Go](https://go.dev/doc/effective_go)
+`,
+	"go.mod": `module {{.Module}}
+
+go 1.22
+`,
+	"metadata.json": `{{.Metadata}}
+`,
+}
+
+// Scaffold writes a new challenge directory and returns the paths it created.
+// A package challenge is also appended to its track's learning path.
+func (s *AuthoringService) Scaffold(opts ScaffoldOptions) ([]string, error) {
+	if strings.TrimSpace(opts.Title) == "" {
+		return nil, fmt.Errorf("a title is required")
+	}
+	if opts.Function == "" {
+		opts.Function = "Solve"
+	}
+	if !goIdentifier.MatchString(opts.Function) {
+		return nil, fmt.Errorf("function %q is not an exported Go identifier", opts.Function)
+	}
+	if opts.Difficulty == "" {
+		opts.Difficulty = Difficulties[0]
+	}
+	if !validDifficulty(opts.Difficulty) {
+		return nil, fmt.Errorf("difficulty must be one of %s", strings.Join(Difficulties, ", "))
+	}
+
+	track, challengeID, found := strings.Cut(opts.Ref, "/")
+	if !found || !submissionPathSegment.MatchString(track) || !submissionPathSegment.MatchString(challengeID) {
+		return nil, fmt.Errorf("invalid challenge ref %q", opts.Ref)
+	}
+
+	data := scaffoldData{ScaffoldOptions: opts, Heading: opts.Title}
+	metadata := struct {
+		Title            string   `json:"title"`
+		ShortDescription string   `json:"short_description,omitempty"`
+		Difficulty       string   `json:"difficulty"`
+		EstimatedTime    string   `json:"estimated_time,omitempty"`
+		Tags             []string `json:"tags"`
+	}{Title: opts.Title, Difficulty: opts.Difficulty, Tags: append([]string{}, opts.Tags...)}
+	var dir string
+	if track == ClassicTrack {
+		id, err := strconv.Atoi(challengeID)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid classic challenge number %q", challengeID)
+		}
+		dir = fmt.Sprintf("../challenge-%d", id)
+		data.Heading = fmt.Sprintf("Challenge %d: %s", id, opts.Title)
+		data.Module = fmt.Sprintf("challenge%d", id)
+	} else {
+		if !strings.HasPrefix(challengeID, "challenge-") {
+			return nil, fmt.Errorf("package challenge %q must be named challenge-N-name", challengeID)
+		}
+		if _, err := readPackageMetadata(filepath.Join(s.packageService.packagesPath, track)); err != nil {
+			return nil, fmt.Errorf("package %s not found: %v", track, err)
+		}
+		dir = filepath.Join(s.packageService.packagesPath, track, challengeID)
+		data.Module = track + "-" + challengeID
+		metadata.ShortDescription = "TODO: one sentence for the challenge card"
+		metadata.EstimatedTime = "45-60 min"
+	}
+
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%s already exists", dir)
+	}
+
+	metadataJSON, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	data.Metadata = string(metadataJSON)
+
+	names := make([]string, 0, len(scaffoldFiles))
+	for name := range scaffoldFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	created := []string{}
+	for _, name := range names {
+		var buf bytes.Buffer
+		if err := template.Must(template.New(name).Parse(scaffoldFiles[name])).Execute(&buf, data); err != nil {
+			return created, fmt.Errorf("failed to render %s: %v", name, err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return created, fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return created, fmt.Errorf("failed to write %s: %v", path, err)
+		}
+		created = append(created, path)
+	}
+
+	if track != ClassicTrack {
+		packageJSON := filepath.Join(s.packageService.packagesPath, track, "package.json")
+		if err := appendToLearningPath(packageJSON, challengeID); err != nil {
+			return created, err
+		}
+		created = append(created, packageJSON)
+	}
+
+	return created, nil
+}
+
+// learningPathList matches the learning_path array of a package.json
+var learningPathList = regexp.MustCompile(`(?s)("learning_path"\s*:\s*\[)(.*?)(\s*\])`)
+
+// appendToLearningPath adds a challenge to the end of a track's learning path, keeping the file's layout
+func appendToLearningPath(packageJSON, challengeID string) error {
+	content, err := os.ReadFile(packageJSON)
+	if err != nil {
+		return err
+	}
+	match := learningPathList.FindSubmatchIndex(content)
+	if match == nil {
+		return fmt.Errorf("%s has no learning_path", packageJSON)
+	}
+
+	items := string(content[match[4]:match[5]])
+	entry := strconv.Quote(challengeID)
+	if strings.TrimSpace(items) == "" {
+		items = "\n    " + entry
+	} else {
+		// Match the indentation of the last entry, or stay on one line
+		separator := ", "
+		if i := strings.LastIndex(items, "\n"); i >= 0 {
+			last := items[i+1:]
+			separator = ",\n" + last[:len(last)-len(strings.TrimLeft(last, " \t"))]
+		}
+		items += separator + entry
+	}
+
+	updated := append([]byte{}, content[:match[4]]...)
+	updated = append(updated, items...)
+	updated = append(updated, content[match[5]:]...)
+
+	var check PackageMetadata
+	if err := json.Unmarshal(updated, &check); err != nil {
+		return fmt.Errorf("failed to update %s: %v", packageJSON, err)
+	}
+	return os.WriteFile(packageJSON, updated, 0644)
+}
+
+// ValidationCheck is the outcome of one validation step
+type ValidationCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ValidationReport collects the checks run against one challenge
+type ValidationReport struct {
+	Ref    string            `json:"ref"`
+	Checks []ValidationCheck `json:"checks"`
+}
+
+// Passed reports whether no check failed
+func (r ValidationReport) Passed() bool {
+	for _, check := range r.Checks {
+		if !check.Passed && !check.Skipped {
+			return false
+		}
+	}
+	return true
+}
+
+// add records a check, which passes if there are no problems
+func (r *ValidationReport) add(name string, problems ...string) {
+	r.Checks = append(r.Checks, ValidationCheck{Name: name, Passed: len(problems) == 0, Message: strings.Join(problems, "; ")})
+}
+
+// skip records a check that could not run
+func (r *ValidationReport) skip(name, reason string) {
+	r.Checks = append(r.Checks, ValidationCheck{Name: name, Skipped: true, Message: reason})
+}
+
+// Validate checks a challenge's metadata and title, that its template compiles but fails the tests,
+// and that a solution passes them. Without a solution the challenge's reference/ directory is used.
+func (s *AuthoringService) Validate(challenge *models.TrackChallenge, solution models.SubmissionFiles) ValidationReport {
+	report := ValidationReport{Ref: challenge.Ref}
+
+	_, problems := checkChallengeMetadata(challenge.Dir, challenge.Track != ClassicTrack)
+	report.add("metadata", problems...)
+	report.add("title", s.checkTitle(challenge)...)
+
+	template := models.SubmissionFiles{challenge.SolutionFile: challenge.Template}
+	result := s.executionService.RunFiles(template, challenge)
+	if failure := buildFailure(result.Output); failure != "" {
+		report.add("template compiles", failure)
+		report.skip("template fails tests", "the template does not compile")
+	} else {
+		report.add("template compiles")
+		if result.Passed {
+			report.add("template fails tests", "the unmodified template passes every test")
+		} else {
+			report.add("template fails tests")
+		}
+	}
+
+	if solution == nil {
+		var err error
+		solution, err = referenceSolution(challenge)
+		if err != nil {
+			report.skip("reference passes tests", err.Error())
+			return report
+		}
+	}
+	result = s.executionService.RunWithOptions(solution, challenge, RunOptions{IncludeHidden: true})
+	if result.Passed {
+		report.add("reference passes tests")
+	} else {
+		report.add("reference passes tests", "the reference solution fails:\n"+strings.TrimSpace(result.Output))
+	}
+
+	return report
+}
+
+// ValidateAll validates every classic and package challenge in the repository
+func (s *AuthoringService) ValidateAll() []ValidationReport {
+	var reports []ValidationReport
+
+	challenges := s.challengeService.GetChallenges()
+	ids := make([]int, 0, len(challenges))
+	for id := range challenges {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		reports = append(reports, s.Validate(ClassicChallenge(challenges[id]), nil))
+	}
+
+	packages := s.packageService.GetPackages()
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, challengeID := range packages[name].LearningPath {
+			challenge, err := s.packageService.GetPackageChallenge(name, challengeID)
+			if err != nil {
+				continue // Listed as coming soon
+			}
+			reports = append(reports, s.Validate(PackageTrackChallenge(challenge), nil))
+		}
+	}
+
+	return reports
+}
+
+// checkTitle checks that ChallengeService finds the title in the README's first heading
+func (s *AuthoringService) checkTitle(challenge *models.TrackChallenge) []string {
+	readme, err := os.ReadFile(filepath.Join(challenge.Dir, "README.md"))
+	if err != nil {
+		return []string{fmt.Sprintf("could not read README.md: %v", err)}
+	}
+
+	var heading string
+	for _, line := range strings.Split(string(readme), "\n") {
+		if strings.HasPrefix(line, "#") {
+			heading = line
+			break
+		}
+	}
+	if !strings.HasPrefix(heading, "# ") {
+		return []string{"README.md must start with a level 1 heading before any other heading"}
+	}
+
+	id, _ := strconv.Atoi(challenge.ID)
+	title := s.challengeService.extractTitle(string(readme), id)
+	switch {
+	case strings.TrimSpace(title) == "":
+		return []string{"the README heading is empty"}
+	case challenge.Track == ClassicTrack && title == fmt.Sprintf("Challenge %d", id):
+		return []string{"no title could be extracted from README.md"}
+	case challenge.Track == ClassicTrack && !strings.HasPrefix(heading, fmt.Sprintf("# Challenge %d: ", id)):
+		return []string{fmt.Sprintf("the README heading should read \"# Challenge %d: <title>\"", id)}
+	case strings.Contains(title, "TODO"):
+		return []string{"the title still contains TODO"}
+	}
+	return nil
+}
+
+// checkChallengeMetadata validates a challenge's metadata.json. Package challenges also need a title and difficulty.
+// metadata.json may carry descriptive fields the UI doesn't read, so only known fields are checked.
+func checkChallengeMetadata(dir string, requireCard bool) (*models.ChallengeMetadata, []string) {
+	var metadata models.ChallengeMetadata
+	data, err := os.ReadFile(filepath.Join(dir, "metadata.json"))
+	if os.IsNotExist(err) && !requireCard {
+		return &metadata, nil
+	}
+	if err != nil {
+		return &metadata, []string{fmt.Sprintf("metadata.json: %v", err)}
+	}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return &metadata, []string{fmt.Sprintf("metadata.json: %v", err)}
+	}
+
+	var problems []string
+	if requireCard && metadata.Title == "" {
+		problems = append(problems, "metadata.json: title is required")
+	}
+	if requireCard && metadata.Difficulty == "" {
+		problems = append(problems, "metadata.json: difficulty is required")
+	}
+	if metadata.Difficulty != "" && !validDifficulty(metadata.Difficulty) {
+		problems = append(problems, fmt.Sprintf("metadata.json: difficulty %q is not one of %s", metadata.Difficulty, strings.Join(Difficulties, ", ")))
+	}
+	if metadata.Icon != "" && !strings.HasPrefix(metadata.Icon, "bi-") {
+		problems = append(problems, fmt.Sprintf("metadata.json: icon %q is not a Bootstrap icon class", metadata.Icon))
+	}
+	for _, module := range metadata.Dependencies.Modules {
+		if !isModulePath(module) {
+			problems = append(problems, fmt.Sprintf("metadata.json: dependencies.modules entry %q is not a module path", module))
+		}
+	}
+	if benchmark := metadata.Execution.Benchmark; benchmark != nil {
+		if err := checkBenchmarkPolicy(benchmark); err != nil {
+			problems = append(problems, fmt.Sprintf("metadata.json: %v", err))
+		}
+		for _, name := range append([]string{benchmark.Baseline}, benchmark.BaselineCode...) {
+			if name == "" {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+				problems = append(problems, fmt.Sprintf("metadata.json: benchmark baseline %s not found", name))
+			}
+		}
+	}
+	if quality := metadata.Execution.Quality; quality != nil {
+		problems = append(problems, checkQualityPolicy(quality)...)
+	}
+	return &metadata, problems
+}
+
+// checkQualityPolicy checks that a quality policy's weights and targets are usable
+func checkQualityPolicy(quality *models.QualityPolicy) []string {
+	var problems []string
+	if w := quality.Weights; w != nil {
+		weights := []float64{w.Complexity, w.FunctionLength, w.Findings, w.Allocations, w.Runtime}
+		total := 0.0
+		for _, weight := range weights {
+			if weight < 0 {
+				problems = append(problems, "metadata.json: quality weights must not be negative")
+				break
+			}
+			total += weight
+		}
+		if total == 0 {
+			problems = append(problems, "metadata.json: quality weights are all zero")
+		}
+	}
+	for _, target := range []float64{quality.MaxComplexity, quality.MaxFunctionLines, quality.MaxAllocs, quality.MaxTestMs} {
+		if target < 0 {
+			problems = append(problems, "metadata.json: quality targets must not be negative")
+			break
+		}
+	}
+	return problems
+}
+
+// validDifficulty reports whether difficulty is one of Difficulties
+func validDifficulty(difficulty string) bool {
+	for _, d := range Difficulties {
+		if d == difficulty {
+			return true
+		}
+	}
+	return false
+}
+
+// buildFailure returns the compiler output of a run whose code did not build, or ""
+func buildFailure(output string) string {
+	if strings.Contains(output, "[build failed]") || strings.Contains(output, "[setup failed]") ||
+		strings.HasPrefix(output, "Failed to") || strings.HasPrefix(output, "Invalid submission") {
+		return strings.TrimSpace(output)
+	}
+	return ""
+}
+
+// packageClause matches the package clause of a Go file
+var packageClause = regexp.MustCompile(`(?m)^package\s+\w+`)
+
+// referenceSolution turns the challenge's reference/ directory into a submission in the template's package
+func referenceSolution(challenge *models.TrackChallenge) (models.SubmissionFiles, error) {
+	if !HasReference(challenge.Dir) {
+		return nil, fmt.Errorf("no %s/ directory, pass a solution to check that the tests can pass", referenceDirName)
+	}
+
+	fset := token.NewFileSet()
+	templateFile, err := parser.ParseFile(fset, challenge.SolutionFile, challenge.Template, parser.PackageClauseOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the template: %v", err)
+	}
+	packageName := templateFile.Name.Name
+
+	paths, err := filepath.Glob(filepath.Join(challenge.Dir, referenceDirName, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	files := models.SubmissionFiles{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Base(path)] = packageClause.ReplaceAllString(string(content), "package "+packageName)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s/ has no Go files", referenceDirName)
+	}
+
+	// A main package needs a main function even though the tests never call it
+	if packageName == "main" && !regexp.MustCompile(`(?m)^func main\(\)`).MatchString(files.GoSource()) {
+		names := files.Names()
+		files[names[0]] += "\nfunc main() {}\n"
+	}
+	if len(files) == 1 {
+		for _, content := range files {
+			return models.SubmissionFiles{challenge.SolutionFile: content}, nil
+		}
+	}
+	return files, nil
+}
+
+// SolutionFromPath reads a solution file, or every non-test Go file of a directory, as a submission
+func SolutionFromPath(challenge *models.TrackChallenge, path string) (models.SubmissionFiles, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return models.SubmissionFiles{challenge.SolutionFile: string(content)}, nil
+	}
+
+	files := models.SubmissionFiles{}
+	paths, _ := filepath.Glob(filepath.Join(path, "*.go"))
+	for _, file := range paths {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Base(file)] = string(content)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", path)
+	}
+	return files, nil
+}
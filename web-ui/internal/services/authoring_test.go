@@ -0,0 +1,366 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"web-ui/internal/models"
+)
+
+// authoringWorkspace creates a repository with an empty gin track and changes into its web-ui directory,
+// which is where the services find challenges and packages
+func authoringWorkspace(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, dir := range []string{"web-ui", filepath.Join("packages", "gin")} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	packageJSON := "{\n  \"name\": \"gin\",\n  \"learning_path\": [\n    \"challenge-1-routing\"\n  ]\n}\n"
+	if err := os.WriteFile(filepath.Join(root, "packages", "gin", "package.json"), []byte(packageJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(root, "web-ui")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return root
+}
+
+func newTestAuthoringService() *AuthoringService {
+	return NewAuthoringService(NewChallengeService(), NewPackageService(), NewExecutionService())
+}
+
+func TestScaffoldRejects(t *testing.T) {
+	root := authoringWorkspace(t)
+	os.MkdirAll(filepath.Join(root, "challenge-2"), 0755)
+	s := newTestAuthoringService()
+
+	tests := []struct {
+		name    string
+		opts    ScaffoldOptions
+		wantErr string
+	}{
+		{"no title", ScaffoldOptions{Ref: "classic/31", Title: " "}, "title is required"},
+		{"unexported function", ScaffoldOptions{Ref: "classic/31", Title: "Sum", Function: "sum"}, "not an exported Go identifier"},
+		{"unknown difficulty", ScaffoldOptions{Ref: "classic/31", Title: "Sum", Difficulty: "Expert"}, "difficulty must be one of"},
+		{"no track", ScaffoldOptions{Ref: "31", Title: "Sum"}, "invalid challenge ref"},
+		{"path traversal", ScaffoldOptions{Ref: "classic/../31", Title: "Sum"}, "invalid challenge ref"},
+		{"classic number", ScaffoldOptions{Ref: "classic/0", Title: "Sum"}, "invalid classic challenge number"},
+		{"package challenge name", ScaffoldOptions{Ref: "gin/routing", Title: "Routing"}, "must be named challenge-N-name"},
+		{"unknown package", ScaffoldOptions{Ref: "echo/challenge-1-routing", Title: "Routing"}, "package echo not found"},
+		{"existing challenge", ScaffoldOptions{Ref: "classic/2", Title: "Sum"}, "already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := s.Scaffold(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Scaffold() error = %v, want one containing %q", err, tt.wantErr)
+			}
+			if len(created) != 0 {
+				t.Errorf("Scaffold() wrote %q", created)
+			}
+		})
+	}
+}
+
+func TestScaffoldWritesChallenge(t *testing.T) {
+	root := authoringWorkspace(t)
+	s := newTestAuthoringService()
+
+	tests := []struct {
+		name        string
+		opts        ScaffoldOptions
+		dir         string
+		wantHeading string
+		wantModule  string
+		wantCard    bool // Package challenges get card fields
+	}{
+		{"classic", ScaffoldOptions{Ref: "classic/31", Title: "Sum", Function: "Sum", Tags: []string{"basics"}}, "challenge-31", "# Challenge 31: Sum\n", "module challenge31\n", false},
+		{"package", ScaffoldOptions{Ref: "gin/challenge-2-uploads", Title: "File Uploads", Difficulty: "Intermediate"}, "packages/gin/challenge-2-uploads", "# File Uploads\n", "module gin-challenge-2-uploads\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := s.Scaffold(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dir := filepath.Join(root, tt.dir)
+			for _, name := range []string{"README.md", "solution-template.go", "solution-template_test.go", "reference/reference.go", "hints.md", "learning.md", "go.mod", "metadata.json"} {
+				if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+					t.Errorf("%s not written: %v", name, err)
+				}
+			}
+			if len(created) < len(scaffoldFiles) {
+				t.Errorf("Scaffold() = %q, want every scaffold file", created)
+			}
+
+			readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+			goMod, _ := os.ReadFile(filepath.Join(dir, "go.mod"))
+			if !strings.HasPrefix(string(readme), tt.wantHeading) || !strings.HasPrefix(string(goMod), tt.wantModule) {
+				t.Errorf("README starts %q, go.mod starts %q", strings.SplitN(string(readme), "\n", 2)[0], strings.SplitN(string(goMod), "\n", 2)[0])
+			}
+			metadata, problems := checkChallengeMetadata(dir, tt.wantCard)
+			if len(problems) > 0 || metadata.Title != tt.opts.Title || (metadata.ShortDescription != "") != tt.wantCard {
+				t.Errorf("metadata = %+v, problems = %q", metadata, problems)
+			}
+		})
+	}
+
+	// The package challenge is appended to the track's learning path
+	metadata, err := readPackageMetadata(filepath.Join(root, "packages", "gin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"challenge-1-routing", "challenge-2-uploads"}; !reflect.DeepEqual(metadata.LearningPath, want) {
+		t.Errorf("learning_path = %q, want %q", metadata.LearningPath, want)
+	}
+}
+
+func TestAppendToLearningPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"empty", `{"learning_path": []}`, "{\"learning_path\": [\n    \"challenge-2\"]}", false},
+		{"one line", `{"learning_path": ["challenge-1"]}`, `{"learning_path": ["challenge-1", "challenge-2"]}`, false},
+		{"indented", "{\n  \"learning_path\": [\n    \"challenge-1\"\n  ]\n}", "{\n  \"learning_path\": [\n    \"challenge-1\",\n    \"challenge-2\"\n  ]\n}", false},
+		{"no learning path", `{"name": "gin"}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "package.json")
+			os.WriteFile(path, []byte(tt.content), 0644)
+			err := appendToLearningPath(path, "challenge-2")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("appendToLearningPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got, _ := os.ReadFile(path); !tt.wantErr && string(got) != tt.want {
+				t.Errorf("package.json = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckChallengeMetadata(t *testing.T) {
+	tests := []struct {
+		name         string
+		metadata     string // "" writes no metadata.json
+		requireCard  bool
+		wantProblems []string
+	}{
+		{"optional for classic", "", false, nil},
+		{"required for packages", "", true, []string{"metadata.json: open"}},
+		{"invalid JSON", "{", false, []string{"metadata.json: unexpected end of JSON input"}},
+		{"card fields", `{"tags": ["x"]}`, true, []string{"title is required", "difficulty is required"}},
+		{"unknown fields ignored", `{"title": "Sum", "difficulty": "Beginner", "author": "alice"}`, true, nil},
+		{"difficulty", `{"difficulty": "Expert"}`, false, []string{`difficulty "Expert" is not one of`}},
+		{"icon", `{"icon": "fa-star"}`, false, []string{"not a Bootstrap icon class"}},
+		{"module path", `{"dependencies": {"modules": ["not a module"]}}`, false, []string{"is not a module path"}},
+		{"benchmark baseline", `{"execution": {"benchmark": {"baseline": "baseline.txt"}}}`, false, []string{"benchmark baseline baseline.txt not found"}},
+		{"benchmark policy", `{"execution": {"benchmark": {"min_speedup": 2}}}`, false, []string{"need a baseline"}},
+		{"quality weights", `{"execution": {"quality": {"weights": {"complexity": 0}}}}`, false, []string{"quality weights are all zero"}},
+		{"quality targets", `{"execution": {"quality": {"max_complexity": -1}}}`, false, []string{"quality targets must not be negative"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.metadata != "" {
+				os.WriteFile(filepath.Join(dir, "metadata.json"), []byte(tt.metadata), 0644)
+			}
+			_, problems := checkChallengeMetadata(dir, tt.requireCard)
+			if len(problems) != len(tt.wantProblems) {
+				t.Fatalf("problems = %q, want %d containing %q", problems, len(tt.wantProblems), tt.wantProblems)
+			}
+			for i, want := range tt.wantProblems {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("problem %d = %q, want one containing %q", i, problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckTitle(t *testing.T) {
+	s := newTestAuthoringService()
+
+	tests := []struct {
+		name    string
+		track   string
+		readme  string
+		wantErr string
+	}{
+		{"classic", ClassicTrack, "# Challenge 7: Bank Account\n", ""},
+		{"package", "gin", "# Routing Basics\n\n## Setup\n", ""},
+		{"no heading", ClassicTrack, "Bank Account\n", "level 1 heading"},
+		{"subheading first", "gin", "## Routing\n# Routing Basics\n", "level 1 heading"},
+		{"classic without number", ClassicTrack, "# Bank Account\n", `should read "# Challenge 7: <title>"`},
+		{"todo", "gin", "# TODO: name this challenge\n", "still contains TODO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.WriteFile(filepath.Join(dir, "README.md"), []byte(tt.readme), 0644)
+			problems := s.checkTitle(&models.TrackChallenge{Track: tt.track, ID: "7", Dir: dir})
+			if got := strings.Join(problems, "; "); (tt.wantErr == "") != (got == "") || !strings.Contains(got, tt.wantErr) {
+				t.Errorf("checkTitle() = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidationReportPassed(t *testing.T) {
+	var report ValidationReport
+	report.add("metadata")
+	report.skip("reference passes tests", "no reference/ directory")
+	if !report.Passed() {
+		t.Errorf("report with a skipped check = %+v, want passed", report)
+	}
+	report.add("title", "the title still contains TODO", "second problem")
+	if report.Passed() || report.Checks[2].Message != "the title still contains TODO; second problem" {
+		t.Errorf("report with a failed check = %+v", report)
+	}
+
+	encoded, _ := json.Marshal(report.Checks[0])
+	if string(encoded) != `{"name":"metadata","passed":true}` {
+		t.Errorf("check JSON = %s", encoded)
+	}
+}
+
+func TestReferenceSolution(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		reference map[string]string
+		want      models.SubmissionFiles
+		wantErr   bool
+	}{
+		{
+			"main package gets main",
+			"package main\n",
+			map[string]string{"reference.go": "package reference\n\nfunc Sum() int { return 1 }\n"},
+			models.SubmissionFiles{"solution-template.go": "package main\n\nfunc Sum() int { return 1 }\n\nfunc main() {}\n"},
+			false,
+		},
+		{
+			"library package keeps file names",
+			"package cache\n",
+			map[string]string{"a.go": "package reference\n", "b.go": "package reference\n", "a_test.go": "package reference\n"},
+			models.SubmissionFiles{"a.go": "package cache\n", "b.go": "package cache\n"},
+			false,
+		},
+		{"no reference", "package main\n", nil, nil, true},
+		{"no Go files", "package main\n", map[string]string{"README.md": "notes"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.reference {
+				os.MkdirAll(filepath.Join(dir, referenceDirName), 0755)
+				os.WriteFile(filepath.Join(dir, referenceDirName, name), []byte(content), 0644)
+			}
+			challenge := &models.TrackChallenge{Dir: dir, SolutionFile: "solution-template.go", Template: tt.template}
+			got, err := referenceSolution(challenge)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("referenceSolution() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("referenceSolution() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFailure(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"# challenge\n./solution.go:3:1: syntax error\nFAIL\tchallenge [build failed]\n", true},
+		{"FAIL\tchallenge [setup failed]\n", true},
+		{"Failed to create temporary directory", true},
+		{"Invalid submission: bad path", true},
+		{"--- FAIL: TestSum (0.00s)\nFAIL\n", false},
+		{"ok\tchallenge\t0.01s\n", false},
+	}
+
+	for _, tt := range tests {
+		if got := buildFailure(tt.output) != ""; got != tt.want {
+			t.Errorf("buildFailure(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestValidateScaffoldedChallenge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	root := authoringWorkspace(t)
+	s := newTestAuthoringService()
+	if _, err := s.Scaffold(ScaffoldOptions{Ref: "classic/31", Title: "Identity", Function: "Identity"}); err != nil {
+		t.Fatal(err)
+	}
+	classic, err := s.challengeService.loadSingleChallenge(31, "../challenge-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge := ClassicChallenge(classic)
+
+	type check struct {
+		Name    string
+		Passed  bool
+		Skipped bool
+	}
+	tests := []struct {
+		name     string
+		solution models.SubmissionFiles
+		setup    func()
+		want     []check
+	}{
+		// A fresh scaffold is valid: the template returns 0 and the reference returns its input
+		{"scaffold", nil, nil, []check{{"metadata", true, false}, {"title", true, false}, {"template compiles", true, false}, {"template fails tests", true, false}, {"reference passes tests", true, false}}},
+		{"wrong solution", models.SubmissionFiles{"solution-template.go": "package main\n\nfunc Identity(input int) int { return -input }\n\nfunc main() {}\n"}, nil,
+			[]check{{"metadata", true, false}, {"title", true, false}, {"template compiles", true, false}, {"template fails tests", true, false}, {"reference passes tests", false, false}}},
+		{"template passes", nil, func() {
+			challenge.Template = "package main\n\nfunc Identity(input int) int { return input }\n\nfunc main() {}\n"
+		}, []check{{"metadata", true, false}, {"title", true, false}, {"template compiles", true, false}, {"template fails tests", false, false}, {"reference passes tests", true, false}}},
+		{"template broken, no reference", nil, func() {
+			challenge.Template = "package main\n\nfunc Identity(input int) int {\n"
+			os.RemoveAll(filepath.Join(root, "challenge-31", referenceDirName))
+		}, []check{{"metadata", true, false}, {"title", true, false}, {"template compiles", false, false}, {"template fails tests", false, true}, {"reference passes tests", false, true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+			report := s.Validate(challenge, tt.solution)
+			var got []check
+			for _, c := range report.Checks {
+				got = append(got, check{c.Name, c.Passed, c.Skipped})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checks = %+v, want %+v", report.Checks, tt.want)
+			}
+		})
+	}
+}
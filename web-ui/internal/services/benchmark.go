@@ -0,0 +1,371 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"web-ui/internal/models"
+)
+
+const (
+	// defaultBenchmarkCount is the number of samples taken per benchmark
+	defaultBenchmarkCount = 5
+	// maxBenchmarkCount bounds the samples a caller may request
+	maxBenchmarkCount = 20
+	// benchmarkAlpha is the significance level for speedup comparisons
+	benchmarkAlpha = 0.05
+)
+
+// BenchmarkReport holds parsed benchmark results and comparisons against the baseline
+type BenchmarkReport struct {
+	Results         []BenchmarkStats      `json:"results"`
+	Comparisons     []BenchmarkComparison `json:"comparisons"`
+	GeomeanSpeedup  float64               `json:"geomeanSpeedup"`  // Geometric mean of all comparison speedups
+	MinSpeedup      float64               `json:"minSpeedup"`      // Required speedup from challenge metadata, 0 if none
+	MeetsMinSpeedup bool                  `json:"meetsMinSpeedup"` // Whether GeomeanSpeedup reached MinSpeedup
+}
+
+// BenchmarkStats summarizes the samples of one benchmark using medians
+type BenchmarkStats struct {
+	Name        string    `json:"name"`
+	Samples     int       `json:"samples"`
+	NsPerOp     float64   `json:"nsPerOp"`
+	BytesPerOp  float64   `json:"bytesPerOp"`
+	AllocsPerOp float64   `json:"allocsPerOp"`
+	nsSamples   []float64 // raw ns/op samples for the significance test
+}
+
+// BenchmarkComparison compares a solution benchmark against its baseline
+type BenchmarkComparison struct {
+	Benchmark           string  `json:"benchmark"`
+	Baseline            string  `json:"baseline"`
+	NsPerOp             float64 `json:"nsPerOp"`
+	BaselineNsPerOp     float64 `json:"baselineNsPerOp"`
+	Speedup             float64 `json:"speedup"` // baseline ns/op divided by solution ns/op
+	AllocsPerOp         float64 `json:"allocsPerOp"`
+	BaselineAllocsPerOp float64 `json:"baselineAllocsPerOp"`
+	PValue              float64 `json:"pValue"`
+	Significant         bool    `json:"significant"` // false means the difference is within noise ("~" in benchstat)
+	Samples             int     `json:"samples"`
+	BaselineSamples     int     `json:"baselineSamples"`
+}
+
+// benchmarkLine matches a go test -bench result line with optional -benchmem columns
+var benchmarkLine = regexp.MustCompile(`^Benchmark(\S+?)(?:-\d+)?\s+\d+\s+([\d.]+) ns/op(?:\s+([\d.]+) B/op)?(?:\s+([\d.]+) allocs/op)?`)
+
+// benchmarkArgs builds the go test arguments for a benchmark run of the benchmarks matching pattern
+func benchmarkArgs(policy *models.BenchmarkPolicy, count int, pattern string) []string {
+	if count <= 0 && policy != nil {
+		count = policy.Count
+	}
+	if count <= 0 {
+		count = defaultBenchmarkCount
+	}
+	if count > maxBenchmarkCount {
+		count = maxBenchmarkCount
+	}
+
+	args := []string{"test", "-run=^$", "-bench=" + pattern, "-benchmem", "-count=" + strconv.Itoa(count)}
+	if policy != nil && policy.Benchtime != "" {
+		args = append(args, "-benchtime="+policy.Benchtime)
+	}
+	return args
+}
+
+// benchmarkPattern returns the -bench pattern matching the named top-level benchmarks, or every benchmark
+func benchmarkPattern(names []string) string {
+	if len(names) == 0 {
+		return "."
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	sort.Strings(quoted)
+	return "^Benchmark(" + strings.Join(quoted, "|") + ")$"
+}
+
+// solutionBenchmarks returns the benchmarks a submission's bench run measures, every benchmark if empty.
+// With explicit comparisons only the solution side runs, so the submission's copy of a baseline is never measured.
+func solutionBenchmarks(policy *models.BenchmarkPolicy) []string {
+	if policy == nil {
+		return nil
+	}
+	names := make([]string, 0, len(policy.Comparisons))
+	for solution := range policy.Comparisons {
+		names = append(names, solution)
+	}
+	return names
+}
+
+// baselineBenchmarks returns the benchmarks the baseline code run measures, every benchmark if empty
+func baselineBenchmarks(policy *models.BenchmarkPolicy) []string {
+	seen := make(map[string]bool, len(policy.Comparisons))
+	names := make([]string, 0, len(policy.Comparisons))
+	for _, base := range policy.Comparisons {
+		if !seen[base] {
+			seen[base] = true
+			names = append(names, base)
+		}
+	}
+	return names
+}
+
+// checkBenchmarkPolicy rejects policies that compare against a baseline without saying where it comes from.
+// Baselines must come from the challenge, never from the submission being measured.
+func checkBenchmarkPolicy(policy *models.BenchmarkPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.Baseline != "" && len(policy.BaselineCode) > 0 {
+		return fmt.Errorf("benchmark sets both baseline and baseline_code")
+	}
+	if policy.Baseline == "" && len(policy.BaselineCode) == 0 && (len(policy.Comparisons) > 0 || policy.MinSpeedup > 0) {
+		return fmt.Errorf("benchmark comparisons and min_speedup need a baseline or baseline_code")
+	}
+	for _, name := range append([]string{policy.Baseline}, policy.BaselineCode...) {
+		if name != "" && (filepath.IsAbs(name) || strings.Contains(filepath.Clean(name), "..")) {
+			return fmt.Errorf("invalid benchmark baseline path %q", name)
+		}
+	}
+	return nil
+}
+
+// loadBenchmarkBaseline returns the baseline samples of a policy: the stored baseline output, or a fresh
+// run of the challenge-owned baseline code against the challenge's test file
+func (es *ExecutionService) loadBenchmarkBaseline(challenge *models.TrackChallenge, count int) ([]BenchmarkStats, error) {
+	policy := challenge.Execution.Benchmark
+	if policy.Baseline != "" {
+		content, err := os.ReadFile(filepath.Join(challenge.Dir, filepath.Clean(policy.Baseline)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read benchmark baseline: %v", err)
+		}
+		return parseBenchmarkOutput(string(content)), nil
+	}
+
+	files := make(models.SubmissionFiles, len(policy.BaselineCode))
+	for _, name := range policy.BaselineCode {
+		content, err := os.ReadFile(filepath.Join(challenge.Dir, filepath.Clean(name)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read baseline code: %v", err)
+		}
+		files[filepath.Base(name)] = string(content)
+	}
+
+	runDir, err := os.MkdirTemp("", "challenge-baseline")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(runDir)
+
+	if err := WriteSubmissionFiles(runDir, files); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(runDir, "solution_test.go"), []byte(challenge.TestFile), 0644); err != nil {
+		return nil, err
+	}
+	if err := copySupportFiles(challenge.Dir, challenge.SupportFiles, runDir, files); err != nil {
+		return nil, err
+	}
+	if err := es.initGoModule(runDir, challenge.Module); err != nil {
+		return nil, err
+	}
+	if err := es.installDependencies(runDir, files.GoSource(), challenge.Dependencies); err != nil {
+		return nil, err
+	}
+
+	output, err := runTool(runDir, "go", benchmarkArgs(policy, count, benchmarkPattern(baselineBenchmarks(policy)))...)
+	if err != nil {
+		return nil, fmt.Errorf("baseline benchmarks failed: %v\n%s", err, output)
+	}
+	return parseBenchmarkOutput(output), nil
+}
+
+// parseBenchmarkOutput groups benchmark samples by name, in order of first appearance
+func parseBenchmarkOutput(output string) []BenchmarkStats {
+	var order []string
+	samples := make(map[string][][3]float64)
+
+	for _, line := range strings.Split(output, "\n") {
+		match := benchmarkLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		name := match[1]
+		if _, seen := samples[name]; !seen {
+			order = append(order, name)
+		}
+		ns, _ := strconv.ParseFloat(match[2], 64)
+		bytes, _ := strconv.ParseFloat(match[3], 64)
+		allocs, _ := strconv.ParseFloat(match[4], 64)
+		samples[name] = append(samples[name], [3]float64{ns, bytes, allocs})
+	}
+
+	stats := make([]BenchmarkStats, 0, len(order))
+	for _, name := range order {
+		var ns, bytes, allocs []float64
+		for _, s := range samples[name] {
+			ns = append(ns, s[0])
+			bytes = append(bytes, s[1])
+			allocs = append(allocs, s[2])
+		}
+		stats = append(stats, BenchmarkStats{
+			Name:        name,
+			Samples:     len(ns),
+			NsPerOp:     median(ns),
+			BytesPerOp:  median(bytes),
+			AllocsPerOp: median(allocs),
+			nsSamples:   ns,
+		})
+	}
+	return stats
+}
+
+// buildBenchmarkReport compares the run's results against the baseline samples of the challenge
+func buildBenchmarkReport(output string, baseline []BenchmarkStats, policy *models.BenchmarkPolicy) (*BenchmarkReport, error) {
+	report := &BenchmarkReport{Results: parseBenchmarkOutput(output)}
+	if len(report.Results) == 0 {
+		return report, fmt.Errorf("no benchmark results found")
+	}
+	if policy == nil {
+		report.MeetsMinSpeedup = true
+		return report, nil
+	}
+	report.MinSpeedup = policy.MinSpeedup
+
+	baselineByName := make(map[string]BenchmarkStats, len(baseline))
+	for _, b := range baseline {
+		baselineByName[b.Name] = b
+	}
+
+	logSum := 0.0
+	for _, result := range report.Results {
+		baselineName, ok := baselineNameFor(result.Name, policy)
+		if !ok {
+			continue
+		}
+		base, exists := baselineByName[baselineName]
+		if !exists || result.NsPerOp == 0 {
+			continue
+		}
+
+		p := mannWhitneyPValue(result.nsSamples, base.nsSamples)
+		comparison := BenchmarkComparison{
+			Benchmark:           result.Name,
+			Baseline:            baselineName,
+			NsPerOp:             result.NsPerOp,
+			BaselineNsPerOp:     base.NsPerOp,
+			Speedup:             base.NsPerOp / result.NsPerOp,
+			AllocsPerOp:         result.AllocsPerOp,
+			BaselineAllocsPerOp: base.AllocsPerOp,
+			PValue:              p,
+			Significant:         p < benchmarkAlpha,
+			Samples:             result.Samples,
+			BaselineSamples:     base.Samples,
+		}
+		report.Comparisons = append(report.Comparisons, comparison)
+
+		// Differences within noise count as no speedup
+		speedup := comparison.Speedup
+		if !comparison.Significant {
+			speedup = 1
+		}
+		logSum += math.Log(speedup)
+	}
+
+	if len(report.Comparisons) > 0 {
+		report.GeomeanSpeedup = math.Exp(logSum / float64(len(report.Comparisons)))
+	}
+	report.MeetsMinSpeedup = policy.MinSpeedup <= 0 || (len(report.Comparisons) > 0 && report.GeomeanSpeedup >= policy.MinSpeedup)
+	return report, nil
+}
+
+// baselineNameFor maps a solution benchmark to its baseline benchmark.
+// Comparisons map name prefixes, so "OptimizedSort" -> "SlowSort" also maps "OptimizedSort/Small" -> "SlowSort/Small".
+func baselineNameFor(name string, policy *models.BenchmarkPolicy) (string, bool) {
+	if len(policy.Comparisons) == 0 {
+		// Without explicit comparisons every benchmark is compared with the same name in the baseline
+		return name, policy.Baseline != "" || len(policy.BaselineCode) > 0
+	}
+
+	for solution, base := range policy.Comparisons {
+		if name == solution || strings.HasPrefix(name, solution+"/") {
+			return base + strings.TrimPrefix(name, solution), true
+		}
+	}
+	return "", false
+}
+
+// median returns the median of the values
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// mannWhitneyPValue returns the two-sided p-value of the Mann-Whitney U test, the test benchstat uses.
+// It uses the normal approximation with tie and continuity corrections.
+func mannWhitneyPValue(a, b []float64) float64 {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	type sample struct {
+		value float64
+		fromA bool
+	}
+	all := make([]sample, 0, len(a)+len(b))
+	for _, v := range a {
+		all = append(all, sample{v, true})
+	}
+	for _, v := range b {
+		all = append(all, sample{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// Assign average ranks to ties
+	rankSumA, tieCorrection := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromA {
+				rankSumA += rank
+			}
+		}
+		t := float64(j - i)
+		tieCorrection += t*t*t - t
+		i = j
+	}
+
+	u := rankSumA - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	n := n1 + n2
+	variance := n1 * n2 / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		z = 0
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
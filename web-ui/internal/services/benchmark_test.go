@@ -0,0 +1,290 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"web-ui/internal/models"
+)
+
+func TestMannWhitneyPValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    []float64
+		wantMin float64
+		wantMax float64
+	}{
+		{"separated samples", []float64{1, 2, 3, 4, 5}, []float64{10, 11, 12, 13, 14}, 0, 0.05},
+		{"separated reversed", []float64{10, 11, 12, 13, 14}, []float64{1, 2, 3, 4, 5}, 0, 0.05},
+		{"identical samples", []float64{5, 5, 5, 5, 5}, []float64{5, 5, 5, 5, 5}, 1, 1},
+		{"interleaved samples", []float64{1, 3, 5, 7, 9}, []float64{2, 4, 6, 8, 10}, 0.5, 1},
+		{"empty sample", nil, []float64{1, 2, 3}, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := mannWhitneyPValue(tt.a, tt.b)
+			if p < tt.wantMin || p > tt.wantMax {
+				t.Errorf("p = %v, want in [%v, %v]", p, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestParseBenchmarkOutput(t *testing.T) {
+	output := `goos: linux
+BenchmarkSort/10-8    	 1000	       300 ns/op	      80 B/op	       1 allocs/op
+BenchmarkSort/10-8    	 1000	       100 ns/op	      80 B/op	       1 allocs/op
+BenchmarkSort/10-8    	 1000	       200 ns/op	      80 B/op	       1 allocs/op
+BenchmarkSum-8        	 5000	        12.5 ns/op
+PASS`
+
+	stats := parseBenchmarkOutput(output)
+	if len(stats) != 2 {
+		t.Fatalf("got %d benchmarks, want 2: %+v", len(stats), stats)
+	}
+	if stats[0].Name != "Sort/10" || stats[0].Samples != 3 || stats[0].NsPerOp != 200 || stats[0].BytesPerOp != 80 || stats[0].AllocsPerOp != 1 {
+		t.Errorf("Sort/10 = %+v", stats[0])
+	}
+	if stats[1].Name != "Sum" || stats[1].NsPerOp != 12.5 || stats[1].AllocsPerOp != 0 {
+		t.Errorf("Sum = %+v", stats[1])
+	}
+}
+
+func TestBaselineNameFor(t *testing.T) {
+	comparisons := &models.BenchmarkPolicy{
+		BaselineCode: []string{"solution-template.go"},
+		Comparisons:  map[string]string{"OptimizedSort": "SlowSort"},
+	}
+	tests := []struct {
+		name   string
+		policy *models.BenchmarkPolicy
+		bench  string
+		want   string
+		wantOK bool
+	}{
+		{"comparison", comparisons, "OptimizedSort", "SlowSort", true},
+		{"sub-benchmark", comparisons, "OptimizedSort/100", "SlowSort/100", true},
+		{"unrelated prefix", comparisons, "OptimizedSortFast", "", false},
+		{"not compared", comparisons, "SlowSort", "", false},
+		{"same name in stored baseline", &models.BenchmarkPolicy{Baseline: "baseline.txt"}, "Sum", "Sum", true},
+		{"same name in baseline code", &models.BenchmarkPolicy{BaselineCode: []string{"slow.go"}}, "Sum", "Sum", true},
+		{"no baseline", &models.BenchmarkPolicy{}, "Sum", "Sum", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := baselineNameFor(tt.bench, tt.policy)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("baselineNameFor(%q) = %q, %v, want %q, %v", tt.bench, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCheckBenchmarkPolicy(t *testing.T) {
+	comparisons := map[string]string{"OptimizedSort": "SlowSort"}
+	tests := []struct {
+		name    string
+		policy  *models.BenchmarkPolicy
+		wantErr bool
+	}{
+		{"no policy", nil, false},
+		{"samples only", &models.BenchmarkPolicy{Count: 5}, false},
+		{"stored baseline", &models.BenchmarkPolicy{Baseline: "baseline.txt", MinSpeedup: 2}, false},
+		{"baseline code", &models.BenchmarkPolicy{BaselineCode: []string{"solution-template.go"}, Comparisons: comparisons, MinSpeedup: 2}, false},
+		{"comparisons without baseline", &models.BenchmarkPolicy{Comparisons: comparisons}, true},
+		{"min speedup without baseline", &models.BenchmarkPolicy{MinSpeedup: 2}, true},
+		{"both baseline sources", &models.BenchmarkPolicy{Baseline: "baseline.txt", BaselineCode: []string{"slow.go"}}, true},
+		{"baseline outside the challenge", &models.BenchmarkPolicy{BaselineCode: []string{"../other/slow.go"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBenchmarkPolicy(tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkBenchmarkPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildBenchmarkReportUsesChallengeBaseline(t *testing.T) {
+	policy := &models.BenchmarkPolicy{
+		BaselineCode: []string{"solution-template.go"},
+		Comparisons:  map[string]string{"Fast": "Slow"},
+		MinSpeedup:   2,
+	}
+	baseline := parseBenchmarkOutput(strings.Repeat("BenchmarkSlow-8 100 110 ns/op\n", 5))
+
+	tests := []struct {
+		name      string
+		output    string
+		wantMeets bool
+	}{
+		{"faster than the baseline", strings.Repeat("BenchmarkFast-8 100 10 ns/op\n", 5), true},
+		{"as fast as the baseline", strings.Repeat("BenchmarkFast-8 100 100 ns/op\nBenchmarkFast-8 100 120 ns/op\n", 3), false},
+		// A slowed-down Slow in the submission's own output must not count as the baseline
+		{"slow benchmark in the submission", strings.Repeat("BenchmarkFast-8 100 100 ns/op\nBenchmarkSlow-8 100 100000 ns/op\n", 5), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := buildBenchmarkReport(tt.output, baseline, policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.MeetsMinSpeedup != tt.wantMeets {
+				t.Errorf("MeetsMinSpeedup = %v (geomean %.2fx), want %v", report.MeetsMinSpeedup, report.GeomeanSpeedup, tt.wantMeets)
+			}
+			if len(report.Comparisons) != 1 || report.Comparisons[0].BaselineNsPerOp != 110 {
+				t.Errorf("comparisons = %+v, want one against the 110 ns/op baseline", report.Comparisons)
+			}
+		})
+	}
+}
+
+// benchmarkTestChallenge writes a challenge whose template holds the slow baseline implementation
+func benchmarkTestChallenge(t *testing.T) *models.TrackChallenge {
+	t.Helper()
+	dir := t.TempDir()
+	template := `package main
+
+func SlowSum(n int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		total += i
+	}
+	return total
+}
+
+func FastSum(n int) int {
+	return 0
+}
+
+func main() {}
+`
+	if err := os.WriteFile(filepath.Join(dir, "solution-template.go"), []byte(template), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return &models.TrackChallenge{
+		Ref:          "classic/900",
+		Dir:          dir,
+		SolutionFile: "solution-template.go",
+		Module:       "challenge",
+		TestFile: `package main
+
+import "testing"
+
+func TestFastSum(t *testing.T) {
+	if got := FastSum(10); got != 45 {
+		t.Fatalf("FastSum(10) = %d", got)
+	}
+}
+
+func BenchmarkSlowSum(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		SlowSum(100000)
+	}
+}
+
+func BenchmarkFastSum(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		FastSum(100000)
+	}
+}
+`,
+		Execution: models.ExecutionPolicy{Benchmark: &models.BenchmarkPolicy{
+			Count:        5,
+			Benchtime:    "20ms",
+			BaselineCode: []string{"solution-template.go"},
+			Comparisons:  map[string]string{"FastSum": "SlowSum"},
+			MinSpeedup:   2,
+		}},
+	}
+}
+
+func TestBenchRunMeasuresChallengeBaseline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	challenge := benchmarkTestChallenge(t)
+
+	tests := []struct {
+		name       string
+		solution   string
+		wantPassed bool
+	}{
+		{
+			name: "closed form",
+			solution: `package main
+
+func SlowSum(n int) int { return FastSum(n) }
+
+func FastSum(n int) int { return n * (n - 1) / 2 }
+
+func main() {}
+`,
+			wantPassed: true,
+		},
+		{
+			// Slowing down the submission's own SlowSum used to fake the speedup
+			name: "slowed down baseline",
+			solution: `package main
+
+import "time"
+
+func SlowSum(n int) int {
+	time.Sleep(time.Millisecond)
+	return FastSum(n)
+}
+
+func FastSum(n int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		total += i
+	}
+	return total
+}
+
+func main() {}
+`,
+			wantPassed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := models.SubmissionFiles{"solution-template.go": tt.solution}
+			result := NewExecutionService().RunWithOptions(files, challenge, RunOptions{Mode: RunModeBench})
+			if result.Passed != tt.wantPassed {
+				t.Fatalf("Passed = %v, want %v:\n%s", result.Passed, tt.wantPassed, result.Output)
+			}
+			if result.Benchmark == nil || len(result.Benchmark.Comparisons) == 0 {
+				t.Fatalf("no benchmark comparisons:\n%s", result.Output)
+			}
+			for _, stats := range result.Benchmark.Results {
+				if stats.Name == "SlowSum" {
+					t.Errorf("the submission's own baseline benchmark was measured: %+v", stats)
+				}
+			}
+		})
+	}
+}
+
+func TestBenchRunRejectsPolicyWithoutBaseline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	challenge := benchmarkTestChallenge(t)
+	challenge.Execution.Benchmark.BaselineCode = nil
+
+	files := models.SubmissionFiles{"solution-template.go": "package main\n\nfunc SlowSum(n int) int { return 0 }\n\nfunc FastSum(n int) int { return n * (n - 1) / 2 }\n\nfunc main() {}\n"}
+	result := NewExecutionService().RunWithOptions(files, challenge, RunOptions{Mode: RunModeBench})
+	if result.Passed || !strings.Contains(result.Output, "Invalid benchmark policy") {
+		t.Fatalf("a policy without a baseline source was accepted:\n%s", result.Output)
+	}
+}
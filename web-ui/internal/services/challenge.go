@@ -101,16 +101,51 @@ func (cs *ChallengeService) loadSingleChallenge(id int, dir string) (*models.Cha
 		hintsContent = hintsFileContent
 	}
 
+	// Read optional hidden tests, which are only used for submissions
+	var hiddenTestContent []byte
+	if content, err := ioutil.ReadFile(filepath.Join(dir, HiddenTestFileName)); err == nil {
+		hiddenTestContent = content
+	}
+
+	// Read optional fuzz targets, which are only used by fuzz runs
+	var fuzzTestContent []byte
+	if content, err := ioutil.ReadFile(filepath.Join(dir, FuzzTestFileName)); err == nil {
+		fuzzTestContent = content
+	}
+
+	// Read optional metadata for tags, prerequisites, difficulty, support files and execution requirements
+	var tags, prerequisites, supportFiles []string
+	var execution models.ExecutionPolicy
+	var dependencies models.Dependencies
+	if metadata := readChallengeMetadata(dir); metadata != nil {
+		tags = metadata.Tags
+		prerequisites = metadata.Prerequisites
+		supportFiles = metadata.SupportFiles
+		execution = metadata.Execution
+		dependencies = metadata.Dependencies
+		if metadata.Difficulty != "" {
+			difficulty = metadata.Difficulty
+		}
+	}
+
 	// Create challenge
 	challenge := &models.Challenge{
 		ID:                id,
 		Title:             title,
 		Description:       cs.filterWebUIDescription(string(readmeContent)),
 		Difficulty:        difficulty,
+		Tags:              tags,
+		Prerequisites:     prerequisites,
 		Template:          string(templateContent),
 		TestFile:          string(testContent),
+		HiddenTestFile:    string(hiddenTestContent),
+		FuzzTestFile:      string(fuzzTestContent),
 		LearningMaterials: string(learningContent),
 		Hints:             string(hintsContent),
+		SupportFiles:      supportFiles,
+		Execution:         execution,
+		Dependencies:      dependencies,
+		Dir:               dir,
 	}
 
 	return challenge, nil
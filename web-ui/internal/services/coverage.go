@@ -0,0 +1,171 @@
+package services
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"web-ui/internal/models"
+)
+
+// Line coverage states used in the annotated source view
+const (
+	LineCovered   = "covered"
+	LineUncovered = "uncovered"
+	LineNotCode   = ""
+)
+
+// CoverageReport is the coverage of the submitted files from a cover run
+type CoverageReport struct {
+	Percent   float64            `json:"percent"`
+	Functions []FunctionCoverage `json:"functions"`
+	Files     []AnnotatedFile    `json:"files"`
+}
+
+// FunctionCoverage is the statement coverage of a single function
+type FunctionCoverage struct {
+	File     string  `json:"file"`
+	Line     int     `json:"line"`
+	Function string  `json:"function"`
+	Percent  float64 `json:"percent"`
+}
+
+// AnnotatedFile is a submitted source file with per-line coverage
+type AnnotatedFile struct {
+	Name  string         `json:"name"`
+	Lines []CoverageLine `json:"lines"`
+}
+
+// CoverageLine is a single source line and whether it was executed
+type CoverageLine struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+	Status string `json:"status"`
+}
+
+// coverageBlock is a single block from a cover profile
+type coverageBlock struct {
+	file       string
+	startLine  int
+	endLine    int
+	statements int
+	count      int
+}
+
+// buildCoverageReport reads the cover profile and `go tool cover -func` output for the submitted files
+func buildCoverageReport(runDir, profilePath, module string, files models.SubmissionFiles) (*CoverageReport, error) {
+	blocks, err := readCoverProfile(profilePath, module)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CoverageReport{}
+
+	funcOutput, _ := runTool(runDir, "go", "tool", "cover", "-func="+profilePath)
+	for _, line := range strings.Split(funcOutput, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || !strings.HasSuffix(fields[2], "%") {
+			continue
+		}
+		location := strings.TrimPrefix(fields[0], module+"/")
+		name, lineStr, _ := strings.Cut(strings.TrimSuffix(location, ":"), ":")
+		if _, submitted := files[name]; !submitted {
+			continue
+		}
+		lineNum, _ := strconv.Atoi(lineStr)
+		percent, _ := strconv.ParseFloat(strings.TrimSuffix(fields[2], "%"), 64)
+		report.Functions = append(report.Functions, FunctionCoverage{
+			File:     name,
+			Line:     lineNum,
+			Function: fields[1],
+			Percent:  percent,
+		})
+	}
+
+	// Statement totals and per-line status for the submitted files only
+	totalStatements, coveredStatements := 0, 0
+	lineStatus := make(map[string]map[int]string)
+	for _, block := range blocks {
+		if _, submitted := files[block.file]; !submitted {
+			continue
+		}
+		totalStatements += block.statements
+		if block.count > 0 {
+			coveredStatements += block.statements
+		}
+
+		if lineStatus[block.file] == nil {
+			lineStatus[block.file] = make(map[int]string)
+		}
+		for n := block.startLine; n <= block.endLine; n++ {
+			// A line counts as covered only if every block touching it ran
+			if block.count == 0 {
+				lineStatus[block.file][n] = LineUncovered
+			} else if lineStatus[block.file][n] != LineUncovered {
+				lineStatus[block.file][n] = LineCovered
+			}
+		}
+	}
+	if totalStatements > 0 {
+		report.Percent = float64(coveredStatements) * 100 / float64(totalStatements)
+	}
+
+	for _, name := range files.Names() {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		annotated := AnnotatedFile{Name: name}
+		for i, text := range strings.Split(files[name], "\n") {
+			annotated.Lines = append(annotated.Lines, CoverageLine{
+				Number: i + 1,
+				Text:   text,
+				Status: lineStatus[name][i+1],
+			})
+		}
+		report.Files = append(report.Files, annotated)
+	}
+
+	return report, nil
+}
+
+// readCoverProfile parses a cover profile, making file names relative to the module
+func readCoverProfile(profilePath, module string) ([]coverageBlock, error) {
+	f, err := os.Open(profilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var blocks []coverageBlock
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Format: name.go:line.column,line.column numberOfStatements count
+		line := scanner.Text()
+		if strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		location, rest, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		colon := strings.LastIndex(location, ":")
+		if len(fields) != 2 || colon < 0 {
+			continue
+		}
+		start, end, ok := strings.Cut(location[colon+1:], ",")
+		if !ok {
+			continue
+		}
+
+		block := coverageBlock{file: filepath.ToSlash(strings.TrimPrefix(location[:colon], module+"/"))}
+		block.startLine, _ = strconv.Atoi(strings.Split(start, ".")[0])
+		block.endLine, _ = strconv.Atoi(strings.Split(end, ".")[0])
+		block.statements, _ = strconv.Atoi(fields[0])
+		block.count, _ = strconv.Atoi(fields[1])
+		blocks = append(blocks, block)
+	}
+	return blocks, scanner.Err()
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"web-ui/internal/models"
+)
+
+func TestTestArgs(t *testing.T) {
+	tests := []struct {
+		mode string
+		race bool
+		want []string
+	}{
+		{RunModeDefault, false, []string{"test", "-v"}},
+		{RunModeRace, true, []string{"test", "-v", "-race"}},
+		{RunModeCover, false, []string{"test", "-v", "-coverprofile=" + coverProfileName}},
+		{RunModeShort, false, []string{"test", "-v", "-short"}},
+		// Challenges that require race-clean runs add -race to every mode
+		{RunModeShort, true, []string{"test", "-v", "-race", "-short"}},
+	}
+
+	for _, tt := range tests {
+		if got := NewExecutionService().testArgs(tt.mode, tt.race); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("testArgs(%q, %v) = %q, want %q", tt.mode, tt.race, got, tt.want)
+		}
+	}
+
+	for _, mode := range []string{"", "race", "cover", "short", "bench", "fuzz"} {
+		if !ValidRunMode(mode) {
+			t.Errorf("ValidRunMode(%q) = false", mode)
+		}
+	}
+	if ValidRunMode("profile") {
+		t.Error("ValidRunMode accepted an unknown mode")
+	}
+}
+
+func TestBuildCoverageReport(t *testing.T) {
+	files := models.SubmissionFiles{
+		"solution-template.go": "package main\n\nfunc Abs(n int) int {\n\tif n < 0 {\n\t\treturn -n\n\t}\n\treturn n\n}",
+	}
+	// Abs ran, but never with a negative number; the test file's block is left out
+	profile := `mode: set
+example/solution-template.go:3.21,4.11 1 1
+example/solution-template.go:4.11,6.3 1 0
+example/solution-template.go:7.2,7.10 1 1
+example/solution_test.go:5.30,7.2 2 1
+`
+	dir := t.TempDir()
+	profilePath := filepath.Join(dir, coverProfileName)
+	if err := os.WriteFile(profilePath, []byte(profile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := buildCoverageReport(dir, profilePath, "example", files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 200.0 / 3; report.Percent != want {
+		t.Errorf("Percent = %v, want %v", report.Percent, want)
+	}
+	if len(report.Files) != 1 || report.Files[0].Name != "solution-template.go" {
+		t.Fatalf("Files = %+v, want the submitted file only", report.Files)
+	}
+
+	want := []string{LineNotCode, LineNotCode, LineCovered, LineUncovered, LineUncovered, LineUncovered, LineCovered, LineNotCode}
+	var got []string
+	for _, line := range report.Files[0].Lines {
+		got = append(got, line.Status)
+	}
+	// Line 4 is shared by a block that ran and one that did not, so it counts as uncovered
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("line statuses = %q, want %q", got, want)
+	}
+
+	if _, err := buildCoverageReport(dir, filepath.Join(dir, "missing.out"), "example", files); err == nil {
+		t.Error("buildCoverageReport accepted a missing profile")
+	}
+}
+
+func TestRunModes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	challenge := &models.TrackChallenge{
+		Ref:    "classic/0",
+		Module: "example",
+		TestFile: `package main
+
+import "testing"
+
+func TestCount(t *testing.T) {
+	if got := Count(100); got != 100 {
+		t.Errorf("Count(100) = %d", got)
+	}
+}
+`,
+	}
+	racy := `package main
+
+import "sync"
+
+func Count(n int) int {
+	total := 0
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			total++
+		}()
+	}
+	wg.Wait()
+	return n
+}
+
+func main() {}
+`
+
+	tests := []struct {
+		name        string
+		mode        string
+		requireRace bool
+		wantPassed  bool
+		wantRace    bool
+	}{
+		{"default ignores races", RunModeDefault, false, true, false},
+		{"race mode", RunModeRace, false, false, true},
+		{"required by the challenge", RunModeDefault, true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := *challenge
+			c.Execution.RequireRace = tt.requireRace
+			result := NewExecutionService().RunWithOptions(models.SubmissionFiles{"solution-template.go": racy}, &c, RunOptions{Mode: tt.mode})
+			if result.Passed != tt.wantPassed || result.RaceDetected != tt.wantRace {
+				t.Errorf("Passed = %v, RaceDetected = %v, want %v, %v:\n%s", result.Passed, result.RaceDetected, tt.wantPassed, tt.wantRace, result.Output)
+			}
+		})
+	}
+
+	t.Run("cover", func(t *testing.T) {
+		result := NewExecutionService().RunWithOptions(models.SubmissionFiles{"solution-template.go": racy}, challenge, RunOptions{Mode: RunModeCover})
+		if !result.Passed || result.Coverage == nil {
+			t.Fatalf("Passed = %v, Coverage = %+v:\n%s", result.Passed, result.Coverage, result.Output)
+		}
+		if len(result.Coverage.Functions) != 2 || result.Coverage.Functions[0].Function != "Count" {
+			t.Errorf("Functions = %+v, want Count and main", result.Coverage.Functions)
+		}
+	})
+}
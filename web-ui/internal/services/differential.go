@@ -0,0 +1,448 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+const (
+	// referenceDirName is the hidden directory holding a challenge's reference solution
+	referenceDirName = "reference"
+	// differentialTestName is the generated test file that compares a submission with the reference
+	differentialTestName = "differential_test.go"
+	// differentialResultName is written by the generated test when it finds a divergent input
+	differentialResultName = "differential.json"
+	// defaultDifferentialIterations is the number of random inputs tried per function
+	defaultDifferentialIterations = 500
+	// maxDifferentialIterations bounds the inputs a challenge may request per function
+	maxDifferentialIterations = 10000
+)
+
+// DifferentialReport is the outcome of comparing a submission with the challenge's reference solution
+type DifferentialReport struct {
+	Functions  []string    `json:"functions"`
+	Iterations int         `json:"iterations"` // Random inputs tried per function
+	Seed       int64       `json:"seed"`       // Seed of the input generator, to reproduce a run
+	Divergence *Divergence `json:"divergence,omitempty"`
+}
+
+// Divergence is the first input on which the submission and the reference disagree
+type Divergence struct {
+	Function  string `json:"function"`
+	Iteration int    `json:"iteration"`
+	Input     string `json:"input"` // Arguments as Go literals
+	Got       string `json:"got"`
+	Want      string `json:"want"`
+	Message   string `json:"message"`
+}
+
+// referenceFunctions describes the exported functions of a reference solution.
+// Generate<Name> and Check<Name> are optional input generators and result checkers for <Name>.
+type referenceFunctions struct {
+	Package  string
+	Names    []string
+	Generate map[string]bool
+	Check    map[string]bool
+}
+
+// HasReference reports whether a challenge ships a hidden reference solution
+func HasReference(challengeDir string) bool {
+	if challengeDir == "" {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(challengeDir, referenceDirName))
+	return err == nil && info.IsDir()
+}
+
+// runDifferential compares a passing submission with the reference solution on random inputs
+func (es *ExecutionService) runDifferential(tempDir string, challenge *models.TrackChallenge, files models.SubmissionFiles, result *ExecutionResult) {
+	referenceDir := filepath.Join(challenge.Dir, referenceDirName)
+	reference, err := parseReference(referenceDir)
+	if err != nil {
+		result.Output += fmt.Sprintf("\nDifferential testing unavailable: %v\n", err)
+		return
+	}
+
+	packageName, submitted := submittedFunctions(files)
+	var names []string
+	for _, name := range reference.Names {
+		if submitted[name] {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	// The reference is only copied in after the challenge tests so submissions cannot import it
+	if err := copyReferenceSources(referenceDir, filepath.Join(tempDir, referenceDirName)); err != nil {
+		result.Output += fmt.Sprintf("\nDifferential testing unavailable: %v\n", err)
+		return
+	}
+
+	policy := challenge.Execution.Differential
+	report := &DifferentialReport{Functions: names, Iterations: defaultDifferentialIterations, Seed: time.Now().UnixNano()}
+	if policy != nil && policy.Iterations > 0 {
+		report.Iterations = policy.Iterations
+	}
+	if report.Iterations > maxDifferentialIterations {
+		report.Iterations = maxDifferentialIterations
+	}
+	if policy != nil && policy.Seed != 0 {
+		report.Seed = policy.Seed
+	}
+
+	source, err := generateDifferentialTest(packageName, challenge.Module, reference, names, report)
+	if err != nil {
+		result.Output += fmt.Sprintf("\nDifferential testing unavailable: %v\n", err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, differentialTestName), source, 0644); err != nil {
+		result.Output += fmt.Sprintf("\nDifferential testing unavailable: %v\n", err)
+		return
+	}
+
+	start := time.Now()
+	output, err := runTool(tempDir, "go", "test", "-run=^TestReferenceDifferential$", "-count=1")
+	result.ExecutionMs += time.Since(start).Milliseconds()
+	result.Differential = report
+	if err == nil {
+		result.Output += fmt.Sprintf("\nDifferential testing: %d random inputs per function matched the reference.\n", report.Iterations)
+		return
+	}
+
+	result.Passed = false
+	result.Output += "\n" + output
+	content, readErr := os.ReadFile(filepath.Join(tempDir, differentialResultName))
+	if readErr != nil {
+		// The generated test did not get to compare results, e.g. a signature mismatch
+		return
+	}
+	var divergence Divergence
+	if json.Unmarshal(content, &divergence) == nil {
+		report.Divergence = &divergence
+	}
+}
+
+// parseReference lists the exported functions of a reference solution
+func parseReference(dir string) (*referenceFunctions, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse reference solution: %v", err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("reference solution must contain exactly one package")
+	}
+
+	reference := &referenceFunctions{Generate: make(map[string]bool), Check: make(map[string]bool)}
+	declared := make(map[string]bool)
+	for name, pkg := range pkgs {
+		reference.Package = name
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.IsExported() {
+					declared[fn.Name.Name] = true
+				}
+			}
+		}
+	}
+
+	// A Generate or Check prefix only marks a helper if the rest names another reference function
+	for name := range declared {
+		if target := strings.TrimPrefix(name, "Generate"); target != name && declared[target] {
+			reference.Generate[target] = true
+			continue
+		}
+		if target := strings.TrimPrefix(name, "Check"); target != name && declared[target] {
+			reference.Check[target] = true
+			continue
+		}
+		reference.Names = append(reference.Names, name)
+	}
+	sort.Strings(reference.Names)
+	return reference, nil
+}
+
+// submittedFunctions returns the package name and top-level functions of the submitted Go files
+func submittedFunctions(files models.SubmissionFiles) (string, map[string]bool) {
+	packageName := ""
+	functions := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, name := range files.Names() {
+		if !strings.HasSuffix(name, ".go") || strings.Contains(name, "/") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, files[name], 0)
+		if err != nil {
+			continue
+		}
+		packageName = file.Name.Name
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+				functions[fn.Name.Name] = true
+			}
+		}
+	}
+	return packageName, functions
+}
+
+// copyReferenceSources copies the reference solution's non-test Go files into the run directory
+func copyReferenceSources(referenceDir, target string) error {
+	sources, err := filepath.Glob(filepath.Join(referenceDir, "*.go"))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return err
+	}
+	for _, source := range sources {
+		if strings.HasSuffix(source, "_test.go") {
+			continue
+		}
+		if err := copyPath(source, filepath.Join(target, filepath.Base(source))); err != nil {
+			return fmt.Errorf("failed to copy reference solution: %v", err)
+		}
+	}
+	return nil
+}
+
+// generateDifferentialTest renders the test file that compares the submission with the reference
+func generateDifferentialTest(packageName, module string, reference *referenceFunctions, names []string, report *DifferentialReport) ([]byte, error) {
+	var buf bytes.Buffer
+	err := differentialTemplate.Execute(&buf, map[string]interface{}{
+		"Package":    packageName,
+		"Import":     module + "/" + referenceDirName,
+		"Reference":  reference,
+		"Names":      names,
+		"Iterations": report.Iterations,
+		"Seed":       report.Seed,
+		"ResultFile": differentialResultName,
+	})
+	return buf.Bytes(), err
+}
+
+// differentialTemplate is compiled into the submission's package, so every identifier is prefixed
+var differentialTemplate = template.Must(template.New("differential").Parse(`package {{.Package}}
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	differentialReference "{{.Import}}"
+)
+
+func TestReferenceDifferential(t *testing.T) {
+	differentialRun(t, {{.Iterations}}, {{.Seed}}, []differentialCase{
+{{- range .Names}}
+		{
+			name:       "{{.}}",
+			submission: {{.}},
+			reference:  differentialReference.{{.}},
+{{- if index $.Reference.Generate .}}
+			generate:   differentialReference.Generate{{.}},
+{{- end}}
+{{- if index $.Reference.Check .}}
+			check:      differentialReference.Check{{.}},
+{{- end}}
+		},
+{{- end}}
+	})
+}
+
+type differentialCase struct {
+	name       string
+	submission interface{}
+	reference  interface{}
+	generate   func(*rand.Rand) []interface{}
+	check      func(args, got, want []interface{}) error
+}
+
+func differentialRun(t *testing.T, iterations int, seed int64, cases []differentialCase) {
+	rng := rand.New(rand.NewSource(seed))
+	for _, c := range cases {
+		submission := reflect.ValueOf(c.submission)
+		reference := reflect.ValueOf(c.reference)
+		if submission.Type() != reference.Type() {
+			t.Fatalf("%s has signature %v, the reference has %v", c.name, submission.Type(), reference.Type())
+		}
+
+		for i := 0; i < iterations; i++ {
+			var args []interface{}
+			if c.generate != nil {
+				args = c.generate(rng)
+			} else {
+				for p := 0; p < reference.Type().NumIn(); p++ {
+					value, ok := quick.Value(reference.Type().In(p), rng)
+					if !ok {
+						t.Fatalf("cannot generate %v arguments for %s, the reference needs a Generate%s function", reference.Type().In(p), c.name, c.name)
+					}
+					args = append(args, value.Interface())
+				}
+			}
+
+			want, wantPanic := differentialCall(reference, args)
+			if wantPanic != "" {
+				t.Fatalf("reference %s panicked on %s: %s", c.name, differentialFormat(args, "%#v"), wantPanic)
+			}
+			got, gotPanic := differentialCall(submission, args)
+
+			message := ""
+			switch {
+			case gotPanic != "":
+				message = "panic: " + gotPanic
+			case c.check != nil:
+				if err := c.check(differentialCopy(args), got, want); err != nil {
+					message = err.Error()
+				}
+			case !differentialEqual(reflect.ValueOf(got), reflect.ValueOf(want)):
+				message = "result differs from the reference"
+			}
+			if message == "" {
+				continue
+			}
+
+			divergence := map[string]interface{}{
+				"function":  c.name,
+				"iteration": i,
+				"input":     differentialFormat(args, "%#v"),
+				"got":       differentialFormat(got, "%v"),
+				"want":      differentialFormat(want, "%v"),
+				"message":   message,
+			}
+			if content, err := json.Marshal(divergence); err == nil {
+				os.WriteFile("{{.ResultFile}}", content, 0644)
+			}
+			t.Fatalf("%s(%s) diverges from the reference: %s\n  got:  %s\n  want: %s",
+				c.name, divergence["input"], message, divergence["got"], divergence["want"])
+		}
+	}
+}
+
+// differentialCall calls fn with a deep copy of args so neither implementation sees the other's mutations
+func differentialCall(fn reflect.Value, args []interface{}) (results []interface{}, panicked string) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = fmt.Sprint(r)
+		}
+	}()
+
+	in := make([]reflect.Value, len(args))
+	for i, arg := range differentialCopy(args) {
+		if arg == nil {
+			in[i] = reflect.Zero(fn.Type().In(i))
+		} else {
+			in[i] = reflect.ValueOf(arg)
+		}
+	}
+	for _, out := range fn.Call(in) {
+		results = append(results, out.Interface())
+	}
+	return results, ""
+}
+
+func differentialCopy(args []interface{}) []interface{} {
+	copied := make([]interface{}, len(args))
+	for i, arg := range args {
+		if arg != nil {
+			copied[i] = differentialCopyValue(reflect.ValueOf(arg)).Interface()
+		}
+	}
+	return copied
+}
+
+func differentialCopyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(differentialCopyValue(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, key := range v.MapKeys() {
+			copied.SetMapIndex(key, differentialCopyValue(v.MapIndex(key)))
+		}
+		return copied
+	}
+	return v
+}
+
+// differentialEqual is reflect.DeepEqual except that nil and empty slices and maps are equal
+func differentialEqual(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !differentialEqual(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for _, key := range a.MapKeys() {
+			if !differentialEqual(a.MapIndex(key), b.MapIndex(key)) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+func differentialFormat(values []interface{}, verb string) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprintf(verb, value)
+	}
+	return strings.Join(parts, ", ")
+}
+`))
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"web-ui/internal/models"
+)
+
+// writeReference writes a challenge directory with a reference solution made of the given source
+func writeReference(t *testing.T, source string) string {
+	t.Helper()
+	dir := t.TempDir()
+	referenceDir := filepath.Join(dir, referenceDirName)
+	if err := os.MkdirAll(referenceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(referenceDir, "reference.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestParseReference(t *testing.T) {
+	dir := writeReference(t, `package reference
+
+import "math/rand"
+
+func Max(numbers []int) int { return 0 }
+
+func GenerateMax(r *rand.Rand) []interface{} { return nil }
+
+func Sort(numbers []int) []int { return numbers }
+
+func CheckSort(input []interface{}, got []interface{}) string { return "" }
+
+// GenerateReport does not name another function, so it is compared like any other
+func GenerateReport() string { return "" }
+
+func unexported() {}
+
+type Stack struct{}
+
+func (s *Stack) Push(n int) {}
+`)
+
+	reference, err := parseReference(filepath.Join(dir, referenceDirName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &referenceFunctions{
+		Package:  "reference",
+		Names:    []string{"GenerateReport", "Max", "Sort"},
+		Generate: map[string]bool{"Max": true},
+		Check:    map[string]bool{"Sort": true},
+	}
+	if !reflect.DeepEqual(reference, want) {
+		t.Errorf("parseReference() = %+v, want %+v", reference, want)
+	}
+
+	if _, err := parseReference(filepath.Join(dir, "missing")); err == nil {
+		t.Error("parseReference accepted a missing directory")
+	}
+}
+
+func TestSubmittedFunctions(t *testing.T) {
+	files := models.SubmissionFiles{
+		"solution-template.go": "package main\n\nfunc Max(numbers []int) int { return 0 }\n\nfunc (s *Stack) Push(n int) {}\n",
+		"helpers.go":           "package main\n\nfunc helper() {}\n",
+		// Files in subpackages are not in scope for the comparison
+		"service/service.go": "package service\n\nfunc Sort() {}\n",
+		"broken.go":          "package main\n\nfunc (",
+	}
+
+	packageName, functions := submittedFunctions(files)
+	if packageName != "main" {
+		t.Errorf("package = %q, want main", packageName)
+	}
+	if want := map[string]bool{"Max": true, "helper": true}; !reflect.DeepEqual(functions, want) {
+		t.Errorf("functions = %v, want %v", functions, want)
+	}
+}
+
+func TestHasReference(t *testing.T) {
+	withReference := writeReference(t, "package reference\n")
+	withFile := t.TempDir()
+	if err := os.WriteFile(filepath.Join(withFile, referenceDirName), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		want bool
+	}{
+		{"reference dir", withReference, true},
+		{"no reference", t.TempDir(), false},
+		{"reference file", withFile, false},
+		{"no challenge dir", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasReference(tt.dir); got != tt.want {
+				t.Errorf("HasReference() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReferenceHiddenFromChallenge(t *testing.T) {
+	challenges := loadShippedChallenges(t)
+	for _, id := range []int{19, 21, 22, 24, 25} {
+		challenge, exists := challenges[id]
+		if !exists {
+			t.Fatalf("challenge %d not loaded", id)
+		}
+		// Challenge directories are relative to web-ui
+		if !HasReference(filepath.Join("..", "..", challenge.Dir)) {
+			t.Errorf("challenge %d ships no reference solution", id)
+		}
+		for _, content := range []string{challenge.Template, challenge.TestFile} {
+			if strings.Contains(content, "package reference") || strings.Contains(content, "/"+referenceDirName+`"`) {
+				t.Errorf("challenge %d exposes its reference solution", id)
+			}
+		}
+	}
+}
+
+func TestDifferentialRunFindsDivergence(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	dir := writeReference(t, `package reference
+
+func Max(numbers []int) int {
+	if len(numbers) == 0 {
+		return 0
+	}
+	max := numbers[0]
+	for _, n := range numbers[1:] {
+		if n > max {
+			max = n
+		}
+	}
+	return max
+}
+`)
+	seed := int64(42)
+	challenge := &models.TrackChallenge{
+		Ref:      "classic/0",
+		Module:   "example",
+		Dir:      dir,
+		TestFile: "package main\n\nimport \"testing\"\n\nfunc TestMax(t *testing.T) {\n\tif Max([]int{1, 3, 2}) != 3 {\n\t\tt.Error(\"wrong max\")\n\t}\n}\n",
+	}
+	challenge.Execution.Differential = &models.DifferentialPolicy{Iterations: 200, Seed: seed}
+
+	tests := []struct {
+		name          string
+		solution      string
+		wantPassed    bool
+		wantDivergent bool
+	}{
+		{"matches reference", "package main\n\nfunc Max(numbers []int) int {\n\tmax := 0\n\tfor i, n := range numbers {\n\t\tif i == 0 || n > max {\n\t\t\tmax = n\n\t\t}\n\t}\n\treturn max\n}\n\nfunc main() {}\n", true, false},
+		// Passes the hard-coded test but is wrong for negative numbers
+		{"diverges", "package main\n\nfunc Max(numbers []int) int {\n\tmax := 0\n\tfor _, n := range numbers {\n\t\tif n > max {\n\t\t\tmax = n\n\t\t}\n\t}\n\treturn max\n}\n\nfunc main() {}\n", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewExecutionService().RunFiles(models.SubmissionFiles{"solution-template.go": tt.solution}, challenge)
+			if result.Passed != tt.wantPassed {
+				t.Fatalf("Passed = %v, want %v:\n%s", result.Passed, tt.wantPassed, result.Output)
+			}
+			if result.Differential == nil || result.Differential.Seed != seed || !reflect.DeepEqual(result.Differential.Functions, []string{"Max"}) {
+				t.Fatalf("Differential = %+v", result.Differential)
+			}
+			if divergence := result.Differential.Divergence; (divergence != nil) != tt.wantDivergent {
+				t.Errorf("Divergence = %+v, want divergent %v", divergence, tt.wantDivergent)
+			} else if divergence != nil && (divergence.Function != "Max" || divergence.Got == divergence.Want) {
+				t.Errorf("Divergence = %+v", divergence)
+			}
+		})
+	}
+}
+
+// loadShippedChallenges loads the repository's classic challenges, which the service finds relative to web-ui
+func loadShippedChallenges(t *testing.T) models.ChallengeMap {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join("..", "..")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	challengeService := NewChallengeService()
+	if err := challengeService.LoadChallenges(); err != nil {
+		t.Fatal(err)
+	}
+	return challengeService.GetChallenges()
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+const (
+	// draftSnapshotInterval is how long autosaves are folded into one version before a new one is started
+	draftSnapshotInterval = 5 * time.Minute
+	// maxDraftVersions bounds the history kept per user and challenge
+	maxDraftVersions = 50
+)
+
+// ErrDraftNotFound is returned when a user has no draft, or no such version, for a challenge
+var ErrDraftNotFound = errors.New("draft not found")
+
+// DraftService autosaves users' in-progress code outside the repository, one JSON file per user and challenge.
+// Autosaves within a few minutes of each other update the same version, so the history stays readable.
+type DraftService struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewDraftService creates a draft service storing drafts in dir
+func NewDraftService(dir string) *DraftService {
+	return &DraftService{dir: dir}
+}
+
+// Save autosaves a user's code for a challenge and returns the version it went into.
+// Code identical to the latest version is not saved again; the latest version is returned as is.
+func (ds *DraftService) Save(username, ref string, files models.SubmissionFiles, at time.Time) (*models.DraftVersion, error) {
+	if err := ValidateSubmissionFiles(files); err != nil {
+		return nil, err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	draft, err := ds.load(username, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if n := len(draft.Versions); n > 0 {
+		latest := &draft.Versions[n-1]
+		if sameFiles(latest.Files, files) {
+			return summarizeDraftVersion(*latest), nil
+		}
+		// A restore always starts a version of its own, so it is never overwritten
+		if latest.RestoredFrom == 0 && at.Sub(latest.CreatedAt) < draftSnapshotInterval {
+			latest.Files = files
+			latest.Bytes = filesSize(files)
+			latest.SavedAt = at
+			return summarizeDraftVersion(*latest), ds.save(draft)
+		}
+	}
+
+	version := ds.append(draft, models.DraftVersion{Files: files, CreatedAt: at, SavedAt: at})
+	return summarizeDraftVersion(version), ds.save(draft)
+}
+
+// Latest returns the newest version of a user's draft for a challenge, with its files
+func (ds *DraftService) Latest(username, ref string) (*models.DraftVersion, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	draft, err := ds.load(username, ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(draft.Versions) == 0 {
+		return nil, ErrDraftNotFound
+	}
+	latest := draft.Versions[len(draft.Versions)-1]
+	return &latest, nil
+}
+
+// History lists the versions of a user's draft for a challenge, newest first and without their files
+func (ds *DraftService) History(username, ref string) (*models.Draft, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	draft, err := ds.load(username, ref)
+	if err != nil {
+		return nil, err
+	}
+	history := &models.Draft{Username: draft.Username, Ref: draft.Ref, Versions: make([]models.DraftVersion, 0, len(draft.Versions))}
+	for i := len(draft.Versions) - 1; i >= 0; i-- {
+		history.Versions = append(history.Versions, *summarizeDraftVersion(draft.Versions[i]))
+	}
+	return history, nil
+}
+
+// Version returns one version of a user's draft for a challenge, with its files
+func (ds *DraftService) Version(username, ref string, version int) (*models.DraftVersion, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	draft, err := ds.load(username, ref)
+	if err != nil {
+		return nil, err
+	}
+	found, ok := findDraftVersion(draft, version)
+	if !ok {
+		return nil, ErrDraftNotFound
+	}
+	return &found, nil
+}
+
+// Restore makes an earlier version the latest by copying it into a new version, which it returns with its files
+func (ds *DraftService) Restore(username, ref string, version int, at time.Time) (*models.DraftVersion, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	draft, err := ds.load(username, ref)
+	if err != nil {
+		return nil, err
+	}
+	found, ok := findDraftVersion(draft, version)
+	if !ok {
+		return nil, ErrDraftNotFound
+	}
+
+	restored := ds.append(draft, models.DraftVersion{Files: found.Files, CreatedAt: at, SavedAt: at, RestoredFrom: version})
+	return &restored, ds.save(draft)
+}
+
+// append adds a version to a draft, numbering it and dropping the oldest versions beyond the limit
+func (ds *DraftService) append(draft *models.Draft, version models.DraftVersion) models.DraftVersion {
+	version.Version = 1
+	if n := len(draft.Versions); n > 0 {
+		version.Version = draft.Versions[n-1].Version + 1
+	}
+	version.Bytes = filesSize(version.Files)
+	draft.Versions = append(draft.Versions, version)
+	if len(draft.Versions) > maxDraftVersions {
+		draft.Versions = draft.Versions[len(draft.Versions)-maxDraftVersions:]
+	}
+	return version
+}
+
+// findDraftVersion returns a version of a draft by number
+func findDraftVersion(draft *models.Draft, version int) (models.DraftVersion, bool) {
+	for _, v := range draft.Versions {
+		if v.Version == version {
+			return v, true
+		}
+	}
+	return models.DraftVersion{}, false
+}
+
+// summarizeDraftVersion returns a version without its files
+func summarizeDraftVersion(version models.DraftVersion) *models.DraftVersion {
+	version.Files = nil
+	return &version
+}
+
+// sameFiles reports whether two sets of files have the same names and contents
+func sameFiles(a, b models.SubmissionFiles) bool {
+	return len(DiffSolutionFiles(a, b)) == 0
+}
+
+// filesSize returns the combined size of a set of files
+func filesSize(files models.SubmissionFiles) int {
+	size := 0
+	for _, content := range files {
+		size += len(content)
+	}
+	return size
+}
+
+// path returns the draft file of a user and challenge ref, e.g. {dir}/alice/classic/7.json
+func (ds *DraftService) path(username, ref string) (string, error) {
+	if !submissionPathSegment.MatchString(username) {
+		return "", fmt.Errorf("invalid username %q", username)
+	}
+	track, id, found := strings.Cut(ref, "/")
+	if !found || !submissionPathSegment.MatchString(track) || !submissionPathSegment.MatchString(id) {
+		return "", fmt.Errorf("invalid challenge ref %q", ref)
+	}
+	return filepath.Join(ds.dir, username, track, id+".json"), nil
+}
+
+// load reads a user's draft for a challenge, returning an empty draft if there is none
+func (ds *DraftService) load(username, ref string) (*models.Draft, error) {
+	path, err := ds.path(username, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	draft := &models.Draft{Username: username, Ref: ref}
+	if err := loadJSON(path, draft); err != nil {
+		return nil, fmt.Errorf("failed to load draft: %v", err)
+	}
+	if draft.Versions == nil {
+		draft.Versions = []models.DraftVersion{}
+	}
+	return draft, nil
+}
+
+// save writes a draft to disk, replacing the previous file atomically
+func (ds *DraftService) save(draft *models.Draft) error {
+	path, err := ds.path(draft.Username, draft.Ref)
+	if err != nil {
+		return err
+	}
+	if err := writeJSONAtomic(path, draft); err != nil {
+		return fmt.Errorf("failed to save draft: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,888 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+const (
+	// editorTestFile is the name the challenge's visible tests get in an editing workspace, as in runs
+	editorTestFile = "solution_test.go"
+	// editorSendBuffer is how many messages can be queued for a client before its session is closed
+	editorSendBuffer = 64
+	// editorMaxFiles is how many files a client can open in one session
+	editorMaxFiles = MaxSubmissionFiles
+	// editorMaxCompletions caps the completions returned for one request
+	editorMaxCompletions = 100
+	// editorMaxMessage is the largest message accepted from gopls
+	editorMaxMessage = 64 << 20
+
+	editorInitTimeout     = 60 * time.Second // gopls loads the workspace before answering initialize
+	editorCallTimeout     = 10 * time.Second
+	editorShutdownTimeout = 3 * time.Second
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+)
+
+// Errors returned by the editor service
+var (
+	ErrEditorUnavailable  = errors.New("gopls is not installed")
+	ErrEditorSessionLimit = errors.New("too many editor sessions for this user")
+	ErrEditorBusy         = errors.New("too many editor sessions on this server")
+	ErrEditorClosed       = errors.New("editor session closed")
+)
+
+// EditorLimits bounds the gopls processes an editor service runs
+type EditorLimits struct {
+	SessionsPerUser int           // Open sessions per username
+	MaxSessions     int           // Open sessions in total
+	IdleTimeout     time.Duration // Sessions without a message for this long are closed
+}
+
+// DefaultEditorLimits are the limits the server runs with
+var DefaultEditorLimits = EditorLimits{
+	SessionsPerUser: 2,
+	MaxSessions:     20,
+	IdleTimeout:     10 * time.Minute,
+}
+
+// EditorService bridges challenge editors to gopls. Each session runs its own gopls
+// in a temporary workspace holding the challenge's template, test file and support files.
+type EditorService struct {
+	mu               sync.Mutex
+	executionService *ExecutionService
+	command          string
+	limits           EditorLimits
+	sessions         map[*EditorSession]bool
+}
+
+// EditorSession is one editor connected to a gopls process
+type EditorSession struct {
+	Username string
+	Ref      string
+	Send     chan []byte // JSON-RPC responses and notifications for the client
+
+	service      *EditorService
+	dir          string
+	solutionFile string
+	cmd          *exec.Cmd
+	stdin        io.WriteCloser
+	writeMu      sync.Mutex
+	done         chan struct{} // Closed when the session is closed
+	lspDone      chan struct{} // Closed when gopls stops writing
+	closeOnce    sync.Once
+
+	mu       sync.Mutex
+	nextID   int
+	pending  map[int]chan *rpcMessage
+	versions map[string]int // Open files and their versions
+	idle     *time.Timer
+}
+
+// rpcMessage is a JSON-RPC 2.0 request, response or notification, to or from either side of the bridge
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is the error of a JSON-RPC response
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// editorParams are the parameters of client requests. Lines and columns start at 1,
+// and columns count UTF-16 code units like the editor does.
+type editorParams struct {
+	File   string  `json:"file"` // Defaults to the challenge's solution file
+	Text   *string `json:"text"` // Full text of the file, for update
+	Line   int     `json:"line"`
+	Column int     `json:"column"`
+}
+
+// LSP structures used by the bridge; positions start at 0
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+type lspCompletionItem struct {
+	Label         string          `json:"label"`
+	Kind          int             `json:"kind"`
+	Detail        string          `json:"detail"`
+	Documentation json.RawMessage `json:"documentation"`
+	InsertText    string          `json:"insertText"`
+	TextEdit      *struct {
+		NewText string `json:"newText"`
+	} `json:"textEdit"`
+}
+
+// lspCompletionKinds names the LSP completion item kinds gopls returns
+var lspCompletionKinds = map[int]string{
+	2:  "method",
+	3:  "function",
+	5:  "field",
+	6:  "variable",
+	7:  "type",
+	8:  "interface",
+	9:  "package",
+	14: "keyword",
+	15: "snippet",
+	21: "constant",
+	22: "struct",
+	25: "type parameter",
+}
+
+// NewEditorService creates an editor service running command, or gopls from the PATH if it is empty
+func NewEditorService(executionService *ExecutionService, command string, limits EditorLimits) *EditorService {
+	if command == "" {
+		command = "gopls"
+	}
+	return &EditorService{
+		executionService: executionService,
+		command:          command,
+		limits:           limits,
+		sessions:         make(map[*EditorSession]bool),
+	}
+}
+
+// Open starts a gopls session on a challenge for a user
+func (s *EditorService) Open(challenge *models.TrackChallenge, username string) (*EditorSession, error) {
+	command, err := exec.LookPath(s.command)
+	if err != nil {
+		return nil, ErrEditorUnavailable
+	}
+
+	s.mu.Lock()
+	count := 0
+	for session := range s.sessions {
+		if session.Username == username {
+			count++
+		}
+	}
+	if count >= s.limits.SessionsPerUser {
+		s.mu.Unlock()
+		return nil, ErrEditorSessionLimit
+	}
+	if len(s.sessions) >= s.limits.MaxSessions {
+		s.mu.Unlock()
+		return nil, ErrEditorBusy
+	}
+	session := &EditorSession{
+		Username:     username,
+		Ref:          challenge.Ref,
+		Send:         make(chan []byte, editorSendBuffer),
+		service:      s,
+		solutionFile: challenge.SolutionFile,
+		done:         make(chan struct{}),
+		lspDone:      make(chan struct{}),
+		pending:      make(map[int]chan *rpcMessage),
+		versions:     make(map[string]int),
+	}
+	// The slot is taken while gopls starts
+	s.sessions[session] = true
+	s.mu.Unlock()
+
+	if err := session.start(command, challenge); err != nil {
+		s.Close(session)
+		return nil, err
+	}
+	return session, nil
+}
+
+// Close shuts down a session's gopls and removes its workspace
+func (s *EditorService) Close(session *EditorSession) {
+	session.close("")
+}
+
+// HandleMessage answers a JSON-RPC message from a session's client. Updates are applied in order;
+// completion, hover and definition requests are answered on the session's Send queue as gopls replies.
+func (s *EditorService) HandleMessage(session *EditorSession, data []byte) {
+	session.touch()
+
+	var request rpcMessage
+	if err := json.Unmarshal(data, &request); err != nil {
+		session.reply(json.RawMessage("null"), nil, &rpcError{Code: rpcParseError, Message: "invalid JSON"})
+		return
+	}
+	if request.Method == "" {
+		session.reply(request.ID, nil, &rpcError{Code: rpcInvalidRequest, Message: "method is required"})
+		return
+	}
+
+	var params editorParams
+	if len(request.Params) > 0 {
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			session.reply(request.ID, nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()})
+			return
+		}
+	}
+	if params.File == "" {
+		params.File = session.solutionFile
+	}
+
+	switch request.Method {
+	case "update":
+		version, err := session.update(params)
+		if err != nil {
+			session.reply(request.ID, nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()})
+			return
+		}
+		session.reply(request.ID, map[string]int{"version": version}, nil)
+	case "completion", "hover", "definition":
+		if err := session.checkPosition(params); err != nil {
+			session.reply(request.ID, nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()})
+			return
+		}
+		go func() {
+			result, err := session.query(request.Method, params)
+			if err != nil {
+				session.reply(request.ID, nil, &rpcError{Code: rpcInternalError, Message: err.Error()})
+				return
+			}
+			session.reply(request.ID, result, nil)
+		}()
+	default:
+		session.reply(request.ID, nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("unknown method %q", request.Method)})
+	}
+}
+
+// Done is closed when the session is closed
+func (session *EditorSession) Done() <-chan struct{} {
+	return session.done
+}
+
+// start prepares the workspace and initializes gopls on it
+func (session *EditorSession) start(command string, challenge *models.TrackChallenge) error {
+	dir, err := os.MkdirTemp("", "editor-session")
+	if err != nil {
+		return fmt.Errorf("failed to create workspace: %v", err)
+	}
+	// gopls reports resolved paths, so the workspace is addressed without symlinks
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	session.dir = dir
+
+	files := models.SubmissionFiles{challenge.SolutionFile: challenge.Template}
+	if err := WriteSubmissionFiles(dir, files); err != nil {
+		return fmt.Errorf("failed to write template: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, editorTestFile), []byte(challenge.TestFile), 0644); err != nil {
+		return fmt.Errorf("failed to write test file: %v", err)
+	}
+	if err := copySupportFiles(challenge.Dir, challenge.SupportFiles, dir, files); err != nil {
+		return fmt.Errorf("failed to copy support files: %v", err)
+	}
+	if err := session.service.executionService.initGoModule(dir, challenge.Module); err != nil {
+		return fmt.Errorf("failed to initialize Go module: %v", err)
+	}
+	// Missing modules are reported by gopls as diagnostics
+	if err := session.service.executionService.installDependencies(dir, challenge.Template+"\n"+challenge.TestFile, challenge.Dependencies); err != nil {
+		log.Printf("Editor session for %s: %v", challenge.Ref, err)
+	}
+
+	cmd := exec.Command(command)
+	cmd.Dir = dir
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start gopls: %v", err)
+	}
+	session.cmd = cmd
+	session.stdin = stdin
+	go session.listen(stdout)
+
+	root := fileURI(dir)
+	initialize := map[string]interface{}{
+		"processId":        os.Getpid(),
+		"rootUri":          root,
+		"workspaceFolders": []map[string]string{{"uri": root, "name": "challenge"}},
+		"capabilities": map[string]interface{}{
+			"textDocument": map[string]interface{}{
+				"completion": map[string]interface{}{
+					"completionItem": map[string]interface{}{"snippetSupport": false, "documentationFormat": []string{"plaintext"}},
+				},
+				"hover":              map[string]interface{}{"contentFormat": []string{"plaintext"}},
+				"publishDiagnostics": map[string]interface{}{"versionSupport": true},
+			},
+			"workspace": map[string]interface{}{"configuration": true},
+		},
+	}
+	if err := session.call("initialize", initialize, nil, editorInitTimeout); err != nil {
+		return fmt.Errorf("failed to initialize gopls: %v", err)
+	}
+	if err := session.notify("initialized", struct{}{}); err != nil {
+		return err
+	}
+	for name, text := range map[string]string{challenge.SolutionFile: challenge.Template, editorTestFile: challenge.TestFile} {
+		if err := session.open(name, text); err != nil {
+			return err
+		}
+	}
+
+	session.mu.Lock()
+	session.idle = time.AfterFunc(session.service.limits.IdleTimeout, func() { session.close("idle") })
+	session.mu.Unlock()
+	return nil
+}
+
+// close stops the session once, telling the client why if there is a reason
+func (session *EditorSession) close(reason string) {
+	session.closeOnce.Do(func() {
+		s := session.service
+		s.mu.Lock()
+		delete(s.sessions, session)
+		s.mu.Unlock()
+
+		session.mu.Lock()
+		if session.idle != nil {
+			session.idle.Stop()
+		}
+		session.mu.Unlock()
+
+		if reason != "" {
+			session.clientNotify("closed", map[string]string{"reason": reason})
+		}
+
+		if session.cmd != nil {
+			session.call("shutdown", nil, nil, editorShutdownTimeout)
+			session.notify("exit", nil)
+			session.stdin.Close()
+
+			exited := make(chan struct{})
+			go func() {
+				session.cmd.Wait()
+				close(exited)
+			}()
+			select {
+			case <-exited:
+			case <-time.After(editorShutdownTimeout):
+				session.cmd.Process.Kill()
+				<-exited
+			}
+		}
+
+		close(session.done)
+		if session.dir != "" {
+			os.RemoveAll(session.dir)
+		}
+	})
+}
+
+// touch restarts the idle timer
+func (session *EditorSession) touch() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.idle != nil {
+		session.idle.Reset(session.service.limits.IdleTimeout)
+	}
+}
+
+// open adds a file to the gopls view of the workspace
+func (session *EditorSession) open(name, text string) error {
+	session.mu.Lock()
+	session.versions[name] = 1
+	session.mu.Unlock()
+	return session.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": session.uri(name), "languageId": "go", "version": 1, "text": text},
+	})
+}
+
+// update replaces the text of an editable file, opening it if it is new, and returns its version
+func (session *EditorSession) update(params editorParams) (int, error) {
+	if params.Text == nil {
+		return 0, fmt.Errorf("text is required")
+	}
+	if err := validateSubmissionPath(params.File); err != nil {
+		return 0, err
+	}
+	if !strings.HasSuffix(params.File, ".go") {
+		return 0, fmt.Errorf("only Go files can be edited: %q", params.File)
+	}
+	if len(*params.Text) > MaxSubmissionBytes {
+		return 0, fmt.Errorf("file is %d bytes, at most %d are allowed", len(*params.Text), MaxSubmissionBytes)
+	}
+
+	session.mu.Lock()
+	version, exists := session.versions[params.File]
+	if !exists && len(session.versions) >= editorMaxFiles {
+		session.mu.Unlock()
+		return 0, fmt.Errorf("at most %d files can be open", editorMaxFiles)
+	}
+	if exists {
+		version++
+		session.versions[params.File] = version
+	}
+	session.mu.Unlock()
+
+	if !exists {
+		return 1, session.open(params.File, *params.Text)
+	}
+	return version, session.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": session.uri(params.File), "version": version},
+		"contentChanges": []map[string]string{{"text": *params.Text}},
+	})
+}
+
+// checkPosition validates the file and position of a query
+func (session *EditorSession) checkPosition(params editorParams) error {
+	session.mu.Lock()
+	_, open := session.versions[params.File]
+	session.mu.Unlock()
+	if !open {
+		return fmt.Errorf("file %q is not open", params.File)
+	}
+	if params.Line < 1 || params.Column < 1 {
+		return fmt.Errorf("line and column start at 1")
+	}
+	return nil
+}
+
+// query asks gopls for completions, hover documentation or definitions at a position
+func (session *EditorSession) query(method string, params editorParams) (interface{}, error) {
+	position := map[string]interface{}{
+		"textDocument": map[string]string{"uri": session.uri(params.File)},
+		"position":     lspPosition{Line: params.Line - 1, Character: params.Column - 1},
+	}
+
+	var raw json.RawMessage
+	if err := session.call("textDocument/"+method, position, &raw, editorCallTimeout); err != nil {
+		return nil, err
+	}
+
+	switch method {
+	case "completion":
+		return completionResult(raw), nil
+	case "hover":
+		var hover *struct {
+			Contents json.RawMessage `json:"contents"`
+			Range    *lspRange       `json:"range"`
+		}
+		if err := json.Unmarshal(raw, &hover); err != nil || hover == nil {
+			return nil, err
+		}
+		result := &models.EditorHover{Contents: markupText(hover.Contents)}
+		if hover.Range != nil {
+			result.Line, result.Column = hover.Range.Start.Line+1, hover.Range.Start.Character+1
+			result.EndLine, result.EndColumn = hover.Range.End.Line+1, hover.Range.End.Character+1
+		}
+		return result, nil
+	default:
+		var locations []lspLocation
+		if err := json.Unmarshal(raw, &locations); err != nil {
+			var location lspLocation
+			if err := json.Unmarshal(raw, &location); err != nil {
+				return nil, err
+			}
+			locations = []lspLocation{location}
+		}
+		result := []models.EditorLocation{}
+		for _, location := range locations {
+			if location.URI != "" {
+				result = append(result, session.location(location))
+			}
+		}
+		return result, nil
+	}
+}
+
+// completionResult converts a completion list, or a bare array of items, to editor completions
+func completionResult(raw json.RawMessage) []models.EditorCompletion {
+	var list struct {
+		Items []lspCompletionItem `json:"items"`
+	}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		json.Unmarshal(raw, &list.Items)
+	}
+
+	completions := []models.EditorCompletion{}
+	for _, item := range list.Items {
+		if len(completions) == editorMaxCompletions {
+			break
+		}
+		completion := models.EditorCompletion{
+			Label:         item.Label,
+			Kind:          lspCompletionKinds[item.Kind],
+			Detail:        item.Detail,
+			Documentation: markupText(item.Documentation),
+			InsertText:    item.Label,
+		}
+		if item.TextEdit != nil {
+			completion.InsertText = item.TextEdit.NewText
+		} else if item.InsertText != "" {
+			completion.InsertText = item.InsertText
+		}
+		completions = append(completions, completion)
+	}
+	return completions
+}
+
+// markupText returns the text of LSP markup content, a marked string or a list of marked strings
+func markupText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var content struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &content); err == nil {
+		return content.Value
+	}
+	var parts []json.RawMessage
+	if err := json.Unmarshal(raw, &parts); err == nil {
+		texts := make([]string, 0, len(parts))
+		for _, part := range parts {
+			texts = append(texts, markupText(part))
+		}
+		return strings.Join(texts, "\n\n")
+	}
+	return ""
+}
+
+// location converts an LSP location, hiding the workspace directory and shortening paths outside it
+func (session *EditorSession) location(location lspLocation) models.EditorLocation {
+	result := models.EditorLocation{
+		Line:   location.Range.Start.Line + 1,
+		Column: location.Range.Start.Character + 1,
+	}
+	if name, ok := session.workspaceFile(location.URI); ok {
+		result.File = name
+	} else {
+		result.File = externalPath(uriPath(location.URI))
+		result.External = true
+	}
+	return result
+}
+
+// listen reads gopls messages until it exits, routing responses to their callers
+func (session *EditorSession) listen(stdout io.Reader) {
+	defer close(session.lspDone)
+	reader := bufio.NewReader(stdout)
+	for {
+		message, err := readLSPMessage(reader)
+		if err != nil {
+			go session.close("gopls exited")
+			return
+		}
+
+		switch {
+		case message.Method == "" && message.ID != nil:
+			id, _ := strconv.Atoi(string(message.ID))
+			session.mu.Lock()
+			reply, waiting := session.pending[id]
+			delete(session.pending, id)
+			session.mu.Unlock()
+			if waiting {
+				reply <- message
+			}
+		case message.ID != nil:
+			session.answer(message)
+		case message.Method == "textDocument/publishDiagnostics":
+			session.publishDiagnostics(message.Params)
+		}
+	}
+}
+
+// answer replies to a request from gopls. Configuration requests get default settings.
+func (session *EditorSession) answer(request *rpcMessage) {
+	var result interface{}
+	if request.Method == "workspace/configuration" {
+		var params struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		json.Unmarshal(request.Params, &params)
+		settings := make([]map[string]interface{}, len(params.Items))
+		for i := range settings {
+			settings[i] = map[string]interface{}{}
+		}
+		result = settings
+	}
+	data, _ := json.Marshal(result)
+	session.write(&rpcMessage{JSONRPC: "2.0", ID: request.ID, Result: data})
+}
+
+// publishDiagnostics forwards gopls diagnostics for a workspace file to the client
+func (session *EditorSession) publishDiagnostics(raw json.RawMessage) {
+	var params struct {
+		URI         string          `json:"uri"`
+		Version     *int            `json:"version"`
+		Diagnostics []lspDiagnostic `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return
+	}
+	name, ok := session.workspaceFile(params.URI)
+	if !ok {
+		return
+	}
+
+	result := models.EditorDiagnostics{File: name, Diagnostics: []models.Diagnostic{}}
+	if params.Version != nil {
+		result.Version = *params.Version
+	} else {
+		session.mu.Lock()
+		result.Version = session.versions[name]
+		session.mu.Unlock()
+	}
+	for _, diagnostic := range params.Diagnostics {
+		severity := models.SeverityInfo
+		switch diagnostic.Severity {
+		case 1:
+			severity = models.SeverityError
+		case 2:
+			severity = models.SeverityWarning
+		}
+		result.Diagnostics = append(result.Diagnostics, models.Diagnostic{
+			Tool:     "gopls",
+			File:     name,
+			Line:     diagnostic.Range.Start.Line + 1,
+			Column:   diagnostic.Range.Start.Character + 1,
+			Message:  diagnostic.Message,
+			Severity: severity,
+		})
+	}
+	session.clientNotify("diagnostics", result)
+}
+
+// call sends a request to gopls and decodes its result into result, if it is not nil
+func (session *EditorSession) call(method string, params, result interface{}, timeout time.Duration) error {
+	reply := make(chan *rpcMessage, 1)
+	session.mu.Lock()
+	session.nextID++
+	id := session.nextID
+	session.pending[id] = reply
+	session.mu.Unlock()
+	defer func() {
+		session.mu.Lock()
+		delete(session.pending, id)
+		session.mu.Unlock()
+	}()
+
+	message, err := newRPCMessage(method, params)
+	if err != nil {
+		return err
+	}
+	message.ID = json.RawMessage(strconv.Itoa(id))
+	if err := session.write(message); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case response := <-reply:
+		if response.Error != nil {
+			return fmt.Errorf("%s: %s", method, response.Error.Message)
+		}
+		if result != nil && len(response.Result) > 0 {
+			return json.Unmarshal(response.Result, result)
+		}
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("%s timed out", method)
+	case <-session.lspDone:
+		return ErrEditorClosed
+	}
+}
+
+// notify sends a notification to gopls
+func (session *EditorSession) notify(method string, params interface{}) error {
+	message, err := newRPCMessage(method, params)
+	if err != nil {
+		return err
+	}
+	return session.write(message)
+}
+
+// write sends a message to gopls with its Content-Length header
+func (session *EditorSession) write(message *rpcMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	session.writeMu.Lock()
+	defer session.writeMu.Unlock()
+	if _, err := fmt.Fprintf(session.stdin, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		return ErrEditorClosed
+	}
+	return nil
+}
+
+// reply queues the response to a client request; notifications, which have no ID, get none
+func (session *EditorSession) reply(id json.RawMessage, result interface{}, rpcErr *rpcError) {
+	if id == nil {
+		return
+	}
+	response := rpcMessage{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			response.Error = &rpcError{Code: rpcInternalError, Message: err.Error()}
+		} else {
+			response.Result = data
+		}
+	}
+	session.queue(response)
+}
+
+// clientNotify queues a notification for the client
+func (session *EditorSession) clientNotify(method string, params interface{}) {
+	message, err := newRPCMessage(method, params)
+	if err != nil {
+		return
+	}
+	session.queue(message)
+}
+
+// queue adds a message to the client's queue, closing the session if the client has fallen too far behind
+func (session *EditorSession) queue(message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	select {
+	case session.Send <- data:
+	case <-session.done:
+	default:
+		go session.close("")
+	}
+}
+
+// uri returns the file URI of a workspace file
+func (session *EditorSession) uri(name string) string {
+	return fileURI(filepath.Join(session.dir, filepath.FromSlash(name)))
+}
+
+// workspaceFile returns the workspace-relative name of a file URI, if it is in the workspace
+func (session *EditorSession) workspaceFile(uri string) (string, bool) {
+	rel, err := filepath.Rel(session.dir, uriPath(uri))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// newRPCMessage creates a JSON-RPC request or notification
+func newRPCMessage(method string, params interface{}) (*rpcMessage, error) {
+	message := &rpcMessage{JSONRPC: "2.0", Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		message.Params = data
+	}
+	return message, nil
+}
+
+// readLSPMessage reads one message framed by a Content-Length header
+func readLSPMessage(reader *bufio.Reader) (*rpcMessage, error) {
+	length := -1
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		name, value, found := strings.Cut(line, ":")
+		if found && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+		}
+	}
+	if length < 0 || length > editorMaxMessage {
+		return nil, fmt.Errorf("invalid message length %d", length)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, err
+	}
+	var message rpcMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return nil, fmt.Errorf("invalid message: %v", err)
+	}
+	return &message, nil
+}
+
+// fileURI returns the file:// URI of a path
+func fileURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// uriPath returns the path of a file:// URI
+func uriPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// externalPath shortens a path in the module cache or a GOROOT to its import path and file name
+func externalPath(path string) string {
+	slashed := filepath.ToSlash(path)
+	if i := strings.Index(slashed, "/pkg/mod/"); i >= 0 {
+		return slashed[i+len("/pkg/mod/"):]
+	}
+	if i := strings.LastIndex(slashed, "/src/"); i >= 0 {
+		return slashed[i+len("/src/"):]
+	}
+	return filepath.Base(path)
+}
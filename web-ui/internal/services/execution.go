@@ -5,7 +5,9 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,24 +15,96 @@ import (
 )
 
 // ExecutionService handles code execution and testing
-type ExecutionService struct{}
+type ExecutionService struct {
+	analyzers []string // Analyzers run on every submission in addition to go vet
+}
 
 // NewExecutionService creates a new execution service
 func NewExecutionService() *ExecutionService {
-	return &ExecutionService{}
+	// Enable every known analyzer that is installed on this machine
+	var analyzers []string
+	for name, command := range analyzerCommands {
+		if _, err := exec.LookPath(command[0]); err == nil {
+			analyzers = append(analyzers, name)
+		}
+	}
+	sort.Strings(analyzers)
+
+	return &ExecutionService{analyzers: analyzers}
 }
 
 // ExecutionResult represents the result of code execution
 type ExecutionResult struct {
-	Passed      bool   `json:"passed"`
-	Output      string `json:"output"`
-	ExecutionMs int64  `json:"executionMs"`
+	Passed         bool                  `json:"passed"`
+	Output         string                `json:"output"`
+	ExecutionMs    int64                 `json:"executionMs"`
+	Diagnostics    []models.Diagnostic   `json:"diagnostics"`            // gofmt, go vet and analyzer findings
+	Mode           string                `json:"mode"`                   // Run mode the tests ran in
+	Race           bool                  `json:"race"`                   // Whether the race detector was enabled
+	RaceDetected   bool                  `json:"raceDetected"`           // Whether the race detector reported a data race
+	Coverage       *CoverageReport       `json:"coverage,omitempty"`     // Only set for cover runs
+	Benchmark      *BenchmarkReport      `json:"benchmark,omitempty"`    // Only set for bench runs
+	Fuzz           *FuzzReport           `json:"fuzz,omitempty"`         // Only set for fuzz runs
+	Differential   *DifferentialReport   `json:"differential,omitempty"` // Only set for challenges with a reference solution
+	HiddenTests    []models.TestOutcome  `json:"hiddenTests,omitempty"`  // Hidden test outcomes, by name only
+	IncludesHidden bool                  `json:"includesHidden"`         // Whether the run included the hidden tests
+	Quality        *models.QualityReport `json:"quality,omitempty"`      // Only set for passing runs
+}
+
+// Run modes accepted by RunWithOptions
+const (
+	RunModeDefault = ""
+	RunModeRace    = "race"  // go test -race
+	RunModeCover   = "cover" // go test -coverprofile with a per-function report
+	RunModeShort   = "short" // go test -short
+	RunModeBench   = "bench" // tests followed by go test -bench -benchmem -count=N
+	RunModeFuzz    = "fuzz"  // tests followed by a bounded go test -fuzz per fuzz target
+)
+
+// RunOptions controls how a submission's tests are run
+type RunOptions struct {
+	Mode          string `json:"mode"`
+	Count         int    `json:"count,omitempty"` // Benchmark samples for bench runs
+	IncludeHidden bool   `json:"-"`               // Also run the challenge's hidden tests, for submissions
+}
+
+// ValidRunMode reports whether mode is a known run mode
+func ValidRunMode(mode string) bool {
+	switch mode {
+	case RunModeDefault, RunModeRace, RunModeCover, RunModeShort, RunModeBench, RunModeFuzz:
+		return true
+	}
+	return false
 }
 
 // RunCode executes the provided code against a challenge's tests
-func (es *ExecutionService) RunCode(code string, challenge *models.Challenge) ExecutionResult {
+func (es *ExecutionService) RunCode(code string, challenge *models.TrackChallenge) ExecutionResult {
+	return es.RunFiles(models.SubmissionFiles{challenge.SolutionFile: code}, challenge)
+}
+
+// RunFiles executes a multi-file submission against a challenge's tests
+func (es *ExecutionService) RunFiles(files models.SubmissionFiles, challenge *models.TrackChallenge) ExecutionResult {
+	return es.RunWithOptions(files, challenge, RunOptions{})
+}
+
+// RunWithOptions executes a multi-file submission in the requested run mode
+func (es *ExecutionService) RunWithOptions(files models.SubmissionFiles, challenge *models.TrackChallenge, opts RunOptions) ExecutionResult {
 	start := time.Now()
 
+	if !ValidRunMode(opts.Mode) {
+		return ExecutionResult{
+			Passed: false,
+			Output: fmt.Sprintf("Unknown run mode %q", opts.Mode),
+		}
+	}
+
+	if err := ValidateSubmissionFiles(files); err != nil {
+		return ExecutionResult{
+			Passed: false,
+			Output: fmt.Sprintf("Invalid submission: %v", err),
+		}
+	}
+
 	// Create temporary directory for execution
 	tempDir, err := ioutil.TempDir("", "challenge-exec")
 	if err != nil {
@@ -41,9 +115,8 @@ func (es *ExecutionService) RunCode(code string, challenge *models.Challenge) Ex
 	}
 	defer os.RemoveAll(tempDir)
 
-	// Write the submitted code to temporary file
-	codePath := filepath.Join(tempDir, "solution-template.go")
-	err = ioutil.WriteFile(codePath, []byte(code), 0644)
+	// Write the submitted files to the temporary directory
+	err = WriteSubmissionFiles(tempDir, files)
 	if err != nil {
 		return ExecutionResult{
 			Passed: false,
@@ -61,8 +134,17 @@ func (es *ExecutionService) RunCode(code string, challenge *models.Challenge) Ex
 		}
 	}
 
+	// Copy fixture and support files declared by the challenge
+	err = copySupportFiles(challenge.Dir, challenge.SupportFiles, tempDir, files)
+	if err != nil {
+		return ExecutionResult{
+			Passed: false,
+			Output: fmt.Sprintf("Failed to copy support files: %v", err),
+		}
+	}
+
 	// Initialize Go module
-	err = es.initGoModule(tempDir, challenge.ID)
+	err = es.initGoModule(tempDir, challenge.Module)
 	if err != nil {
 		return ExecutionResult{
 			Passed: false,
@@ -71,7 +153,7 @@ func (es *ExecutionService) RunCode(code string, challenge *models.Challenge) Ex
 	}
 
 	// Automatically detect and install dependencies based on imports
-	err = es.installDependencies(tempDir, code, challenge.ID)
+	err = es.installDependencies(tempDir, files.GoSource(), challenge.Dependencies)
 	if err != nil {
 		return ExecutionResult{
 			Passed: false,
@@ -79,8 +161,12 @@ func (es *ExecutionService) RunCode(code string, challenge *models.Challenge) Ex
 		}
 	}
 
+	// Run static analysis before the tests
+	diagnostics := es.analyzeSubmission(tempDir, files, challenge.Execution)
+
 	// Run tests
-	cmd := exec.Command("go", "test", "-v")
+	race := opts.Mode == RunModeRace || challenge.Execution.RequireRace
+	cmd := exec.Command("go", es.testArgs(opts.Mode, race)...)
 	cmd.Dir = tempDir
 
 	output, err := cmd.CombinedOutput()
@@ -90,6 +176,9 @@ func (es *ExecutionService) RunCode(code string, challenge *models.Challenge) Ex
 	result := ExecutionResult{
 		Output:      outputStr,
 		ExecutionMs: executionTime,
+		Diagnostics: diagnostics,
+		Mode:        opts.Mode,
+		Race:        race,
 	}
 
 	if err == nil {
@@ -106,21 +195,130 @@ func (es *ExecutionService) RunCode(code string, challenge *models.Challenge) Ex
 		}
 	}
 
+	if race && strings.Contains(outputStr, "WARNING: DATA RACE") {
+		result.RaceDetected = true
+		result.Passed = false
+	}
+
+	if opts.IncludeHidden {
+		es.runHiddenTests(tempDir, challenge, race, &result)
+	}
+
+	// Challenges with a hidden reference solution also compare the submission on random inputs
+	if result.Passed && HasReference(challenge.Dir) {
+		es.runDifferential(tempDir, challenge, files, &result)
+	}
+
+	if opts.Mode == RunModeBench && result.Passed {
+		es.runBenchmarks(tempDir, challenge, opts, &result)
+	}
+
+	if opts.Mode == RunModeFuzz && result.Passed {
+		es.runFuzz(tempDir, challenge, &result)
+	}
+
+	if opts.Mode == RunModeCover {
+		report, err := buildCoverageReport(tempDir, filepath.Join(tempDir, coverProfileName), challenge.Module, files)
+		if err != nil {
+			result.Output += fmt.Sprintf("\nCoverage report unavailable: %v\n", err)
+		} else {
+			result.Coverage = report
+		}
+	}
+
+	// Challenges may require a clean go vet and analyzer report to pass
+	if result.Passed && challenge.Execution.RequireCleanVet && hasBlockingDiagnostics(diagnostics) {
+		result.Passed = false
+		result.Output += "\nThis challenge requires a clean go vet report. Fix the diagnostics above to pass.\n"
+	}
+
+	// Passing runs get a quality score that does not affect passing
+	if result.Passed {
+		result.Quality = scoreQuality(files, challenge.Execution.Quality, &result)
+	}
+
 	return result
 }
 
+// runBenchmarks runs the benchmarks of a passing submission and applies the challenge's speedup requirement
+func (es *ExecutionService) runBenchmarks(tempDir string, challenge *models.TrackChallenge, opts RunOptions, result *ExecutionResult) {
+	policy := challenge.Execution.Benchmark
+	if err := checkBenchmarkPolicy(policy); err != nil {
+		result.Passed = false
+		result.Output += fmt.Sprintf("\nInvalid benchmark policy: %v\n", err)
+		return
+	}
+
+	start := time.Now()
+	output, err := runTool(tempDir, "go", benchmarkArgs(policy, opts.Count, benchmarkPattern(solutionBenchmarks(policy)))...)
+	result.ExecutionMs += time.Since(start).Milliseconds()
+	result.Output += "\n" + output
+
+	if err != nil {
+		result.Passed = false
+		result.Output += fmt.Sprintf("\nBenchmarks failed: %v\n", err)
+		return
+	}
+
+	var baseline []BenchmarkStats
+	if policy != nil && (policy.Baseline != "" || len(policy.BaselineCode) > 0) {
+		start = time.Now()
+		baseline, err = es.loadBenchmarkBaseline(challenge, opts.Count)
+		result.ExecutionMs += time.Since(start).Milliseconds()
+		if err != nil {
+			result.Passed = false
+			result.Output += fmt.Sprintf("\nBenchmark baseline unavailable: %v\n", err)
+			return
+		}
+	}
+
+	report, err := buildBenchmarkReport(output, baseline, policy)
+	result.Benchmark = report
+	if err != nil {
+		result.Output += fmt.Sprintf("\nBenchmark comparison unavailable: %v\n", err)
+	}
+
+	if report != nil && !report.MeetsMinSpeedup {
+		result.Passed = false
+		result.Output += fmt.Sprintf("\nThis challenge requires a %.1fx speedup over the baseline, got %.2fx.\n", report.MinSpeedup, report.GeomeanSpeedup)
+	}
+}
+
+// RequiresBenchmark reports whether a challenge can only pass with a bench run
+func RequiresBenchmark(challenge *models.TrackChallenge) bool {
+	return challenge.Execution.Benchmark != nil && challenge.Execution.Benchmark.MinSpeedup > 0
+}
+
+// coverProfileName is the cover profile written by cover runs
+const coverProfileName = "coverage.out"
+
+// testArgs builds the go test arguments for a run mode
+func (es *ExecutionService) testArgs(mode string, race bool) []string {
+	args := []string{"test", "-v"}
+	if race {
+		args = append(args, "-race")
+	}
+	switch mode {
+	case RunModeCover:
+		args = append(args, "-coverprofile="+coverProfileName)
+	case RunModeShort:
+		args = append(args, "-short")
+	}
+	return args
+}
+
 // initGoModule initializes a Go module in the temporary directory
-func (es *ExecutionService) initGoModule(tempDir string, challengeID int) error {
+func (es *ExecutionService) initGoModule(tempDir string, module string) error {
 	// Initialize go.mod
-	cmd := exec.Command("go", "mod", "init", fmt.Sprintf("challenge-%d", challengeID))
+	cmd := exec.Command("go", "mod", "init", module)
 	cmd.Dir = tempDir
 	return cmd.Run()
 }
 
-// installDependencies installs dependencies for the given challenge
-func (es *ExecutionService) installDependencies(tempDir string, code string, challengeID int) error {
+// installDependencies installs the challenge's declared dependencies and the code's external imports
+func (es *ExecutionService) installDependencies(tempDir string, code string, dependencies models.Dependencies) error {
 	// Detect imports from the code
-	requiredPackages := es.detectRequiredPackages(code, challengeID)
+	requiredPackages := es.detectRequiredPackages(code, dependencies)
 
 	if len(requiredPackages) == 0 {
 		return nil // No external dependencies needed
@@ -146,36 +344,13 @@ func (es *ExecutionService) installDependencies(tempDir string, code string, cha
 	return nil
 }
 
-// detectRequiredPackages analyzes the code to detect required external packages
-func (es *ExecutionService) detectRequiredPackages(code string, challengeID int) []string {
+// detectRequiredPackages returns the declared modules plus the external packages and drivers the code imports
+func (es *ExecutionService) detectRequiredPackages(code string, dependencies models.Dependencies) []string {
 	packages := make(map[string]bool)
 
-	// Common package mappings for known dependencies
-	knownPackages := map[string][]string{
-		"github.com/mattn/go-sqlite3": {"github.com/mattn/go-sqlite3"},
-		"database/sql":                {"github.com/mattn/go-sqlite3"}, // SQLite driver needed for database/sql
-		"google.golang.org/grpc":      {"google.golang.org/grpc", "google.golang.org/grpc/codes", "google.golang.org/grpc/status"},
-		"github.com/google/uuid":      {"github.com/google/uuid"},
-		"github.com/gorilla/mux":      {"github.com/gorilla/mux"},
-		"github.com/gin-gonic/gin":    {"github.com/gin-gonic/gin"},
-		"github.com/stretchr/testify": {"github.com/stretchr/testify"},
-		"go.mongodb.org/mongo-driver": {"go.mongodb.org/mongo-driver/mongo", "go.mongodb.org/mongo-driver/bson"},
-		"github.com/redis/go-redis":   {"github.com/redis/go-redis/v9"},
-		"gorm.io/gorm":                {"gorm.io/gorm", "gorm.io/driver/sqlite"},
-	}
-
-	// Challenge-specific package requirements
-	challengePackages := map[int][]string{
-		13: {"github.com/mattn/go-sqlite3"},                                                             // SQL Database Operations
-		14: {"google.golang.org/grpc", "google.golang.org/grpc/codes", "google.golang.org/grpc/status"}, // gRPC
-		9:  {"github.com/google/uuid"},                                                                  // RESTful API with UUID
-	}
-
-	// Add challenge-specific packages
-	if challengePkgs, exists := challengePackages[challengeID]; exists {
-		for _, pkg := range challengePkgs {
-			packages[pkg] = true
-		}
+	// Modules declared in package.json or metadata.json are always installed
+	for _, module := range dependencies.Modules {
+		packages[module] = true
 	}
 
 	// Scan code for import statements
@@ -200,12 +375,11 @@ func (es *ExecutionService) detectRequiredPackages(code string, challengeID int)
 			// Extract import path
 			importPath := es.extractImportPath(line)
 			if importPath != "" {
-				// Check if this import requires external packages
-				if deps, exists := knownPackages[importPath]; exists {
-					for _, dep := range deps {
-						packages[dep] = true
-					}
-				} else if es.isExternalPackage(importPath) {
+				// Imports with a declared driver also need the driver's modules
+				for _, dep := range dependencies.Drivers[importPath] {
+					packages[dep] = true
+				}
+				if es.isExternalPackage(importPath) && !declaresModule(dependencies.Modules, importPath) {
 					packages[importPath] = true
 				}
 			}
@@ -217,10 +391,22 @@ func (es *ExecutionService) detectRequiredPackages(code string, challengeID int)
 	for pkg := range packages {
 		result = append(result, pkg)
 	}
+	sort.Strings(result)
 
 	return result
 }
 
+// declaresModule reports whether an import path belongs to one of the declared modules
+func declaresModule(modules []string, importPath string) bool {
+	for _, module := range modules {
+		path := models.ModulePath(module)
+		if importPath == path || strings.HasPrefix(importPath, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // extractImportPath extracts the import path from an import line
 func (es *ExecutionService) extractImportPath(line string) string {
 	// Remove 'import' keyword
@@ -277,72 +463,76 @@ func (es *ExecutionService) isExternalPackage(importPath string) bool {
 
 // SaveSubmissionRequest represents a request to save a submission to filesystem
 type SaveSubmissionRequest struct {
-	Username    string `json:"username"`
-	ChallengeID int    `json:"challengeId"`
-	Code        string `json:"code"`
+	Username    string                 `json:"username"`
+	ChallengeID int                    `json:"challengeId"`
+	Code        string                 `json:"code"`
+	Files       models.SubmissionFiles `json:"files,omitempty"` // Optional multi-file submission
 }
 
 // SaveSubmissionResponse represents the response from saving a submission
 type SaveSubmissionResponse struct {
-	Success     bool     `json:"success"`
-	Message     string   `json:"message"`
-	FilePath    string   `json:"filePath"`
-	GitCommands []string `json:"gitCommands"`
+	Success     bool              `json:"success"`
+	Message     string            `json:"message"`
+	FilePath    string            `json:"filePath"`
+	GitPath     string            `json:"gitPath,omitempty"` // The saved file or directory relative to the repository root
+	Git         *models.GitCommit `json:"git,omitempty"`     // The branch and commit the submission was recorded in
+	GitError    string            `json:"gitError,omitempty"`
+	GitCommands []string          `json:"gitCommands"` // What is left to do by hand to open a pull request
 }
 
-// SaveSubmissionToFilesystem saves a user's submission to the filesystem
-func (es *ExecutionService) SaveSubmissionToFilesystem(request SaveSubmissionRequest) SaveSubmissionResponse {
-	// Get working directory for correct relative paths
-	workDir, _ := os.Getwd()
-
-	// Try different path approaches to handle potential path issues
-	var submissionDir string
-	var fileSaved bool
-
-	// Try multiple path options to ensure it works in different environments
-	pathOptions := []string{
-		// Option 1: From web-ui directory (standard case)
-		filepath.Join("..", fmt.Sprintf("challenge-%d", request.ChallengeID), "submissions", request.Username),
-		// Option 2: From root workspace
-		filepath.Join(fmt.Sprintf("challenge-%d", request.ChallengeID), "submissions", request.Username),
-		// Option 3: Absolute path from detected workspace root
-		filepath.Join(workDir, "..", fmt.Sprintf("challenge-%d", request.ChallengeID), "submissions", request.Username),
+// SubmissionGitPath returns what is committed for a user's submission, relative to the repository root:
+// the single solution file, or the whole submission directory for multi-file submissions
+func SubmissionGitPath(challenge *models.TrackChallenge, username string, multiFile bool) string {
+	dir := path.Join(challenge.RepoPath(), "submissions", username)
+	if multiFile {
+		return dir
 	}
+	return path.Join(dir, challenge.SolutionFile)
+}
 
-	for _, dirPath := range pathOptions {
-		err := os.MkdirAll(dirPath, 0755)
-		if err != nil {
-			continue
+// SaveSolution saves a user's solution in the challenge's submissions directory.
+// A single-file solution is saved under the challenge's solution file name.
+func (es *ExecutionService) SaveSolution(challenge *models.TrackChallenge, username string, code string, files models.SubmissionFiles) SaveSubmissionResponse {
+	multiFile := len(files) > 0
+	if !multiFile {
+		files = models.SubmissionFiles{challenge.SolutionFile: code}
+	}
+	if err := ValidateSubmissionFiles(files); err != nil {
+		return SaveSubmissionResponse{
+			Success: false,
+			Message: fmt.Sprintf("Invalid submission: %v", err),
 		}
-
-		solutionFile := filepath.Join(dirPath, "solution-template.go")
-		err = ioutil.WriteFile(solutionFile, []byte(request.Code), 0644)
-		if err != nil {
-			continue
+	}
+	if !ValidUsername(username) {
+		return SaveSubmissionResponse{
+			Success: false,
+			Message: fmt.Sprintf("Invalid username %q", username),
 		}
-
-		submissionDir = dirPath
-		fileSaved = true
-		break
 	}
 
-	if !fileSaved {
+	submissionDir := challenge.SubmissionDir(username)
+	if err := os.MkdirAll(submissionDir, 0755); err != nil {
 		return SaveSubmissionResponse{
 			Success: false,
-			Message: "Failed to save solution to any available path",
+			Message: fmt.Sprintf("Failed to create submission directory: %v", err),
 		}
 	}
+	if err := WriteSubmissionFiles(submissionDir, files); err != nil {
+		return SaveSubmissionResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to save solution: %v", err),
+		}
+	}
+
+	filePath := submissionDir
+	if !multiFile {
+		filePath = filepath.Join(submissionDir, challenge.SolutionFile)
+	}
 
-	// Return success response with git commands
 	return SaveSubmissionResponse{
 		Success:  true,
 		Message:  "Solution saved to filesystem",
-		FilePath: filepath.Join(submissionDir, "solution-template.go"),
-		GitCommands: []string{
-			"cd " + filepath.Join(workDir, ".."),
-			fmt.Sprintf("git add %s", filepath.Join(fmt.Sprintf("challenge-%d", request.ChallengeID), "submissions", request.Username, "solution-template.go")),
-			fmt.Sprintf("git commit -m \"Add solution for Challenge %d\"", request.ChallengeID),
-			"git push origin main",
-		},
+		FilePath: filePath,
+		GitPath:  SubmissionGitPath(challenge, username, multiFile),
 	}
 }
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+const (
+	// FuzzTestFileName is the challenge test file holding fuzz targets and their oracles.
+	// It is only copied into fuzz runs, so default runs and the browser never see it.
+	FuzzTestFileName = "fuzz_test.go"
+	// FuzzBuildTag guards the fuzz test file, so a plain go test in the challenge directory skips it
+	FuzzBuildTag = "fuzz"
+	// defaultFuzzTime is how long each fuzz target runs unless the challenge sets fuzztime
+	defaultFuzzTime = 10 * time.Second
+	// maxFuzzTime bounds the time a challenge may give a single fuzz target
+	maxFuzzTime = 30 * time.Second
+)
+
+// FuzzReport holds the outcome of fuzzing each target of a challenge
+type FuzzReport struct {
+	Targets  []FuzzTargetResult `json:"targets"`
+	FuzzTime string             `json:"fuzzTime"`          // Time given to each target
+	Failure  *FuzzFailure       `json:"failure,omitempty"` // First counterexample found
+}
+
+// FuzzTargetResult is the outcome of fuzzing a single target
+type FuzzTargetResult struct {
+	Name      string `json:"name"`
+	Passed    bool   `json:"passed"`
+	ElapsedMs int64  `json:"elapsedMs"`
+}
+
+// FuzzFailure is a minimized counterexample found by the fuzzer
+type FuzzFailure struct {
+	Target     string   `json:"target"`
+	Message    string   `json:"message"`              // Failure reported by the oracle check
+	Inputs     []string `json:"inputs"`               // Go literals of the failing arguments
+	CorpusFile string   `json:"corpusFile,omitempty"` // Path of the regression corpus entry, relative to the challenge
+	Corpus     string   `json:"corpus,omitempty"`     // Contents of the corpus entry
+	Seed       string   `json:"seed,omitempty"`       // The input as an f.Add call for the fuzz target
+}
+
+var (
+	// fuzzTargetDecl matches a fuzz target declared in a test file
+	fuzzTargetDecl = regexp.MustCompile(`(?m)^func (Fuzz\w+)\(\w+ \*testing\.F\)`)
+	// fuzzCorpusWritten matches the line go test prints after minimizing a failing input
+	fuzzCorpusWritten = regexp.MustCompile(`Failing input written to (\S+)`)
+	// fuzzFailureMessage matches the oracle's failure message or the panic in go test output
+	fuzzFailureMessage = regexp.MustCompile(`(?m)^\s+[\w.-]+\.go:\d+: (.*)$`)
+)
+
+// fuzzTargets returns the fuzz targets declared in a fuzz test file, limited to those the policy names
+func fuzzTargets(testFile string, policy *models.FuzzPolicy) []string {
+	allowed := make(map[string]bool)
+	if policy != nil {
+		for _, name := range policy.Targets {
+			allowed[name] = true
+		}
+	}
+
+	var targets []string
+	for _, match := range fuzzTargetDecl.FindAllStringSubmatch(testFile, -1) {
+		if len(allowed) == 0 || allowed[match[1]] {
+			targets = append(targets, match[1])
+		}
+	}
+	return targets
+}
+
+// fuzzTimeFor returns the bounded time given to each fuzz target
+func fuzzTimeFor(policy *models.FuzzPolicy) time.Duration {
+	fuzzTime := defaultFuzzTime
+	if policy != nil && policy.FuzzTime != "" {
+		if d, err := time.ParseDuration(policy.FuzzTime); err == nil && d > 0 {
+			fuzzTime = d
+		}
+	}
+	if fuzzTime > maxFuzzTime {
+		fuzzTime = maxFuzzTime
+	}
+	return fuzzTime
+}
+
+// runFuzz fuzzes each target of a passing submission and reports the first minimized counterexample
+func (es *ExecutionService) runFuzz(tempDir string, challenge *models.TrackChallenge, result *ExecutionResult) {
+	targets := fuzzTargets(challenge.FuzzTestFile, challenge.Execution.Fuzz)
+	if len(targets) == 0 {
+		result.Output += "\nThis challenge has no fuzz targets.\n"
+		return
+	}
+
+	// The fuzz targets are only added after the challenge tests, so default runs stay unchanged
+	if err := os.WriteFile(filepath.Join(tempDir, FuzzTestFileName), []byte(challenge.FuzzTestFile), 0644); err != nil {
+		result.Passed = false
+		result.Output += fmt.Sprintf("\nFailed to write fuzz targets: %v\n", err)
+		return
+	}
+
+	fuzzTime := fuzzTimeFor(challenge.Execution.Fuzz)
+	report := &FuzzReport{FuzzTime: fuzzTime.String()}
+	result.Fuzz = report
+
+	for _, target := range targets {
+		start := time.Now()
+		output, err := runTool(tempDir, "go", "test", "-tags="+FuzzBuildTag, "-run=^$", "-fuzz=^"+target+"$", "-fuzztime="+fuzzTime.String())
+		elapsed := time.Since(start).Milliseconds()
+		result.ExecutionMs += elapsed
+		result.Output += "\n" + output
+
+		report.Targets = append(report.Targets, FuzzTargetResult{Name: target, Passed: err == nil, ElapsedMs: elapsed})
+		if err == nil {
+			continue
+		}
+
+		result.Passed = false
+		report.Failure = parseFuzzFailure(tempDir, target, output)
+		// The counterexample is what the user needs, so later targets are skipped
+		break
+	}
+}
+
+// parseFuzzFailure reads the minimized failing input that go test wrote to the corpus
+func parseFuzzFailure(runDir, target, output string) *FuzzFailure {
+	failure := &FuzzFailure{Target: target}
+	if match := fuzzFailureMessage.FindStringSubmatch(output); match != nil {
+		failure.Message = strings.TrimSpace(match[1])
+	}
+
+	match := fuzzCorpusWritten.FindStringSubmatch(output)
+	if match == nil {
+		// Seed corpus entries fail without writing a new corpus file
+		return failure
+	}
+
+	corpusFile := filepath.ToSlash(match[1])
+	content, err := os.ReadFile(filepath.Join(runDir, filepath.FromSlash(corpusFile)))
+	if err != nil {
+		return failure
+	}
+
+	failure.CorpusFile = corpusFile
+	failure.Corpus = string(content)
+	failure.Inputs = parseCorpusValues(string(content))
+	if len(failure.Inputs) > 0 {
+		failure.Seed = fmt.Sprintf("f.Add(%s)", strings.Join(failure.Inputs, ", "))
+	}
+	return failure
+}
+
+// parseCorpusValues returns the Go literals of a "go test fuzz v1" corpus file
+func parseCorpusValues(content string) []string {
+	var values []string
+	for i, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		if i == 0 {
+			// Version header
+			continue
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			values = append(values, line)
+		}
+	}
+	return values
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+const fuzzTestSource = `//go:build fuzz
+
+package main
+
+import "testing"
+
+func FuzzDouble(f *testing.F) {
+	f.Add(2)
+	f.Fuzz(func(t *testing.T, n int) {
+		if got := Double(n); got != n*2 {
+			t.Errorf("Double(%d) = %d, want %d", n, got, n*2)
+		}
+	})
+}
+
+func FuzzHalf(f *testing.F) {}
+`
+
+func TestFuzzTargets(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *models.FuzzPolicy
+		want   []string
+	}{
+		{"all targets", nil, []string{"FuzzDouble", "FuzzHalf"}},
+		{"policy without targets", &models.FuzzPolicy{FuzzTime: "1s"}, []string{"FuzzDouble", "FuzzHalf"}},
+		{"named target", &models.FuzzPolicy{Targets: []string{"FuzzHalf"}}, []string{"FuzzHalf"}},
+		{"unknown target", &models.FuzzPolicy{Targets: []string{"FuzzMissing"}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fuzzTargets(fuzzTestSource, tt.policy); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fuzzTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzTimeFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *models.FuzzPolicy
+		want   time.Duration
+	}{
+		{"default", nil, defaultFuzzTime},
+		{"configured", &models.FuzzPolicy{FuzzTime: "3s"}, 3 * time.Second},
+		{"capped", &models.FuzzPolicy{FuzzTime: "10m"}, maxFuzzTime},
+		{"invalid", &models.FuzzPolicy{FuzzTime: "soon"}, defaultFuzzTime},
+		{"negative", &models.FuzzPolicy{FuzzTime: "-1s"}, defaultFuzzTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fuzzTimeFor(tt.policy); got != tt.want {
+				t.Errorf("fuzzTimeFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFuzzFailure(t *testing.T) {
+	runDir := t.TempDir()
+	corpusFile := "testdata/fuzz/FuzzReverse/abc123"
+	if err := os.MkdirAll(filepath.Join(runDir, filepath.Dir(corpusFile)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	corpus := "go test fuzz v1\nstring(\"hello \")\nint(3)\n"
+	if err := os.WriteFile(filepath.Join(runDir, filepath.FromSlash(corpusFile)), []byte(corpus), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := `--- FAIL: FuzzReverse (0.02s)
+    --- FAIL: FuzzReverse (0.00s)
+        fuzz_test.go:12: Reverse("hello ") = " olleh", want "olleh"
+
+    Failing input written to ` + corpusFile + `
+FAIL`
+
+	failure := parseFuzzFailure(runDir, "FuzzReverse", output)
+	if failure.Message != `Reverse("hello ") = " olleh", want "olleh"` {
+		t.Errorf("Message = %q", failure.Message)
+	}
+	if failure.CorpusFile != corpusFile || failure.Corpus != corpus {
+		t.Errorf("corpus = %q %q", failure.CorpusFile, failure.Corpus)
+	}
+	if want := []string{`string("hello ")`, "int(3)"}; !reflect.DeepEqual(failure.Inputs, want) {
+		t.Errorf("Inputs = %v, want %v", failure.Inputs, want)
+	}
+	if failure.Seed != `f.Add(string("hello "), int(3))` {
+		t.Errorf("Seed = %q", failure.Seed)
+	}
+
+	// Seed corpus failures write no corpus file
+	failure = parseFuzzFailure(runDir, "FuzzReverse", "--- FAIL: FuzzReverse\n        fuzz_test.go:12: boom\n")
+	if failure.Message != "boom" || failure.CorpusFile != "" || failure.Seed != "" {
+		t.Errorf("seed failure = %+v", failure)
+	}
+}
+
+func TestShippedFuzzTargetsNeedBuildTag(t *testing.T) {
+	for id, challenge := range loadShippedChallenges(t) {
+		// CI judges run a plain go test in the challenge directory, which must skip the fuzz targets
+		if challenge.FuzzTestFile != "" && !strings.HasPrefix(challenge.FuzzTestFile, "//go:build "+FuzzBuildTag+"\n") {
+			t.Errorf("challenge %d: %s does not start with //go:build %s", id, FuzzTestFileName, FuzzBuildTag)
+		}
+	}
+}
+
+func TestFuzzTargetsOnlyRunInFuzzMode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+
+	challenge := &models.TrackChallenge{
+		Ref:          "classic/901",
+		SolutionFile: "solution-template.go",
+		Module:       "challenge",
+		TestFile: `package main
+
+import "testing"
+
+func TestDouble(t *testing.T) {
+	if got := Double(2); got != 4 {
+		t.Fatalf("Double(2) = %d", got)
+	}
+}
+`,
+		FuzzTestFile: fuzzTestSource,
+		Execution:    models.ExecutionPolicy{Fuzz: &models.FuzzPolicy{Targets: []string{"FuzzDouble"}, FuzzTime: "2s"}},
+	}
+
+	// Correct for the visible test only; the fuzz seed 2 passes, but small odd inputs don't
+	files := models.SubmissionFiles{"solution-template.go": `package main
+
+func Double(n int) int {
+	if n%2 != 0 {
+		return n
+	}
+	return n * 2
+}
+
+func main() {}
+`}
+
+	result := NewExecutionService().RunWithOptions(files, challenge, RunOptions{})
+	if !result.Passed || result.Fuzz != nil || strings.Contains(result.Output, "FuzzDouble") {
+		t.Fatalf("a default run used the fuzz targets:\n%s", result.Output)
+	}
+
+	result = NewExecutionService().RunWithOptions(files, challenge, RunOptions{Mode: RunModeFuzz})
+	if result.Passed || result.Fuzz == nil || result.Fuzz.Failure == nil {
+		t.Fatalf("the fuzz run found no counterexample:\n%s", result.Output)
+	}
+	if result.Fuzz.Failure.Target != "FuzzDouble" || result.Fuzz.Failure.Seed == "" {
+		t.Errorf("failure = %+v", result.Fuzz.Failure)
+	}
+}
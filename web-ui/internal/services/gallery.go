@@ -0,0 +1,436 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/scanner"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"web-ui/internal/models"
+)
+
+// Gallery errors
+var (
+	ErrGalleryLocked    = errors.New("solve the challenge to see other solutions")
+	ErrSolutionNotFound = errors.New("no saved solution")
+)
+
+// maxDiffCells bounds the line diff table; larger functions are shown as fully replaced
+const maxDiffCells = 1 << 20
+
+// GalleryService shows users who have passed a challenge the other users' solutions, with metrics and diffs
+type GalleryService struct {
+	scoreboardService *ScoreboardService
+	progressService   *ProgressService
+}
+
+// NewGalleryService creates a gallery over the scoreboards and submission histories
+func NewGalleryService(scoreboardService *ScoreboardService, progressService *ProgressService) *GalleryService {
+	return &GalleryService{
+		scoreboardService: scoreboardService,
+		progressService:   progressService,
+	}
+}
+
+// HasPassed reports whether a user passed every test of a challenge on its scoreboard, or has a passing
+// submission in their history. Submissions are only recorded in the history when they ran the hidden tests.
+func (gs *GalleryService) HasPassed(ref, username string) bool {
+	if gs.scoreboardService.HasPassed(ref, username) {
+		return true
+	}
+	state, err := gs.progressService.State(username)
+	if err != nil {
+		return false
+	}
+	for _, record := range state.History {
+		if record.Ref == ref && record.Passed {
+			return true
+		}
+	}
+	return false
+}
+
+// Solutions lists the saved solutions of every other user in username order. Entries hidden from the
+// scoreboard are left out. It returns ErrGalleryLocked until the requester has passed the challenge.
+func (gs *GalleryService) Solutions(challenge *models.TrackChallenge, requester string) (*models.SolutionGallery, error) {
+	if !gs.HasPassed(challenge.Ref, requester) {
+		return nil, ErrGalleryLocked
+	}
+
+	gallery := &models.SolutionGallery{Ref: challenge.Ref, Solutions: []models.GallerySolution{}}
+	entries, err := os.ReadDir(filepath.Join(challenge.Dir, "submissions"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return gallery, nil
+		}
+		return nil, fmt.Errorf("failed to read submissions: %v", err)
+	}
+
+	for _, entry := range entries {
+		username := entry.Name()
+		if !entry.IsDir() || !ValidUsername(username) || username == requester || gs.scoreboardService.IsHidden(challenge.Ref, username) {
+			continue
+		}
+		files, err := ReadSolutionFiles(challenge.SubmissionDir(username))
+		if err != nil || len(files) == 0 {
+			continue
+		}
+
+		solution := models.GallerySolution{
+			Username:     username,
+			Files:        sortedKeys(files),
+			OnScoreboard: gs.scoreboardService.HasPassed(challenge.Ref, username),
+		}
+		if solution.Metrics, err = MeasureSolution(files); err != nil {
+			solution.Error = err.Error()
+		}
+		if record := gs.latestBenchmark(challenge.Ref, username); record != nil {
+			solution.Benchmarks = record.Benchmarks
+			at := record.SubmittedAt
+			solution.BenchmarkAt = &at
+		}
+		gallery.Solutions = append(gallery.Solutions, solution)
+	}
+	return gallery, nil
+}
+
+// latestBenchmark returns the user's latest passing submission with benchmark results, if any
+func (gs *GalleryService) latestBenchmark(ref, username string) *models.SubmissionRecord {
+	state, err := gs.progressService.State(username)
+	if err != nil {
+		return nil
+	}
+	for i := len(state.History) - 1; i >= 0; i-- {
+		if record := state.History[i]; record.Ref == ref && record.Passed && len(record.Benchmarks) > 0 {
+			return &record
+		}
+	}
+	return nil
+}
+
+// Compare diffs two users' solutions function by function. It returns ErrGalleryLocked until the
+// requester has passed the challenge, and ErrSolutionNotFound if either user has no saved solution.
+func (gs *GalleryService) Compare(challenge *models.TrackChallenge, requester, left, right string) (*models.SolutionComparison, error) {
+	if !gs.HasPassed(challenge.Ref, requester) {
+		return nil, ErrGalleryLocked
+	}
+
+	var sides [2]models.SubmissionFiles
+	for i, username := range []string{left, right} {
+		if !ValidUsername(username) || (username != requester && gs.scoreboardService.IsHidden(challenge.Ref, username)) {
+			return nil, fmt.Errorf("%w for %s", ErrSolutionNotFound, username)
+		}
+		files, err := ReadSolutionFiles(challenge.SubmissionDir(username))
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("%w for %s", ErrSolutionNotFound, username)
+		}
+		sides[i] = files
+	}
+
+	comparison, err := CompareSolutions(sides[0], sides[1])
+	if err != nil {
+		return nil, err
+	}
+	comparison.Ref = challenge.Ref
+	comparison.Left = left
+	comparison.Right = right
+	return comparison, nil
+}
+
+// goSolutionFile is a parsed non-test Go file of a solution
+type goSolutionFile struct {
+	fset    *token.FileSet
+	file    *ast.File
+	source  []byte
+	imports string // Import block to give function snippets the file's package names
+}
+
+// parseSolution parses the non-test Go files of a solution in name order, without comments
+func parseSolution(files models.SubmissionFiles) ([]goSolutionFile, error) {
+	var parsed []goSolutionFile
+	for _, name := range sortedKeys(files) {
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, name, files[name], parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", name, err)
+		}
+
+		var imports strings.Builder
+		for _, spec := range file.Imports {
+			if spec.Name != nil {
+				imports.WriteString(spec.Name.Name + " ")
+			}
+			imports.WriteString(spec.Path.Value + "\n")
+		}
+		block := ""
+		if imports.Len() > 0 {
+			block = "import (\n" + imports.String() + ")\n"
+		}
+		parsed = append(parsed, goSolutionFile{fset: fset, file: file, source: []byte(files[name]), imports: block})
+	}
+	return parsed, nil
+}
+
+// MeasureSolution counts the lines of code, functions and cyclomatic complexity of a solution's non-test Go files
+func MeasureSolution(files models.SubmissionFiles) (models.SolutionMetrics, error) {
+	var metrics models.SolutionMetrics
+	parsed, err := parseSolution(files)
+	if err != nil {
+		return metrics, err
+	}
+	for _, file := range parsed {
+		metrics.Lines += codeLines(file.source)
+		for _, decl := range file.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			complexity := cyclomaticComplexity(fn)
+			metrics.Functions++
+			metrics.Complexity += complexity
+			if complexity > metrics.MaxComplexity {
+				metrics.MaxComplexity = complexity
+			}
+		}
+	}
+	return metrics, nil
+}
+
+// codeLines counts the lines holding at least one token, so blank and comment-only lines don't count
+func codeLines(source []byte) int {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	s.Init(fset.AddFile("", -1, len(source)), source, nil, 0)
+	lines := map[int]bool{}
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue // Automatically inserted
+		}
+		lines[fset.Position(pos).Line] = true
+	}
+	return len(lines)
+}
+
+// cyclomaticComplexity is 1 plus the number of branch points in a function: if, for, range,
+// non-default case and select clauses, and && and || operators
+func cyclomaticComplexity(fn *ast.FuncDecl) int {
+	complexity := 1
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if node.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if node.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+// solutionFunction is a function of a solution prepared for diffing
+type solutionFunction struct {
+	side       models.FunctionSide
+	normalized string // Token stream with identifiers renamed, for equivalence
+}
+
+// solutionFunctions returns a solution's functions by name, and the names in declaration order
+func solutionFunctions(files models.SubmissionFiles) (map[string]*solutionFunction, []string, error) {
+	parsed, err := parseSolution(files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	functions := map[string]*solutionFunction{}
+	var names []string
+	for _, file := range parsed {
+		for _, decl := range file.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			name := functionKey(fn)
+			if _, exists := functions[name]; exists {
+				continue // init functions may repeat; the first one is compared
+			}
+
+			var source, signature bytes.Buffer
+			if err := printer.Fprint(&source, file.fset, fn); err != nil {
+				return nil, nil, err
+			}
+			header := *fn
+			header.Body = nil
+			if err := printer.Fprint(&signature, file.fset, &header); err != nil {
+				return nil, nil, err
+			}
+			tokens, err := normalizeGoSource("package p\n" + file.imports + source.String())
+			if err != nil {
+				return nil, nil, err
+			}
+
+			complexity := 0
+			if fn.Body != nil {
+				complexity = cyclomaticComplexity(fn)
+			}
+			functions[name] = &solutionFunction{
+				side: models.FunctionSide{
+					Signature:  signature.String(),
+					Source:     source.String(),
+					Lines:      codeLines(source.Bytes()),
+					Complexity: complexity,
+				},
+				normalized: strings.Join(tokens, " "),
+			}
+			names = append(names, name)
+		}
+	}
+	return functions, names, nil
+}
+
+// functionKey names a function, or a method by its receiver's base type, e.g. "Stack.Push"
+func functionKey(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	for {
+		switch expr := recv.(type) {
+		case *ast.StarExpr:
+			recv = expr.X
+			continue
+		case *ast.IndexExpr:
+			recv = expr.X
+			continue
+		case *ast.IndexListExpr:
+			recv = expr.X
+			continue
+		case *ast.Ident:
+			return expr.Name + "." + fn.Name.Name
+		}
+		return fn.Name.Name
+	}
+}
+
+// CompareSolutions diffs two solutions by function declaration. Functions are identical when they only differ
+// in comments and formatting, and equivalent when they also differ in the names of identifiers.
+func CompareSolutions(left, right models.SubmissionFiles) (*models.SolutionComparison, error) {
+	leftFunctions, leftNames, err := solutionFunctions(left)
+	if err != nil {
+		return nil, fmt.Errorf("left solution: %v", err)
+	}
+	rightFunctions, rightNames, err := solutionFunctions(right)
+	if err != nil {
+		return nil, fmt.Errorf("right solution: %v", err)
+	}
+
+	comparison := &models.SolutionComparison{Functions: []models.FunctionDiff{}}
+	if comparison.LeftMetrics, err = MeasureSolution(left); err != nil {
+		return nil, err
+	}
+	if comparison.RightMetrics, err = MeasureSolution(right); err != nil {
+		return nil, err
+	}
+
+	for _, name := range leftNames {
+		l := leftFunctions[name]
+		diff := models.FunctionDiff{Name: name, Left: &l.side}
+		r, exists := rightFunctions[name]
+		switch {
+		case !exists:
+			diff.Status = models.FunctionRemoved
+		case l.side.Source == r.side.Source:
+			diff.Status = models.FunctionIdentical
+		case l.normalized == r.normalized:
+			diff.Status = models.FunctionEquivalent
+		default:
+			diff.Status = models.FunctionChanged
+			diff.Diff = diffLines(strings.Split(l.side.Source, "\n"), strings.Split(r.side.Source, "\n"))
+		}
+		if exists {
+			diff.Right = &r.side
+		}
+		comparison.Functions = append(comparison.Functions, diff)
+	}
+	for _, name := range rightNames {
+		if _, exists := leftFunctions[name]; !exists {
+			comparison.Functions = append(comparison.Functions, models.FunctionDiff{
+				Name:   name,
+				Status: models.FunctionAdded,
+				Right:  &rightFunctions[name].side,
+			})
+		}
+	}
+	return comparison, nil
+}
+
+// diffLines returns a line diff from a to b using their longest common subsequence
+func diffLines(a, b []string) []models.DiffLine {
+	var lines []models.DiffLine
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			lines = append(lines, models.DiffLine{Op: "-", Text: line})
+		}
+		for _, line := range b {
+			lines = append(lines, models.DiffLine{Op: "+", Text: line})
+		}
+		return lines
+	}
+
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, models.DiffLine{Op: " ", Text: a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, models.DiffLine{Op: "-", Text: a[i]})
+			i++
+		default:
+			lines = append(lines, models.DiffLine{Op: "+", Text: b[j]})
+			j++
+		}
+	}
+	return lines
+}
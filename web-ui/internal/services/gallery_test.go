@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+func TestGalleryHasPassed(t *testing.T) {
+	ss := NewScoreboardService()
+	// CI writes a scoreboard row for failing submissions too
+	ss.scoreboards["classic/1"] = []models.ScoreboardEntry{{Username: "alice"}, {Username: "bob"}, {Username: "mallory"}}
+	ss.passes["classic/1"] = parseFullPasses("| alice | 7 | 7 |\n| bob | 3 | 7 |\n| mallory | 7 | 7 |\n")
+	ss.Hide("classic/1", "mallory", true)
+
+	ps := NewProgressService(t.TempDir())
+	ps.RecordSubmission("carol", models.SubmissionRecord{Ref: "classic/1", Passed: true, SubmittedAt: time.Now()})
+	ps.RecordSubmission("dave", models.SubmissionRecord{Ref: "classic/1", Passed: false, SubmittedAt: time.Now()})
+	ps.RecordSubmission("dave", models.SubmissionRecord{Ref: "classic/2", Passed: true, SubmittedAt: time.Now()})
+	gs := NewGalleryService(ss, ps)
+
+	tests := []struct {
+		username string
+		want     bool
+	}{
+		{"alice", true},   // Full pass on the scoreboard
+		{"bob", false},    // Only a failing scoreboard row
+		{"mallory", true}, // Hidden entries still passed
+		{"carol", true},   // Passing submission in the history
+		{"dave", false},   // Failed this challenge, passed another
+		{"erin", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			if got := gs.HasPassed("classic/1", tt.username); got != tt.want {
+				t.Errorf("HasPassed(%q) = %v, want %v", tt.username, got, tt.want)
+			}
+		})
+	}
+}
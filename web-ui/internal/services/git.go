@@ -0,0 +1,248 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+// DefaultGitMessageTemplate is the commit message of a submission unless GitOptions.MessageTemplate is set
+const DefaultGitMessageTemplate = "Add solution for {{.Ref}} by {{.Username}}"
+
+// ErrNothingToCommit is returned when a submission is already committed as it is in HEAD
+var ErrNothingToCommit = errors.New("submission is unchanged since HEAD")
+
+// GitOptions configures how submissions are committed
+type GitOptions struct {
+	Remote          string // Remote to push to, "origin" by default
+	Push            bool   // Push the branch after committing
+	DryRun          bool   // Create the commit but not the branch, and only check that the push would succeed
+	MessageTemplate string // text/template over GitMessageData, DefaultGitMessageTemplate by default
+}
+
+// GitMessageData is what commit message templates can refer to
+type GitMessageData struct {
+	Username string
+	Ref      string // "classic/7" or "gin/challenge-2-middleware"
+	Track    string
+	ID       string
+	Title    string
+	Path     string // The committed file or directory, relative to the repository root
+}
+
+// GitService records saved submissions in git, each on a branch of its own ready for a pull request.
+// Commits are built in a temporary index from HEAD, so the user's checkout, index and current branch are left alone.
+type GitService struct {
+	repoDir string
+	options GitOptions
+}
+
+// NewGitService creates a git service for the repository at repoDir
+func NewGitService(repoDir string, options GitOptions) *GitService {
+	if options.Remote == "" {
+		options.Remote = "origin"
+	}
+	if options.MessageTemplate == "" {
+		options.MessageTemplate = DefaultGitMessageTemplate
+	}
+	return &GitService{repoDir: repoDir, options: options}
+}
+
+// SubmissionBranch returns the branch a submission saved at the given time is committed to
+func SubmissionBranch(challenge *models.TrackChallenge, username string, at time.Time) string {
+	return fmt.Sprintf("submission/%s/%s-%s-%s", username, challenge.Track, challenge.ID, at.UTC().Format("20060102-150405"))
+}
+
+// Message renders the commit message of a submission
+func (gs *GitService) Message(challenge *models.TrackChallenge, username, path string) (string, error) {
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(gs.options.MessageTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid commit message template: %v", err)
+	}
+	var message bytes.Buffer
+	data := GitMessageData{
+		Username: username,
+		Ref:      challenge.Ref,
+		Track:    challenge.Track,
+		ID:       challenge.ID,
+		Title:    challenge.Title,
+		Path:     path,
+	}
+	if err := tmpl.Execute(&message, data); err != nil {
+		return "", fmt.Errorf("failed to render commit message: %v", err)
+	}
+	if strings.TrimSpace(message.String()) == "" {
+		return "", errors.New("commit message template rendered an empty message")
+	}
+	return message.String(), nil
+}
+
+// CommitSubmission commits path, a saved submission relative to the repository root, on top of HEAD
+// in a new branch, and pushes the branch when the options ask for it
+func (gs *GitService) CommitSubmission(challenge *models.TrackChallenge, username, path string, at time.Time) (*models.GitCommit, error) {
+	name, _ := gs.git(nil, "config", "user.name")
+	email, _ := gs.git(nil, "config", "user.email")
+	if name == "" || email == "" {
+		return nil, errors.New("git user.name and user.email must be configured to commit")
+	}
+	message, err := gs.Message(challenge, username, path)
+	if err != nil {
+		return nil, err
+	}
+	branch := SubmissionBranch(challenge, username, at)
+	if _, err := gs.git(nil, "check-ref-format", "--branch", branch); err != nil {
+		return nil, fmt.Errorf("invalid branch name %q", branch)
+	}
+
+	// Stage the submission in a temporary index holding HEAD's tree
+	indexDir, err := os.MkdirTemp("", "gip-index")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create index: %v", err)
+	}
+	defer os.RemoveAll(indexDir)
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(indexDir, "index")}
+
+	parent, err := gs.git(nil, "rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+	if err == nil {
+		_, err = gs.git(env, "read-tree", parent)
+	} else {
+		// A repository without commits yet starts from an empty tree
+		parent = ""
+		_, err = gs.git(env, "read-tree", "--empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gs.git(env, "add", "--", path); err != nil {
+		return nil, err
+	}
+	tree, err := gs.git(env, "write-tree")
+	if err != nil {
+		return nil, err
+	}
+
+	commitArgs := []string{"commit-tree", tree, "-m", message}
+	if parent != "" {
+		parentTree, err := gs.git(nil, "rev-parse", parent+"^{tree}")
+		if err != nil {
+			return nil, err
+		}
+		if parentTree == tree {
+			return nil, ErrNothingToCommit
+		}
+		commitArgs = append(commitArgs, "-p", parent)
+	}
+	sha, err := gs.git(nil, commitArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	commit := &models.GitCommit{
+		Branch:  branch,
+		SHA:     sha,
+		Message: message,
+		Author:  fmt.Sprintf("%s <%s>", name, email),
+		DryRun:  gs.options.DryRun,
+	}
+	if !gs.options.DryRun {
+		// The empty old value makes this fail rather than move an existing branch
+		if _, err := gs.git(nil, "update-ref", "refs/heads/"+branch, sha, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if gs.options.Push {
+		args := []string{"push"}
+		if gs.options.DryRun {
+			args = append(args, "--dry-run")
+		}
+		args = append(args, gs.options.Remote, sha+":refs/heads/"+branch)
+		if _, err := gs.git(nil, args...); err != nil {
+			return commit, err
+		}
+		commit.Remote = gs.options.Remote
+		commit.Pushed = !gs.options.DryRun
+	}
+	return commit, nil
+}
+
+// Commands returns the shell commands that finish a submission by hand. Without a commit they create
+// the branch, stage and commit path from the repository root; after one they only push its branch.
+// Arguments are quoted where needed, so each command can be pasted into a shell as it is.
+func (gs *GitService) Commands(challenge *models.TrackChallenge, username, path string, commit *models.GitCommit, at time.Time) []string {
+	repoDir, err := filepath.Abs(gs.repoDir)
+	if err != nil {
+		repoDir = gs.repoDir
+	}
+	commands := []string{shellCommand("cd", repoDir)}
+	if commit != nil {
+		if commit.Pushed {
+			return nil
+		}
+		if commit.DryRun {
+			commands = append(commands, shellCommand("git", "branch", commit.Branch, commit.SHA))
+		}
+		return append(commands, shellCommand("git", "push", "-u", gs.options.Remote, commit.Branch))
+	}
+
+	message, err := gs.Message(challenge, username, path)
+	if err != nil {
+		message = fmt.Sprintf("Add solution for %s by %s", challenge.Ref, username)
+	}
+	branch := SubmissionBranch(challenge, username, at)
+	return append(commands,
+		shellCommand("git", "switch", "-c", branch),
+		shellCommand("git", "add", "--", path),
+		shellCommand("git", "commit", "-m", message),
+		shellCommand("git", "push", "-u", gs.options.Remote, branch),
+	)
+}
+
+// git runs a git command in the repository with extra environment variables and returns its trimmed output
+func (gs *GitService) git(env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = gs.repoDir
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %v\n%s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// shellCommand joins a command's arguments into a POSIX shell command line, quoting each argument
+// that contains anything other than characters the shell passes through as they are
+func shellCommand(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = arg
+		if arg == "" || strings.IndexFunc(arg, unsafeShellRune) >= 0 {
+			quoted[i] = shellQuote(arg)
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// unsafeShellRune reports whether r needs quoting on a shell command line
+func unsafeShellRune(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	}
+	return !strings.ContainsRune("-_./:=@+,%^", r)
+}
+
+// shellQuote quotes a string for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
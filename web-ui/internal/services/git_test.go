@@ -0,0 +1,227 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+// gitSubmissionPath is where the test repositories hold alice's solution to classic/1
+const gitSubmissionPath = "challenge-1/submissions/alice/solution-template.go"
+
+// runGit runs git in dir and returns its trimmed output
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// newGitTestRepo creates a clone of a local bare repository, with an uncommitted submission saved in it.
+// Global and system git config are ignored, so the identity comes from the clone's config when set.
+func newGitTestRepo(t *testing.T, withIdentity bool) (repo, remote string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := t.TempDir()
+	remote = filepath.Join(dir, "remote.git")
+	repo = filepath.Join(dir, "repo")
+	runGit(t, dir, "init", "--quiet", "--bare", remote)
+	runGit(t, dir, "init", "--quiet", repo)
+	runGit(t, repo, "config", "user.name", "Alice")
+	runGit(t, repo, "config", "user.email", "alice@example.com")
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("# Practice\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "README.md")
+	runGit(t, repo, "commit", "--quiet", "-m", "Initial commit")
+	runGit(t, repo, "remote", "add", "origin", remote)
+	runGit(t, repo, "push", "--quiet", "origin", "HEAD:refs/heads/main")
+	if !withIdentity {
+		runGit(t, repo, "config", "--unset", "user.name")
+		runGit(t, repo, "config", "--unset", "user.email")
+	}
+
+	solution := filepath.Join(repo, filepath.FromSlash(gitSubmissionPath))
+	if err := os.MkdirAll(filepath.Dir(solution), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(solution, []byte("package main\n\nfunc Sum(a, b int) int { return a + b }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return repo, remote
+}
+
+// gitTestChallenge is the classic challenge the test repositories hold a submission to
+func gitTestChallenge() *models.TrackChallenge {
+	return &models.TrackChallenge{Ref: "classic/1", Track: "classic", ID: "1", Title: "Sum of Two Numbers", SolutionFile: "solution-template.go"}
+}
+
+func TestGitCommitSubmissionDryRun(t *testing.T) {
+	repo, remote := newGitTestRepo(t, true)
+	head := runGit(t, repo, "rev-parse", "HEAD")
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	gitService := NewGitService(repo, GitOptions{Push: true, DryRun: true})
+	commit, err := gitService.CommitSubmission(gitTestChallenge(), "alice", gitSubmissionPath, at)
+	if err != nil {
+		t.Fatalf("CommitSubmission: %v", err)
+	}
+
+	if commit.Branch != "submission/alice/classic-1-20240102-150405" {
+		t.Errorf("branch = %q", commit.Branch)
+	}
+	if commit.Message != "Add solution for classic/1 by alice" || commit.Author != "Alice <alice@example.com>" {
+		t.Errorf("message = %q, author = %q", commit.Message, commit.Author)
+	}
+	if !commit.DryRun || commit.Pushed {
+		t.Errorf("dryRun = %v, pushed = %v, want a dry run that did not push", commit.DryRun, commit.Pushed)
+	}
+
+	// The commit exists on top of HEAD with just the submission added
+	if parent := runGit(t, repo, "rev-parse", commit.SHA+"^"); parent != head {
+		t.Errorf("parent = %s, want HEAD %s", parent, head)
+	}
+	if files := runGit(t, repo, "diff-tree", "--no-commit-id", "--name-only", "-r", commit.SHA); files != gitSubmissionPath {
+		t.Errorf("committed files = %q, want %s", files, gitSubmissionPath)
+	}
+	if author := runGit(t, repo, "log", "-1", "--format=%an <%ae>", commit.SHA); author != commit.Author {
+		t.Errorf("commit author = %q, want %q", author, commit.Author)
+	}
+
+	// Neither the local nor the remote branch was created, and the checkout is untouched
+	if branches := runGit(t, repo, "branch", "--list", "submission/*"); branches != "" {
+		t.Errorf("dry run created branches %q", branches)
+	}
+	if branches := runGit(t, remote, "branch", "--list"); strings.Contains(branches, "submission/") {
+		t.Errorf("dry run pushed branches %q", branches)
+	}
+	if current := runGit(t, repo, "rev-parse", "HEAD"); current != head {
+		t.Errorf("HEAD moved to %s", current)
+	}
+	if status := runGit(t, repo, "status", "--porcelain"); status != "?? challenge-1/" {
+		t.Errorf("status = %q, want the submission still untracked", status)
+	}
+
+	commands := gitService.Commands(gitTestChallenge(), "alice", gitSubmissionPath, commit, at)
+	want := []string{"git branch " + commit.Branch + " " + commit.SHA, "git push -u origin " + commit.Branch}
+	if len(commands) != 3 || commands[1] != want[0] || commands[2] != want[1] {
+		t.Errorf("commands = %q, want cd followed by %q", commands, want)
+	}
+}
+
+func TestGitCommitSubmissionPush(t *testing.T) {
+	repo, remote := newGitTestRepo(t, true)
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	gitService := NewGitService(repo, GitOptions{Push: true, MessageTemplate: "{{.Title}} ({{.Ref}})\n\nSolved by {{.Username}} in {{.Path}}"})
+	commit, err := gitService.CommitSubmission(gitTestChallenge(), "alice", gitSubmissionPath, at)
+	if err != nil {
+		t.Fatalf("CommitSubmission: %v", err)
+	}
+	if !commit.Pushed || commit.Remote != "origin" || commit.DryRun {
+		t.Errorf("commit = %+v, want pushed to origin", commit)
+	}
+	if sha := runGit(t, repo, "rev-parse", "refs/heads/"+commit.Branch); sha != commit.SHA {
+		t.Errorf("local branch at %s, want %s", sha, commit.SHA)
+	}
+	if sha := runGit(t, remote, "rev-parse", "refs/heads/"+commit.Branch); sha != commit.SHA {
+		t.Errorf("remote branch at %s, want %s", sha, commit.SHA)
+	}
+	if subject := runGit(t, repo, "log", "-1", "--format=%s", commit.SHA); subject != "Sum of Two Numbers (classic/1)" {
+		t.Errorf("subject = %q", subject)
+	}
+	if commands := gitService.Commands(gitTestChallenge(), "alice", gitSubmissionPath, commit, at); len(commands) != 0 {
+		t.Errorf("commands after a push = %q, want none", commands)
+	}
+
+	// The branch is per submission, so the same second cannot commit twice
+	if _, err := gitService.CommitSubmission(gitTestChallenge(), "alice", gitSubmissionPath, at); err == nil {
+		t.Error("second commit to an existing branch succeeded")
+	}
+}
+
+func TestGitCommitSubmissionErrors(t *testing.T) {
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	t.Run("unchanged", func(t *testing.T) {
+		repo, _ := newGitTestRepo(t, true)
+		runGit(t, repo, "add", gitSubmissionPath)
+		runGit(t, repo, "commit", "--quiet", "-m", "Add solution")
+		_, err := NewGitService(repo, GitOptions{}).CommitSubmission(gitTestChallenge(), "alice", gitSubmissionPath, at)
+		if !errors.Is(err, ErrNothingToCommit) {
+			t.Errorf("err = %v, want ErrNothingToCommit", err)
+		}
+	})
+
+	t.Run("no identity", func(t *testing.T) {
+		repo, _ := newGitTestRepo(t, false)
+		_, err := NewGitService(repo, GitOptions{}).CommitSubmission(gitTestChallenge(), "alice", gitSubmissionPath, at)
+		if err == nil || !strings.Contains(err.Error(), "user.name") {
+			t.Errorf("err = %v, want the missing identity reported", err)
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		repo, _ := newGitTestRepo(t, true)
+		_, err := NewGitService(repo, GitOptions{MessageTemplate: "{{.Missing}}"}).CommitSubmission(gitTestChallenge(), "alice", gitSubmissionPath, at)
+		if err == nil {
+			t.Error("commit with an invalid message template succeeded")
+		}
+	})
+
+	t.Run("manual commands", func(t *testing.T) {
+		repo, _ := newGitTestRepo(t, true)
+		commands := NewGitService(repo, GitOptions{Remote: "fork"}).Commands(gitTestChallenge(), "alice", gitSubmissionPath, nil, at)
+		want := []string{
+			"git switch -c submission/alice/classic-1-20240102-150405",
+			"git add -- " + gitSubmissionPath,
+			"git commit -m 'Add solution for classic/1 by alice'",
+			"git push -u fork submission/alice/classic-1-20240102-150405",
+		}
+		if len(commands) != len(want)+1 || !strings.HasPrefix(commands[0], "cd ") {
+			t.Fatalf("commands = %q", commands)
+		}
+		for i, command := range want {
+			if commands[i+1] != command {
+				t.Errorf("command %d = %q, want %q", i+1, commands[i+1], command)
+			}
+		}
+	})
+}
+
+func TestShellCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"plain", []string{"git", "add", "--", "challenge-1/submissions/alice/solution-template.go"}, "git add -- challenge-1/submissions/alice/solution-template.go"},
+		{"spaces", []string{"cd", "/home/alice/go practice"}, "cd '/home/alice/go practice'"},
+		{"metacharacters", []string{"git", "add", "--", "a;rm -rf $HOME"}, "git add -- 'a;rm -rf $HOME'"},
+		{"single quote", []string{"git", "commit", "-m", "Alice's solution"}, `git commit -m 'Alice'\''s solution'`},
+		{"empty", []string{"git", "commit", "-m", ""}, "git commit -m ''"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shellCommand(tt.args...); got != tt.want {
+				t.Errorf("shellCommand(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
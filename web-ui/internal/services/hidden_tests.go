@@ -0,0 +1,76 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"web-ui/internal/models"
+)
+
+const (
+	// HiddenTestFileName is the challenge test file used only for submissions and never sent to the browser
+	HiddenTestFileName = "hidden_test.go"
+	// HiddenTestBuildTag guards the hidden test file, so a plain go test in the challenge directory skips it
+	HiddenTestBuildTag = "hidden"
+)
+
+var (
+	// hiddenTestDecl matches a top-level test declared in the hidden test file
+	hiddenTestDecl = regexp.MustCompile(`(?m)^func (Test\w+)\(\w+ \*testing\.T\)`)
+	// testResultLine matches the result line of a top-level test in go test -v output
+	testResultLine = regexp.MustCompile(`(?m)^--- (PASS|FAIL): (Test\w+) `)
+)
+
+// runHiddenTests runs the challenge's hidden tests and reports their outcomes by name only.
+// Their output is discarded so expected values never reach the user.
+func (es *ExecutionService) runHiddenTests(tempDir string, challenge *models.TrackChallenge, race bool, result *ExecutionResult) {
+	result.IncludesHidden = true
+	names := hiddenTestDecl.FindAllStringSubmatch(challenge.HiddenTestFile, -1)
+	if len(names) == 0 {
+		return
+	}
+
+	hiddenPath := filepath.Join(tempDir, HiddenTestFileName)
+	if err := os.WriteFile(hiddenPath, []byte(challenge.HiddenTestFile), 0644); err != nil {
+		result.Passed = false
+		result.Output += fmt.Sprintf("\nFailed to write hidden tests: %v\n", err)
+		return
+	}
+	// Later steps such as benchmarks must not compile or print the hidden tests
+	defer os.Remove(hiddenPath)
+
+	pattern := make([]string, len(names))
+	for i, match := range names {
+		pattern[i] = match[1]
+	}
+	args := []string{"test", "-v", "-count=1", "-tags=" + HiddenTestBuildTag, "-run=^(" + strings.Join(pattern, "|") + ")$"}
+	if race {
+		args = append(args, "-race")
+	}
+	output, _ := runTool(tempDir, "go", args...)
+
+	outcomes := make(map[string]bool)
+	for _, match := range testResultLine.FindAllStringSubmatch(output, -1) {
+		outcomes[match[2]] = match[1] == "PASS"
+	}
+
+	var failed []string
+	for _, name := range pattern {
+		// Tests missing from the output did not build or did not finish
+		passed := outcomes[name]
+		result.HiddenTests = append(result.HiddenTests, models.TestOutcome{Name: name, Passed: passed})
+		if !passed {
+			failed = append(failed, name)
+		}
+	}
+
+	if len(failed) > 0 {
+		result.Passed = false
+		result.Output += fmt.Sprintf("\nHidden tests: %d of %d passed. Failed: %s\n", len(pattern)-len(failed), len(pattern), strings.Join(failed, ", "))
+	} else {
+		result.Output += fmt.Sprintf("\nHidden tests: all %d passed.\n", len(pattern))
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"web-ui/internal/models"
+)
+
+func TestHiddenTestPatterns(t *testing.T) {
+	source := `package main
+
+import "testing"
+
+func TestSecretEdgeCases(t *testing.T) {}
+
+func TestSecretLargeInput(tb *testing.T) {}
+
+func helperTest(t *testing.T) {}
+
+func BenchmarkSecret(b *testing.B) {}
+`
+	var names []string
+	for _, match := range hiddenTestDecl.FindAllStringSubmatch(source, -1) {
+		names = append(names, match[1])
+	}
+	if want := []string{"TestSecretEdgeCases", "TestSecretLargeInput"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("hidden tests = %q, want %q", names, want)
+	}
+
+	output := "=== RUN   TestSecretEdgeCases\n--- PASS: TestSecretEdgeCases (0.00s)\n=== RUN   TestSecretLargeInput\n    --- FAIL: TestSecretLargeInput/sub (0.00s)\n--- FAIL: TestSecretLargeInput (0.01s)\n"
+	outcomes := make(map[string]string)
+	for _, match := range testResultLine.FindAllStringSubmatch(output, -1) {
+		outcomes[match[2]] = match[1]
+	}
+	// Subtest results are indented and do not count
+	if want := map[string]string{"TestSecretEdgeCases": "PASS", "TestSecretLargeInput": "FAIL"}; !reflect.DeepEqual(outcomes, want) {
+		t.Errorf("outcomes = %v, want %v", outcomes, want)
+	}
+}
+
+func TestHiddenTestsNotSerialized(t *testing.T) {
+	challenge := loadShippedChallenges(t)[19]
+	if challenge == nil || challenge.HiddenTestFile == "" {
+		t.Fatal("challenge 19 has no hidden tests")
+	}
+	encoded, err := json.Marshal(challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(encoded), strings.TrimSpace(challenge.HiddenTestFile)) || strings.Contains(string(encoded), HiddenTestFileName) {
+		t.Error("the challenge sent to the browser contains its hidden tests")
+	}
+}
+
+func TestShippedHiddenTestsNeedBuildTag(t *testing.T) {
+	for id, challenge := range loadShippedChallenges(t) {
+		// CI judges run a plain go test in the challenge directory, which must skip the hidden tests
+		if challenge.HiddenTestFile != "" && !strings.HasPrefix(challenge.HiddenTestFile, "//go:build "+HiddenTestBuildTag+"\n") {
+			t.Errorf("challenge %d: %s does not start with //go:build %s", id, HiddenTestFileName, HiddenTestBuildTag)
+		}
+	}
+}
+
+func TestRunHiddenTests(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	challenge := &models.TrackChallenge{
+		Ref:      "classic/0",
+		Module:   "example",
+		TestFile: "package main\n\nimport \"testing\"\n\nfunc TestDouble(t *testing.T) {\n\tif Double(2) != 4 {\n\t\tt.Error(\"Double(2) != 4\")\n\t}\n}\n",
+		HiddenTestFile: `//go:build hidden
+
+package main
+
+import "testing"
+
+func TestDoubleNegative(t *testing.T) {
+	if got := Double(-3); got != -6 {
+		t.Errorf("secret expected value -6, got %d", got)
+	}
+}
+
+func TestDoubleZero(t *testing.T) {
+	if Double(0) != 0 {
+		t.Error("secret zero")
+	}
+}
+`,
+	}
+	// Hard-codes the visible test's answer
+	hardCoded := models.SubmissionFiles{"solution-template.go": "package main\n\nfunc Double(n int) int {\n\tif n == 2 {\n\t\treturn 4\n\t}\n\treturn 0\n}\n\nfunc main() {}\n"}
+	correct := models.SubmissionFiles{"solution-template.go": "package main\n\nfunc Double(n int) int { return 2 * n }\n\nfunc main() {}\n"}
+
+	tests := []struct {
+		name          string
+		files         models.SubmissionFiles
+		includeHidden bool
+		wantPassed    bool
+		wantHidden    []models.TestOutcome
+	}{
+		{"run skips hidden tests", hardCoded, false, true, nil},
+		{"submission fails hidden tests", hardCoded, true, false, []models.TestOutcome{{Name: "TestDoubleNegative", Passed: false}, {Name: "TestDoubleZero", Passed: true}}},
+		{"submission passes hidden tests", correct, true, true, []models.TestOutcome{{Name: "TestDoubleNegative", Passed: true}, {Name: "TestDoubleZero", Passed: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewExecutionService().RunWithOptions(tt.files, challenge, RunOptions{IncludeHidden: tt.includeHidden})
+			if result.Passed != tt.wantPassed || result.IncludesHidden != tt.includeHidden {
+				t.Fatalf("Passed = %v, IncludesHidden = %v, want %v, %v:\n%s", result.Passed, result.IncludesHidden, tt.wantPassed, tt.includeHidden, result.Output)
+			}
+			if !reflect.DeepEqual(result.HiddenTests, tt.wantHidden) {
+				t.Errorf("HiddenTests = %+v, want %+v", result.HiddenTests, tt.wantHidden)
+			}
+			// Only names reach the user, never the hidden tests' messages or expected values
+			if strings.Contains(result.Output, "secret") {
+				t.Errorf("output leaks the hidden tests:\n%s", result.Output)
+			}
+		})
+	}
+}
@@ -0,0 +1,456 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+const (
+	defaultInterviewDuration  = 45 * time.Minute
+	maxInterviewDuration      = 4 * time.Hour
+	defaultAutosaveInterval   = 30 * time.Second
+	minAutosaveInterval       = 5 * time.Second
+	defaultInterviewChallenge = 2
+	maxInterviewChallenges    = 10
+	// maxSnapshotsPerChallenge bounds the code timeline kept for one challenge
+	maxSnapshotsPerChallenge = 500
+)
+
+// Errors returned by the interview service
+var (
+	ErrInterviewNotFound     = errors.New("interview session not found")
+	ErrInterviewEnded        = errors.New("interview session has ended")
+	ErrInterviewActive       = errors.New("interview session is still in progress")
+	ErrChallengeNotInSession = errors.New("challenge is not part of this interview session")
+)
+
+// InterviewRequest holds the options for starting an interview session
+type InterviewRequest struct {
+	Username        string   `json:"username"`
+	ChallengeIDs    []int    `json:"challengeIds"` // Explicit challenges; picked at random from the filters if empty
+	Count           int      `json:"count"`        // Number of random challenges
+	Difficulty      string   `json:"difficulty"`
+	Tags            []string `json:"tags"` // A challenge matches if it has any of the tags
+	DurationMinutes int      `json:"durationMinutes"`
+	AutosaveSeconds int      `json:"autosaveSeconds"`
+}
+
+// InterviewService manages timed interview sessions, persisted as one JSON file per session
+type InterviewService struct {
+	mu       sync.Mutex
+	dir      string
+	sessions map[string]*models.InterviewSession
+}
+
+// NewInterviewService creates an interview service storing sessions in dir
+func NewInterviewService(dir string) *InterviewService {
+	return &InterviewService{
+		dir:      dir,
+		sessions: make(map[string]*models.InterviewSession),
+	}
+}
+
+// LoadSessions reads the persisted sessions, ending those whose time ran out while the server was down
+func (s *InterviewService) LoadSessions() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create interview directory: %v", err)
+	}
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		var session models.InterviewSession
+		if err := loadJSON(file, &session); err != nil {
+			return err
+		}
+		s.sessions[session.ID] = &session
+		s.expire(&session)
+	}
+	return nil
+}
+
+// StartSession picks the session's challenges and starts the countdown
+func (s *InterviewService) StartSession(req InterviewRequest, challenges models.ChallengeMap) (*models.InterviewSession, error) {
+	if req.Username == "" {
+		return nil, fmt.Errorf("username is required")
+	}
+
+	ids, err := pickInterviewChallenges(req, challenges)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := defaultInterviewDuration
+	if req.DurationMinutes > 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	if duration > maxInterviewDuration {
+		return nil, fmt.Errorf("sessions can last at most %v", maxInterviewDuration)
+	}
+	autosave := defaultAutosaveInterval
+	if req.AutosaveSeconds > 0 {
+		autosave = time.Duration(req.AutosaveSeconds) * time.Second
+	}
+	if autosave < minAutosaveInterval {
+		autosave = minAutosaveInterval
+	}
+
+	now := time.Now()
+	session := &models.InterviewSession{
+		ID:              randomToken(8),
+		Username:        req.Username,
+		ChallengeIDs:    ids,
+		Difficulty:      req.Difficulty,
+		Tags:            req.Tags,
+		DurationSeconds: int(duration.Seconds()),
+		AutosaveSeconds: int(autosave.Seconds()),
+		StartedAt:       now,
+		EndsAt:          now.Add(duration),
+		Status:          models.InterviewActive,
+		Challenges:      make(map[int]*models.InterviewProgress),
+	}
+	for _, id := range ids {
+		session.Challenges[id] = &models.InterviewProgress{ChallengeID: id, Snapshots: []models.CodeSnapshot{}}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.ID] = session
+	if err := s.save(session); err != nil {
+		delete(s.sessions, session.ID)
+		return nil, err
+	}
+	return copySession(session), nil
+}
+
+// pickInterviewChallenges validates explicit challenge IDs or picks random challenges matching the filters
+func pickInterviewChallenges(req InterviewRequest, challenges models.ChallengeMap) ([]int, error) {
+	if len(req.ChallengeIDs) > maxInterviewChallenges {
+		return nil, fmt.Errorf("a session can have at most %d challenges", maxInterviewChallenges)
+	}
+	if len(req.ChallengeIDs) > 0 {
+		seen := make(map[int]bool)
+		for _, id := range req.ChallengeIDs {
+			if _, exists := challenges[id]; !exists {
+				return nil, fmt.Errorf("challenge %d not found", id)
+			}
+			if seen[id] {
+				return nil, fmt.Errorf("challenge %d is listed twice", id)
+			}
+			seen[id] = true
+		}
+		return req.ChallengeIDs, nil
+	}
+
+	var candidates []int
+	for id, challenge := range challenges {
+		if req.Difficulty != "" && !strings.EqualFold(challenge.Difficulty, req.Difficulty) {
+			continue
+		}
+		if len(req.Tags) > 0 && !hasAnyTag(challenge.Tags, req.Tags) {
+			continue
+		}
+		candidates = append(candidates, id)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no challenges match the requested difficulty and tags")
+	}
+	sort.Ints(candidates)
+
+	count := req.Count
+	if count <= 0 {
+		count = defaultInterviewChallenge
+	}
+	if count > maxInterviewChallenges {
+		count = maxInterviewChallenges
+	}
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+
+	mathrand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	picked := candidates[:count]
+	sort.Ints(picked)
+	return picked, nil
+}
+
+// hasAnyTag reports whether tags contains any of wanted, ignoring case
+func hasAnyTag(tags, wanted []string) bool {
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if strings.EqualFold(tag, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetSession returns a copy of a session, ending it first if its time has run out
+func (s *InterviewService) GetSession(id string) (*models.InterviewSession, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, exists := s.sessions[id]
+	if !exists {
+		return nil, false
+	}
+	s.expire(session)
+	return copySession(session), true
+}
+
+// ListSessions returns copies of a user's sessions, newest first
+func (s *InterviewService) ListSessions(username string) []*models.InterviewSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := []*models.InterviewSession{}
+	for _, session := range s.sessions {
+		if username == "" || session.Username == username {
+			s.expire(session)
+			sessions = append(sessions, copySession(session))
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.After(sessions[j].StartedAt)
+	})
+	return sessions
+}
+
+// CheckActive returns an error unless the session is running and includes the challenge
+func (s *InterviewService) CheckActive(id string, challengeID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.activeProgress(id, challengeID, time.Now())
+	return err
+}
+
+// SaveSnapshot records an autosaved copy of the editor.
+// It returns false without error when the code is unchanged or the autosave interval has not passed.
+func (s *InterviewService) SaveSnapshot(id string, challengeID int, code string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	progress, err := s.activeProgress(id, challengeID, now)
+	if err != nil {
+		return false, err
+	}
+
+	session := s.sessions[id]
+	if last := lastSnapshot(progress, models.SnapshotAutosave); last != nil {
+		// Allow some jitter in the client's timer
+		minGap := time.Duration(session.AutosaveSeconds) * time.Second / 2
+		if last.Code == code || now.Sub(last.At) < minGap {
+			return false, nil
+		}
+	}
+
+	appendSnapshot(progress, models.CodeSnapshot{At: now, Source: models.SnapshotAutosave, Code: code})
+	return true, s.save(session)
+}
+
+// RecordRun records a test run that started at startedAt.
+// Runs started before the session ended are recorded even if they finish after it.
+func (s *InterviewService) RecordRun(id string, challengeID int, code string, passed bool, startedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	progress, err := s.activeProgress(id, challengeID, startedAt)
+	if err != nil {
+		return err
+	}
+
+	progress.Runs++
+	if passed {
+		progress.PassedRuns++
+		if progress.FirstGreenAt == nil {
+			at := time.Now()
+			progress.FirstGreenAt = &at
+		}
+	}
+	appendSnapshot(progress, models.CodeSnapshot{At: time.Now(), Source: models.SnapshotRun, Code: code, Passed: &passed})
+	return s.save(s.sessions[id])
+}
+
+// EndSession locks a session against further runs and snapshots
+func (s *InterviewService) EndSession(id string) (*models.InterviewSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, exists := s.sessions[id]
+	if !exists {
+		return nil, ErrInterviewNotFound
+	}
+	if session.Status == models.InterviewEnded {
+		return copySession(session), nil
+	}
+
+	s.end(session, time.Now())
+	return copySession(session), s.save(session)
+}
+
+// Report summarizes an ended session
+func (s *InterviewService) Report(id string, challenges models.ChallengeMap) (*models.InterviewReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, exists := s.sessions[id]
+	if !exists {
+		return nil, ErrInterviewNotFound
+	}
+	s.expire(session)
+	if session.Status != models.InterviewEnded {
+		return nil, ErrInterviewActive
+	}
+
+	report := &models.InterviewReport{
+		SessionID:       session.ID,
+		Username:        session.Username,
+		StartedAt:       session.StartedAt,
+		EndedAt:         *session.EndedAt,
+		DurationSeconds: int(session.EndedAt.Sub(session.StartedAt).Seconds()),
+	}
+
+	for _, challengeID := range session.ChallengeIDs {
+		progress := session.Challenges[challengeID]
+		entry := models.InterviewChallengeReport{
+			ChallengeID: challengeID,
+			Runs:        progress.Runs,
+			Timeline:    copySnapshots(progress.Snapshots),
+		}
+		if challenge, exists := challenges[challengeID]; exists {
+			entry.Title = challenge.Title
+		}
+		if progress.Runs > 0 {
+			entry.PassRate = float64(progress.PassedRuns) / float64(progress.Runs)
+		}
+		if progress.FirstGreenAt != nil {
+			seconds := int(progress.FirstGreenAt.Sub(session.StartedAt).Seconds())
+			entry.TimeToFirstGreenSeconds = &seconds
+			report.Solved++
+		}
+		report.Challenges = append(report.Challenges, entry)
+	}
+	return report, nil
+}
+
+// activeProgress returns the progress for a challenge if the session was active at the given time
+func (s *InterviewService) activeProgress(id string, challengeID int, at time.Time) (*models.InterviewProgress, error) {
+	session, exists := s.sessions[id]
+	if !exists {
+		return nil, ErrInterviewNotFound
+	}
+	s.expire(session)
+	if session.EndedAt != nil && !at.Before(*session.EndedAt) {
+		return nil, ErrInterviewEnded
+	}
+
+	progress, exists := session.Challenges[challengeID]
+	if !exists {
+		return nil, ErrChallengeNotInSession
+	}
+	return progress, nil
+}
+
+// expire ends a session whose countdown has run out
+func (s *InterviewService) expire(session *models.InterviewSession) {
+	if session.Status == models.InterviewActive && !time.Now().Before(session.EndsAt) {
+		s.end(session, session.EndsAt)
+		s.save(session)
+	}
+}
+
+// end marks a session as ended at the given time
+func (s *InterviewService) end(session *models.InterviewSession, at time.Time) {
+	session.Status = models.InterviewEnded
+	session.EndedAt = &at
+}
+
+// save writes a session to disk, replacing the previous file atomically
+func (s *InterviewService) save(session *models.InterviewSession) error {
+	if err := writeJSONAtomic(filepath.Join(s.dir, session.ID+".json"), session); err != nil {
+		return fmt.Errorf("failed to save interview session: %v", err)
+	}
+	return nil
+}
+
+// copySession returns a deep copy of a session, so callers can read it after the lock is released
+// while runs and snapshots keep being recorded
+func copySession(session *models.InterviewSession) *models.InterviewSession {
+	copied := *session
+	copied.ChallengeIDs = append([]int{}, session.ChallengeIDs...)
+	if session.Tags != nil {
+		copied.Tags = append([]string{}, session.Tags...)
+	}
+	if session.EndedAt != nil {
+		endedAt := *session.EndedAt
+		copied.EndedAt = &endedAt
+	}
+	copied.Challenges = make(map[int]*models.InterviewProgress, len(session.Challenges))
+	for id, progress := range session.Challenges {
+		progressCopy := *progress
+		if progress.FirstGreenAt != nil {
+			firstGreenAt := *progress.FirstGreenAt
+			progressCopy.FirstGreenAt = &firstGreenAt
+		}
+		progressCopy.Snapshots = copySnapshots(progress.Snapshots)
+		copied.Challenges[id] = &progressCopy
+	}
+	return &copied
+}
+
+// copySnapshots returns a deep copy of a code timeline
+func copySnapshots(snapshots []models.CodeSnapshot) []models.CodeSnapshot {
+	copied := make([]models.CodeSnapshot, len(snapshots))
+	for i, snapshot := range snapshots {
+		if snapshot.Passed != nil {
+			passed := *snapshot.Passed
+			snapshot.Passed = &passed
+		}
+		copied[i] = snapshot
+	}
+	return copied
+}
+
+// lastSnapshot returns the most recent snapshot from the given source
+func lastSnapshot(progress *models.InterviewProgress, source string) *models.CodeSnapshot {
+	for i := len(progress.Snapshots) - 1; i >= 0; i-- {
+		if progress.Snapshots[i].Source == source {
+			return &progress.Snapshots[i]
+		}
+	}
+	return nil
+}
+
+// appendSnapshot adds a snapshot, dropping the oldest autosave once the timeline is full
+func appendSnapshot(progress *models.InterviewProgress, snapshot models.CodeSnapshot) {
+	progress.Snapshots = append(progress.Snapshots, snapshot)
+	if len(progress.Snapshots) <= maxSnapshotsPerChallenge {
+		return
+	}
+	for i, existing := range progress.Snapshots {
+		if existing.Source == models.SnapshotAutosave {
+			progress.Snapshots = append(progress.Snapshots[:i], progress.Snapshots[i+1:]...)
+			return
+		}
+	}
+	progress.Snapshots = progress.Snapshots[1:]
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+// interviewTestChallenges returns five classic challenges with mixed difficulties and tags
+func interviewTestChallenges() models.ChallengeMap {
+	return models.ChallengeMap{
+		1: {ID: 1, Title: "Sum", Difficulty: "Beginner", Tags: []string{"basics"}},
+		2: {ID: 2, Title: "Reverse", Difficulty: "Beginner", Tags: []string{"strings"}},
+		3: {ID: 3, Title: "Workers", Difficulty: "Intermediate", Tags: []string{"concurrency"}},
+		4: {ID: 4, Title: "BFS", Difficulty: "Intermediate", Tags: []string{"Concurrency", "graphs"}},
+		5: {ID: 5, Title: "Cache", Difficulty: "Advanced", Tags: []string{"concurrency"}},
+	}
+}
+
+func TestPickInterviewChallenges(t *testing.T) {
+	challenges := interviewTestChallenges()
+
+	tests := []struct {
+		name    string
+		req     InterviewRequest
+		want    []int // Exactly these, or nil to only check the count and filters
+		count   int
+		wantErr bool
+	}{
+		{"explicit", InterviewRequest{ChallengeIDs: []int{3, 1}}, []int{3, 1}, 2, false},
+		{"explicit unknown", InterviewRequest{ChallengeIDs: []int{1, 99}}, nil, 0, true},
+		{"explicit duplicate", InterviewRequest{ChallengeIDs: []int{1, 1}}, nil, 0, true},
+		{"explicit too many", InterviewRequest{ChallengeIDs: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}}, nil, 0, true},
+		{"difficulty", InterviewRequest{Difficulty: "beginner", Count: 5}, []int{1, 2}, 2, false},
+		{"tags ignore case", InterviewRequest{Tags: []string{"concurrency"}, Difficulty: "Intermediate", Count: 2}, []int{3, 4}, 2, false},
+		{"default count", InterviewRequest{Tags: []string{"concurrency"}}, nil, defaultInterviewChallenge, false},
+		{"no match", InterviewRequest{Difficulty: "Expert"}, nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pickInterviewChallenges(tt.req, challenges)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pickInterviewChallenges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != tt.count || (tt.want != nil && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("pickInterviewChallenges() = %v, want %d challenges %v", got, tt.count, tt.want)
+			}
+			for _, id := range got {
+				if tt.req.Tags != nil && !hasAnyTag(challenges[id].Tags, tt.req.Tags) {
+					t.Errorf("challenge %d does not match tags %q", id, tt.req.Tags)
+				}
+			}
+		})
+	}
+}
+
+func TestInterviewSessionLifecycle(t *testing.T) {
+	dir := t.TempDir()
+	challenges := interviewTestChallenges()
+	s := NewInterviewService(dir)
+
+	session, err := s.StartSession(InterviewRequest{Username: "alice", ChallengeIDs: []int{1, 3}, AutosaveSeconds: 1}, challenges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.AutosaveSeconds != int(minAutosaveInterval.Seconds()) || session.DurationSeconds != int(defaultInterviewDuration.Seconds()) {
+		t.Errorf("autosave = %ds, duration = %ds, want the minimum and the default", session.AutosaveSeconds, session.DurationSeconds)
+	}
+
+	// The first snapshot is kept; an immediate second one is within the autosave interval
+	for i, want := range []bool{true, false} {
+		saved, err := s.SaveSnapshot(session.ID, 1, "package main // "+string(rune('a'+i)))
+		if err != nil || saved != want {
+			t.Errorf("snapshot %d saved = %v (%v), want %v", i, saved, err, want)
+		}
+	}
+	if _, err := s.SaveSnapshot(session.ID, 2, "package main"); !errors.Is(err, ErrChallengeNotInSession) {
+		t.Errorf("snapshot for another challenge: %v, want ErrChallengeNotInSession", err)
+	}
+
+	started := time.Now()
+	for _, passed := range []bool{false, true, true, false} {
+		if err := s.RecordRun(session.ID, 1, "package main", passed, started); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Report(session.ID, challenges); !errors.Is(err, ErrInterviewActive) {
+		t.Errorf("report of an active session: %v, want ErrInterviewActive", err)
+	}
+
+	if _, err := s.EndSession(session.ID); err != nil {
+		t.Fatal(err)
+	}
+	// Ending locks the session, but a run that started before the end is still recorded
+	if err := s.CheckActive(session.ID, 1); !errors.Is(err, ErrInterviewEnded) {
+		t.Errorf("CheckActive after end: %v, want ErrInterviewEnded", err)
+	}
+	if err := s.RecordRun(session.ID, 1, "package main", true, started); err != nil {
+		t.Errorf("run started before the end: %v", err)
+	}
+
+	// Sessions survive a restart
+	restarted := NewInterviewService(dir)
+	if err := restarted.LoadSessions(); err != nil {
+		t.Fatal(err)
+	}
+	report, err := restarted.Report(session.ID, challenges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Solved != 1 || len(report.Challenges) != 2 {
+		t.Fatalf("report = %+v, want one of two challenges solved", report)
+	}
+	sum, workers := report.Challenges[0], report.Challenges[1]
+	if sum.Title != "Sum" || sum.Runs != 5 || sum.PassRate != 0.6 || sum.TimeToFirstGreenSeconds == nil || len(sum.Timeline) != 6 {
+		t.Errorf("challenge 1 report = %+v", sum)
+	}
+	if workers.Runs != 0 || workers.PassRate != 0 || workers.TimeToFirstGreenSeconds != nil {
+		t.Errorf("challenge 3 report = %+v, want no runs", workers)
+	}
+}
+
+func TestInterviewSessionExpires(t *testing.T) {
+	dir := t.TempDir()
+	s := NewInterviewService(dir)
+	session, err := s.StartSession(InterviewRequest{Username: "alice", ChallengeIDs: []int{1}}, interviewTestChallenges())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Move the countdown into the past, as if the server had been down
+	session.EndsAt = time.Now().Add(-time.Minute)
+	if err := s.save(session); err != nil {
+		t.Fatal(err)
+	}
+	restarted := NewInterviewService(dir)
+	if err := restarted.LoadSessions(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, exists := restarted.GetSession(session.ID)
+	if !exists || loaded.Status != models.InterviewEnded || loaded.EndedAt == nil || !loaded.EndedAt.Equal(loaded.EndsAt) {
+		t.Fatalf("session = %+v, want ended at its deadline", loaded)
+	}
+	if _, err := restarted.SaveSnapshot(session.ID, 1, "package main"); !errors.Is(err, ErrInterviewEnded) {
+		t.Errorf("snapshot after expiry: %v, want ErrInterviewEnded", err)
+	}
+}
+
+func TestAppendSnapshotDropsOldestAutosave(t *testing.T) {
+	progress := &models.InterviewProgress{}
+	passed := true
+	appendSnapshot(progress, models.CodeSnapshot{Source: models.SnapshotRun, Code: "run", Passed: &passed})
+	for i := 0; i < maxSnapshotsPerChallenge; i++ {
+		appendSnapshot(progress, models.CodeSnapshot{Source: models.SnapshotAutosave, Code: string(rune('a' + i%26))})
+	}
+
+	if len(progress.Snapshots) != maxSnapshotsPerChallenge {
+		t.Fatalf("%d snapshots, want %d", len(progress.Snapshots), maxSnapshotsPerChallenge)
+	}
+	// Run snapshots are kept over autosaves
+	if progress.Snapshots[0].Source != models.SnapshotRun || progress.Snapshots[1].Code != "b" {
+		t.Errorf("timeline starts with %+v, %+v, want the run then the second autosave", progress.Snapshots[0], progress.Snapshots[1])
+	}
+}
+
+func TestInterviewSessionsAreCopies(t *testing.T) {
+	s := NewInterviewService(t.TempDir())
+	session, err := s.StartSession(InterviewRequest{Username: "alice", ChallengeIDs: []int{1}}, interviewTestChallenges())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Poll the session while runs are recorded, as the editor does during an interview; run with -race
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			s.RecordRun(session.ID, 1, fmt.Sprintf("package main // %d", i), i%2 == 0, time.Now())
+		}
+	}()
+	for polling := true; polling; {
+		select {
+		case <-done:
+			polling = false
+		default:
+		}
+		polled, _ := s.GetSession(session.ID)
+		listed := s.ListSessions("alice")
+		if _, err := json.Marshal([]interface{}{polled, listed}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	polled, _ := s.GetSession(session.ID)
+	polled.Challenges[1].Snapshots[0].Code = "changed"
+	polled.ChallengeIDs[0] = 2
+	if again, _ := s.GetSession(session.ID); again.Challenges[1].Snapshots[0].Code == "changed" || again.ChallengeIDs[0] != 1 {
+		t.Error("changing a returned session changed the stored one")
+	}
+	if len(polled.Challenges[1].Snapshots) != 50 || polled.Challenges[1].Runs != 50 {
+		t.Errorf("%d snapshots and %d runs, want 50", len(polled.Challenges[1].Snapshots), polled.Challenges[1].Runs)
+	}
+}
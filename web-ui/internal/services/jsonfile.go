@@ -0,0 +1,49 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// loadJSON decodes a JSON file into value, leaving value as it is when the file does not exist
+func loadJSON(path string, value interface{}) error {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(content, value); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return nil
+}
+
+// writeJSONAtomic writes a value as indented JSON, creating the file's directory and replacing the previous file atomically,
+// so readers never see a partly written file
+func writeJSONAtomic(path string, value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// userStatePath returns the path of a user's state file in a data directory
+func userStatePath(dir, username string) (string, error) {
+	if !submissionPathSegment.MatchString(username) {
+		return "", fmt.Errorf("invalid username %q", username)
+	}
+	return filepath.Join(dir, username+".json"), nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestJSONFileRoundTrip(t *testing.T) {
+	type state struct {
+		Name  string         `json:"name"`
+		Score map[string]int `json:"score"`
+	}
+
+	tests := []struct {
+		name    string
+		content *string // nil leaves the file missing
+		want    state
+		wantErr bool
+	}{
+		{"missing file keeps defaults", nil, state{Name: "default"}, false},
+		{"existing file", strPtr(`{"name": "alice", "score": {"go": 3}}`), state{Name: "alice", Score: map[string]int{"go": 3}}, false},
+		{"corrupt file", strPtr(`{"name": `), state{Name: "default"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			if tt.content != nil {
+				if err := os.WriteFile(path, []byte(*tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := state{Name: "default"}
+			if err := loadJSON(path, &got); (err != nil) != tt.wantErr {
+				t.Fatalf("loadJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// A save creates missing directories, leaves no temporary file behind and loads back unchanged
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+	saved := state{Name: "bob", Score: map[string]int{"gin": 2}}
+	if err := writeJSONAtomic(path, saved); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+	var loaded state
+	if err := loadJSON(path, &loaded); err != nil || !reflect.DeepEqual(loaded, saved) {
+		t.Errorf("loaded %+v (%v), want %+v", loaded, err, saved)
+	}
+}
+
+func TestUserStatePath(t *testing.T) {
+	tests := []struct {
+		username string
+		wantErr  bool
+	}{
+		{"alice", false},
+		{"", true},
+		{"..", true},
+		{"a/b", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			path, err := userStatePath("data", tt.username)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("userStatePath(%q) error = %v, wantErr %v", tt.username, err, tt.wantErr)
+			}
+			if !tt.wantErr && path != filepath.Join("data", tt.username+".json") {
+				t.Errorf("userStatePath(%q) = %q", tt.username, path)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,487 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+// Leaderboard windows
+const (
+	LeaderboardWindowWeek  = "week"
+	LeaderboardWindowMonth = "month"
+	LeaderboardWindowAll   = "all"
+	// LeaderboardTrackAll ranks completions across the classic and package tracks
+	LeaderboardTrackAll = "all"
+)
+
+// Leaderboard orderings
+const (
+	// LeaderboardOrderCompletions ranks users by completed challenges
+	LeaderboardOrderCompletions = "completions"
+	// LeaderboardOrderQuality ranks users by the mean of their best quality score per challenge
+	LeaderboardOrderQuality = "quality"
+)
+
+// leaderboardOrders are the orderings every ranking is precomputed in
+var leaderboardOrders = []string{LeaderboardOrderCompletions, LeaderboardOrderQuality}
+
+const (
+	defaultLeaderboardLimit = 50
+	maxLeaderboardLimit     = 200
+	// leaderboardNeighbours is how many users either side of the caller are returned
+	leaderboardNeighbours = 5
+	// leaderboardMaxAge rebuilds an index that has not been refreshed by a submission, so the windows move on
+	leaderboardMaxAge = time.Hour
+)
+
+// leaderboardWindows maps each window to how far back it reaches; zero means forever
+var leaderboardWindows = map[string]time.Duration{
+	LeaderboardWindowWeek:  7 * 24 * time.Hour,
+	LeaderboardWindowMonth: 30 * 24 * time.Hour,
+	LeaderboardWindowAll:   0,
+}
+
+// LeaderboardQuery selects and pages a leaderboard ranking
+type LeaderboardQuery struct {
+	Track      string // "classic", a package name or "all"
+	Window     string // "week", "month" or "all"
+	Difficulty string // Only challenges of this difficulty, any if empty
+	Order      string // "completions" or "quality"
+	Page       int    // 1-based
+	Limit      int
+	Username   string // Caller, whose rank and neighbourhood are returned
+}
+
+// leaderboardChallenge is what the index knows about a challenge
+type leaderboardChallenge struct {
+	track      string
+	difficulty string // Lower case
+}
+
+// LeaderboardService keeps a precomputed ranking for every track, window and difficulty.
+// It is built from the scoreboards, saved solutions and submission histories and refreshed on each passing submission.
+// Completions are dated by the first passing submission recorded for them; those without one are only ranked in the all-time window.
+// Entries hidden from the scoreboards are left out of every ranking.
+type LeaderboardService struct {
+	mu            sync.RWMutex
+	scoreboards   *ScoreboardService
+	challenges    map[string]leaderboardChallenge
+	tracks        []string
+	completions   map[string]map[string]time.Time    // Username -> challenge ref -> earliest completion, zero if undated
+	quality       map[string]map[string]qualityScore // Username -> challenge ref -> best quality score
+	rankings      map[string][]models.LeaderboardEntry
+	positions     map[string]map[string]int // Ranking key -> username -> index in the ranking
+	updatedAt     time.Time
+	hiddenVersion int // Scoreboard hidden version the rankings were built at
+}
+
+// qualityScore is a user's best quality score for a challenge and when it was reached
+type qualityScore struct {
+	score float64
+	at    time.Time
+}
+
+// NewLeaderboardService creates an empty leaderboard index over the scoreboards
+func NewLeaderboardService(scoreboards *ScoreboardService) *LeaderboardService {
+	return &LeaderboardService{
+		scoreboards: scoreboards,
+		challenges:  make(map[string]leaderboardChallenge),
+		completions: make(map[string]map[string]time.Time),
+		quality:     make(map[string]map[string]qualityScore),
+	}
+}
+
+// Load builds the index from the challenges' saved solutions and the users' submission histories, by username.
+// The scoreboards' completions are read on every rebuild.
+func (ls *LeaderboardService) Load(challenges models.ChallengeMap, packages models.PackageMap, histories map[string][]models.SubmissionRecord) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ls.challenges = make(map[string]leaderboardChallenge)
+	ls.completions = make(map[string]map[string]time.Time)
+	ls.quality = make(map[string]map[string]qualityScore)
+	ls.tracks = []string{LeaderboardTrackAll, ClassicTrack}
+
+	for id, challenge := range challenges {
+		ref := ClassicRef(id)
+		ls.challenges[ref] = leaderboardChallenge{track: ClassicTrack, difficulty: strings.ToLower(challenge.Difficulty)}
+	}
+
+	for name, pkg := range packages {
+		ls.tracks = append(ls.tracks, name)
+		for _, challengeID := range pkg.LearningPath {
+			ref := PackageRef(name, challengeID)
+			challenge := leaderboardChallenge{track: name}
+			if info := pkg.ChallengeDetails[challengeID]; info != nil {
+				challenge.difficulty = strings.ToLower(info.Difficulty)
+			}
+			ls.challenges[ref] = challenge
+			if located, ok := LocateChallenge(ref, challenges, packages); ok {
+				ls.loadSolutions(located)
+			}
+		}
+	}
+	sort.Strings(ls.tracks[2:])
+
+	for username, history := range histories {
+		for _, record := range history {
+			if _, exists := ls.challenges[record.Ref]; !exists || !record.Passed {
+				continue
+			}
+			ls.complete(username, record.Ref, record.SubmittedAt)
+			if record.Quality > 0 {
+				ls.scoreQuality(username, record.Ref, record.Quality, record.SubmittedAt)
+			}
+		}
+	}
+
+	ls.rebuild(time.Now())
+	return nil
+}
+
+// Record adds a passing submission to the index and refreshes the rankings
+func (ls *LeaderboardService) Record(username, ref string, at time.Time) error {
+	if !submissionPathSegment.MatchString(username) {
+		return fmt.Errorf("invalid username %q", username)
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if _, exists := ls.challenges[ref]; !exists {
+		return fmt.Errorf("unknown challenge %q", ref)
+	}
+	ls.complete(username, ref, at)
+	ls.rebuild(time.Now())
+	return nil
+}
+
+// RecordQuality adds the quality score of a passing submission, keeping each user's best per challenge
+func (ls *LeaderboardService) RecordQuality(username, ref string, score float64, at time.Time) error {
+	if !submissionPathSegment.MatchString(username) {
+		return fmt.Errorf("invalid username %q", username)
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if _, exists := ls.challenges[ref]; !exists {
+		return fmt.Errorf("unknown challenge %q", ref)
+	}
+	ls.scoreQuality(username, ref, score, at)
+	ls.rebuild(time.Now())
+	return nil
+}
+
+// scoreQuality records a quality score, keeping the best one and the earliest time it was reached
+func (ls *LeaderboardService) scoreQuality(username, ref string, score float64, at time.Time) {
+	scores := ls.quality[username]
+	if scores == nil {
+		scores = make(map[string]qualityScore)
+		ls.quality[username] = scores
+	}
+	if previous, exists := scores[ref]; !exists || score > previous.score || (score == previous.score && at.Before(previous.at)) {
+		scores[ref] = qualityScore{score: score, at: at}
+	}
+}
+
+// Tracks returns the tracks that can be ranked
+func (ls *LeaderboardService) Tracks() []string {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+	return append([]string(nil), ls.tracks...)
+}
+
+// Query returns a page of the ranking selected by the query, with the caller's rank and neighbourhood
+func (ls *LeaderboardService) Query(query LeaderboardQuery) (*models.LeaderboardPage, error) {
+	if query.Track == "" {
+		query.Track = LeaderboardTrackAll
+	}
+	if query.Window == "" {
+		query.Window = LeaderboardWindowAll
+	}
+	if query.Order == "" {
+		query.Order = LeaderboardOrderCompletions
+	}
+	if !containsString(leaderboardOrders, query.Order) {
+		return nil, fmt.Errorf("invalid order %q: must be completions or quality", query.Order)
+	}
+	if _, exists := leaderboardWindows[query.Window]; !exists {
+		return nil, fmt.Errorf("invalid window %q: must be week, month or all", query.Window)
+	}
+	query.Difficulty = strings.ToLower(query.Difficulty)
+	if _, exists := difficultyRank[query.Difficulty]; query.Difficulty != "" && !exists {
+		return nil, fmt.Errorf("invalid difficulty %q: must be beginner, intermediate or advanced", query.Difficulty)
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = defaultLeaderboardLimit
+	}
+	if query.Limit > maxLeaderboardLimit {
+		query.Limit = maxLeaderboardLimit
+	}
+
+	ls.mu.RLock()
+	if ls.stale() {
+		ls.mu.RUnlock()
+		ls.mu.Lock()
+		if ls.stale() {
+			ls.rebuild(time.Now())
+		}
+		ls.mu.Unlock()
+		ls.mu.RLock()
+	}
+	defer ls.mu.RUnlock()
+
+	if !containsString(ls.tracks, query.Track) {
+		return nil, fmt.Errorf("invalid track %q: must be one of %s", query.Track, strings.Join(ls.tracks, ", "))
+	}
+
+	key := leaderboardKey(query.Track, query.Window, query.Difficulty, query.Order)
+	ranking := ls.rankings[key]
+	page := &models.LeaderboardPage{
+		Track:      query.Track,
+		Window:     query.Window,
+		Difficulty: query.Difficulty,
+		Order:      query.Order,
+		Page:       query.Page,
+		Limit:      query.Limit,
+		Total:      len(ranking),
+		Entries:    []models.LeaderboardEntry{},
+		UpdatedAt:  ls.updatedAt,
+	}
+
+	if start := (query.Page - 1) * query.Limit; start < len(ranking) {
+		end := start + query.Limit
+		if end > len(ranking) {
+			end = len(ranking)
+		}
+		page.Entries = ranking[start:end]
+	}
+
+	if i, exists := ls.positions[key][query.Username]; exists && query.Username != "" {
+		me := ranking[i]
+		page.Me = &me
+		from, to := i-leaderboardNeighbours, i+leaderboardNeighbours+1
+		if from < 0 {
+			from = 0
+		}
+		if to > len(ranking) {
+			to = len(ranking)
+		}
+		page.Neighbourhood = ranking[from:to]
+	}
+	return page, nil
+}
+
+// loadSolutions records every saved solution of a package challenge.
+// A saved solution does not say when it passed, so these completions are undated until a submission history dates them.
+func (ls *LeaderboardService) loadSolutions(challenge *models.TrackChallenge) {
+	entries, err := os.ReadDir(filepath.Join(challenge.Dir, "submissions"))
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !submissionPathSegment.MatchString(entry.Name()) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(challenge.SubmissionDir(entry.Name()), challenge.SolutionFile)); err == nil {
+			ls.complete(entry.Name(), challenge.Ref, time.Time{})
+		}
+	}
+}
+
+// complete records a completion, keeping the earliest date
+func (ls *LeaderboardService) complete(username, ref string, at time.Time) {
+	addCompletion(ls.completions, username, ref, at)
+}
+
+// addCompletion adds a completion to a username -> ref -> date index, keeping the earliest date;
+// an undated completion never replaces a dated one
+func addCompletion(completions map[string]map[string]time.Time, username, ref string, at time.Time) {
+	refs := completions[username]
+	if refs == nil {
+		refs = make(map[string]time.Time)
+		completions[username] = refs
+	}
+	if previous, exists := refs[ref]; !exists || (!at.IsZero() && (previous.IsZero() || at.Before(previous))) {
+		refs[ref] = at
+	}
+}
+
+// visibleCompletions merges the recorded completions with the scoreboards' full passes, leaving out hidden entries
+func (ls *LeaderboardService) visibleCompletions() map[string]map[string]time.Time {
+	completions := make(map[string]map[string]time.Time, len(ls.completions))
+	for username, refs := range ls.completions {
+		for ref, at := range refs {
+			if !ls.scoreboards.IsHidden(ref, username) {
+				addCompletion(completions, username, ref, at)
+			}
+		}
+	}
+	for ref, usernames := range ls.scoreboards.FullPasses() {
+		if _, exists := ls.challenges[ref]; !exists {
+			continue
+		}
+		for _, username := range usernames {
+			addCompletion(completions, username, ref, time.Time{})
+		}
+	}
+	return completions
+}
+
+// rebuild recomputes every ranking
+func (ls *LeaderboardService) rebuild(now time.Time) {
+	ls.rankings = make(map[string][]models.LeaderboardEntry)
+	ls.positions = make(map[string]map[string]int)
+	ls.hiddenVersion = ls.scoreboards.HiddenVersion()
+	completions := ls.visibleCompletions()
+
+	for _, track := range ls.tracks {
+		for window, span := range leaderboardWindows {
+			var since time.Time
+			if span > 0 {
+				since = now.Add(-span)
+			}
+			for _, difficulty := range []string{"", "beginner", "intermediate", "advanced"} {
+				for _, order := range leaderboardOrders {
+					key := leaderboardKey(track, window, difficulty, order)
+					var ranking []models.LeaderboardEntry
+					if order == LeaderboardOrderQuality {
+						ranking = ls.rankQuality(completions, track, difficulty, since)
+					} else {
+						ranking = ls.rank(completions, track, difficulty, since)
+					}
+					positions := make(map[string]int, len(ranking))
+					for i, entry := range ranking {
+						positions[entry.Username] = i
+					}
+					ls.rankings[key] = ranking
+					ls.positions[key] = positions
+				}
+			}
+		}
+	}
+	ls.updatedAt = now
+}
+
+// rank orders users by completions since a time, breaking ties by who reached their count first
+func (ls *LeaderboardService) rank(completions map[string]map[string]time.Time, track, difficulty string, since time.Time) []models.LeaderboardEntry {
+	ranking := []models.LeaderboardEntry{}
+	for username, refs := range completions {
+		entry := models.LeaderboardEntry{Username: username}
+		for ref, at := range refs {
+			challenge := ls.challenges[ref]
+			if track != LeaderboardTrackAll && challenge.track != track {
+				continue
+			}
+			if difficulty != "" && challenge.difficulty != difficulty {
+				continue
+			}
+			// Undated completions are left out of every window but all-time
+			if !since.IsZero() && (at.IsZero() || at.Before(since)) {
+				continue
+			}
+			entry.CompletedCount++
+			if at.After(entry.LastCompletedAt) {
+				entry.LastCompletedAt = at
+			}
+		}
+		if entry.CompletedCount > 0 {
+			ranking = append(ranking, entry)
+		}
+	}
+
+	sort.Slice(ranking, func(i, j int) bool {
+		a, b := ranking[i], ranking[j]
+		if a.CompletedCount != b.CompletedCount {
+			return a.CompletedCount > b.CompletedCount
+		}
+		if !a.LastCompletedAt.Equal(b.LastCompletedAt) {
+			return a.LastCompletedAt.Before(b.LastCompletedAt)
+		}
+		return a.Username < b.Username
+	})
+	for i := range ranking {
+		ranking[i].Rank = i + 1
+	}
+	return ranking
+}
+
+// rankQuality orders users by the mean of their best quality scores on challenges scored since a time,
+// breaking ties by how many challenges were scored and then by who reached their scores first
+func (ls *LeaderboardService) rankQuality(completions map[string]map[string]time.Time, track, difficulty string, since time.Time) []models.LeaderboardEntry {
+	ranking := []models.LeaderboardEntry{}
+	for username, scores := range ls.quality {
+		entry := models.LeaderboardEntry{Username: username}
+		total := 0.0
+		for ref, score := range scores {
+			if ls.scoreboards.IsHidden(ref, username) {
+				continue
+			}
+			challenge := ls.challenges[ref]
+			if track != LeaderboardTrackAll && challenge.track != track {
+				continue
+			}
+			if difficulty != "" && challenge.difficulty != difficulty {
+				continue
+			}
+			if score.at.Before(since) {
+				continue
+			}
+			entry.ScoredCount++
+			total += score.score
+			if score.at.After(entry.LastCompletedAt) {
+				entry.LastCompletedAt = score.at
+			}
+		}
+		if entry.ScoredCount == 0 {
+			continue
+		}
+		entry.QualityScore = roundTo(total/float64(entry.ScoredCount), 1)
+		for ref, at := range completions[username] {
+			challenge := ls.challenges[ref]
+			if (track == LeaderboardTrackAll || challenge.track == track) && (difficulty == "" || challenge.difficulty == difficulty) && (since.IsZero() || (!at.IsZero() && !at.Before(since))) {
+				entry.CompletedCount++
+			}
+		}
+		ranking = append(ranking, entry)
+	}
+
+	sort.Slice(ranking, func(i, j int) bool {
+		a, b := ranking[i], ranking[j]
+		if a.QualityScore != b.QualityScore {
+			return a.QualityScore > b.QualityScore
+		}
+		if a.ScoredCount != b.ScoredCount {
+			return a.ScoredCount > b.ScoredCount
+		}
+		if !a.LastCompletedAt.Equal(b.LastCompletedAt) {
+			return a.LastCompletedAt.Before(b.LastCompletedAt)
+		}
+		return a.Username < b.Username
+	})
+	for i := range ranking {
+		ranking[i].Rank = i + 1
+	}
+	return ranking
+}
+
+// stale reports whether the rankings need a rebuild, because the windows moved on or entries were hidden or shown
+func (ls *LeaderboardService) stale() bool {
+	return time.Since(ls.updatedAt) > leaderboardMaxAge || ls.scoreboards.HiddenVersion() != ls.hiddenVersion
+}
+
+// leaderboardKey identifies a precomputed ranking
+func leaderboardKey(track, window, difficulty, order string) string {
+	return track + "|" + window + "|" + difficulty + "|" + order
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+// newLeaderboardTestService builds an index over classic challenges 1-3, with alice and bob on challenge 1's
+// scoreboard and bob on challenge 2's, and the given submission histories
+func newLeaderboardTestService(t *testing.T, histories map[string][]models.SubmissionRecord) (*LeaderboardService, *ScoreboardService) {
+	t.Helper()
+	dir := t.TempDir()
+	tables := map[int]string{
+		1: "| alice | 3 | 3 |\n| bob | 3 | 3 |\n",
+		2: "| bob | 5 | 5 |\n",
+	}
+	challenges := make(models.ChallengeMap)
+	for id := 1; id <= 3; id++ {
+		challengeDir := filepath.Join(dir, "challenge-"+strconv.Itoa(id))
+		if err := os.MkdirAll(challengeDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if table, exists := tables[id]; exists {
+			content := "| Username | Passed Tests | Total Tests |\n|---|---|---|\n" + table
+			if err := os.WriteFile(filepath.Join(challengeDir, "SCOREBOARD.md"), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		challenges[id] = &models.Challenge{ID: id, Difficulty: "Beginner", Dir: challengeDir}
+	}
+
+	scoreboards := NewScoreboardService()
+	if err := scoreboards.LoadScoreboards(challenges, nil); err != nil {
+		t.Fatal(err)
+	}
+	ls := NewLeaderboardService(scoreboards)
+	if err := ls.Load(challenges, nil, histories); err != nil {
+		t.Fatal(err)
+	}
+	return ls, scoreboards
+}
+
+func TestLeaderboardWindows(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	histories := map[string][]models.SubmissionRecord{
+		// Dates alice's scoreboard completion of challenge 1 and adds challenge 2
+		"alice": {
+			{Ref: "classic/1", Passed: true, SubmittedAt: daysAgo(20)},
+			{Ref: "classic/2", Passed: false, SubmittedAt: daysAgo(5)},
+			{Ref: "classic/2", Passed: true, SubmittedAt: daysAgo(3)},
+		},
+		"carol": {{Ref: "classic/3", Passed: true, SubmittedAt: daysAgo(40)}},
+		"dave":  {{Ref: "classic/9", Passed: true, SubmittedAt: daysAgo(1)}},
+	}
+	ls, _ := newLeaderboardTestService(t, histories)
+
+	tests := []struct {
+		window string
+		want   map[string]int // Username -> completed count
+	}{
+		{LeaderboardWindowWeek, map[string]int{"alice": 1}},
+		{LeaderboardWindowMonth, map[string]int{"alice": 2}},
+		// bob's scoreboard completions are undated, so they only count all-time
+		{LeaderboardWindowAll, map[string]int{"alice": 2, "bob": 2, "carol": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.window, func(t *testing.T) {
+			page, err := ls.Query(LeaderboardQuery{Track: ClassicTrack, Window: tt.window})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]int)
+			for _, entry := range page.Entries {
+				got[entry.Username] = entry.CompletedCount
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("counts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaderboardRecordDatesCompletion(t *testing.T) {
+	ls, _ := newLeaderboardTestService(t, nil)
+
+	page, err := ls.Query(LeaderboardQuery{Window: LeaderboardWindowWeek})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 0 {
+		t.Fatalf("week has %d entries before any submission, want 0", page.Total)
+	}
+
+	if err := ls.Record("bob", "classic/1", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	page, err = ls.Query(LeaderboardQuery{Window: LeaderboardWindowWeek, Username: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Me == nil || page.Me.CompletedCount != 1 || page.Me.LastCompletedAt.IsZero() {
+		t.Errorf("bob's week entry = %+v, want one dated completion", page.Me)
+	}
+}
+
+func TestLeaderboardHidesFlaggedEntries(t *testing.T) {
+	histories := map[string][]models.SubmissionRecord{
+		"alice": {{Ref: "classic/2", Passed: true, SubmittedAt: time.Now()}},
+	}
+	ls, scoreboards := newLeaderboardTestService(t, histories)
+
+	counts := func(window string) map[string]int {
+		t.Helper()
+		page, err := ls.Query(LeaderboardQuery{Window: window})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]int)
+		for _, entry := range page.Entries {
+			got[entry.Username] = entry.CompletedCount
+		}
+		return got
+	}
+
+	tests := []struct {
+		name   string
+		hide   bool
+		ref    string
+		user   string
+		window string
+		want   map[string]int
+	}{
+		{"nothing hidden", false, "", "", LeaderboardWindowAll, map[string]int{"alice": 2, "bob": 2}},
+		// A scoreboard pass is dropped once hidden
+		{"scoreboard pass hidden", true, "classic/1", "bob", LeaderboardWindowAll, map[string]int{"alice": 2, "bob": 1}},
+		// So is a completion dated by a recorded submission
+		{"recorded completion hidden", true, "classic/2", "alice", LeaderboardWindowWeek, map[string]int{}},
+		{"completion shown again", false, "classic/2", "alice", LeaderboardWindowWeek, map[string]int{"alice": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.ref != "" {
+				scoreboards.Hide(tt.ref, tt.user, tt.hide)
+			}
+			if got := counts(tt.window); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("counts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaderboardComplete(t *testing.T) {
+	early := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := early.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name  string
+		dates []time.Time
+		want  time.Time
+	}{
+		{"undated", []time.Time{{}}, time.Time{}},
+		{"dated after undated", []time.Time{{}, late}, late},
+		{"undated after dated", []time.Time{late, {}}, late},
+		{"earliest kept", []time.Time{late, early, late}, early},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls := NewLeaderboardService(NewScoreboardService())
+			for _, at := range tt.dates {
+				ls.complete("alice", "classic/1", at)
+			}
+			if got := ls.completions["alice"]["classic/1"]; !got.Equal(tt.want) {
+				t.Errorf("completion = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaderboardQueryValidation(t *testing.T) {
+	ls, _ := newLeaderboardTestService(t, nil)
+
+	tests := []struct {
+		name  string
+		query LeaderboardQuery
+	}{
+		{"window", LeaderboardQuery{Window: "decade"}},
+		{"order", LeaderboardQuery{Order: "speed"}},
+		{"difficulty", LeaderboardQuery{Difficulty: "expert"}},
+		{"track", LeaderboardQuery{Track: "echo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ls.Query(tt.query); err == nil {
+				t.Errorf("Query(%+v) accepted an invalid %s", tt.query, tt.name)
+			}
+		})
+	}
+}
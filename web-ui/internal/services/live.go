@@ -0,0 +1,545 @@
+package services
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"web-ui/internal/models"
+)
+
+const (
+	// liveHistorySize is how many deltas are kept to transform edits made against older versions
+	liveHistorySize = 1000
+	// liveSendBuffer is how many messages can be queued for a client before it is dropped
+	liveSendBuffer = 256
+)
+
+// Errors returned by the live session service
+var (
+	ErrLiveSessionNotFound = errors.New("live session not found")
+	ErrLiveInvalidToken    = errors.New("invalid live session token")
+)
+
+// LiveService relays a candidate's editor and test runs to interviewers.
+// Each session keeps a JSON file with its settings and an append-only JSON lines transcript.
+type LiveService struct {
+	mu    sync.Mutex
+	dir   string
+	rooms map[string]*liveRoom
+}
+
+// liveRoom is the shared state of one session
+type liveRoom struct {
+	mu           sync.Mutex
+	session      *models.LiveSession
+	code         []uint16
+	version      int
+	history      []models.CodeDelta // history[i] turned version historyStart+i into the next version
+	historyStart int
+	notes        []models.LiveEvent
+	seq          int
+	clients      map[*LiveClient]bool
+	transcript   string
+}
+
+// LiveClient is one connection to a live session
+type LiveClient struct {
+	Role string
+	Name string
+	Send chan []byte // Closed when the client leaves or falls too far behind
+	room *liveRoom
+}
+
+// liveMessage is a message sent by a client
+type liveMessage struct {
+	Type        string            `json:"type"`
+	BaseVersion int               `json:"baseVersion"` // Version the delta was made against
+	Delta       *models.CodeDelta `json:"delta"`
+	Result      json.RawMessage   `json:"result"`
+	Text        string            `json:"text"`
+}
+
+// liveParticipant is a connected client as shown to others
+type liveParticipant struct {
+	Role string `json:"role"`
+	Name string `json:"name"`
+}
+
+// liveState is sent when a client joins or needs to resynchronize
+type liveState struct {
+	Type         string             `json:"type"`
+	SessionID    string             `json:"sessionId"`
+	ChallengeID  int                `json:"challengeId"`
+	Role         string             `json:"role"`
+	Code         string             `json:"code"`
+	Version      int                `json:"version"`
+	Notes        []models.LiveEvent `json:"notes,omitempty"`
+	Participants []liveParticipant  `json:"participants"`
+}
+
+// NewLiveService creates a live session service storing sessions in dir
+func NewLiveService(dir string) *LiveService {
+	return &LiveService{
+		dir:   dir,
+		rooms: make(map[string]*liveRoom),
+	}
+}
+
+// LoadSessions restores sessions by replaying their transcripts
+func (s *LiveService) LoadSessions() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create live session directory: %v", err)
+	}
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", file, err)
+		}
+		var session models.LiveSession
+		if err := json.Unmarshal(content, &session); err != nil {
+			return fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+
+		room := s.newRoom(&session)
+		events, err := readTranscript(room.transcript)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			room.seq = event.Seq
+			switch event.Type {
+			case models.LiveEventDelta:
+				if code, err := applyDelta(room.code, *event.Delta); err == nil {
+					room.code = code
+					room.version = event.Version
+				}
+			case models.LiveEventNote:
+				room.notes = append(room.notes, event)
+			}
+		}
+		// Edits made against versions from before the restart must resynchronize
+		room.historyStart = room.version
+		s.rooms[session.ID] = room
+	}
+	return nil
+}
+
+// CreateSession starts a live session for a candidate working on a challenge
+func (s *LiveService) CreateSession(challengeID int, candidate string) (*models.LiveSession, error) {
+	if candidate == "" {
+		return nil, fmt.Errorf("candidate is required")
+	}
+
+	session := &models.LiveSession{
+		ID:               randomToken(8),
+		ChallengeID:      challengeID,
+		Candidate:        candidate,
+		CandidateToken:   randomToken(16),
+		InterviewerToken: randomToken(16),
+		CreatedAt:        time.Now(),
+	}
+
+	content, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create live session directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, session.ID+".json"), content, 0600); err != nil {
+		return nil, fmt.Errorf("failed to save live session: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rooms[session.ID] = s.newRoom(session)
+	return session, nil
+}
+
+// GetSession returns a session's settings
+func (s *LiveService) GetSession(id string) (*models.LiveSession, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, exists := s.rooms[id]
+	if !exists {
+		return nil, false
+	}
+	return room.session, true
+}
+
+// Role returns the role granted by a token
+func (s *LiveService) Role(id, token string) (string, error) {
+	s.mu.Lock()
+	room, exists := s.rooms[id]
+	s.mu.Unlock()
+	if !exists {
+		return "", ErrLiveSessionNotFound
+	}
+	return room.role(token)
+}
+
+// Transcript returns every recorded event of a session
+func (s *LiveService) Transcript(id string) ([]models.LiveEvent, error) {
+	s.mu.Lock()
+	room, exists := s.rooms[id]
+	s.mu.Unlock()
+	if !exists {
+		return nil, ErrLiveSessionNotFound
+	}
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+	return readTranscript(room.transcript)
+}
+
+// Join connects a client to a session, queueing the current state as its first message
+func (s *LiveService) Join(id, token, name string) (*LiveClient, error) {
+	s.mu.Lock()
+	room, exists := s.rooms[id]
+	s.mu.Unlock()
+	if !exists {
+		return nil, ErrLiveSessionNotFound
+	}
+	role, err := room.role(token)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = role
+		if role == models.LiveCandidate {
+			name = room.session.Candidate
+		}
+	}
+
+	client := &LiveClient{Role: role, Name: name, Send: make(chan []byte, liveSendBuffer), room: room}
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+	room.clients[client] = true
+	room.sendState(client)
+	room.record(models.LiveEvent{Type: models.LiveEventJoin, Role: role, Name: name})
+	room.broadcastPresence()
+	return client, nil
+}
+
+// Leave disconnects a client
+func (s *LiveService) Leave(client *LiveClient) {
+	room := client.room
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if !room.clients[client] {
+		return
+	}
+	room.drop(client)
+	room.record(models.LiveEvent{Type: models.LiveEventLeave, Role: client.Role, Name: client.Name})
+	room.broadcastPresence()
+}
+
+// HandleMessage applies a message from a client and relays it to the others
+func (s *LiveService) HandleMessage(client *LiveClient, data []byte) {
+	room := client.room
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if !room.clients[client] {
+		return
+	}
+
+	var message liveMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		room.sendError(client, "invalid message")
+		return
+	}
+
+	switch message.Type {
+	case models.LiveEventDelta:
+		if client.Role != models.LiveCandidate {
+			room.sendError(client, "only the candidate can edit the code")
+			return
+		}
+		if message.Delta == nil {
+			room.sendError(client, "delta is required")
+			return
+		}
+		room.applyClientDelta(client, message.BaseVersion, *message.Delta)
+	case models.LiveEventRun:
+		if client.Role != models.LiveCandidate {
+			room.sendError(client, "only the candidate can share test runs")
+			return
+		}
+		if !json.Valid(message.Result) {
+			room.sendError(client, "result must be JSON")
+			return
+		}
+		event := room.record(models.LiveEvent{Type: models.LiveEventRun, Role: client.Role, Name: client.Name, Result: message.Result})
+		room.broadcast(event, nil, nil)
+	case models.LiveEventNote:
+		if client.Role != models.LiveInterviewer {
+			room.sendError(client, "notes are only available to interviewers")
+			return
+		}
+		if strings.TrimSpace(message.Text) == "" {
+			room.sendError(client, "note is empty")
+			return
+		}
+		event := room.record(models.LiveEvent{Type: models.LiveEventNote, Role: client.Role, Name: client.Name, Note: message.Text})
+		room.notes = append(room.notes, event)
+		room.broadcast(event, nil, func(c *LiveClient) bool { return c.Role == models.LiveInterviewer })
+	default:
+		room.sendError(client, fmt.Sprintf("unknown message type %q", message.Type))
+	}
+}
+
+// newRoom creates the in-memory state for a session
+func (s *LiveService) newRoom(session *models.LiveSession) *liveRoom {
+	return &liveRoom{
+		session:    session,
+		code:       []uint16{},
+		clients:    make(map[*LiveClient]bool),
+		transcript: filepath.Join(s.dir, session.ID+".jsonl"),
+	}
+}
+
+// role returns the role granted by a token
+func (room *liveRoom) role(token string) (string, error) {
+	switch token {
+	case "":
+		return "", ErrLiveInvalidToken
+	case room.session.CandidateToken:
+		return models.LiveCandidate, nil
+	case room.session.InterviewerToken:
+		return models.LiveInterviewer, nil
+	}
+	return "", ErrLiveInvalidToken
+}
+
+// applyClientDelta transforms a delta made against baseVersion to the current code, applies it and relays it.
+// Overlapping concurrent edits resolve to the last writer.
+func (room *liveRoom) applyClientDelta(client *LiveClient, baseVersion int, delta models.CodeDelta) {
+	if baseVersion < room.historyStart || baseVersion > room.version {
+		room.sendError(client, "stale version")
+		room.sendState(client)
+		return
+	}
+
+	transformed := delta
+	for _, applied := range room.history[baseVersion-room.historyStart:] {
+		transformed = transformDelta(transformed, applied)
+	}
+
+	code, err := applyDelta(room.code, transformed)
+	if err != nil {
+		room.sendError(client, err.Error())
+		room.sendState(client)
+		return
+	}
+
+	room.code = code
+	room.version++
+	room.history = append(room.history, transformed)
+	if len(room.history) > liveHistorySize {
+		drop := len(room.history) - liveHistorySize
+		room.history = room.history[drop:]
+		room.historyStart += drop
+	}
+
+	event := room.record(models.LiveEvent{Type: models.LiveEventDelta, Role: client.Role, Name: client.Name, Version: room.version, Delta: &transformed})
+	room.broadcast(event, client, nil)
+	room.send(client, map[string]interface{}{"type": "ack", "version": room.version})
+	if transformed != delta {
+		// The sender's editor no longer matches; send it the merged code
+		room.sendState(client)
+	}
+}
+
+// record stamps an event and appends it to the transcript
+func (room *liveRoom) record(event models.LiveEvent) models.LiveEvent {
+	room.seq++
+	event.Seq = room.seq
+	event.At = time.Now()
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return event
+	}
+	file, err := os.OpenFile(room.transcript, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return event
+	}
+	defer file.Close()
+	file.Write(append(line, '\n'))
+	return event
+}
+
+// sendState sends the full code and participants to a client
+func (room *liveRoom) sendState(client *LiveClient) {
+	state := liveState{
+		Type:         "state",
+		SessionID:    room.session.ID,
+		ChallengeID:  room.session.ChallengeID,
+		Role:         client.Role,
+		Code:         string(utf16.Decode(room.code)),
+		Version:      room.version,
+		Participants: room.participants(),
+	}
+	if client.Role == models.LiveInterviewer {
+		state.Notes = room.notes
+	}
+	room.send(client, state)
+}
+
+// sendError reports a rejected message to its sender
+func (room *liveRoom) sendError(client *LiveClient, message string) {
+	room.send(client, map[string]interface{}{"type": "error", "message": message})
+}
+
+// broadcastPresence tells every client who is connected
+func (room *liveRoom) broadcastPresence() {
+	room.broadcast(map[string]interface{}{"type": "presence", "participants": room.participants()}, nil, nil)
+}
+
+// broadcast sends a message to every client except skip that passes the filter
+func (room *liveRoom) broadcast(message interface{}, skip *LiveClient, filter func(*LiveClient) bool) {
+	for client := range room.clients {
+		if client != skip && (filter == nil || filter(client)) {
+			room.send(client, message)
+		}
+	}
+}
+
+// send queues a message for a client, dropping the client if its queue is full
+func (room *liveRoom) send(client *LiveClient, message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	select {
+	case client.Send <- data:
+	default:
+		room.drop(client)
+	}
+}
+
+// drop removes a client and closes its queue
+func (room *liveRoom) drop(client *LiveClient) {
+	if room.clients[client] {
+		delete(room.clients, client)
+		close(client.Send)
+	}
+}
+
+// participants lists the connected clients, candidate first
+func (room *liveRoom) participants() []liveParticipant {
+	participants := []liveParticipant{}
+	for client := range room.clients {
+		participants = append(participants, liveParticipant{Role: client.Role, Name: client.Name})
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		if participants[i].Role != participants[j].Role {
+			return participants[i].Role == models.LiveCandidate
+		}
+		return participants[i].Name < participants[j].Name
+	})
+	return participants
+}
+
+// transformDelta rewrites delta so it applies after applied, which was made against the same version.
+// Non-overlapping edits are shifted; overlapping edits let delta replace both ranges.
+func transformDelta(delta, applied models.CodeDelta) models.CodeDelta {
+	shift := utf16Len(applied.Text) - (applied.To - applied.From)
+	bothInsertAtSamePoint := delta.From == applied.From && applied.From == applied.To
+
+	switch {
+	case delta.To <= applied.From && !bothInsertAtSamePoint:
+		return delta
+	case delta.From >= applied.To:
+		return models.CodeDelta{From: delta.From + shift, To: delta.To + shift, Text: delta.Text}
+	default:
+		from := delta.From
+		if applied.From < from {
+			from = applied.From
+		}
+		to := delta.To
+		if applied.To > to {
+			to = applied.To
+		}
+		return models.CodeDelta{From: from, To: to + shift, Text: delta.Text}
+	}
+}
+
+// applyDelta returns code with a delta applied
+func applyDelta(code []uint16, delta models.CodeDelta) ([]uint16, error) {
+	if delta.From < 0 || delta.To < delta.From || delta.To > len(code) {
+		return nil, fmt.Errorf("delta range %d-%d is outside the code", delta.From, delta.To)
+	}
+
+	text := utf16.Encode([]rune(delta.Text))
+	result := make([]uint16, 0, len(code)-(delta.To-delta.From)+len(text))
+	result = append(result, code[:delta.From]...)
+	result = append(result, text...)
+	result = append(result, code[delta.To:]...)
+	if len(result) > MaxSubmissionBytes {
+		return nil, fmt.Errorf("code exceeds %d characters", MaxSubmissionBytes)
+	}
+	return result, nil
+}
+
+// utf16Len returns the length of s in UTF-16 code units
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
+// readTranscript reads the events of a transcript file
+func readTranscript(path string) ([]models.LiveEvent, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return []models.LiveEvent{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events := []models.LiveEvent{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 8<<20)
+	for scanner.Scan() {
+		var event models.LiveEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			// Skip a line cut short by a crash
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// randomToken returns n random bytes, hex encoded
+func randomToken(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -23,7 +23,7 @@ func NewPackageService() *PackageService {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		packagesPath: "../packages", // Relative to web-ui directory
+		packagesPath: packagesPath,
 	}
 }
 
@@ -42,6 +42,30 @@ type PackageMetadata struct {
 	Tags             []string `json:"tags"`
 	EstimatedTime    string   `json:"estimated_time"`
 	RealWorldUsage   []string `json:"real_world_usage"`
+
+	Icon         string              `json:"icon,omitempty"`         // Bootstrap icon class of the track
+	Dependencies models.Dependencies `json:"dependencies,omitempty"` // Modules every challenge of the track needs
+	Defaults     ChallengeDefaults   `json:"defaults,omitempty"`     // Fallbacks for challenges without metadata
+}
+
+// ChallengeDefaults are the card values used for a track's challenges that don't declare their own
+type ChallengeDefaults struct {
+	Icon          string `json:"icon,omitempty"`
+	EstimatedTime string `json:"estimated_time,omitempty"`
+}
+
+// readPackageMetadata reads and parses a track's package.json
+func readPackageMetadata(packagePath string) (*PackageMetadata, error) {
+	metadataBytes, err := os.ReadFile(filepath.Join(packagePath, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata PackageMetadata
+	if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
 }
 
 func (s *PackageService) LoadPackages() error {
@@ -83,16 +107,9 @@ func (s *PackageService) loadPackage(packagePath, packageName string) *models.Pa
 	}
 
 	// Load package.json
-	metadataPath := filepath.Join(packagePath, "package.json")
-	metadataBytes, err := os.ReadFile(metadataPath)
+	metadata, err := readPackageMetadata(packagePath)
 	if err != nil {
-		fmt.Printf("Error reading package.json for %s: %v\n", packageName, err)
-		return nil
-	}
-
-	var metadata PackageMetadata
-	if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
-		fmt.Printf("Error parsing package.json for %s: %v\n", packageName, err)
+		fmt.Printf("Error loading package.json for %s: %v\n", packageName, err)
 		return nil
 	}
 
@@ -103,7 +120,7 @@ func (s *PackageService) loadPackage(packagePath, packageName string) *models.Pa
 	}
 
 	// Load challenge details dynamically
-	challengeDetails := s.loadChallengeDetails(packagePath, metadata.LearningPath)
+	challengeDetails := s.loadChallengeDetails(packagePath, metadata.LearningPath, metadata.Defaults)
 
 	return &models.Package{
 		Name:             packageName,
@@ -120,12 +137,13 @@ func (s *PackageService) loadPackage(packagePath, packageName string) *models.Pa
 		Tags:             metadata.Tags,
 		EstimatedTime:    metadata.EstimatedTime,
 		RealWorldUsage:   metadata.RealWorldUsage,
+		Icon:             metadata.Icon,
 		ChallengeDetails: challengeDetails,
 	}
 }
 
 // loadChallengeDetails dynamically loads metadata for each challenge in the learning path
-func (s *PackageService) loadChallengeDetails(packagePath string, learningPath []string) map[string]*models.ChallengeInfo {
+func (s *PackageService) loadChallengeDetails(packagePath string, learningPath []string, defaults ChallengeDefaults) map[string]*models.ChallengeInfo {
 	challengeDetails := make(map[string]*models.ChallengeInfo)
 
 	for i, challengeID := range learningPath {
@@ -139,10 +157,10 @@ func (s *PackageService) loadChallengeDetails(packagePath string, learningPath [
 				Title:         s.generateTitleFromID(challengeID),
 				Description:   "Coming soon",
 				Difficulty:    s.inferDifficultyFromOrder(i),
-				EstimatedTime: s.inferEstimatedTime(challengeID),
+				EstimatedTime: defaults.EstimatedTime,
 				Status:        "coming-soon",
 				Order:         i + 1,
-				Icon:          s.inferIconFromID(challengeID),
+				Icon:          defaults.Icon,
 			}
 			continue
 		}
@@ -155,12 +173,12 @@ func (s *PackageService) loadChallengeDetails(packagePath string, learningPath [
 				Title:               metadata.Title,
 				Description:         metadata.ShortDescription,
 				Difficulty:          metadata.Difficulty,
-				EstimatedTime:       metadata.EstimatedTime,
+				EstimatedTime:       valueOr(metadata.EstimatedTime, defaults.EstimatedTime),
 				LearningObjectives:  metadata.LearningObjectives,
 				Prerequisites:       metadata.Prerequisites,
 				Tags:                metadata.Tags,
 				RealWorldConnection: metadata.RealWorldConnection,
-				Icon:                metadata.Icon,
+				Icon:                valueOr(metadata.Icon, defaults.Icon),
 				Status:              "available",
 				Order:               i + 1,
 			}
@@ -171,10 +189,10 @@ func (s *PackageService) loadChallengeDetails(packagePath string, learningPath [
 				Title:         s.generateTitleFromID(challengeID),
 				Description:   s.generateDescriptionFromReadme(challengePath),
 				Difficulty:    s.inferDifficultyFromOrder(i),
-				EstimatedTime: s.inferEstimatedTime(challengeID),
+				EstimatedTime: defaults.EstimatedTime,
 				Status:        "available",
 				Order:         i + 1,
-				Icon:          s.inferIconFromID(challengeID),
+				Icon:          defaults.Icon,
 			}
 		}
 	}
@@ -184,6 +202,11 @@ func (s *PackageService) loadChallengeDetails(packagePath string, learningPath [
 
 // loadChallengeMetadata loads metadata from challenge directory
 func (s *PackageService) loadChallengeMetadata(challengePath string) *models.ChallengeMetadata {
+	return readChallengeMetadata(challengePath)
+}
+
+// readChallengeMetadata reads the optional metadata.json of a classic or package challenge
+func readChallengeMetadata(challengePath string) *models.ChallengeMetadata {
 	metadataPath := filepath.Join(challengePath, "metadata.json")
 
 	// Check if metadata.json exists
@@ -249,42 +272,12 @@ func (s *PackageService) inferDifficultyFromOrder(order int) string {
 	}
 }
 
-func (s *PackageService) inferEstimatedTime(challengeID string) string {
-	switch {
-	case strings.Contains(challengeID, "basic"):
-		return "30-45 min"
-	case strings.Contains(challengeID, "middleware"):
-		return "45-60 min"
-	case strings.Contains(challengeID, "validation") || strings.Contains(challengeID, "error"):
-		return "60-90 min"
-	case strings.Contains(challengeID, "auth"):
-		return "90-120 min"
-	case strings.Contains(challengeID, "file") || strings.Contains(challengeID, "upload"):
-		return "60-90 min"
-	default:
-		return "45-60 min"
-	}
-}
-
-func (s *PackageService) inferIconFromID(challengeID string) string {
-	switch {
-	case strings.Contains(challengeID, "basic") || strings.Contains(challengeID, "routing"):
-		return "bi-play-circle"
-	case strings.Contains(challengeID, "middleware"):
-		return "bi-layers"
-	case strings.Contains(challengeID, "validation") || strings.Contains(challengeID, "error"):
-		return "bi-shield-check"
-	case strings.Contains(challengeID, "auth"):
-		return "bi-person-lock"
-	case strings.Contains(challengeID, "file") || strings.Contains(challengeID, "upload"):
-		return "bi-cloud-upload"
-	case strings.Contains(challengeID, "database") || strings.Contains(challengeID, "db"):
-		return "bi-database"
-	case strings.Contains(challengeID, "cli"):
-		return "bi-terminal"
-	default:
-		return "bi-code-slash"
+// valueOr returns value, or fallback if value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
 	}
+	return value
 }
 
 func (s *PackageService) loadChallenges(packagePath string) []models.PackageChallenge {
@@ -339,6 +332,12 @@ func (s *PackageService) loadChallenge(challengePath, challengeName string) *mod
 		testFile = "// Test file not available"
 	}
 
+	// Load hidden tests, which are only used on submit
+	hiddenTestFile := s.readFileContent(filepath.Join(challengePath, HiddenTestFileName))
+
+	// Load fuzz targets, which are only used by fuzz runs
+	fuzzTestFile := s.readFileContent(filepath.Join(challengePath, FuzzTestFileName))
+
 	// Load hints
 	hints := s.readFileContent(filepath.Join(challengePath, "hints.md"))
 	if hints == "" {
@@ -368,6 +367,18 @@ func (s *PackageService) loadChallenge(challengePath, challengeName string) *mod
 		}
 	}
 
+	var supportFiles []string
+	var execution models.ExecutionPolicy
+	var dependencies models.Dependencies
+	if track, err := readPackageMetadata(filepath.Dir(challengePath)); err == nil {
+		dependencies = track.Dependencies
+	}
+	if metadata != nil {
+		supportFiles = metadata.SupportFiles
+		execution = metadata.Execution
+		dependencies = dependencies.Merge(metadata.Dependencies)
+	}
+
 	return &models.PackageChallenge{
 		ID:                challengeName,
 		Title:             title,
@@ -375,8 +386,14 @@ func (s *PackageService) loadChallenge(challengePath, challengeName string) *mod
 		Difficulty:        difficulty,
 		Template:          template,
 		TestFile:          testFile,
+		HiddenTestFile:    hiddenTestFile,
+		FuzzTestFile:      fuzzTestFile,
 		Hints:             hints,
 		LearningMaterials: learningMaterials, // Use learning.md for learning materials tab
+		SupportFiles:      supportFiles,
+		Execution:         execution,
+		Dependencies:      dependencies,
+		Dir:               challengePath,
 	}
 }
 
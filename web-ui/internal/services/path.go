@@ -0,0 +1,385 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"web-ui/internal/models"
+)
+
+// ClassicTrack is the track name of the numbered challenges
+const ClassicTrack = "classic"
+
+var classicPrerequisite = regexp.MustCompile(`^(?:classic/|challenge-)(\d+)$`)
+
+// difficultyRank orders difficulties for recommendations; unknown difficulties sort in the middle
+var difficultyRank = map[string]int{
+	"beginner":     0,
+	"intermediate": 1,
+	"advanced":     2,
+}
+
+// PathService builds the learning-path graph from the prerequisites in challenge and package metadata.
+// A prerequisite is a challenge if it names one ("challenge-7", "classic/7", "gin/challenge-2-middleware",
+// a challenge of the same package, or a whole package); anything else is a concept and adds no edge.
+// Package challenges also depend on the previous challenge in the package's learning path.
+type PathService struct {
+	enforce bool
+}
+
+// pathGraph is the resolved graph
+type pathGraph struct {
+	nodes  map[string]*models.PathNode
+	order  []string       // Topological order; challenges in or behind a cycle come last
+	cycle  map[string]int // Cycle each challenge belongs to, if any
+	cycles [][]string
+}
+
+// NewPathService creates a path service. With enforce set, challenges whose prerequisites are not completed are locked.
+func NewPathService(enforce bool) *PathService {
+	return &PathService{enforce: enforce}
+}
+
+// Enforced reports whether challenges with missing prerequisites are locked
+func (ps *PathService) Enforced() bool {
+	return ps.enforce
+}
+
+// ClassicRef returns the path reference of a classic challenge
+func ClassicRef(id int) string {
+	return fmt.Sprintf("%s/%d", ClassicTrack, id)
+}
+
+// PackageRef returns the path reference of a package challenge
+func PackageRef(packageName, challengeID string) string {
+	return packageName + "/" + challengeID
+}
+
+// Cycles returns the prerequisite cycles in the graph
+func (ps *PathService) Cycles(challenges models.ChallengeMap, packages models.PackageMap) [][]string {
+	return buildPathGraph(challenges, packages).cycles
+}
+
+// Plan orders every challenge after its prerequisites and recommends the next one for a user
+func (ps *PathService) Plan(username string, challenges models.ChallengeMap, packages models.PackageMap, completed map[string]bool) *models.LearningPlan {
+	graph := buildPathGraph(challenges, packages)
+	plan := &models.LearningPlan{
+		Username: username,
+		Enforced: ps.enforce,
+		Total:    len(graph.order),
+		Plan:     make([]models.PathNode, 0, len(graph.order)),
+		Cycles:   graph.cycles,
+	}
+
+	startedTracks := make(map[string]bool)
+	for _, ref := range graph.order {
+		if completed[ref] {
+			startedTracks[graph.nodes[ref].Track] = true
+		}
+	}
+
+	var recommended *models.PathNode
+	for _, ref := range graph.order {
+		node := *graph.nodes[ref]
+		node.Completed = completed[ref]
+		node.Available = node.Completed || len(graph.missing(ref, completed)) == 0
+		node.Locked = ps.enforce && !node.Available
+		if node.Completed {
+			plan.Completed++
+		}
+		plan.Plan = append(plan.Plan, node)
+
+		if !node.Completed && node.Available && betterRecommendation(&node, recommended, startedTracks) {
+			candidate := node
+			recommended = &candidate
+		}
+	}
+	plan.Recommended = recommended
+	return plan
+}
+
+// MissingPrerequisites returns the prerequisites of a challenge that block it, or nil when prerequisites are not enforced
+func (ps *PathService) MissingPrerequisites(ref string, challenges models.ChallengeMap, packages models.PackageMap, completed map[string]bool) []string {
+	if !ps.enforce || completed[ref] {
+		return nil
+	}
+	return buildPathGraph(challenges, packages).missing(ref, completed)
+}
+
+// PrerequisitesError reports the uncompleted prerequisites that lock a challenge for a user
+type PrerequisitesError struct {
+	Ref     string
+	Missing []string
+}
+
+func (e *PrerequisitesError) Error() string {
+	return "Challenge locked. Complete these challenges first: " + strings.Join(e.Missing, ", ")
+}
+
+// CheckPrerequisites returns a *PrerequisitesError when prerequisites are enforced and a user has not completed
+// those of a challenge. Anonymous runs are not checked, as nothing records them.
+func (ps *PathService) CheckPrerequisites(username, ref string, challenges models.ChallengeMap, packages models.PackageMap, users *UserService) error {
+	if username == "" || !ps.enforce {
+		return nil
+	}
+	completed := users.GetCompletedRefs(username, challenges, packages)
+	if missing := ps.MissingPrerequisites(ref, challenges, packages, completed); len(missing) > 0 {
+		return &PrerequisitesError{Ref: ref, Missing: missing}
+	}
+	return nil
+}
+
+// betterRecommendation prefers challenges in tracks the user has started, then easier ones, then earlier ones in the plan
+func betterRecommendation(node, current *models.PathNode, startedTracks map[string]bool) bool {
+	if current == nil {
+		return true
+	}
+	if startedTracks[node.Track] != startedTracks[current.Track] {
+		return startedTracks[node.Track]
+	}
+	return rankDifficulty(node.Difficulty) < rankDifficulty(current.Difficulty)
+}
+
+// rankDifficulty returns the order of a difficulty label
+func rankDifficulty(difficulty string) int {
+	if rank, ok := difficultyRank[strings.ToLower(difficulty)]; ok {
+		return rank
+	}
+	return 1
+}
+
+// missing returns the uncompleted prerequisites of a challenge, ignoring edges inside a cycle
+func (g *pathGraph) missing(ref string, completed map[string]bool) []string {
+	node, exists := g.nodes[ref]
+	if !exists {
+		return nil
+	}
+
+	var missing []string
+	for _, prerequisite := range node.Prerequisites {
+		if completed[prerequisite] {
+			continue
+		}
+		if c, inCycle := g.cycle[ref]; inCycle {
+			if pc, prerequisiteInCycle := g.cycle[prerequisite]; prerequisiteInCycle && pc == c {
+				continue
+			}
+		}
+		missing = append(missing, prerequisite)
+	}
+	return missing
+}
+
+// buildPathGraph resolves prerequisites into edges and sorts the challenges topologically
+func buildPathGraph(challenges models.ChallengeMap, packages models.PackageMap) *pathGraph {
+	g := &pathGraph{nodes: make(map[string]*models.PathNode), cycle: make(map[string]int)}
+	sortKey := make(map[string]string)
+	packageRefs := make(map[string][]string)
+
+	ids := make([]int, 0, len(challenges))
+	for id := range challenges {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		ref := ClassicRef(id)
+		g.nodes[ref] = &models.PathNode{Ref: ref, Track: ClassicTrack, Title: challenges[id].Title, Difficulty: challenges[id].Difficulty, Prerequisites: []string{}}
+		sortKey[ref] = fmt.Sprintf("0/%06d", id)
+	}
+
+	packageNames := make([]string, 0, len(packages))
+	for name := range packages {
+		packageNames = append(packageNames, name)
+	}
+	sort.Strings(packageNames)
+	for _, name := range packageNames {
+		for i, challengeID := range packages[name].LearningPath {
+			ref := PackageRef(name, challengeID)
+			node := &models.PathNode{Ref: ref, Track: name, Title: challengeID, Prerequisites: []string{}}
+			if info := packages[name].ChallengeDetails[challengeID]; info != nil {
+				node.Title = info.Title
+				node.Difficulty = info.Difficulty
+			}
+			g.nodes[ref] = node
+			sortKey[ref] = fmt.Sprintf("1/%s/%06d", name, i)
+			packageRefs[name] = append(packageRefs[name], ref)
+		}
+	}
+
+	// resolve turns a prerequisite into challenge refs, or records it as a concept
+	resolve := func(node *models.PathNode, prerequisite string) {
+		prerequisite = strings.TrimSpace(prerequisite)
+		var refs []string
+		if m := classicPrerequisite.FindStringSubmatch(prerequisite); m != nil {
+			id, _ := strconv.Atoi(m[1])
+			if _, exists := challenges[id]; exists {
+				refs = []string{ClassicRef(id)}
+			}
+		} else if _, exists := g.nodes[prerequisite]; exists {
+			refs = []string{prerequisite}
+		} else if _, exists := g.nodes[PackageRef(node.Track, prerequisite)]; exists && node.Track != ClassicTrack {
+			refs = []string{PackageRef(node.Track, prerequisite)}
+		} else if track, exists := packageRefs[prerequisite]; exists {
+			refs = track
+		}
+
+		if refs == nil {
+			node.Concepts = append(node.Concepts, prerequisite)
+			return
+		}
+		for _, ref := range refs {
+			if !containsString(node.Prerequisites, ref) {
+				node.Prerequisites = append(node.Prerequisites, ref)
+			}
+		}
+	}
+
+	for _, id := range ids {
+		node := g.nodes[ClassicRef(id)]
+		for _, prerequisite := range challenges[id].Prerequisites {
+			resolve(node, prerequisite)
+		}
+	}
+	for _, name := range packageNames {
+		pkg := packages[name]
+		for i, challengeID := range pkg.LearningPath {
+			node := g.nodes[PackageRef(name, challengeID)]
+			if i == 0 {
+				for _, prerequisite := range pkg.Prerequisites {
+					resolve(node, prerequisite)
+				}
+			} else {
+				resolve(node, PackageRef(name, pkg.LearningPath[i-1]))
+			}
+			if info := pkg.ChallengeDetails[challengeID]; info != nil {
+				for _, prerequisite := range info.Prerequisites {
+					resolve(node, prerequisite)
+				}
+			}
+		}
+	}
+
+	g.sort(sortKey)
+	return g
+}
+
+// sort orders the graph with Kahn's algorithm, picking ready challenges by sortKey, and finds the cycles
+func (g *pathGraph) sort(sortKey map[string]string) {
+	indegree := make(map[string]int)
+	dependents := make(map[string][]string)
+	for ref, node := range g.nodes {
+		indegree[ref] = len(node.Prerequisites)
+		for _, prerequisite := range node.Prerequisites {
+			dependents[prerequisite] = append(dependents[prerequisite], ref)
+		}
+	}
+
+	less := func(refs []string) func(i, j int) bool {
+		return func(i, j int) bool { return sortKey[refs[i]] < sortKey[refs[j]] }
+	}
+
+	var ready []string
+	for ref, degree := range indegree {
+		if degree == 0 {
+			ready = append(ready, ref)
+		}
+	}
+	done := make(map[string]bool)
+	for len(ready) > 0 {
+		sort.Slice(ready, less(ready))
+		ref := ready[0]
+		ready = ready[1:]
+		g.order = append(g.order, ref)
+		done[ref] = true
+		for _, dependent := range dependents[ref] {
+			indegree[dependent]--
+			if indegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(g.order) == len(g.nodes) {
+		return
+	}
+
+	// Everything left is in a cycle or depends on one
+	var remaining []string
+	for ref := range g.nodes {
+		if !done[ref] {
+			remaining = append(remaining, ref)
+		}
+	}
+	sort.Slice(remaining, less(remaining))
+	g.order = append(g.order, remaining...)
+	g.findCycles(remaining)
+}
+
+// findCycles records the strongly connected components of the given challenges that form cycles (Tarjan's algorithm)
+func (g *pathGraph) findCycles(refs []string) {
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	next := 0
+
+	var connect func(ref string)
+	connect = func(ref string) {
+		index[ref] = next
+		lowlink[ref] = next
+		next++
+		stack = append(stack, ref)
+		onStack[ref] = true
+
+		for _, prerequisite := range g.nodes[ref].Prerequisites {
+			if _, visited := index[prerequisite]; !visited {
+				connect(prerequisite)
+				if lowlink[prerequisite] < lowlink[ref] {
+					lowlink[ref] = lowlink[prerequisite]
+				}
+			} else if onStack[prerequisite] && index[prerequisite] < lowlink[ref] {
+				lowlink[ref] = index[prerequisite]
+			}
+		}
+
+		if lowlink[ref] != index[ref] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == ref {
+				break
+			}
+		}
+		if len(component) > 1 || containsString(g.nodes[ref].Prerequisites, ref) {
+			sort.Strings(component)
+			for _, member := range component {
+				g.cycle[member] = len(g.cycles)
+			}
+			g.cycles = append(g.cycles, component)
+		}
+	}
+
+	for _, ref := range refs {
+		if _, visited := index[ref]; !visited {
+			connect(ref)
+		}
+	}
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"web-ui/internal/models"
+)
+
+// pathTestCatalog returns classic challenges 1-3, where 2 needs 1 and 3 needs gin, and a two-challenge gin track
+func pathTestCatalog(dir string) (models.ChallengeMap, models.PackageMap) {
+	challenges := models.ChallengeMap{
+		1: {ID: 1, Difficulty: "Beginner", Dir: filepath.Join(dir, "challenge-1"), Prerequisites: []string{"Basic Go syntax"}},
+		2: {ID: 2, Difficulty: "Beginner", Dir: filepath.Join(dir, "challenge-2"), Prerequisites: []string{"challenge-1"}},
+		3: {ID: 3, Difficulty: "Advanced", Dir: filepath.Join(dir, "challenge-3"), Prerequisites: []string{"gin"}},
+	}
+	packages := models.PackageMap{
+		"gin": {LearningPath: []string{"challenge-1-routing", "challenge-2-middleware"}, Prerequisites: []string{"classic/1"}},
+	}
+	return challenges, packages
+}
+
+func TestPathGraphPrerequisites(t *testing.T) {
+	challenges, packages := pathTestCatalog(t.TempDir())
+	graph := buildPathGraph(challenges, packages)
+
+	tests := []struct {
+		ref          string
+		wantPrereqs  []string
+		wantConcepts []string
+	}{
+		{"classic/1", []string{}, []string{"Basic Go syntax"}},
+		{"classic/2", []string{"classic/1"}, nil},
+		{"classic/3", []string{"gin/challenge-1-routing", "gin/challenge-2-middleware"}, nil},
+		{"gin/challenge-1-routing", []string{"classic/1"}, nil},
+		{"gin/challenge-2-middleware", []string{"gin/challenge-1-routing"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			node := graph.nodes[tt.ref]
+			if node == nil {
+				t.Fatalf("%s is not in the graph", tt.ref)
+			}
+			if !reflect.DeepEqual(node.Prerequisites, tt.wantPrereqs) || !reflect.DeepEqual(node.Concepts, tt.wantConcepts) {
+				t.Errorf("prerequisites = %v, concepts = %v, want %v, %v", node.Prerequisites, node.Concepts, tt.wantPrereqs, tt.wantConcepts)
+			}
+		})
+	}
+
+	want := []string{"classic/1", "classic/2", "gin/challenge-1-routing", "gin/challenge-2-middleware", "classic/3"}
+	if !reflect.DeepEqual(graph.order, want) {
+		t.Errorf("order = %v, want %v", graph.order, want)
+	}
+}
+
+func TestPathGraphCycles(t *testing.T) {
+	challenges := models.ChallengeMap{
+		1: {ID: 1, Prerequisites: []string{"challenge-2"}},
+		2: {ID: 2, Prerequisites: []string{"challenge-1"}},
+		3: {ID: 3, Prerequisites: []string{"challenge-1"}},
+	}
+	graph := buildPathGraph(challenges, nil)
+
+	if want := [][]string{{"classic/1", "classic/2"}}; !reflect.DeepEqual(graph.cycles, want) {
+		t.Fatalf("cycles = %v, want %v", graph.cycles, want)
+	}
+	// Edges inside a cycle don't lock its challenges, edges out of it still do
+	if missing := graph.missing("classic/1", nil); len(missing) != 0 {
+		t.Errorf("missing(classic/1) = %v, want none", missing)
+	}
+	if missing := graph.missing("classic/3", nil); !reflect.DeepEqual(missing, []string{"classic/1"}) {
+		t.Errorf("missing(classic/3) = %v, want [classic/1]", missing)
+	}
+}
+
+func TestPlanRecommendation(t *testing.T) {
+	challenges, packages := pathTestCatalog(t.TempDir())
+
+	tests := []struct {
+		name      string
+		completed map[string]bool
+		want      string
+	}{
+		{"new user", nil, "classic/1"},
+		{"classic started", map[string]bool{"classic/1": true}, "classic/2"},
+		{"easier first", map[string]bool{"classic/1": true, "gin/challenge-1-routing": true}, "classic/2"},
+		{"locked skipped", map[string]bool{"classic/1": true, "classic/2": true, "gin/challenge-1-routing": true}, "gin/challenge-2-middleware"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := NewPathService(true).Plan("alice", challenges, packages, tt.completed)
+			if plan.Recommended == nil || plan.Recommended.Ref != tt.want {
+				t.Fatalf("recommended = %+v, want %s", plan.Recommended, tt.want)
+			}
+			for _, node := range plan.Plan {
+				if node.Locked == node.Available {
+					t.Errorf("%s: locked = %v, available = %v", node.Ref, node.Locked, node.Available)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckPrerequisites(t *testing.T) {
+	dir := t.TempDir()
+	challenges, packages := pathTestCatalog(dir)
+	solution := filepath.Join(dir, "challenge-1", "submissions", "bob", "solution-template.go")
+	if err := os.MkdirAll(filepath.Dir(solution), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(solution, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		enforce     bool
+		username    string
+		ref         string
+		wantMissing []string
+	}{
+		{"not enforced", false, "alice", "classic/2", nil},
+		{"anonymous", true, "", "classic/2", nil},
+		{"no prerequisites", true, "alice", "classic/1", nil},
+		{"missing classic prerequisite", true, "alice", "classic/2", []string{"classic/1"}},
+		{"completed classic prerequisite", true, "bob", "classic/2", nil},
+		{"previous package challenge", true, "bob", "gin/challenge-2-middleware", []string{"gin/challenge-1-routing"}},
+		{"whole package", true, "bob", "classic/3", []string{"gin/challenge-1-routing", "gin/challenge-2-middleware"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewPathService(tt.enforce).CheckPrerequisites(tt.username, tt.ref, challenges, packages, NewUserService())
+			if tt.wantMissing == nil {
+				if err != nil {
+					t.Fatalf("CheckPrerequisites() = %v, want nil", err)
+				}
+				return
+			}
+			var locked *PrerequisitesError
+			if !errors.As(err, &locked) {
+				t.Fatalf("CheckPrerequisites() = %v, want a *PrerequisitesError", err)
+			}
+			if locked.Ref != tt.ref || !reflect.DeepEqual(locked.Missing, tt.wantMissing) {
+				t.Errorf("locked = %+v, want %v missing", locked, tt.wantMissing)
+			}
+		})
+	}
+}
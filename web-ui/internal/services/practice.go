@@ -0,0 +1,273 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+const (
+	initialEaseFactor = 2.5
+	minEaseFactor     = 1.3
+	// attemptIdleTimeout restarts the solve timer when a challenge is picked up again after a break
+	attemptIdleTimeout = 2 * time.Hour
+)
+
+// expectedSolveTime is how long a review of a challenge should take, by difficulty
+var expectedSolveTime = map[string]time.Duration{
+	"Beginner":     15 * time.Minute,
+	"Intermediate": 30 * time.Minute,
+	"Advanced":     45 * time.Minute,
+}
+
+// PracticeService schedules re-attempts of solved challenges with SM-2.
+// Each passing submission is a review, graded by the failed runs and the time it took.
+type PracticeService struct {
+	mu     sync.Mutex
+	dir    string
+	states map[string]*models.PracticeState
+}
+
+// NewPracticeService creates a practice service storing one JSON file per user in dir
+func NewPracticeService(dir string) *PracticeService {
+	return &PracticeService{
+		dir:    dir,
+		states: make(map[string]*models.PracticeState),
+	}
+}
+
+// RecordRun counts a test run towards the user's current attempt at a challenge
+func (ps *PracticeService) RecordRun(username string, challengeID int, passed bool, at time.Time) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	state, err := ps.load(username)
+	if err != nil {
+		return err
+	}
+
+	attempt := currentAttempt(state, challengeID, at)
+	if !passed {
+		attempt.FailedRuns++
+	}
+	return ps.save(state)
+}
+
+// RecordSubmission records a submission. A passing one is reviewed and rescheduled, a failing one counts as a failed run.
+func (ps *PracticeService) RecordSubmission(username string, challenge *models.Challenge, passed bool, at time.Time) (*models.PracticeCard, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	state, err := ps.load(username)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := currentAttempt(state, challenge.ID, at)
+	if !passed {
+		attempt.FailedRuns++
+		return state.Cards[challenge.ID], ps.save(state)
+	}
+
+	solve := at.Sub(attempt.StartedAt)
+	review := models.PracticeReview{
+		At:           at,
+		Quality:      reviewQuality(attempt.FailedRuns, solve, expectedSolveTime[challenge.Difficulty]),
+		FailedRuns:   attempt.FailedRuns,
+		SolveSeconds: int(solve.Seconds()),
+	}
+	delete(state.Attempts, challenge.ID)
+
+	card := state.Cards[challenge.ID]
+	if card == nil {
+		card = &models.PracticeCard{ChallengeID: challenge.ID, EaseFactor: initialEaseFactor}
+		state.Cards[challenge.ID] = card
+	}
+	scheduleReview(card, review)
+	return card, ps.save(state)
+}
+
+// Seed schedules solved challenges that have no card yet, treating each solve time as a first review
+func (ps *PracticeService) Seed(username string, solved map[int]time.Time) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	state, err := ps.load(username)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for challengeID, solvedAt := range solved {
+		if state.Cards[challengeID] != nil {
+			continue
+		}
+		card := &models.PracticeCard{ChallengeID: challengeID, EaseFactor: initialEaseFactor}
+		scheduleReview(card, models.PracticeReview{At: solvedAt, Quality: 4, Seeded: true})
+		state.Cards[challengeID] = card
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return ps.save(state)
+}
+
+// Due returns the cards due by the end of the day of now, most overdue first
+func (ps *PracticeService) Due(username string, challenges models.ChallengeMap, now time.Time) ([]models.PracticeItem, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	state, err := ps.load(username)
+	if err != nil {
+		return nil, err
+	}
+
+	endOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	items := []models.PracticeItem{}
+	for challengeID, card := range state.Cards {
+		challenge, exists := challenges[challengeID]
+		if !exists || !card.DueAt.Before(endOfDay) {
+			continue
+		}
+		overdue := 0
+		if now.After(card.DueAt) {
+			overdue = int(now.Sub(card.DueAt).Hours() / 24)
+		}
+		items = append(items, models.PracticeItem{
+			ChallengeID:  challengeID,
+			Title:        challenge.Title,
+			Difficulty:   challenge.Difficulty,
+			DueAt:        card.DueAt,
+			OverdueDays:  overdue,
+			IntervalDays: card.IntervalDays,
+			Repetitions:  card.Repetitions,
+			EaseFactor:   card.EaseFactor,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].DueAt.Equal(items[j].DueAt) {
+			return items[i].DueAt.Before(items[j].DueAt)
+		}
+		return items[i].ChallengeID < items[j].ChallengeID
+	})
+	return items, nil
+}
+
+// Cards returns a user's cards with their review history, ordered by challenge
+func (ps *PracticeService) Cards(username string) ([]*models.PracticeCard, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	state, err := ps.load(username)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]*models.PracticeCard, 0, len(state.Cards))
+	for _, card := range state.Cards {
+		cards = append(cards, card)
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		return cards[i].ChallengeID < cards[j].ChallengeID
+	})
+	return cards, nil
+}
+
+// currentAttempt returns the open attempt at a challenge, starting a new one if there is none or it went idle
+func currentAttempt(state *models.PracticeState, challengeID int, at time.Time) *models.PracticeAttempt {
+	attempt := state.Attempts[challengeID]
+	if attempt == nil || at.Sub(attempt.LastRunAt) > attemptIdleTimeout {
+		attempt = &models.PracticeAttempt{StartedAt: at}
+		state.Attempts[challengeID] = attempt
+	}
+	attempt.LastRunAt = at
+	return attempt
+}
+
+// reviewQuality grades a solve from 0 to 5, taking points off for failed runs and for exceeding the expected time
+func reviewQuality(failedRuns int, solve, expected time.Duration) int {
+	quality := 5
+	for _, threshold := range []int{1, 3, 6} {
+		if failedRuns >= threshold {
+			quality--
+		}
+	}
+	if expected > 0 {
+		if solve > expected {
+			quality--
+		}
+		if solve > 2*expected {
+			quality--
+		}
+	}
+	if quality < 0 {
+		quality = 0
+	}
+	return quality
+}
+
+// scheduleReview applies an SM-2 review to a card
+func scheduleReview(card *models.PracticeCard, review models.PracticeReview) {
+	if review.Quality >= 3 {
+		switch card.Repetitions {
+		case 0:
+			card.IntervalDays = 1
+		case 1:
+			card.IntervalDays = 6
+		default:
+			card.IntervalDays = int(math.Round(float64(card.IntervalDays) * card.EaseFactor))
+		}
+		card.Repetitions++
+	} else {
+		card.Repetitions = 0
+		card.IntervalDays = 1
+	}
+
+	q := float64(5 - review.Quality)
+	card.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if card.EaseFactor < minEaseFactor {
+		card.EaseFactor = minEaseFactor
+	}
+
+	card.DueAt = review.At.AddDate(0, 0, card.IntervalDays)
+	card.Reviews = append(card.Reviews, review)
+}
+
+// load returns a user's state, reading it from disk on first use
+func (ps *PracticeService) load(username string) (*models.PracticeState, error) {
+	path, err := userStatePath(ps.dir, username)
+	if err != nil {
+		return nil, err
+	}
+	if state, exists := ps.states[username]; exists {
+		return state, nil
+	}
+
+	state := &models.PracticeState{Username: username}
+	if err := loadJSON(path, state); err != nil {
+		return nil, fmt.Errorf("failed to load practice schedule: %v", err)
+	}
+	if state.Cards == nil {
+		state.Cards = make(map[int]*models.PracticeCard)
+	}
+	if state.Attempts == nil {
+		state.Attempts = make(map[int]*models.PracticeAttempt)
+	}
+
+	ps.states[username] = state
+	return state, nil
+}
+
+// save writes a user's state to disk, replacing the previous file atomically
+func (ps *PracticeService) save(state *models.PracticeState) error {
+	if err := writeJSONAtomic(filepath.Join(ps.dir, state.Username+".json"), state); err != nil {
+		return fmt.Errorf("failed to save practice schedule: %v", err)
+	}
+	return nil
+}
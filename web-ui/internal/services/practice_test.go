@@ -0,0 +1,159 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+func TestReviewQuality(t *testing.T) {
+	expected := 30 * time.Minute
+
+	tests := []struct {
+		name       string
+		failedRuns int
+		solve      time.Duration
+		expected   time.Duration
+		want       int
+	}{
+		{"clean and quick", 0, 10 * time.Minute, expected, 5},
+		{"one failed run", 1, 10 * time.Minute, expected, 4},
+		{"three failed runs", 3, 10 * time.Minute, expected, 3},
+		{"six failed runs", 6, 10 * time.Minute, expected, 2},
+		{"slow", 0, 45 * time.Minute, expected, 4},
+		{"very slow", 0, 90 * time.Minute, expected, 3},
+		{"struggled", 10, 3 * time.Hour, expected, 0},
+		{"unknown difficulty", 0, 3 * time.Hour, 0, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reviewQuality(tt.failedRuns, tt.solve, tt.expected); got != tt.want {
+				t.Errorf("reviewQuality(%d, %v, %v) = %d, want %d", tt.failedRuns, tt.solve, tt.expected, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleReview(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	card := &models.PracticeCard{ChallengeID: 1, EaseFactor: initialEaseFactor}
+
+	// Each step reviews the card on the day it came due
+	tests := []struct {
+		quality         int
+		wantInterval    int
+		wantRepetitions int
+		wantEase        float64
+	}{
+		{5, 1, 1, 2.6},
+		{5, 6, 2, 2.7},
+		{4, 16, 3, 2.7},          // round(6 * 2.7)
+		{3, 43, 4, 2.56},         // round(16 * 2.7)
+		{2, 1, 0, 2.24},          // A failed review starts over
+		{0, 1, 0, 1.44},          // The ease factor keeps falling
+		{0, 1, 0, minEaseFactor}, // but not below the minimum
+		{4, 1, 1, minEaseFactor},
+	}
+
+	at := start
+	for i, tt := range tests {
+		scheduleReview(card, models.PracticeReview{At: at, Quality: tt.quality})
+		if card.IntervalDays != tt.wantInterval || card.Repetitions != tt.wantRepetitions || math.Abs(card.EaseFactor-tt.wantEase) > 1e-9 {
+			t.Fatalf("review %d (quality %d): interval %d, repetitions %d, ease %.2f, want %d, %d, %.2f",
+				i+1, tt.quality, card.IntervalDays, card.Repetitions, card.EaseFactor, tt.wantInterval, tt.wantRepetitions, tt.wantEase)
+		}
+		if want := at.AddDate(0, 0, tt.wantInterval); !card.DueAt.Equal(want) {
+			t.Fatalf("review %d: due %v, want %v", i+1, card.DueAt, want)
+		}
+		at = card.DueAt
+	}
+	if len(card.Reviews) != len(tests) {
+		t.Errorf("%d reviews recorded, want %d", len(card.Reviews), len(tests))
+	}
+}
+
+func TestPracticeSubmissionsSchedule(t *testing.T) {
+	dir := t.TempDir()
+	ps := NewPracticeService(dir)
+	challenge := &models.Challenge{ID: 7, Title: "Bank Account", Difficulty: "Intermediate"}
+	challenges := models.ChallengeMap{7: challenge, 8: {ID: 8, Title: "Sort", Difficulty: "Beginner"}}
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	// Two failed runs, then a pass after 20 minutes
+	ps.RecordRun("alice", 7, false, start)
+	ps.RecordRun("alice", 7, true, start.Add(5*time.Minute))
+	if _, err := ps.RecordSubmission("alice", challenge, false, start.Add(10*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	card, err := ps.RecordSubmission("alice", challenge, true, start.Add(20*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	review := card.Reviews[0]
+	if review.FailedRuns != 2 || review.SolveSeconds != 1200 || review.Quality != 4 || card.IntervalDays != 1 {
+		t.Errorf("review = %+v, card = %+v, want 2 failed runs in 20 minutes graded 4", review, card)
+	}
+
+	// Seeding leaves existing cards alone
+	if err := ps.Seed("alice", map[int]time.Time{7: start.AddDate(-1, 0, 0), 8: start}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want []int
+	}{
+		{"nothing due", start, nil},
+		// Both come due on the next day, ordered by time due
+		{"next day", start.AddDate(0, 0, 1).Add(-8 * time.Hour), []int{8, 7}},
+		{"overdue", start.AddDate(0, 0, 5), []int{8, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ps.Due("alice", challenges, tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, item := range items {
+				got = append(got, item.ChallengeID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("due = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The schedule is persisted per user
+	cards, err := NewPracticeService(dir).Cards("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 || cards[0].ChallengeID != 7 || len(cards[0].Reviews) != 1 || !cards[1].Reviews[0].Seeded {
+		t.Errorf("cards after reload = %+v", cards)
+	}
+	if _, err := ps.Cards("../alice"); err == nil {
+		t.Error("Cards accepted an invalid username")
+	}
+}
+
+func TestCurrentAttemptRestartsWhenIdle(t *testing.T) {
+	state := &models.PracticeState{Attempts: make(map[int]*models.PracticeAttempt)}
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	first := currentAttempt(state, 1, start)
+	first.FailedRuns++
+	if again := currentAttempt(state, 1, start.Add(time.Hour)); again != first {
+		t.Error("a run within the idle timeout started a new attempt")
+	}
+	later := currentAttempt(state, 1, start.Add(time.Hour+attemptIdleTimeout+time.Minute))
+	if later == first || later.FailedRuns != 0 || !later.StartedAt.Equal(start.Add(time.Hour+attemptIdleTimeout+time.Minute)) {
+		t.Errorf("attempt after a break = %+v, want a new one", later)
+	}
+}
@@ -0,0 +1,422 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+const (
+	// progressArchiveVersion is the archive layout written by WriteProgressArchive
+	progressArchiveVersion = 1
+	// maxSubmissionHistory bounds the history kept per user
+	maxSubmissionHistory = 1000
+)
+
+// ProgressService stores each user's submission history and hint reveals, and
+// reads and writes the archives used to move progress between machines
+type ProgressService struct {
+	mu     sync.Mutex
+	dir    string
+	states map[string]*models.ProgressState
+}
+
+// NewProgressService creates a progress service storing one JSON file per user in dir
+func NewProgressService(dir string) *ProgressService {
+	return &ProgressService{
+		dir:    dir,
+		states: make(map[string]*models.ProgressState),
+	}
+}
+
+// RecordSubmission appends a submission to the user's history
+func (ps *ProgressService) RecordSubmission(username string, record models.SubmissionRecord) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	state, err := ps.load(username)
+	if err != nil {
+		return err
+	}
+	state.History = append(state.History, record)
+	if len(state.History) > maxSubmissionHistory {
+		state.History = state.History[len(state.History)-maxSubmissionHistory:]
+	}
+	return ps.save(state)
+}
+
+// RevealHints records how many hints of a challenge the user has revealed, keeping the highest count
+func (ps *ProgressService) RevealHints(username, ref string, revealed int) (int, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	state, err := ps.load(username)
+	if err != nil {
+		return 0, err
+	}
+	if revealed <= state.Hints[ref] {
+		return state.Hints[ref], nil
+	}
+	state.Hints[ref] = revealed
+	return revealed, ps.save(state)
+}
+
+// State returns a copy of a user's history and hint reveals
+func (ps *ProgressService) State(username string) (*models.ProgressState, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	state, err := ps.load(username)
+	if err != nil {
+		return nil, err
+	}
+	copied := &models.ProgressState{
+		Username: state.Username,
+		History:  append([]models.SubmissionRecord{}, state.History...),
+		Hints:    make(map[string]int, len(state.Hints)),
+	}
+	for ref, revealed := range state.Hints {
+		copied.Hints[ref] = revealed
+	}
+	return copied, nil
+}
+
+// Histories returns the submission history of every user with saved progress, by username
+func (ps *ProgressService) Histories() (map[string][]models.SubmissionRecord, error) {
+	entries, err := os.ReadDir(ps.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read progress directory: %v", err)
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	histories := make(map[string][]models.SubmissionRecord)
+	for _, entry := range entries {
+		username, found := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !found || !submissionPathSegment.MatchString(username) {
+			continue
+		}
+		state, err := ps.load(username)
+		if err != nil {
+			return nil, err
+		}
+		histories[username] = append([]models.SubmissionRecord{}, state.History...)
+	}
+	return histories, nil
+}
+
+// FirstPasses returns the time of the earliest passing submission of each challenge in a history
+func FirstPasses(history []models.SubmissionRecord) map[string]time.Time {
+	passes := make(map[string]time.Time)
+	for _, record := range history {
+		if !record.Passed {
+			continue
+		}
+		if first, exists := passes[record.Ref]; !exists || record.SubmittedAt.Before(first) {
+			passes[record.Ref] = record.SubmittedAt
+		}
+	}
+	return passes
+}
+
+// Merge adds imported history entries the user does not have and raises hint counts,
+// returning how many of each changed. With dryRun set nothing is saved.
+func (ps *ProgressService) Merge(username string, history []models.SubmissionRecord, hints map[string]int, dryRun bool) (int, int, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	state, err := ps.load(username)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	seen := make(map[string]bool, len(state.History))
+	for _, record := range state.History {
+		seen[record.Ref+"@"+record.SubmittedAt.UTC().Format(time.RFC3339Nano)] = true
+	}
+	var added []models.SubmissionRecord
+	for _, record := range history {
+		key := record.Ref + "@" + record.SubmittedAt.UTC().Format(time.RFC3339Nano)
+		if !seen[key] {
+			seen[key] = true
+			added = append(added, record)
+		}
+	}
+
+	raised := make(map[string]int)
+	for ref, revealed := range hints {
+		if revealed > state.Hints[ref] {
+			raised[ref] = revealed
+		}
+	}
+
+	if dryRun || (len(added) == 0 && len(raised) == 0) {
+		return len(added), len(raised), nil
+	}
+
+	state.History = append(state.History, added...)
+	sort.SliceStable(state.History, func(i, j int) bool {
+		return state.History[i].SubmittedAt.Before(state.History[j].SubmittedAt)
+	})
+	if len(state.History) > maxSubmissionHistory {
+		state.History = state.History[len(state.History)-maxSubmissionHistory:]
+	}
+	for ref, revealed := range raised {
+		state.Hints[ref] = revealed
+	}
+	return len(added), len(raised), ps.save(state)
+}
+
+// UserSolutions returns the saved solutions of a user for every classic and package challenge, by ref
+func UserSolutions(username string, challenges models.ChallengeMap, packages models.PackageMap) (map[string]models.SubmissionFiles, error) {
+	if !ValidUsername(username) {
+		return nil, fmt.Errorf("invalid username %q", username)
+	}
+
+	refs := make([]string, 0, len(challenges))
+	for id := range challenges {
+		refs = append(refs, ClassicRef(id))
+	}
+	for name, pkg := range packages {
+		for _, challengeID := range pkg.LearningPath {
+			refs = append(refs, PackageRef(name, challengeID))
+		}
+	}
+
+	solutions := make(map[string]models.SubmissionFiles)
+	for _, ref := range refs {
+		challenge, _ := LocateChallenge(ref, challenges, packages)
+		files, err := ReadSolutionFiles(challenge.SubmissionDir(username))
+		if err != nil {
+			return nil, err
+		}
+		if len(files) > 0 {
+			solutions[ref] = files
+		}
+	}
+	return solutions, nil
+}
+
+// ReadSolutionFiles reads the submission files saved in dir, or none if it does not exist
+func ReadSolutionFiles(dir string) (models.SubmissionFiles, error) {
+	files := make(models.SubmissionFiles)
+	err := filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if validateSubmissionPath(rel) != nil {
+			return nil
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[rel] = string(content)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read solution in %s: %v", dir, err)
+	}
+	return files, nil
+}
+
+// DiffSolutionFiles returns the names of the files that differ between two solutions
+func DiffSolutionFiles(a, b models.SubmissionFiles) []string {
+	var names []string
+	for name, content := range a {
+		if other, exists := b[name]; !exists || other != content {
+			names = append(names, name)
+		}
+	}
+	for name := range b {
+		if _, exists := a[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteProgressArchive writes an export as a zip archive: manifest.json, history.json, hints.json,
+// achievements.json and each solution under solutions/<track>/<challenge>/
+func WriteProgressArchive(w io.Writer, export *models.ProgressExport) error {
+	archive := zip.NewWriter(w)
+	create := func(name string) (io.Writer, error) {
+		return archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: export.ExportedAt})
+	}
+
+	writeJSON := func(name string, value interface{}) error {
+		content, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		file, err := create(name)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(content)
+		return err
+	}
+
+	export.Version = progressArchiveVersion
+	if err := writeJSON("manifest.json", export); err != nil {
+		return err
+	}
+	if err := writeJSON("history.json", export.History); err != nil {
+		return err
+	}
+	if err := writeJSON("hints.json", export.Hints); err != nil {
+		return err
+	}
+	if export.Achievements != nil {
+		if err := writeJSON("achievements.json", export.Achievements); err != nil {
+			return err
+		}
+	}
+
+	refs := make([]string, 0, len(export.Solutions))
+	for ref := range export.Solutions {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		files := export.Solutions[ref]
+		for _, name := range files.Names() {
+			file, err := create(path.Join("solutions", ref, name))
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(file, files[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return archive.Close()
+}
+
+// ReadProgressArchive parses an archive written by WriteProgressArchive
+func ReadProgressArchive(data []byte) (*models.ProgressExport, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid progress archive: %v", err)
+	}
+
+	export := &models.ProgressExport{
+		Solutions: make(map[string]models.SubmissionFiles),
+		Hints:     make(map[string]int),
+	}
+	hasManifest := false
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if file.UncompressedSize64 > MaxSubmissionBytes {
+			return nil, fmt.Errorf("%s is too large", file.Name)
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(io.LimitReader(reader, MaxSubmissionBytes+1))
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file.Name, err)
+		}
+
+		var target interface{}
+		switch file.Name {
+		case "manifest.json":
+			hasManifest = true
+			target = export
+		case "history.json":
+			target = &export.History
+		case "hints.json":
+			target = &export.Hints
+		case "achievements.json":
+			export.Achievements = &models.AchievementState{}
+			target = export.Achievements
+		default:
+			parts := strings.SplitN(file.Name, "/", 4)
+			if len(parts) != 4 || parts[0] != "solutions" {
+				return nil, fmt.Errorf("unexpected file %s in progress archive", file.Name)
+			}
+			ref := parts[1] + "/" + parts[2]
+			if export.Solutions[ref] == nil {
+				export.Solutions[ref] = make(models.SubmissionFiles)
+			}
+			export.Solutions[ref][parts[3]] = string(content)
+			continue
+		}
+		if err := json.Unmarshal(content, target); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", file.Name, err)
+		}
+	}
+
+	if !hasManifest {
+		return nil, fmt.Errorf("progress archive has no manifest.json")
+	}
+	if export.Version != progressArchiveVersion {
+		return nil, fmt.Errorf("unsupported progress archive version %d", export.Version)
+	}
+	if export.Hints == nil {
+		export.Hints = make(map[string]int)
+	}
+	return export, nil
+}
+
+// load returns a user's state, reading it from disk on first use
+func (ps *ProgressService) load(username string) (*models.ProgressState, error) {
+	path, err := userStatePath(ps.dir, username)
+	if err != nil {
+		return nil, err
+	}
+	if state, exists := ps.states[username]; exists {
+		return state, nil
+	}
+
+	state := &models.ProgressState{Username: username}
+	if err := loadJSON(path, state); err != nil {
+		return nil, fmt.Errorf("failed to load progress: %v", err)
+	}
+	if state.History == nil {
+		state.History = []models.SubmissionRecord{}
+	}
+	if state.Hints == nil {
+		state.Hints = make(map[string]int)
+	}
+
+	ps.states[username] = state
+	return state, nil
+}
+
+// save writes a user's state to disk, replacing the previous file atomically
+func (ps *ProgressService) save(state *models.ProgressState) error {
+	if err := writeJSONAtomic(filepath.Join(ps.dir, state.Username+".json"), state); err != nil {
+		return fmt.Errorf("failed to save progress: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+func TestProgressArchiveRoundTrip(t *testing.T) {
+	exportedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	export := &models.ProgressExport{
+		Username:   "alice",
+		ExportedAt: exportedAt,
+		Solutions: map[string]models.SubmissionFiles{
+			"classic/1": {"solution-template.go": "package main"},
+			"gin/challenge-1-routing": {
+				"solution.go":        "package main",
+				"handlers/routes.go": "package handlers",
+			},
+		},
+		History:      []models.SubmissionRecord{{Ref: "classic/1", Passed: true, SubmittedAt: exportedAt.Add(-time.Hour)}},
+		Hints:        map[string]int{"classic/1": 2},
+		Achievements: &models.AchievementState{Username: "alice"},
+	}
+
+	var archive bytes.Buffer
+	if err := WriteProgressArchive(&archive, export); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadProgressArchive(archive.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Username != "alice" || got.Version != progressArchiveVersion || !got.ExportedAt.Equal(exportedAt) {
+		t.Errorf("manifest = %+v", got)
+	}
+	if !reflect.DeepEqual(got.Solutions, export.Solutions) || !reflect.DeepEqual(got.Hints, export.Hints) {
+		t.Errorf("solutions = %v, hints = %v", got.Solutions, got.Hints)
+	}
+	if len(got.History) != 1 || !got.History[0].SubmittedAt.Equal(export.History[0].SubmittedAt) || got.Achievements == nil {
+		t.Errorf("history = %+v, achievements = %+v", got.History, got.Achievements)
+	}
+}
+
+func TestReadProgressArchiveRejects(t *testing.T) {
+	archive := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		for name, content := range files {
+			f, err := w.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Write([]byte(content))
+		}
+		w.Close()
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"not a zip", []byte("progress"), "invalid progress archive"},
+		{"no manifest", archive(map[string]string{"hints.json": "{}"}), "no manifest.json"},
+		{"future version", archive(map[string]string{"manifest.json": `{"version": 2}`}), "unsupported progress archive version"},
+		{"unexpected file", archive(map[string]string{"manifest.json": `{"version": 1}`, "notes.txt": ""}), "unexpected file"},
+		{"corrupt history", archive(map[string]string{"manifest.json": `{"version": 1}`, "history.json": "{"}), "failed to parse history.json"},
+		{"too large", archive(map[string]string{"manifest.json": `{"version": 1}`, "solutions/classic/1/main.go": strings.Repeat("x", MaxSubmissionBytes+1)}), "too large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadProgressArchive(tt.data); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadProgressArchive() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProgressMerge(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	ps := NewProgressService(t.TempDir())
+	ps.RecordSubmission("bob", models.SubmissionRecord{Ref: "classic/1", Passed: false, SubmittedAt: at})
+	ps.RevealHints("bob", "classic/1", 2)
+
+	history := []models.SubmissionRecord{
+		{Ref: "classic/1", Passed: false, SubmittedAt: at}, // Already recorded
+		{Ref: "classic/1", Passed: true, SubmittedAt: at.Add(time.Hour)},
+		{Ref: "classic/2", Passed: true, SubmittedAt: at.Add(-time.Hour)},
+	}
+	hints := map[string]int{"classic/1": 1, "classic/2": 3}
+
+	tests := []struct {
+		name        string
+		dryRun      bool
+		wantHistory int
+		wantHints   int
+		wantSaved   int // History entries saved afterwards
+	}{
+		{"dry run", true, 2, 1, 1},
+		{"merge", false, 2, 1, 3},
+		{"merge again", false, 0, 0, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, raised, err := ps.Merge("bob", history, hints, tt.dryRun)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if added != tt.wantHistory || raised != tt.wantHints {
+				t.Errorf("Merge() = %d, %d, want %d, %d", added, raised, tt.wantHistory, tt.wantHints)
+			}
+			state, err := ps.State("bob")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(state.History) != tt.wantSaved {
+				t.Errorf("%d history entries saved, want %d", len(state.History), tt.wantSaved)
+			}
+		})
+	}
+
+	// Merged history is kept in submission order, and hint counts are never lowered
+	state, _ := ps.State("bob")
+	if state.History[0].Ref != "classic/2" || !state.History[2].Passed {
+		t.Errorf("history = %+v, want it sorted by time", state.History)
+	}
+	if want := map[string]int{"classic/1": 2, "classic/2": 3}; !reflect.DeepEqual(state.Hints, want) {
+		t.Errorf("hints = %v, want %v", state.Hints, want)
+	}
+}
+
+func TestProgressHistories(t *testing.T) {
+	dir := t.TempDir()
+	ps := NewProgressService(dir)
+	record := models.SubmissionRecord{Ref: "classic/1", Passed: true, SubmittedAt: time.Now()}
+	if err := ps.RecordSubmission("alice", record); err != nil {
+		t.Fatal(err)
+	}
+	// Files that are not user state are skipped
+	os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0644)
+	os.WriteFile(filepath.Join(dir, ".hidden.json"), []byte("{"), 0644)
+
+	histories, err := NewProgressService(dir).Histories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(histories) != 1 || len(histories["alice"]) != 1 || histories["alice"][0].Ref != "classic/1" {
+		t.Errorf("Histories() = %+v, want alice's one submission", histories)
+	}
+
+	if _, err := ps.RevealHints("../alice", "classic/1", 1); err == nil {
+		t.Error("RevealHints accepted an invalid username")
+	}
+}
+
+func TestDiffSolutionFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b models.SubmissionFiles
+		want []string
+	}{
+		{"identical", models.SubmissionFiles{"main.go": "a"}, models.SubmissionFiles{"main.go": "a"}, nil},
+		{"changed", models.SubmissionFiles{"main.go": "a"}, models.SubmissionFiles{"main.go": "b"}, []string{"main.go"}},
+		{"one side only", models.SubmissionFiles{"main.go": "a", "x.go": ""}, models.SubmissionFiles{"main.go": "a", "y.go": ""}, []string{"x.go", "y.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffSolutionFiles(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffSolutionFiles() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserSolutionsCoversBothTracks(t *testing.T) {
+	dir := t.TempDir()
+	classicDir := filepath.Join(dir, "challenge-1")
+	challenges := models.ChallengeMap{1: {ID: 1, Dir: classicDir}, 2: {ID: 2, Dir: filepath.Join(dir, "challenge-2")}}
+	packages := models.PackageMap{"gin": {LearningPath: []string{"challenge-1-routing"}}}
+
+	if err := WriteSubmissionFiles(filepath.Join(classicDir, "submissions", "alice"), models.SubmissionFiles{"solution-template.go": "package main"}); err != nil {
+		t.Fatal(err)
+	}
+	gin, _ := LocateChallenge(PackageRef("gin", "challenge-1-routing"), challenges, packages)
+	// Package challenges are found relative to the web-ui directory
+	wd, _ := os.Getwd()
+	webUI := filepath.Join(dir, "web-ui")
+	os.MkdirAll(webUI, 0755)
+	if err := os.Chdir(webUI); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := WriteSubmissionFiles(gin.SubmissionDir("alice"), models.SubmissionFiles{"solution.go": "package main"}); err != nil {
+		t.Fatal(err)
+	}
+
+	solutions, err := UserSolutions("alice", challenges, packages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]models.SubmissionFiles{
+		"classic/1":               {"solution-template.go": "package main"},
+		"gin/challenge-1-routing": {"solution.go": "package main"},
+	}
+	if !reflect.DeepEqual(solutions, want) {
+		t.Errorf("UserSolutions() = %v, want %v", solutions, want)
+	}
+	if _, err := UserSolutions("a/b", challenges, packages); err == nil {
+		t.Error("UserSolutions accepted an invalid username")
+	}
+}
+
+func TestFirstPasses(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	history := []models.SubmissionRecord{
+		{Ref: "classic/1", Passed: true, SubmittedAt: at.Add(time.Hour)},
+		{Ref: "classic/1", Passed: true, SubmittedAt: at}, // Imported histories need not be sorted
+		{Ref: "classic/1", Passed: false, SubmittedAt: at.Add(-time.Hour)},
+		{Ref: "classic/2", Passed: false, SubmittedAt: at},
+	}
+	if got, want := FirstPasses(history), map[string]time.Time{"classic/1": at}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FirstPasses() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"go/ast"
+	"math"
+	"regexp"
+	"strconv"
+
+	"web-ui/internal/models"
+)
+
+// Default quality targets
+const (
+	defaultQualityMaxComplexity    = 10
+	defaultQualityMaxFunctionLines = 40
+	defaultQualityMaxTestMs        = 1000
+)
+
+// defaultQualityWeights weighs the quality components of challenges that don't set their own
+var defaultQualityWeights = models.QualityWeights{
+	Complexity:     0.25,
+	FunctionLength: 0.2,
+	Findings:       0.25,
+	Allocations:    0.15,
+	Runtime:        0.15,
+}
+
+// testDurationLine matches the package summary of go test, e.g. "ok  	module	0.012s"
+var testDurationLine = regexp.MustCompile(`(?m)^ok\s+\S+\s+([\d.]+)s`)
+
+// scoreQuality scores a passing run on the complexity and length of its functions, its findings,
+// its allocations when benchmarked and its test runtime. It returns nil if nothing could be measured.
+func scoreQuality(files models.SubmissionFiles, policy *models.QualityPolicy, result *ExecutionResult) *models.QualityReport {
+	if policy == nil {
+		policy = &models.QualityPolicy{}
+	}
+	weights := defaultQualityWeights
+	if policy.Weights != nil {
+		weights = *policy.Weights
+	}
+
+	var components []models.QualityComponent
+	add := func(name string, value, target, weight float64, measured bool) {
+		component := models.QualityComponent{Name: name, Value: value, Target: target, Weight: weight, Measured: measured}
+		if measured {
+			component.Score = ratioScore(value, target)
+		}
+		components = append(components, component)
+	}
+
+	maxComplexity, maxLines, measured := functionShape(files)
+	add(models.QualityComplexity, float64(maxComplexity), targetOr(policy.MaxComplexity, defaultQualityMaxComplexity), weights.Complexity, measured)
+	add(models.QualityFunctionLength, float64(maxLines), targetOr(policy.MaxFunctionLines, defaultQualityMaxFunctionLines), weights.FunctionLength, measured)
+
+	findings := 0
+	for _, diagnostic := range result.Diagnostics {
+		if diagnostic.Severity != models.SeverityInfo {
+			findings++
+		}
+	}
+	add(models.QualityFindings, float64(findings), 0, weights.Findings, true)
+
+	allocs, baselineAllocs, benchmarked := benchmarkAllocations(result.Benchmark)
+	add(models.QualityAllocations, allocs, targetOr(policy.MaxAllocs, baselineAllocs), weights.Allocations, benchmarked)
+
+	add(models.QualityRuntime, testRuntimeMs(result), targetOr(policy.MaxTestMs, defaultQualityMaxTestMs), weights.Runtime, true)
+
+	// Weights are shares of the measured components, so a run without benchmarks is scored on the rest
+	total := 0.0
+	for _, component := range components {
+		if component.Measured && component.Weight > 0 {
+			total += component.Weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	report := &models.QualityReport{Components: components}
+	for i := range report.Components {
+		component := &report.Components[i]
+		if !component.Measured || component.Weight <= 0 {
+			component.Weight = 0
+			continue
+		}
+		weight := component.Weight / total
+		report.Score += component.Score * weight
+		component.Weight = roundTo(weight, 3)
+		component.Score = roundTo(component.Score, 1)
+	}
+	report.Score = roundTo(report.Score, 1)
+	return report
+}
+
+// ratioScore scores a lower-is-better value: 100 up to the target, then falling in proportion to the overshoot
+func ratioScore(value, target float64) float64 {
+	if value <= target {
+		return 100
+	}
+	return 100 * (target + 1) / (value + 1)
+}
+
+// functionShape returns the highest cyclomatic complexity and the longest function, in lines of code,
+// of a solution's non-test Go files. measured is false if the files don't parse or declare no functions.
+func functionShape(files models.SubmissionFiles) (maxComplexity, maxLines int, measured bool) {
+	parsed, err := parseSolution(files)
+	if err != nil {
+		return 0, 0, false
+	}
+	for _, file := range parsed {
+		for _, decl := range file.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			measured = true
+			maxComplexity = max(maxComplexity, cyclomaticComplexity(fn))
+			start, end := file.fset.Position(fn.Pos()).Offset, file.fset.Position(fn.End()).Offset
+			maxLines = max(maxLines, codeLines(file.source[start:end]))
+		}
+	}
+	return maxComplexity, maxLines, measured
+}
+
+// benchmarkAllocations returns the mean allocs/op of a bench run and of its baseline comparisons, if any
+func benchmarkAllocations(report *BenchmarkReport) (allocs, baseline float64, measured bool) {
+	if report == nil || len(report.Results) == 0 {
+		return 0, 0, false
+	}
+	for _, stats := range report.Results {
+		allocs += stats.AllocsPerOp
+	}
+	allocs /= float64(len(report.Results))
+	for _, comparison := range report.Comparisons {
+		baseline += comparison.BaselineAllocsPerOp
+	}
+	if len(report.Comparisons) > 0 {
+		baseline /= float64(len(report.Comparisons))
+	}
+	return allocs, baseline, true
+}
+
+// testRuntimeMs returns the test time go test reported for the run, or the whole run's time if it reported none
+func testRuntimeMs(result *ExecutionResult) float64 {
+	if match := testDurationLine.FindStringSubmatch(result.Output); match != nil {
+		if seconds, err := strconv.ParseFloat(match[1], 64); err == nil {
+			return roundTo(seconds*1000, 1)
+		}
+	}
+	return float64(result.ExecutionMs)
+}
+
+// targetOr returns a configured target, or the fallback if it is not set
+func targetOr(target, fallback float64) float64 {
+	if target > 0 {
+		return target
+	}
+	return fallback
+}
+
+// roundTo rounds a value to a number of decimals
+func roundTo(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
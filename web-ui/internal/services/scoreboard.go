@@ -5,34 +5,115 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"web-ui/internal/models"
 )
 
-// ScoreboardService handles scoreboard-related operations
+// ScoreboardService handles scoreboard-related operations. It is the one loader of the SCOREBOARD.md files:
+// the leaderboards read their completions from it, so entries it hides are hidden everywhere.
 type ScoreboardService struct {
-	scoreboards models.ScoreboardMap
+	mu            sync.RWMutex
+	scoreboards   map[string][]models.ScoreboardEntry // Challenge ref -> entries
+	passes        map[string][]string                 // Challenge ref -> users who passed every test
+	hidden        map[string]bool                     // "ref|username" of entries flagged as copies
+	hiddenVersion int                                 // Changes whenever an entry is hidden or shown
 }
 
 // NewScoreboardService creates a new scoreboard service
 func NewScoreboardService() *ScoreboardService {
 	return &ScoreboardService{
-		scoreboards: make(models.ScoreboardMap),
+		scoreboards: make(map[string][]models.ScoreboardEntry),
+		passes:      make(map[string][]string),
+		hidden:      make(map[string]bool),
 	}
 }
 
-// LoadScoreboards loads all scoreboards from the filesystem
-func (ss *ScoreboardService) LoadScoreboards(challenges models.ChallengeMap) error {
-	for id := range challenges {
-		challengeDir := filepath.Join("..", "challenge-"+strconv.Itoa(id))
-		ss.loadScoreboardForChallenge(id, challengeDir)
+// Hide hides or shows a user's entries on a challenge's scoreboard and every leaderboard
+func (ss *ScoreboardService) Hide(ref, username string, hidden bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if hidden == ss.hidden[ref+"|"+username] {
+		return
+	}
+	if hidden {
+		ss.hidden[ref+"|"+username] = true
+	} else {
+		delete(ss.hidden, ref+"|"+username)
+	}
+	ss.hiddenVersion++
+}
+
+// HiddenVersion changes whenever an entry is hidden or shown, so rankings built from the scoreboards know to rebuild
+func (ss *ScoreboardService) HiddenVersion() int {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.hiddenVersion
+}
+
+// FullPasses returns, by challenge ref, the users who passed every test, without hidden entries.
+// It is the source of scoreboard completions for every leaderboard.
+func (ss *ScoreboardService) FullPasses() map[string][]string {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	passes := make(map[string][]string, len(ss.passes))
+	for ref, usernames := range ss.passes {
+		for _, username := range usernames {
+			if !ss.hidden[ref+"|"+username] {
+				passes[ref] = append(passes[ref], username)
+			}
+		}
+	}
+	return passes
+}
+
+// IsHidden reports whether a user's entries on a challenge's scoreboard are hidden
+func (ss *ScoreboardService) IsHidden(ref, username string) bool {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.hidden[ref+"|"+username]
+}
+
+// HasPassed reports whether a user passed every test of a challenge on its scoreboard, hidden or not.
+// Scoreboards also list failing submissions, so being on one is not enough.
+func (ss *ScoreboardService) HasPassed(ref, username string) bool {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return containsString(ss.passes[ref], username)
+}
+
+// visible returns the entries of a scoreboard that are not hidden
+func (ss *ScoreboardService) visible(ref string, entries []models.ScoreboardEntry) []models.ScoreboardEntry {
+	if len(ss.hidden) == 0 {
+		return entries
+	}
+	shown := make([]models.ScoreboardEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !ss.hidden[ref+"|"+entry.Username] {
+			shown = append(shown, entry)
+		}
+	}
+	return shown
+}
+
+// LoadScoreboards loads the classic and package scoreboards from the filesystem
+func (ss *ScoreboardService) LoadScoreboards(challenges models.ChallengeMap, packages models.PackageMap) error {
+	for id, challenge := range challenges {
+		ss.loadScoreboardForChallenge(ClassicRef(id), id, ClassicChallenge(challenge).Dir)
+	}
+	for name, pkg := range packages {
+		for _, challengeID := range pkg.LearningPath {
+			if challenge, ok := LocateChallenge(PackageRef(name, challengeID), challenges, packages); ok {
+				ss.loadScoreboardForChallenge(challenge.Ref, 0, challenge.Dir)
+			}
+		}
 	}
 	return nil
 }
 
 // loadScoreboardForChallenge loads the scoreboard for a specific challenge
-func (ss *ScoreboardService) loadScoreboardForChallenge(id int, dir string) {
+func (ss *ScoreboardService) loadScoreboardForChallenge(ref string, id int, dir string) {
 	scoreboardPath := filepath.Join(dir, "SCOREBOARD.md")
 	scoreboardContent, err := ioutil.ReadFile(scoreboardPath)
 	if err != nil {
@@ -40,12 +121,16 @@ func (ss *ScoreboardService) loadScoreboardForChallenge(id int, dir string) {
 	}
 
 	// Parse scoreboard markdown table
-	entries := ss.parseScoreboardMarkdown(string(scoreboardContent), id)
-	ss.scoreboards[id] = entries
+	entries := ss.parseScoreboardMarkdown(string(scoreboardContent), ref, id)
+	passes := parseFullPasses(string(scoreboardContent))
+	ss.mu.Lock()
+	ss.scoreboards[ref] = entries
+	ss.passes[ref] = passes
+	ss.mu.Unlock()
 }
 
 // parseScoreboardMarkdown parses the scoreboard markdown table
-func (ss *ScoreboardService) parseScoreboardMarkdown(content string, challengeID int) []models.ScoreboardEntry {
+func (ss *ScoreboardService) parseScoreboardMarkdown(content string, ref string, challengeID int) []models.ScoreboardEntry {
 	lines := strings.Split(content, "\n")
 	entries := []models.ScoreboardEntry{}
 
@@ -102,6 +187,7 @@ func (ss *ScoreboardService) parseScoreboardMarkdown(content string, challengeID
 		// Use current time for existing entries
 		entry := models.ScoreboardEntry{
 			Username:    username,
+			Ref:         ref,
 			ChallengeID: challengeID,
 			SubmittedAt: time.Now(),
 		}
@@ -122,29 +208,72 @@ func (ss *ScoreboardService) isNumeric(s string) bool {
 	return true
 }
 
-// GetScoreboard returns the scoreboard for a specific challenge
+// GetScoreboard returns the scoreboard for a classic challenge
 func (ss *ScoreboardService) GetScoreboard(challengeID int) ([]models.ScoreboardEntry, bool) {
-	scoreboard, exists := ss.scoreboards[challengeID]
-	return scoreboard, exists
+	return ss.Scoreboard(ClassicRef(challengeID))
 }
 
-// GetAllScoreboards returns all scoreboards
+// Scoreboard returns the scoreboard for a challenge ref of either track, without hidden entries
+func (ss *ScoreboardService) Scoreboard(ref string) ([]models.ScoreboardEntry, bool) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	scoreboard, exists := ss.scoreboards[ref]
+	return ss.visible(ref, scoreboard), exists
+}
+
+// GetAllScoreboards returns the classic scoreboards by challenge ID, without hidden entries
 func (ss *ScoreboardService) GetAllScoreboards() models.ScoreboardMap {
-	return ss.scoreboards
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	scoreboards := make(models.ScoreboardMap)
+	for ref, entries := range ss.scoreboards {
+		if len(entries) > 0 && entries[0].ChallengeID != 0 {
+			scoreboards[entries[0].ChallengeID] = ss.visible(ref, entries)
+		}
+	}
+	return scoreboards
 }
 
-// AddSubmission adds a submission to the scoreboard
-func (ss *ScoreboardService) AddSubmission(submission models.Submission) {
+// Record adds a passing submission to a challenge's scoreboard.
+// Only runs that included the challenge's hidden tests should be recorded.
+func (ss *ScoreboardService) Record(challenge *models.TrackChallenge, username string, at time.Time) {
 	entry := models.ScoreboardEntry{
-		Username:    submission.Username,
-		ChallengeID: submission.ChallengeID,
-		SubmittedAt: submission.SubmittedAt,
+		Username:    username,
+		Ref:         challenge.Ref,
+		SubmittedAt: at,
 	}
-
-	// Add to the scoreboard for this challenge
-	if ss.scoreboards[submission.ChallengeID] == nil {
-		ss.scoreboards[submission.ChallengeID] = []models.ScoreboardEntry{}
+	if challenge.Classic != nil {
+		entry.ChallengeID = challenge.Classic.ID
+	}
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.scoreboards[challenge.Ref] = append(ss.scoreboards[challenge.Ref], entry)
+	if !containsString(ss.passes[challenge.Ref], username) {
+		ss.passes[challenge.Ref] = append(ss.passes[challenge.Ref], username)
 	}
+}
 
-	ss.scoreboards[submission.ChallengeID] = append(ss.scoreboards[submission.ChallengeID], entry)
+// parseFullPasses returns the users in a SCOREBOARD.md table who passed every test
+func parseFullPasses(content string) []string {
+	var usernames []string
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.Contains(line, "|") || strings.Contains(line, "Username") || strings.Contains(line, "---") {
+			continue
+		}
+		parts := strings.Split(line, "|")
+		if len(parts) < 4 {
+			continue
+		}
+
+		username := strings.TrimSpace(parts[1])
+		if !submissionPathSegment.MatchString(username) {
+			continue
+		}
+		passed, err1 := strconv.Atoi(strings.TrimSpace(parts[2]))
+		total, err2 := strconv.Atoi(strings.TrimSpace(parts[3]))
+		if err1 == nil && err2 == nil && passed > 0 && passed == total {
+			usernames = append(usernames, username)
+		}
+	}
+	return usernames
 }
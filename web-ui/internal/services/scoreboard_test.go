@@ -0,0 +1,53 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFullPasses(t *testing.T) {
+	content := `# Scoreboard for challenge-1
+| Username   | Passed Tests | Total Tests |
+|------------|--------------|-------------|
+| alice | 7 | 7 |
+| bob | 6 | 7 |
+| carol | 0 | 0 |
+| ../eve | 7 | 7 |
+| dave | seven | 7 |
+| erin | 3 | 3 |
+`
+	if got, want := parseFullPasses(content), []string{"alice", "erin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseFullPasses() = %v, want %v", got, want)
+	}
+}
+
+func TestFullPassesHidesEntries(t *testing.T) {
+	ss := NewScoreboardService()
+	ss.passes["classic/1"] = []string{"alice", "bob"}
+	ss.passes["classic/2"] = []string{"bob"}
+
+	tests := []struct {
+		name        string
+		ref, user   string
+		hidden      bool
+		want        map[string][]string
+		wantVersion int
+	}{
+		{"hide", "classic/1", "bob", true, map[string][]string{"classic/1": {"alice"}, "classic/2": {"bob"}}, 1},
+		{"hide again", "classic/1", "bob", true, map[string][]string{"classic/1": {"alice"}, "classic/2": {"bob"}}, 1},
+		{"hide last pass", "classic/2", "bob", true, map[string][]string{"classic/1": {"alice"}}, 2},
+		{"show", "classic/1", "bob", false, map[string][]string{"classic/1": {"alice", "bob"}}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss.Hide(tt.ref, tt.user, tt.hidden)
+			if got := ss.FullPasses(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FullPasses() = %v, want %v", got, tt.want)
+			}
+			if got := ss.HiddenVersion(); got != tt.wantVersion {
+				t.Errorf("HiddenVersion() = %d, want %d", got, tt.wantVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/scanner"
+	"go/token"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"web-ui/internal/models"
+)
+
+// Similarity analysis defaults
+const (
+	DefaultSimilarityThreshold = 0.8 // Pairs at or above this score are clustered
+	similarityKGram            = 12  // Tokens per fingerprinted k-gram
+	similarityWindow           = 8   // Winnowing window, in k-grams
+	similarityMinFingerprints  = 10  // Submissions with fewer fingerprints beyond the template are too short to compare
+)
+
+// SimilarityService compares the submissions of a challenge and keeps the admin flags that hide
+// suspected copies from the scoreboards
+type SimilarityService struct {
+	dir   string
+	mu    sync.Mutex
+	flags []models.SimilarityFlag
+}
+
+// NewSimilarityService creates a similarity service that keeps flags in dir
+func NewSimilarityService(dir string) *SimilarityService {
+	return &SimilarityService{dir: dir}
+}
+
+// fingerprint is the winnowed hashes of one submission
+type fingerprint struct {
+	username string
+	hashes   map[uint64]bool
+}
+
+// Analyze fingerprints every submission of a challenge and reports the pairs and clusters at or above threshold.
+// Fingerprints that also occur in the template are ignored, so shared boilerplate doesn't count as copying.
+func (s *SimilarityService) Analyze(challenge *models.TrackChallenge, threshold float64) (*models.SimilarityReport, error) {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultSimilarityThreshold
+	}
+
+	report := &models.SimilarityReport{
+		Ref:       challenge.Ref,
+		Threshold: threshold,
+		Pairs:     []models.SimilarityPair{},
+		Clusters:  []models.SimilarityCluster{},
+		Skipped:   []string{},
+		TooShort:  []string{},
+	}
+
+	template := map[uint64]bool{}
+	if tokens, err := normalizeGoSource(challenge.Template); err == nil {
+		template = winnow(tokens)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(challenge.Dir, "submissions"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return report, nil
+		}
+		return nil, fmt.Errorf("failed to read submissions: %v", err)
+	}
+
+	var prints []fingerprint
+	for _, entry := range entries {
+		if !entry.IsDir() || !submissionPathSegment.MatchString(entry.Name()) {
+			continue
+		}
+		tokens, err := normalizeSubmission(challenge.SubmissionDir(entry.Name()))
+		if err != nil || len(tokens) == 0 {
+			report.Skipped = append(report.Skipped, entry.Name())
+			continue
+		}
+		hashes := winnow(tokens)
+		for hash := range template {
+			delete(hashes, hash)
+		}
+		if len(hashes) < similarityMinFingerprints {
+			report.TooShort = append(report.TooShort, entry.Name())
+			continue
+		}
+		prints = append(prints, fingerprint{username: entry.Name(), hashes: hashes})
+	}
+	report.Submissions = len(prints)
+
+	// Compare every pair, and cluster the similar ones with union-find
+	parent := make([]int, len(prints))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := 0; i < len(prints); i++ {
+		for j := i + 1; j < len(prints); j++ {
+			score := jaccard(prints[i].hashes, prints[j].hashes)
+			if score < threshold {
+				continue
+			}
+			report.Pairs = append(report.Pairs, models.SimilarityPair{
+				Users: [2]string{prints[i].username, prints[j].username},
+				Score: roundScore(score),
+			})
+			parent[find(i)] = find(j)
+		}
+	}
+	sort.Slice(report.Pairs, func(i, j int) bool {
+		if report.Pairs[i].Score != report.Pairs[j].Score {
+			return report.Pairs[i].Score > report.Pairs[j].Score
+		}
+		return report.Pairs[i].Users[0]+"/"+report.Pairs[i].Users[1] < report.Pairs[j].Users[0]+"/"+report.Pairs[j].Users[1]
+	})
+
+	members := map[int][]string{}
+	for i, print := range prints {
+		members[find(i)] = append(members[find(i)], print.username)
+	}
+	for _, users := range members {
+		if len(users) < 2 {
+			continue
+		}
+		sort.Strings(users)
+		cluster := models.SimilarityCluster{Users: users}
+		inCluster := map[string]bool{}
+		for _, user := range users {
+			inCluster[user] = true
+		}
+		for _, pair := range report.Pairs {
+			if inCluster[pair.Users[0]] && pair.Score > cluster.MaxScore {
+				cluster.MaxScore = pair.Score
+			}
+		}
+		report.Clusters = append(report.Clusters, cluster)
+	}
+	sort.Slice(report.Clusters, func(i, j int) bool {
+		if report.Clusters[i].MaxScore != report.Clusters[j].MaxScore {
+			return report.Clusters[i].MaxScore > report.Clusters[j].MaxScore
+		}
+		return report.Clusters[i].Users[0] < report.Clusters[j].Users[0]
+	})
+
+	return report, nil
+}
+
+// normalizeSubmission normalizes the non-test Go files of a submission directory in name order
+func normalizeSubmission(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var tokens []string
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fileTokens, err := normalizeGoSource(string(content))
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, fileTokens...)
+	}
+	return tokens, nil
+}
+
+// normalizeGoSource parses Go source without comments, renames every declared identifier to the same name
+// and returns the token stream of the reprinted file, so naming, comments, formatting and declaration order don't matter
+func normalizeGoSource(source string) ([]string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "solution.go", source, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	// Package names used in selectors keep their names, as do predeclared identifiers
+	imported := map[string]bool{}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imported[name] = true
+	}
+
+	keep := map[*ast.Ident]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.File:
+			keep[node.Name] = true
+		case *ast.SelectorExpr:
+			if x, ok := node.X.(*ast.Ident); ok && imported[x.Name] {
+				keep[x] = true
+				keep[node.Sel] = true
+			}
+		case *ast.Ident:
+			if !keep[node] && !predeclared[node.Name] && node.Name != "_" {
+				node.Name = "v"
+			}
+		}
+		return true
+	})
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, file); err != nil {
+		return nil, err
+	}
+
+	var s scanner.Scanner
+	printed := buf.Bytes()
+	s.Init(fset.AddFile("normalized.go", -1, len(printed)), printed, nil, 0)
+	var tokens []string
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		switch {
+		case tok == token.SEMICOLON && lit == "\n":
+			continue // Automatically inserted
+		case tok == token.IDENT || tok.IsLiteral():
+			tokens = append(tokens, lit)
+		default:
+			tokens = append(tokens, tok.String())
+		}
+	}
+	return tokens, nil
+}
+
+// predeclared are Go's predeclared identifiers, which keep their names when normalizing
+var predeclared = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true, "complex64": true, "complex128": true,
+	"error": true, "float32": true, "float64": true, "int": true, "int8": true, "int16": true, "int32": true,
+	"int64": true, "rune": true, "string": true, "uint": true, "uint8": true, "uint16": true, "uint32": true,
+	"uint64": true, "uintptr": true, "true": true, "false": true, "iota": true, "nil": true, "append": true,
+	"cap": true, "clear": true, "close": true, "complex": true, "copy": true, "delete": true, "imag": true,
+	"len": true, "make": true, "max": true, "min": true, "new": true, "panic": true, "print": true,
+	"println": true, "real": true, "recover": true, "main": true,
+}
+
+// winnow hashes every k-gram of tokens and keeps the minimum hash of each window
+func winnow(tokens []string) map[uint64]bool {
+	hashes := map[uint64]bool{}
+	if len(tokens) < similarityKGram {
+		if len(tokens) > 0 {
+			hashes[hashTokens(tokens)] = true
+		}
+		return hashes
+	}
+
+	grams := make([]uint64, 0, len(tokens)-similarityKGram+1)
+	for i := 0; i+similarityKGram <= len(tokens); i++ {
+		grams = append(grams, hashTokens(tokens[i:i+similarityKGram]))
+	}
+	if len(grams) <= similarityWindow {
+		for _, hash := range grams {
+			hashes[hash] = true
+		}
+		return hashes
+	}
+
+	for i := 0; i+similarityWindow <= len(grams); i++ {
+		min := grams[i]
+		for _, hash := range grams[i+1 : i+similarityWindow] {
+			if hash < min {
+				min = hash
+			}
+		}
+		hashes[min] = true
+	}
+	return hashes
+}
+
+// hashTokens hashes a k-gram of tokens
+func hashTokens(tokens []string) uint64 {
+	h := fnv.New64a()
+	for _, tok := range tokens {
+		h.Write([]byte(tok))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// jaccard returns the Jaccard similarity of two fingerprint sets, 0 if either is empty
+func jaccard(a, b map[uint64]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for hash := range a {
+		if b[hash] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// roundScore rounds a similarity score to three decimals for reports
+func roundScore(score float64) float64 {
+	return float64(int(score*1000+0.5)) / 1000
+}
+
+// LoadFlags reads the saved flags
+func (s *SimilarityService) LoadFlags() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := loadJSON(filepath.Join(s.dir, "flags.json"), &s.flags); err != nil {
+		return fmt.Errorf("failed to load similarity flags: %v", err)
+	}
+	return nil
+}
+
+// HideFlagged hides every flagged entry from the scoreboards
+func (s *SimilarityService) HideFlagged(scoreboardService *ScoreboardService) {
+	for _, flag := range s.Flags() {
+		scoreboardService.Hide(flag.Ref, flag.Username, true)
+	}
+}
+
+// Flags returns the flagged entries, newest first
+func (s *SimilarityService) Flags() []models.SimilarityFlag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flags := append([]models.SimilarityFlag{}, s.flags...)
+	sort.SliceStable(flags, func(i, j int) bool { return flags[i].FlaggedAt.After(flags[j].FlaggedAt) })
+	return flags
+}
+
+// Flag marks a user's entry for a challenge as a suspected copy, replacing any earlier flag for it
+func (s *SimilarityService) Flag(ref, username, reason string, at time.Time) (models.SimilarityFlag, error) {
+	if !ValidUsername(username) {
+		return models.SimilarityFlag{}, fmt.Errorf("invalid username %q", username)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flag := models.SimilarityFlag{Ref: ref, Username: username, Reason: reason, FlaggedAt: at}
+	flags := []models.SimilarityFlag{flag}
+	for _, existing := range s.flags {
+		if existing.Ref != ref || existing.Username != username {
+			flags = append(flags, existing)
+		}
+	}
+	if err := s.save(flags); err != nil {
+		return models.SimilarityFlag{}, err
+	}
+	s.flags = flags
+	return flag, nil
+}
+
+// Unflag removes the flag on a user's entry, reporting whether there was one
+func (s *SimilarityService) Unflag(ref, username string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flags := []models.SimilarityFlag{}
+	for _, existing := range s.flags {
+		if existing.Ref != ref || existing.Username != username {
+			flags = append(flags, existing)
+		}
+	}
+	if len(flags) == len(s.flags) {
+		return false, nil
+	}
+	if err := s.save(flags); err != nil {
+		return false, err
+	}
+	s.flags = flags
+	return true, nil
+}
+
+// save writes the flags to disk, replacing the previous file atomically
+func (s *SimilarityService) save(flags []models.SimilarityFlag) error {
+	if err := writeJSONAtomic(filepath.Join(s.dir, "flags.json"), flags); err != nil {
+		return fmt.Errorf("failed to save similarity flags: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"web-ui/internal/models"
+)
+
+const (
+	// MaxSubmissionFiles is the maximum number of files accepted in one submission
+	MaxSubmissionFiles = 32
+	// MaxSubmissionBytes is the maximum combined size of all submitted files
+	MaxSubmissionBytes = 1 << 20
+)
+
+// allowedSubmissionExtensions lists the file types a submission may contain
+var allowedSubmissionExtensions = map[string]bool{
+	".go":    true,
+	".proto": true,
+	".sql":   true,
+	".json":  true,
+	".txt":   true,
+}
+
+// submissionPathSegment matches a single safe path segment
+var submissionPathSegment = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+
+// ValidUsername reports whether a username is safe to use as a directory and file name
+func ValidUsername(username string) bool {
+	return submissionPathSegment.MatchString(username)
+}
+
+// ValidateSubmissionFiles checks that every file name in a submission is allowed
+func ValidateSubmissionFiles(files models.SubmissionFiles) error {
+	if len(files) == 0 {
+		return fmt.Errorf("submission contains no files")
+	}
+	if len(files) > MaxSubmissionFiles {
+		return fmt.Errorf("submission contains %d files, at most %d are allowed", len(files), MaxSubmissionFiles)
+	}
+
+	totalSize := 0
+	hasGoFile := false
+	for name, content := range files {
+		if err := validateSubmissionPath(name); err != nil {
+			return err
+		}
+		if strings.HasSuffix(name, ".go") {
+			hasGoFile = true
+		}
+		totalSize += len(content)
+	}
+
+	if !hasGoFile {
+		return fmt.Errorf("submission must contain at least one .go file")
+	}
+	if totalSize > MaxSubmissionBytes {
+		return fmt.Errorf("submission is %d bytes, at most %d are allowed", totalSize, MaxSubmissionBytes)
+	}
+	return nil
+}
+
+// validateSubmissionPath checks a single relative file path from a submission
+func validateSubmissionPath(name string) error {
+	if name == "" || strings.Contains(name, `\`) || path.IsAbs(name) || path.Clean(name) != name {
+		return fmt.Errorf("invalid file name %q", name)
+	}
+
+	for _, segment := range strings.Split(name, "/") {
+		if !submissionPathSegment.MatchString(segment) {
+			return fmt.Errorf("invalid file name %q", name)
+		}
+	}
+
+	if top, _, inDir := strings.Cut(name, "/"); inDir && strings.EqualFold(top, referenceDirName) {
+		// Differential runs copy the hidden reference solution into this directory
+		return fmt.Errorf("files under %s/ cannot be submitted: %q", referenceDirName, name)
+	}
+	if strings.HasSuffix(name, "_test.go") {
+		return fmt.Errorf("test files cannot be submitted: %q", name)
+	}
+	if !allowedSubmissionExtensions[path.Ext(name)] {
+		return fmt.Errorf("file type not allowed: %q", name)
+	}
+	return nil
+}
+
+// ExtractSubmissionArchive reads a .zip, .tar or .tar.gz upload into a set of files.
+// A single top-level directory shared by every entry is stripped.
+func ExtractSubmissionArchive(filename string, data []byte) (models.SubmissionFiles, error) {
+	var files models.SubmissionFiles
+	var err error
+
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		files, err = extractZip(data)
+	case strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz"):
+		gz, gzErr := gzip.NewReader(bytes.NewReader(data))
+		if gzErr != nil {
+			return nil, fmt.Errorf("invalid gzip archive: %v", gzErr)
+		}
+		files, err = extractTar(gz)
+	case strings.HasSuffix(lower, ".tar"):
+		files, err = extractTar(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unsupported archive type %q, use .zip, .tar or .tar.gz", filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return stripCommonDir(files), nil
+}
+
+// extractZip reads the regular files of a zip archive
+func extractZip(data []byte) (models.SubmissionFiles, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %v", err)
+	}
+
+	files := make(models.SubmissionFiles)
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if len(files) >= MaxSubmissionFiles {
+			return nil, fmt.Errorf("archive contains more than %d files", MaxSubmissionFiles)
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, MaxSubmissionBytes+1))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", f.Name, err)
+		}
+		files[strings.TrimPrefix(f.Name, "./")] = string(content)
+	}
+	return files, nil
+}
+
+// extractTar reads the regular files of a tar stream
+func extractTar(r io.Reader) (models.SubmissionFiles, error) {
+	reader := tar.NewReader(r)
+	files := make(models.SubmissionFiles)
+
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid tar archive: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if len(files) >= MaxSubmissionFiles {
+			return nil, fmt.Errorf("archive contains more than %d files", MaxSubmissionFiles)
+		}
+
+		content, err := io.ReadAll(io.LimitReader(reader, MaxSubmissionBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", header.Name, err)
+		}
+		files[strings.TrimPrefix(header.Name, "./")] = string(content)
+	}
+	return files, nil
+}
+
+// stripCommonDir removes a top-level directory shared by every file
+func stripCommonDir(files models.SubmissionFiles) models.SubmissionFiles {
+	prefix := ""
+	for name := range files {
+		dir, _, found := strings.Cut(name, "/")
+		if !found || (prefix != "" && dir != prefix) {
+			return files
+		}
+		prefix = dir
+	}
+	if prefix == "" {
+		return files
+	}
+
+	stripped := make(models.SubmissionFiles, len(files))
+	for name, content := range files {
+		stripped[strings.TrimPrefix(name, prefix+"/")] = content
+	}
+	return stripped
+}
+
+// WriteSubmissionFiles writes a validated set of files below dir
+func WriteSubmissionFiles(dir string, files models.SubmissionFiles) error {
+	for _, name := range files.Names() {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, []byte(files[name]), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplaceSubmissionFiles replaces the contents of dir with a validated set of files,
+// so no file of the previous submission is left behind
+func ReplaceSubmissionFiles(dir string, files models.SubmissionFiles) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	return WriteSubmissionFiles(dir, files)
+}
+
+// copySupportFiles copies the fixture and support files a challenge declares into the run directory.
+// Patterns are relative to the challenge directory and may use filepath.Match globs.
+func copySupportFiles(challengeDir string, patterns []string, runDir string, files models.SubmissionFiles) error {
+	if challengeDir == "" || len(patterns) == 0 {
+		return nil
+	}
+
+	var matches []string
+	for _, pattern := range patterns {
+		if filepath.IsAbs(pattern) || strings.Contains(filepath.Clean(pattern), "..") {
+			return fmt.Errorf("invalid support file pattern %q", pattern)
+		}
+		found, err := filepath.Glob(filepath.Join(challengeDir, pattern))
+		if err != nil {
+			return fmt.Errorf("invalid support file pattern %q: %v", pattern, err)
+		}
+		if len(found) == 0 {
+			return fmt.Errorf("support file %q not found", pattern)
+		}
+		matches = append(matches, found...)
+	}
+	sort.Strings(matches)
+
+	for _, source := range matches {
+		rel, err := filepath.Rel(challengeDir, source)
+		if err != nil {
+			return err
+		}
+		if rel == referenceDirName || strings.HasPrefix(filepath.ToSlash(rel), referenceDirName+"/") {
+			// The reference solution stays hidden from submissions
+			return fmt.Errorf("support file pattern %q matches the reference solution", filepath.ToSlash(rel))
+		}
+		if _, exists := files[filepath.ToSlash(rel)]; exists {
+			return fmt.Errorf("submitted file %q conflicts with a challenge support file", filepath.ToSlash(rel))
+		}
+		if err := copyPath(source, filepath.Join(runDir, rel)); err != nil {
+			return fmt.Errorf("failed to copy support file %s: %v", rel, err)
+		}
+	}
+	return nil
+}
+
+// copyPath copies a file or directory tree
+func copyPath(source, target string) error {
+	return filepath.Walk(source, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, p)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(target, rel)
+		if info.IsDir() {
+			return os.MkdirAll(dest, 0755)
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(dest, content, 0644)
+	})
+}
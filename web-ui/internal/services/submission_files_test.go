@@ -0,0 +1,226 @@
+package services
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"web-ui/internal/models"
+)
+
+func TestValidateSubmissionFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   models.SubmissionFiles
+		wantErr string
+	}{
+		{"single file", models.SubmissionFiles{"solution-template.go": "package main"}, ""},
+		{"layered", models.SubmissionFiles{"main.go": "", "repository/user.go": "", "api.proto": "", "schema.sql": ""}, ""},
+		{"empty", models.SubmissionFiles{}, "no files"},
+		{"no go file", models.SubmissionFiles{"notes.txt": ""}, "at least one .go file"},
+		{"test file", models.SubmissionFiles{"main.go": "", "main_test.go": ""}, "test files cannot be submitted"},
+		{"extension", models.SubmissionFiles{"main.go": "", "run.sh": ""}, "file type not allowed"},
+		{"parent dir", models.SubmissionFiles{"../main.go": ""}, "invalid file name"},
+		{"absolute", models.SubmissionFiles{"/tmp/main.go": ""}, "invalid file name"},
+		{"unclean", models.SubmissionFiles{"a//main.go": ""}, "invalid file name"},
+		{"backslash", models.SubmissionFiles{`a\main.go`: ""}, "invalid file name"},
+		{"hidden", models.SubmissionFiles{".main.go": ""}, "invalid file name"},
+		{"reference file name", models.SubmissionFiles{"reference.go": "", "service/reference/types.go": ""}, ""},
+		{"reference dir", models.SubmissionFiles{"main.go": "", "reference/init.go": ""}, "files under reference/ cannot be submitted"},
+		{"reference dir case", models.SubmissionFiles{"main.go": "", "Reference/init.go": ""}, "files under reference/ cannot be submitted"},
+		{"too large", models.SubmissionFiles{"main.go": strings.Repeat("x", MaxSubmissionBytes+1)}, "bytes, at most"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSubmissionFiles(tt.files)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateSubmissionFiles() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateSubmissionFiles() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	tooMany := make(models.SubmissionFiles)
+	for i := 0; i <= MaxSubmissionFiles; i++ {
+		tooMany[strings.Repeat("a", i+1)+".go"] = ""
+	}
+	if err := ValidateSubmissionFiles(tooMany); err == nil {
+		t.Errorf("ValidateSubmissionFiles accepted %d files", len(tooMany))
+	}
+}
+
+func TestExtractSubmissionArchive(t *testing.T) {
+	files := map[string]string{
+		"project/main.go":            "package main",
+		"project/service/service.go": "package service",
+	}
+	want := models.SubmissionFiles{"main.go": "package main", "service/service.go": "package service"}
+
+	var zipped bytes.Buffer
+	zw := zip.NewWriter(&zipped)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+
+	var tarred bytes.Buffer
+	tw := tar.NewWriter(&tarred)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	gw.Write(tarred.Bytes())
+	gw.Close()
+
+	tests := []struct {
+		filename string
+		data     []byte
+		wantErr  bool
+	}{
+		{"solution.zip", zipped.Bytes(), false},
+		{"solution.tar", tarred.Bytes(), false},
+		{"solution.tar.gz", gzipped.Bytes(), false},
+		{"SOLUTION.TGZ", gzipped.Bytes(), false},
+		{"solution.rar", zipped.Bytes(), true},
+		{"solution.zip", []byte("not a zip"), true},
+		{"solution.tar.gz", tarred.Bytes(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got, err := ExtractSubmissionArchive(tt.filename, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExtractSubmissionArchive(%q) error = %v, wantErr %v", tt.filename, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, want) {
+				t.Errorf("ExtractSubmissionArchive(%q) = %v, want %v", tt.filename, got, want)
+			}
+		})
+	}
+}
+
+func TestStripCommonDir(t *testing.T) {
+	tests := []struct {
+		name  string
+		files models.SubmissionFiles
+		want  models.SubmissionFiles
+	}{
+		{"shared dir", models.SubmissionFiles{"a/main.go": "", "a/b/c.go": ""}, models.SubmissionFiles{"main.go": "", "b/c.go": ""}},
+		{"different dirs", models.SubmissionFiles{"a/main.go": "", "b/c.go": ""}, models.SubmissionFiles{"a/main.go": "", "b/c.go": ""}},
+		{"top-level file", models.SubmissionFiles{"a/main.go": "", "c.go": ""}, models.SubmissionFiles{"a/main.go": "", "c.go": ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripCommonDir(tt.files); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stripCommonDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubmissionFilesRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	files := models.SubmissionFiles{"main.go": "package main", "service/service.go": "package service"}
+	if err := WriteSubmissionFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+	// Files that could not have been submitted are not read back
+	if err := os.WriteFile(filepath.Join(dir, "main_test.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadSolutionFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, files) {
+		t.Errorf("ReadSolutionFiles() = %v, want %v", got, files)
+	}
+
+	if got, err := ReadSolutionFiles(filepath.Join(dir, "missing")); err != nil || len(got) != 0 {
+		t.Errorf("ReadSolutionFiles(missing) = %v, %v, want no files", got, err)
+	}
+
+	// Replacing leaves no file of the previous submission behind
+	replacement := models.SubmissionFiles{"main.go": "package main // v2"}
+	if err := ReplaceSubmissionFiles(dir, replacement); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadSolutionFiles(dir); err != nil || !reflect.DeepEqual(got, replacement) {
+		t.Errorf("after ReplaceSubmissionFiles: %v, %v, want %v", got, err, replacement)
+	}
+}
+
+func TestCopySupportFiles(t *testing.T) {
+	challengeDir := t.TempDir()
+	for name, content := range map[string]string{
+		"testdata/users.json":   "[]",
+		"testdata/orders.json":  "[]",
+		"schema.sql":            "CREATE TABLE users (id INT);",
+		"reference/solution.go": "package main",
+	} {
+		path := filepath.Join(challengeDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		files    models.SubmissionFiles
+		want     []string
+		wantErr  bool
+	}{
+		{"directory", []string{"testdata"}, nil, []string{"testdata/orders.json", "testdata/users.json"}, false},
+		{"glob", []string{"*.sql"}, nil, []string{"schema.sql"}, false},
+		{"missing", []string{"fixtures"}, nil, nil, true},
+		{"escapes", []string{"../secret"}, nil, nil, true},
+		{"reference", []string{"reference"}, nil, nil, true},
+		{"conflict", []string{"schema.sql"}, models.SubmissionFiles{"schema.sql": ""}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runDir := t.TempDir()
+			err := copySupportFiles(challengeDir, tt.patterns, runDir, tt.files)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("copySupportFiles(%q) error = %v, wantErr %v", tt.patterns, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := ReadSolutionFiles(runDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if names := got.Names(); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("copied %q, want %q", names, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"web-ui/internal/models"
+)
+
+const (
+	// classicSolutionFile is the file a classic challenge's single-file solution is saved under
+	classicSolutionFile = "solution-template.go"
+	// packageSolutionFile is the file a package challenge's single-file solution is saved under
+	packageSolutionFile = "solution.go"
+	// packagesPath is the directory of the package tracks, relative to the web-ui directory
+	packagesPath = "../packages"
+)
+
+// ClassicChallenge returns the track view of a classic challenge
+func ClassicChallenge(challenge *models.Challenge) *models.TrackChallenge {
+	return &models.TrackChallenge{
+		Ref:               ClassicRef(challenge.ID),
+		Track:             ClassicTrack,
+		ID:                strconv.Itoa(challenge.ID),
+		Title:             challenge.Title,
+		Description:       challenge.Description,
+		Difficulty:        challenge.Difficulty,
+		Tags:              challenge.Tags,
+		Template:          challenge.Template,
+		TestFile:          challenge.TestFile,
+		HiddenTestFile:    challenge.HiddenTestFile,
+		FuzzTestFile:      challenge.FuzzTestFile,
+		LearningMaterials: challenge.LearningMaterials,
+		Hints:             challenge.Hints,
+		SupportFiles:      challenge.SupportFiles,
+		Execution:         challenge.Execution,
+		Dependencies:      challenge.Dependencies,
+		Dir:               challenge.Dir,
+		SolutionFile:      classicSolutionFile,
+		Module:            fmt.Sprintf("challenge-%d", challenge.ID),
+		Classic:           challenge,
+	}
+}
+
+// PackageTrackChallenge returns the track view of a package challenge
+func PackageTrackChallenge(challenge *models.PackageChallenge) *models.TrackChallenge {
+	return &models.TrackChallenge{
+		Ref:               PackageRef(challenge.PackageName, challenge.ID),
+		Track:             challenge.PackageName,
+		ID:                challenge.ID,
+		Title:             challenge.Title,
+		Description:       challenge.Description,
+		Difficulty:        challenge.Difficulty,
+		Tags:              challenge.Tags,
+		Template:          challenge.Template,
+		TestFile:          challenge.TestFile,
+		HiddenTestFile:    challenge.HiddenTestFile,
+		FuzzTestFile:      challenge.FuzzTestFile,
+		LearningMaterials: challenge.LearningMaterials,
+		Hints:             challenge.Hints,
+		SupportFiles:      challenge.SupportFiles,
+		Execution:         challenge.Execution,
+		Dependencies:      challenge.Dependencies,
+		Dir:               challenge.Dir,
+		SolutionFile:      packageSolutionFile,
+		Module:            challenge.PackageName + "/" + challenge.ID,
+	}
+}
+
+// ResolveChallenge returns the challenge behind a ref of either track
+func ResolveChallenge(ref string, challenges models.ChallengeMap, packageService *PackageService) (*models.TrackChallenge, error) {
+	track, challengeID, found := strings.Cut(ref, "/")
+	if !found || !submissionPathSegment.MatchString(track) || !submissionPathSegment.MatchString(challengeID) {
+		return nil, fmt.Errorf("invalid challenge ref %q", ref)
+	}
+
+	if track == ClassicTrack {
+		id, err := strconv.Atoi(challengeID)
+		if err != nil || challenges[id] == nil || ClassicRef(id) != ref {
+			return nil, fmt.Errorf("challenge %s not found", ref)
+		}
+		return ClassicChallenge(challenges[id]), nil
+	}
+
+	challenge, err := packageService.GetPackageChallenge(track, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	return PackageTrackChallenge(challenge), nil
+}
+
+// LocateChallenge returns the track view of a ref in the loaded catalog without reading the challenge's files.
+// Package challenges only get the fields that locate them: ref, track, ID, directory, solution file and module.
+func LocateChallenge(ref string, challenges models.ChallengeMap, packages models.PackageMap) (*models.TrackChallenge, bool) {
+	track, challengeID, found := strings.Cut(ref, "/")
+	if !found {
+		return nil, false
+	}
+
+	if track == ClassicTrack {
+		id, err := strconv.Atoi(challengeID)
+		if err != nil || challenges[id] == nil || ClassicRef(id) != ref {
+			return nil, false
+		}
+		return ClassicChallenge(challenges[id]), true
+	}
+
+	pkg := packages[track]
+	if pkg == nil || !containsString(pkg.LearningPath, challengeID) {
+		return nil, false
+	}
+	return &models.TrackChallenge{
+		Ref:          ref,
+		Track:        track,
+		ID:           challengeID,
+		Dir:          filepath.Join(packagesPath, track, challengeID),
+		SolutionFile: packageSolutionFile,
+		Module:       track + "/" + challengeID,
+	}, true
+}
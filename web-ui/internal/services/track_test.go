@@ -0,0 +1,46 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"web-ui/internal/models"
+)
+
+func TestLocateChallenge(t *testing.T) {
+	challenges := models.ChallengeMap{3: {ID: 3, Dir: filepath.Join("..", "challenge-3")}}
+	packages := models.PackageMap{"gin": {LearningPath: []string{"challenge-1-basic-routing"}}}
+
+	tests := []struct {
+		ref          string
+		wantOK       bool
+		wantDir      string
+		wantSolution string
+	}{
+		{"classic/3", true, filepath.Join("..", "challenge-3"), "solution-template.go"},
+		{"gin/challenge-1-basic-routing", true, filepath.Join("..", "packages", "gin", "challenge-1-basic-routing"), "solution.go"},
+		{"classic/4", false, "", ""},
+		{"classic/03", false, "", ""},
+		{"gin/challenge-9-missing", false, "", ""},
+		{"echo/challenge-1-basic-routing", false, "", ""},
+		{"classic", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			challenge, ok := LocateChallenge(tt.ref, challenges, packages)
+			if ok != tt.wantOK {
+				t.Fatalf("LocateChallenge(%q) ok = %v, want %v", tt.ref, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if challenge.Ref != tt.ref || challenge.Dir != tt.wantDir || challenge.SolutionFile != tt.wantSolution {
+				t.Errorf("LocateChallenge(%q) = %q %q %q", tt.ref, challenge.Ref, challenge.Dir, challenge.SolutionFile)
+			}
+			if want := filepath.Join(tt.wantDir, "submissions", "alice"); challenge.SubmissionDir("alice") != want {
+				t.Errorf("SubmissionDir = %q, want %q", challenge.SubmissionDir("alice"), want)
+			}
+		})
+	}
+}
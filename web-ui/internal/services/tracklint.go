@@ -0,0 +1,200 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"web-ui/internal/models"
+)
+
+// TrackFiles are the files every available challenge of a package track must have
+var TrackFiles = []string{"README.md", "metadata.json", "solution-template.go", "solution-template_test.go"}
+
+// LintTracks lints the named package tracks, or every track if none are named, returning problems by track
+func (s *PackageService) LintTracks(names ...string) (map[string][]string, error) {
+	if len(names) == 0 {
+		entries, err := os.ReadDir(s.packagesPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read packages directory: %v", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+
+	problems := make(map[string][]string)
+	for _, name := range names {
+		problems[name] = s.LintTrack(name)
+	}
+	return problems, nil
+}
+
+// LintTrack checks a package track's package.json and challenges so a new track works without Go changes
+func (s *PackageService) LintTrack(name string) []string {
+	var problems []string
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if !submissionPathSegment.MatchString(name) {
+		return []string{fmt.Sprintf("invalid track name %q", name)}
+	}
+	packagePath := filepath.Join(s.packagesPath, name)
+
+	var metadata PackageMetadata
+	if err := decodeStrict(filepath.Join(packagePath, "package.json"), &metadata); err != nil {
+		return []string{fmt.Sprintf("package.json: %v", err)}
+	}
+
+	if metadata.Name != name {
+		report("package.json: name %q does not match the directory %q", metadata.Name, name)
+	}
+	required := map[string]string{
+		"display_name":            metadata.DisplayName,
+		"description":             metadata.Description,
+		"github_url":              metadata.GitHubURL,
+		"category":                metadata.Category,
+		"icon":                    metadata.Icon,
+		"defaults.icon":           metadata.Defaults.Icon,
+		"defaults.estimated_time": metadata.Defaults.EstimatedTime,
+	}
+	for _, field := range sortedKeys(required) {
+		if strings.TrimSpace(required[field]) == "" {
+			report("package.json: %s is required", field)
+		}
+	}
+	for _, field := range []string{"icon", "defaults.icon"} {
+		if icon := required[field]; icon != "" && !strings.HasPrefix(icon, "bi-") {
+			report("package.json: %s %q is not a Bootstrap icon class", field, icon)
+		}
+	}
+	if len(metadata.LearningPath) == 0 {
+		report("package.json: learning_path is empty")
+	}
+	if len(metadata.Dependencies.Modules) == 0 {
+		report("package.json: dependencies.modules is empty")
+	}
+	for _, module := range metadata.Dependencies.Modules {
+		if !isModulePath(module) {
+			report("package.json: dependencies.modules entry %q is not a module path", module)
+		}
+	}
+	for importPath, modules := range metadata.Dependencies.Drivers {
+		for _, module := range modules {
+			if !isModulePath(module) {
+				report("package.json: driver %q of %s is not a module path", module, importPath)
+			}
+		}
+	}
+
+	// Every challenge directory must be on the learning path, and every available challenge complete
+	listed := make(map[string]bool)
+	for _, challengeID := range metadata.LearningPath {
+		if listed[challengeID] {
+			report("package.json: %s is listed twice in learning_path", challengeID)
+		}
+		listed[challengeID] = true
+	}
+	entries, err := os.ReadDir(packagePath)
+	if err != nil {
+		report("%v", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), "challenge-") && !listed[entry.Name()] {
+			report("%s: not listed in learning_path", entry.Name())
+		}
+	}
+
+	for _, challengeID := range metadata.LearningPath {
+		challengePath := filepath.Join(packagePath, challengeID)
+		if _, err := os.Stat(challengePath); os.IsNotExist(err) {
+			continue // Listed as coming soon
+		}
+		for _, problem := range s.lintTrackChallenge(challengePath, metadata.Dependencies) {
+			report("%s: %s", challengeID, problem)
+		}
+	}
+
+	return problems
+}
+
+// lintTrackChallenge checks one challenge's files, metadata and imports against the declared dependencies
+func (s *PackageService) lintTrackChallenge(challengePath string, trackDependencies models.Dependencies) []string {
+	var problems []string
+	for _, name := range TrackFiles {
+		if _, err := os.Stat(filepath.Join(challengePath, name)); err != nil {
+			problems = append(problems, fmt.Sprintf("missing %s", name))
+		}
+	}
+
+	metadata, metadataProblems := checkChallengeMetadata(challengePath, true)
+	if _, err := os.Stat(filepath.Join(challengePath, "metadata.json")); err == nil {
+		problems = append(problems, metadataProblems...)
+	}
+	dependencies := trackDependencies.Merge(metadata.Dependencies)
+
+	es := &ExecutionService{}
+	fset := token.NewFileSet()
+	for _, name := range []string{"solution-template.go", "solution-template_test.go", HiddenTestFileName} {
+		file, err := parser.ParseFile(fset, filepath.Join(challengePath, name), nil, parser.ImportsOnly)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || !es.isExternalPackage(importPath) {
+				continue
+			}
+			if !declaresModule(dependencies.Modules, importPath) {
+				problems = append(problems, fmt.Sprintf("%s: import %q is not covered by dependencies.modules", name, importPath))
+			}
+		}
+	}
+
+	return problems
+}
+
+// decodeStrict decodes a JSON file, rejecting unknown fields so typos in package.json declarations are caught
+func decodeStrict(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// isModulePath reports whether a declared module looks like "host/path" with an optional "@version"
+func isModulePath(module string) bool {
+	path, version, versioned := strings.Cut(module, "@")
+	if versioned && version == "" {
+		return false
+	}
+	host, _, found := strings.Cut(path, "/")
+	return found && strings.Contains(host, ".") && !strings.ContainsAny(path, " \t")
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeLintTestTrack writes an "echo" track with one complete challenge, then applies the given edits
+func writeLintTestTrack(t *testing.T, edit func(t *testing.T, track string)) *PackageService {
+	t.Helper()
+	dir := t.TempDir()
+	track := filepath.Join(dir, "echo")
+	challenge := filepath.Join(track, "challenge-1-routing")
+	if err := os.MkdirAll(challenge, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(track, "package.json"): `{
+  "name": "echo",
+  "display_name": "Echo",
+  "description": "High performance web framework",
+  "github_url": "https://github.com/labstack/echo",
+  "category": "web",
+  "icon": "bi-globe",
+  "learning_path": ["challenge-1-routing", "challenge-2-coming-soon"],
+  "dependencies": {"modules": ["github.com/labstack/echo/v4"]},
+  "defaults": {"icon": "bi-code", "estimated_time": "30 min"}
+}`,
+		filepath.Join(challenge, "README.md"):                 "# Routing\n",
+		filepath.Join(challenge, "metadata.json"):             `{"title": "Routing", "difficulty": "Beginner"}`,
+		filepath.Join(challenge, "solution-template.go"):      "package main\n\nimport \"github.com/labstack/echo/v4\"\n\nvar _ = echo.New\n",
+		filepath.Join(challenge, "solution-template_test.go"): "package main\n\nimport \"testing\"\n\nfunc TestRouting(t *testing.T) {}\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if edit != nil {
+		edit(t, track)
+	}
+	return &PackageService{packagesPath: dir}
+}
+
+func TestLintTrack(t *testing.T) {
+	write := func(t *testing.T, path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		edit func(t *testing.T, track string)
+		want []string
+	}{
+		{"complete track", nil, nil},
+		{"missing test file", func(t *testing.T, track string) {
+			os.Remove(filepath.Join(track, "challenge-1-routing", "solution-template_test.go"))
+		}, []string{"challenge-1-routing: missing solution-template_test.go"}},
+		{"undeclared import", func(t *testing.T, track string) {
+			write(t, filepath.Join(track, "challenge-1-routing", "solution-template_test.go"),
+				"package main\n\nimport \"github.com/stretchr/testify/assert\"\n\nvar _ = assert.Equal\n")
+		}, []string{`challenge-1-routing: solution-template_test.go: import "github.com/stretchr/testify/assert" is not covered by dependencies.modules`}},
+		{"unlisted challenge", func(t *testing.T, track string) {
+			os.MkdirAll(filepath.Join(track, "challenge-3-extra"), 0755)
+		}, []string{"challenge-3-extra: not listed in learning_path"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := writeLintTestTrack(t, tt.edit)
+			if got := service.LintTrack("echo"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LintTrack() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShippedTracksLint(t *testing.T) {
+	service := &PackageService{packagesPath: filepath.Join("..", "..", packagesPath)}
+	results, err := service.LintTracks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) == 0 {
+		t.Fatal("no tracks found")
+	}
+	for track, problems := range results {
+		for _, problem := range problems {
+			t.Errorf("%s: %s", track, problem)
+		}
+	}
+}
@@ -1,12 +1,12 @@
 package services
 
 import (
-	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"web-ui/internal/models"
 )
@@ -38,11 +38,11 @@ func (us *UserService) LoadUserAttempts(username string, challenges models.Chall
 	}
 
 	// Scan all challenge directories for this user's submissions
-	for id := range challenges {
-		if us.hasUserSubmission(username, id) {
+	for id, challenge := range challenges {
+		if us.hasUserSubmission(username, challenge) {
 			userAttempt.AttemptedIDs[id] = true
 			// Calculate score based on test results
-			score := us.calculateScore(username, id)
+			score := us.calculateScore(username, challenge)
 			userAttempt.Scores[id] = score
 		}
 	}
@@ -52,50 +52,59 @@ func (us *UserService) LoadUserAttempts(username string, challenges models.Chall
 	return userAttempt
 }
 
-// hasUserSubmission checks if a user has a submission for a challenge
-func (us *UserService) hasUserSubmission(username string, challengeID int) bool {
-	// Try different path formats to handle potential path issues
-	// Absolute path
-	submissionDir := filepath.Join("..", fmt.Sprintf("challenge-%d", challengeID), "submissions", username)
-	submissionFile := filepath.Join(submissionDir, "solution-template.go")
-
-	// Check if the file exists
-	if _, err := os.Stat(submissionFile); err == nil {
-		return true
-	}
+// submissionFile returns the path of a user's saved solution for a challenge
+func submissionFile(challenge *models.TrackChallenge, username string) string {
+	return filepath.Join(challenge.SubmissionDir(username), challenge.SolutionFile)
+}
 
-	// Alternative path (direct from workspace root)
-	altSubmissionFile := filepath.Join(fmt.Sprintf("challenge-%d", challengeID), "submissions", username, "solution-template.go")
+// hasUserSubmission checks if a user has a submission for a challenge
+func (us *UserService) hasUserSubmission(username string, challenge *models.Challenge) bool {
+	_, err := os.Stat(submissionFile(ClassicChallenge(challenge), username))
+	return err == nil
+}
 
-	if _, err := os.Stat(altSubmissionFile); err == nil {
-		return true
+// GetSubmissionTimes returns when each of a user's saved solutions was last written
+func (us *UserService) GetSubmissionTimes(username string, challenges models.ChallengeMap) map[int]time.Time {
+	times := make(map[int]time.Time)
+	for id, challenge := range challenges {
+		if info, err := os.Stat(submissionFile(ClassicChallenge(challenge), username)); err == nil {
+			times[id] = info.ModTime()
+		}
 	}
+	return times
+}
 
-	return false
+// GetCompletedRefs returns the path references of the classic and package challenges a user has saved a solution for
+func (us *UserService) GetCompletedRefs(username string, challenges models.ChallengeMap, packages models.PackageMap) map[string]bool {
+	completed := make(map[string]bool)
+	for id := range us.GetUserAttempts(username, challenges).AttemptedIDs {
+		completed[ClassicRef(id)] = true
+	}
+	for packageName, pkg := range packages {
+		for _, challengeID := range pkg.LearningPath {
+			challenge, ok := LocateChallenge(PackageRef(packageName, challengeID), challenges, packages)
+			if !ok {
+				continue
+			}
+			if _, err := os.Stat(submissionFile(challenge, username)); err == nil {
+				completed[challenge.Ref] = true
+			}
+		}
+	}
+	return completed
 }
 
 // GetExistingSolution returns the content of an existing solution file if it exists
-func (us *UserService) GetExistingSolution(username string, challengeID int) string {
+func (us *UserService) GetExistingSolution(username string, challenge *models.Challenge) string {
 	if username == "" {
 		return ""
 	}
 
-	// Try different path formats
-	// First try the relative path from web-ui
-	submissionFile := filepath.Join("..", fmt.Sprintf("challenge-%d", challengeID), "submissions", username, "solution-template.go")
-	content, err := ioutil.ReadFile(submissionFile)
-	if err == nil {
-		return string(content)
-	}
-
-	// Try alternative path from root directory
-	altSubmissionFile := filepath.Join(fmt.Sprintf("challenge-%d", challengeID), "submissions", username, "solution-template.go")
-	content, err = ioutil.ReadFile(altSubmissionFile)
-	if err == nil {
-		return string(content)
+	content, err := ioutil.ReadFile(submissionFile(ClassicChallenge(challenge), username))
+	if err != nil {
+		return ""
 	}
-
-	return ""
+	return string(content)
 }
 
 // RefreshUserAttempts clears the cache for a user and reloads their attempts
@@ -115,18 +124,12 @@ func (us *UserService) GetUserAttempts(username string, challenges models.Challe
 }
 
 // calculateScore calculates the score for a user's submission for a challenge
-func (us *UserService) calculateScore(username string, challengeID int) int {
+func (us *UserService) calculateScore(username string, challenge *models.Challenge) int {
 	// Read the scoreboard file for this challenge
-	scoreboardPath := filepath.Join("..", fmt.Sprintf("challenge-%d", challengeID), "SCOREBOARD.md")
-	content, err := ioutil.ReadFile(scoreboardPath)
+	content, err := ioutil.ReadFile(filepath.Join(ClassicChallenge(challenge).Dir, "SCOREBOARD.md"))
 	if err != nil {
-		// Try alternative path
-		scoreboardPath = filepath.Join(fmt.Sprintf("challenge-%d", challengeID), "SCOREBOARD.md")
-		content, err = ioutil.ReadFile(scoreboardPath)
-		if err != nil {
-			// No scoreboard file, return default score
-			return 50
-		}
+		// No scoreboard file, return default score
+		return 50
 	}
 
 	scoreboardContent := string(content)
@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DataDir returns the directory for server state kept outside the repository.
+// It is $GIP_DATA_DIR if set, otherwise a go-interview-practice directory in the user config directory.
+func DataDir() string {
+	if dir := os.Getenv("GIP_DATA_DIR"); dir != "" {
+		return dir
+	}
+	if configDir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(configDir, "go-interview-practice")
+	}
+	return filepath.Join(os.TempDir(), "go-interview-practice")
+}
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"web-ui/internal/server"
 	"web-ui/internal/services"
+	"web-ui/internal/utils"
 )
 
 //go:embed templates static
@@ -20,6 +24,22 @@ func main() {
 	userService := services.NewUserService()
 	executionService := services.NewExecutionService()
 	packageService := services.NewPackageService()
+	interviewService := services.NewInterviewService(filepath.Join(utils.DataDir(), "interviews"))
+	liveService := services.NewLiveService(filepath.Join(utils.DataDir(), "live"))
+	practiceService := services.NewPracticeService(filepath.Join(utils.DataDir(), "practice"))
+	pathService := services.NewPathService(os.Getenv("GIP_ENFORCE_PREREQUISITES") == "1")
+	achievementService := services.NewAchievementService(filepath.Join(utils.DataDir(), "achievements"), "../achievements.json")
+	leaderboardService := services.NewLeaderboardService(scoreboardService)
+	progressService := services.NewProgressService(filepath.Join(utils.DataDir(), "progress"))
+	similarityService := services.NewSimilarityService(filepath.Join(utils.DataDir(), "similarity"))
+	editorService := services.NewEditorService(executionService, os.Getenv("GIP_GOPLS"), services.DefaultEditorLimits)
+	draftService := services.NewDraftService(filepath.Join(utils.DataDir(), "drafts"))
+	gitService := services.NewGitService("..", services.GitOptions{
+		Remote:          os.Getenv("GIP_GIT_REMOTE"),
+		Push:            os.Getenv("GIP_GIT_PUSH") == "1",
+		DryRun:          os.Getenv("GIP_GIT_DRY_RUN") == "1",
+		MessageTemplate: os.Getenv("GIP_GIT_MESSAGE"),
+	})
 
 	// Load data
 	log.Println("Loading challenges...")
@@ -27,14 +47,48 @@ func main() {
 		log.Fatalf("Failed to load challenges: %v", err)
 	}
 
+	log.Println("Loading packages...")
+	if err := packageService.LoadPackages(); err != nil {
+		log.Fatalf("Failed to load packages: %v", err)
+	}
+
 	log.Println("Loading scoreboards...")
-	if err := scoreboardService.LoadScoreboards(challengeService.GetChallenges()); err != nil {
+	if err := scoreboardService.LoadScoreboards(challengeService.GetChallenges(), packageService.GetPackages()); err != nil {
 		log.Fatalf("Failed to load scoreboards: %v", err)
 	}
 
-	log.Println("Loading packages...")
-	if err := packageService.LoadPackages(); err != nil {
-		log.Fatalf("Failed to load packages: %v", err)
+	log.Println("Loading similarity flags...")
+	if err := similarityService.LoadFlags(); err != nil {
+		log.Fatalf("Failed to load similarity flags: %v", err)
+	}
+	similarityService.HideFlagged(scoreboardService)
+
+	for _, cycle := range pathService.Cycles(challengeService.GetChallenges(), packageService.GetPackages()) {
+		log.Printf("Warning: prerequisite cycle between %s", strings.Join(cycle, ", "))
+	}
+
+	log.Println("Loading achievement rules...")
+	if err := achievementService.LoadRules(challengeService.GetChallenges(), packageService.GetPackages()); err != nil {
+		log.Fatalf("Failed to load achievement rules: %v", err)
+	}
+
+	log.Println("Building leaderboard index...")
+	histories, err := progressService.Histories()
+	if err != nil {
+		log.Fatalf("Failed to load submission histories: %v", err)
+	}
+	if err := leaderboardService.Load(challengeService.GetChallenges(), packageService.GetPackages(), histories); err != nil {
+		log.Fatalf("Failed to build leaderboard index: %v", err)
+	}
+
+	log.Println("Loading interview sessions...")
+	if err := interviewService.LoadSessions(); err != nil {
+		log.Fatalf("Failed to load interview sessions: %v", err)
+	}
+
+	log.Println("Loading live sessions...")
+	if err := liveService.LoadSessions(); err != nil {
+		log.Fatalf("Failed to load live sessions: %v", err)
 	}
 
 	// Initialize server
@@ -45,6 +99,18 @@ func main() {
 		userService,
 		executionService,
 		packageService,
+		interviewService,
+		liveService,
+		practiceService,
+		pathService,
+		achievementService,
+		leaderboardService,
+		progressService,
+		similarityService,
+		editorService,
+		draftService,
+		gitService,
+		os.Getenv("GIP_ADMIN_TOKEN"),
 	)
 
 	// Setup routes
@@ -484,6 +484,590 @@ function formatTestOutput(output) {
         .replace(/--- PASS/g, '<span class="text-success">--- PASS</span>');
 }
 
+// Show gofmt/go vet/analyzer diagnostics as editor markers and return an HTML summary
+function renderDiagnostics(diagnostics, editor, fileName = 'solution-template.go') {
+    if (editor) {
+        const annotations = (diagnostics || [])
+            .filter(d => d.file === fileName && d.line > 0)
+            .map(d => ({
+                row: d.line - 1,
+                column: Math.max(d.column - 1, 0),
+                text: `${d.tool}: ${d.message}`,
+                type: d.severity === 'info' ? 'info' : d.severity
+            }));
+        editor.session.setAnnotations(annotations);
+    }
+
+    if (!diagnostics || diagnostics.length === 0) return '';
+
+    const badge = { error: 'danger', warning: 'warning', info: 'secondary' };
+    const rows = diagnostics.map(d => `
+        <li class="list-group-item d-flex align-items-start">
+            <span class="badge bg-${badge[d.severity] || 'secondary'} me-2">${escapeHtml(d.tool)}</span>
+            <span>${d.file ? `<code>${escapeHtml(d.file)}:${d.line}:${d.column}</code> ` : ''}${escapeHtml(d.message)}</span>
+        </li>`).join('');
+
+    return `<div class="card mb-3">
+        <div class="card-header">Static Analysis (${diagnostics.length})</div>
+        <ul class="list-group list-group-flush">${rows}</ul>
+    </div>`;
+}
+
+// Render the per-function coverage table and annotated source of a cover run
+function renderCoverage(coverage) {
+    if (!coverage) return '';
+
+    const functions = (coverage.functions || []).map(fn => `
+        <tr>
+            <td><code>${escapeHtml(fn.function)}</code></td>
+            <td class="text-muted">${escapeHtml(fn.file)}:${fn.line}</td>
+            <td class="text-end">${fn.percent.toFixed(1)}%</td>
+        </tr>`).join('');
+
+    const lineClass = { covered: 'coverage-covered', uncovered: 'coverage-uncovered' };
+    const sources = (coverage.files || []).map(file => `
+        <h6 class="mt-3">${escapeHtml(file.name)}</h6>
+        <pre class="coverage-source">${file.lines.map(line =>
+            `<span class="${lineClass[line.status] || ''}">${String(line.number).padStart(4)}  ${escapeHtml(line.text)}</span>`
+        ).join('\n')}</pre>`).join('');
+
+    return `<div class="card mb-3">
+        <div class="card-header">Coverage: ${coverage.percent.toFixed(1)}% of statements</div>
+        <div class="card-body">
+            <table class="table table-sm mb-0"><tbody>${functions}</tbody></table>
+            ${sources}
+        </div>
+    </div>`;
+}
+
+// Render benchmark results and speedups over the baseline of a bench run
+function renderBenchmark(benchmark) {
+    if (!benchmark) return '';
+
+    const comparisons = benchmark.comparisons || [];
+    const rows = comparisons.length > 0
+        ? comparisons.map(c => `
+        <tr>
+            <td><code>${escapeHtml(c.benchmark)}</code></td>
+            <td class="text-muted"><code>${escapeHtml(c.baseline)}</code></td>
+            <td class="text-end">${c.nsPerOp.toFixed(0)} ns/op</td>
+            <td class="text-end">${c.baselineNsPerOp.toFixed(0)} ns/op</td>
+            <td class="text-end">${c.significant ? c.speedup.toFixed(2) + 'x' : '~'}</td>
+            <td class="text-end text-muted">p=${c.pValue.toFixed(3)}</td>
+        </tr>`).join('')
+        : (benchmark.results || []).map(r => `
+        <tr>
+            <td><code>${escapeHtml(r.name)}</code></td>
+            <td></td>
+            <td class="text-end">${r.nsPerOp.toFixed(0)} ns/op</td>
+            <td class="text-end">${r.bytesPerOp.toFixed(0)} B/op</td>
+            <td class="text-end">${r.allocsPerOp.toFixed(0)} allocs/op</td>
+            <td></td>
+        </tr>`).join('');
+
+    let summary = '';
+    if (comparisons.length > 0) {
+        summary = `Geomean speedup: ${benchmark.geomeanSpeedup.toFixed(2)}x`;
+        if (benchmark.minSpeedup > 0) {
+            summary += ` (required ${benchmark.minSpeedup.toFixed(1)}x)`;
+        }
+    }
+
+    return `<div class="card mb-3">
+        <div class="card-header d-flex justify-content-between">
+            <span>Benchmarks</span>
+            <span class="${benchmark.meetsMinSpeedup ? 'text-success' : 'text-danger'}">${summary}</span>
+        </div>
+        <div class="card-body">
+            <table class="table table-sm mb-0"><tbody>${rows}</tbody></table>
+        </div>
+    </div>`;
+}
+
+// Render the fuzz targets of a fuzz run and the minimized failing input, if any
+function renderFuzz(fuzz) {
+    if (!fuzz) return '';
+
+    const rows = (fuzz.targets || []).map(target => `
+        <tr>
+            <td><code>${escapeHtml(target.name)}</code></td>
+            <td class="text-end">${target.elapsedMs}ms</td>
+            <td class="text-end ${target.passed ? 'text-success' : 'text-danger'}">${target.passed ? 'passed' : 'failed'}</td>
+        </tr>`).join('');
+
+    let failure = '';
+    if (fuzz.failure) {
+        failure = `<div class="alert alert-danger mt-3 mb-0">
+            <h6 class="alert-heading">${escapeHtml(fuzz.failure.target)} found a failing input</h6>
+            <p class="mb-2">${escapeHtml(fuzz.failure.message || '')}</p>
+            ${fuzz.failure.seed ? `<p class="mb-1">Add it to your regression tests:</p>
+            <pre class="mb-0"><code>${escapeHtml(fuzz.failure.seed)}</code></pre>` : ''}
+        </div>`;
+    }
+
+    return `<div class="card mb-3">
+        <div class="card-header">Fuzzing (${escapeHtml(fuzz.fuzzTime)} per target)</div>
+        <div class="card-body">
+            <table class="table table-sm mb-0"><tbody>${rows}</tbody></table>
+            ${failure}
+        </div>
+    </div>`;
+}
+
+// Render the comparison with a challenge's reference solution
+function renderDifferential(differential) {
+    if (!differential) return '';
+
+    if (!differential.divergence) {
+        return `<div class="alert alert-success mb-3">
+            ${(differential.functions || []).map(escapeHtml).join(', ')} matched the reference solution on
+            ${differential.iterations} random inputs each.
+        </div>`;
+    }
+
+    const d = differential.divergence;
+    return `<div class="alert alert-danger mb-3">
+        <h6 class="alert-heading">${escapeHtml(d.function)} differs from the reference solution</h6>
+        <p class="mb-2">${escapeHtml(d.message)}</p>
+        <pre class="mb-1"><code>${escapeHtml(d.function)}(${escapeHtml(d.input)})</code></pre>
+        <div><strong>Got:</strong> <code>${escapeHtml(d.got)}</code></div>
+        <div><strong>Want:</strong> <code>${escapeHtml(d.want)}</code></div>
+        <small class="text-muted">Seed ${differential.seed}</small>
+    </div>`;
+}
+
+// Render the quality score of a passing run and its breakdown
+function renderQuality(quality) {
+    if (!quality) return '';
+
+    const labels = {
+        complexity: 'Highest cyclomatic complexity',
+        function_length: 'Longest function (lines)',
+        findings: 'vet and analyzer findings',
+        allocations: 'Allocations per op',
+        runtime: 'Test runtime (ms)'
+    };
+    const rows = (quality.components || []).map(c => `
+        <tr class="${c.measured && c.weight > 0 ? '' : 'text-muted'}">
+            <td>${escapeHtml(labels[c.name] || c.name)}</td>
+            <td class="text-end">${c.measured ? c.value : '-'}</td>
+            <td class="text-end text-muted">target ${c.target}</td>
+            <td class="text-end">${c.measured ? c.score.toFixed(0) : 'not measured'}</td>
+            <td class="text-end text-muted">${(c.weight * 100).toFixed(0)}%</td>
+        </tr>`).join('');
+
+    return `<div class="card mb-3">
+        <div class="card-header d-flex justify-content-between">
+            <span>Quality</span>
+            <span class="fw-bold">${quality.score.toFixed(1)} / 100</span>
+        </div>
+        <div class="card-body">
+            <table class="table table-sm mb-0"><tbody>${rows}</tbody></table>
+        </div>
+    </div>`;
+}
+
+// Render hidden test outcomes, which are reported by name only
+function renderHiddenTests(hiddenTests) {
+    if (!hiddenTests || hiddenTests.length === 0) return '';
+
+    const passed = hiddenTests.filter(test => test.passed).length;
+    const rows = hiddenTests.map(test => `
+        <li class="list-group-item d-flex justify-content-between">
+            <code>${escapeHtml(test.name)}</code>
+            <span class="${test.passed ? 'text-success' : 'text-danger'}">${test.passed ? 'passed' : 'failed'}</span>
+        </li>`).join('');
+
+    return `<div class="card mb-3">
+        <div class="card-header">Hidden Tests (${passed}/${hiddenTests.length} passed)</div>
+        <ul class="list-group list-group-flush">${rows}</ul>
+    </div>`;
+}
+
+// Render the branch and commit a saved submission was recorded in, and the git commands left to run
+function renderSavedSubmissionGit(data) {
+    const git = data.git;
+    let html = '';
+    if (git) {
+        const state = git.pushed ? `pushed to <code>${escapeHtml(git.remote)}</code>`
+            : git.dryRun ? 'dry run, the branch was not created' : 'not pushed yet';
+        html += `<p class="small mb-2">Committed <code>${escapeHtml(git.sha.slice(0, 12))}</code> as ${escapeHtml(git.author)}
+            on branch <code>${escapeHtml(git.branch)}</code> (${state}).</p>`;
+    }
+    if (data.gitError) {
+        html += `<div class="alert alert-warning small py-2">Could not commit automatically: ${escapeHtml(data.gitError)}</div>`;
+    }
+    const commands = data.gitCommands || [];
+    if (commands.length) {
+        html += `<div class="bg-dark text-light p-2 rounded small"><pre class="mb-0"><code class="saved-git-commands">${escapeHtml(commands.join('\n'))}</code></pre></div>`;
+    }
+    return html;
+}
+
+// Record how many hints of a challenge the user has revealed, so they travel with exported progress
+function recordHintReveal(challengeRef, revealed) {
+    const match = document.cookie.match(/(?:^|; )username=([^;]*)/);
+    const username = match ? decodeURIComponent(match[1]) : localStorage.getItem('githubUsername');
+    if (!username) return;
+
+    fetch(`/api/v1/users/${encodeURIComponent(username)}/hints`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ challenge: challengeRef, revealed: revealed })
+    }).catch(() => {});
+}
+
+// Join the interview session named by the ?interview= parameter: show the countdown,
+// autosave the editor and lock it when the session ends
+function initInterviewSession(editor, challengeId) {
+    const id = new URLSearchParams(window.location.search).get('interview');
+    const state = { id: id, ended: false, active: () => Boolean(id) && !state.ended };
+    if (!id) return state;
+
+    const banner = document.getElementById('interview-banner');
+    let countdown, autosave;
+
+    function lock(message) {
+        state.ended = true;
+        clearInterval(countdown);
+        clearInterval(autosave);
+        editor.setReadOnly(true);
+        const runButton = document.getElementById('run-button');
+        if (runButton) runButton.disabled = true;
+        banner.innerHTML = `${escapeHtml(message)} <a href="/api/interviews/${encodeURIComponent(id)}/report">View report</a>`;
+    }
+
+    function saveSnapshot() {
+        fetch(`/api/interviews/${encodeURIComponent(id)}/snapshots`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ challengeId: challengeId, code: editor.getValue() })
+        }).then(response => {
+            if (response.status === 409) lock('The interview session has ended.');
+        });
+    }
+
+    fetch(`/api/interviews/${encodeURIComponent(id)}`)
+        .then(response => response.ok ? response.json() : Promise.reject(new Error('Interview session not found')))
+        .then(session => {
+            banner.classList.remove('d-none');
+            if (session.status !== 'active') {
+                lock('The interview session has ended.');
+                return;
+            }
+
+            const endsAt = Date.now() + session.remainingSeconds * 1000;
+            const tick = () => {
+                const remaining = Math.max(0, Math.round((endsAt - Date.now()) / 1000));
+                const minutes = Math.floor(remaining / 60);
+                const seconds = String(remaining % 60).padStart(2, '0');
+                banner.textContent = `Interview in progress: ${minutes}:${seconds} remaining`;
+                if (remaining === 0) {
+                    saveSnapshot();
+                    lock('Time is up. The interview session has ended.');
+                }
+            };
+            tick();
+            countdown = setInterval(tick, 1000);
+            autosave = setInterval(saveSnapshot, session.autosaveSeconds * 1000);
+        })
+        .catch(error => {
+            banner.classList.remove('d-none');
+            banner.textContent = error.message;
+            state.ended = true;
+        });
+
+    return state;
+}
+
+// Join the live session named by the ?live= and ?token= parameters. The candidate's edits and
+// test runs are relayed to interviewers, whose editor is read-only and who can keep private notes.
+function initLiveSession(editor) {
+    const params = new URLSearchParams(window.location.search);
+    const id = params.get('live');
+    const live = { active: false, shareRun: () => {} };
+    if (!id) return live;
+
+    const panel = document.getElementById('live-panel');
+    const status = document.getElementById('live-status');
+    const notesCard = document.getElementById('live-notes');
+    const notesList = document.getElementById('live-notes-list');
+    const noteInput = document.getElementById('live-note-input');
+    const doc = editor.session.getDocument();
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const socket = new WebSocket(`${protocol}//${window.location.host}/api/live/${encodeURIComponent(id)}/ws?token=${encodeURIComponent(params.get('token') || '')}`);
+
+    let role = null;
+    let version = 0;
+    let pending = 0; // Edits sent but not yet acknowledged
+    let applyingRemote = false;
+
+    function send(message) {
+        if (socket.readyState === WebSocket.OPEN) socket.send(JSON.stringify(message));
+    }
+
+    function applyRemote(fn) {
+        applyingRemote = true;
+        try { fn(); } finally { applyingRemote = false; }
+    }
+
+    function addNote(note) {
+        const item = document.createElement('li');
+        item.className = 'list-group-item small';
+        item.innerHTML = `<strong>${escapeHtml(note.name)}</strong> <span class="text-muted">${new Date(note.at).toLocaleTimeString()}</span><br>${escapeHtml(note.note)}`;
+        notesList.appendChild(item);
+    }
+
+    function showParticipants(participants) {
+        const names = participants.map(p => `${escapeHtml(p.name)} (${p.role})`).join(', ');
+        status.innerHTML = `Live session as <strong>${role}</strong>. Connected: ${names}`;
+    }
+
+    function showRun(result) {
+        const resultsDiv = document.getElementById('test-results');
+        if (!resultsDiv) return;
+        resultsDiv.innerHTML = `<div class="alert ${result.passed ? 'alert-success' : 'alert-danger'} mb-3">
+                <h4 class="alert-heading">${result.passed ? 'Candidate run passed' : 'Candidate run failed'}</h4>
+                <p>Execution time: ${result.executionMs || 0}ms</p>
+            </div>
+            <div class="card"><div class="card-header">Test Output</div>
+                <div class="card-body"><pre><code>${escapeHtml(result.output || '')}</code></pre></div>
+            </div>`;
+    }
+
+    editor.session.on('change', function(delta) {
+        if (applyingRemote || role !== 'candidate') return;
+        const from = doc.positionToIndex(delta.start);
+        const text = delta.lines.join('\n');
+        const change = delta.action === 'insert'
+            ? { from: from, to: from, text: text }
+            : { from: from, to: from + text.length, text: '' };
+        // Unacknowledged edits are applied by the server before this one
+        send({ type: 'delta', baseVersion: version + pending, delta: change });
+        pending++;
+    });
+
+    socket.addEventListener('message', function(event) {
+        const message = JSON.parse(event.data);
+        switch (message.type) {
+        case 'state':
+            role = message.role;
+            version = message.version;
+            pending = 0;
+            panel.classList.remove('d-none');
+            applyRemote(() => {
+                // An empty session starts from the candidate's editor
+                if (role === 'candidate' && message.version === 0) {
+                    send({ type: 'delta', baseVersion: 0, delta: { from: 0, to: 0, text: editor.getValue() } });
+                    pending++;
+                } else {
+                    editor.setValue(message.code, 1);
+                }
+            });
+            editor.setReadOnly(role !== 'candidate');
+            if (role === 'interviewer') {
+                notesCard.classList.remove('d-none');
+                notesList.innerHTML = '';
+                (message.notes || []).forEach(addNote);
+            }
+            showParticipants(message.participants);
+            break;
+        case 'delta': {
+            const start = doc.indexToPosition(message.delta.from);
+            const end = doc.indexToPosition(message.delta.to);
+            applyRemote(() => editor.session.replace(new ace.Range(start.row, start.column, end.row, end.column), message.delta.text));
+            version = message.version;
+            break;
+        }
+        case 'ack':
+            version = message.version;
+            pending = Math.max(0, pending - 1);
+            break;
+        case 'run':
+            if (role === 'interviewer') showRun(message.result);
+            break;
+        case 'note':
+            addNote(message);
+            break;
+        case 'presence':
+            showParticipants(message.participants);
+            break;
+        case 'error':
+            console.warn('Live session:', message.message);
+            break;
+        }
+    });
+
+    socket.addEventListener('close', function() {
+        status.textContent = 'Disconnected from the live session. Reload the page to reconnect.';
+    });
+
+    document.getElementById('live-note-button').addEventListener('click', function() {
+        const text = noteInput.value.trim();
+        if (!text) return;
+        send({ type: 'note', text: text });
+        noteInput.value = '';
+    });
+
+    live.active = true;
+    live.shareRun = function(result) {
+        if (role !== 'candidate') return;
+        send({ type: 'run', result: { passed: result.passed, executionMs: result.executionMs, output: result.output } });
+    };
+    return live;
+}
+
+// Connect the editor to a gopls session at /api/v1/editor/{ref} for completion, hover documentation,
+// go to definition (F12 or Ctrl-click) and diagnostics as you type. Lines and columns start at 1.
+function initEditorIntelligence(editor, ref, fileName = 'solution-template.go') {
+    const match = document.cookie.match(/(?:^|; )username=([^;]*)/);
+    const username = match ? decodeURIComponent(match[1]) : localStorage.getItem('githubUsername');
+    if (!username || typeof WebSocket === 'undefined') return;
+
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const path = ref.split('/').map(encodeURIComponent).join('/');
+    const socket = new WebSocket(`${protocol}//${window.location.host}/api/v1/editor/${path}?username=${encodeURIComponent(username)}`);
+    const pending = new Map();
+    let nextId = 1;
+    let updateTimeout = null;
+
+    function call(method, params) {
+        if (socket.readyState !== WebSocket.OPEN) return Promise.reject(new Error('not connected'));
+        const id = nextId++;
+        socket.send(JSON.stringify({ jsonrpc: '2.0', id: id, method: method, params: params }));
+        return new Promise((resolve, reject) => pending.set(id, { resolve, reject }));
+    }
+
+    function sendUpdate() {
+        socket.send(JSON.stringify({ jsonrpc: '2.0', method: 'update', params: { file: fileName, text: editor.getValue() } }));
+    }
+
+    // Queries are answered against the latest text, so a scheduled update is sent first
+    function flush() {
+        if (updateTimeout === null || socket.readyState !== WebSocket.OPEN) return;
+        clearTimeout(updateTimeout);
+        updateTimeout = null;
+        sendUpdate();
+    }
+
+    function at(position) {
+        return { file: fileName, line: position.row + 1, column: position.column + 1 };
+    }
+
+    const tooltip = document.createElement('div');
+    tooltip.className = 'card shadow-sm small p-2 d-none';
+    tooltip.style.cssText = 'position: fixed; z-index: 1000; max-width: 480px; max-height: 240px; overflow: auto; white-space: pre-wrap; font-family: monospace;';
+    document.body.appendChild(tooltip);
+
+    function showTooltip(text, x, y) {
+        tooltip.textContent = text.length > 2000 ? text.slice(0, 2000) + '…' : text;
+        tooltip.style.left = `${x + 12}px`;
+        tooltip.style.top = `${y + 12}px`;
+        tooltip.classList.remove('d-none');
+    }
+
+    function hideTooltip() {
+        tooltip.classList.add('d-none');
+    }
+
+    // The session starts from the template, so the editor's code replaces it
+    socket.addEventListener('open', function() {
+        clearTimeout(updateTimeout);
+        updateTimeout = null;
+        sendUpdate();
+    });
+
+    socket.addEventListener('message', function(event) {
+        const message = JSON.parse(event.data);
+        if (message.id !== undefined && pending.has(message.id)) {
+            const request = pending.get(message.id);
+            pending.delete(message.id);
+            if (message.error) request.reject(new Error(message.error.message));
+            else request.resolve(message.result);
+            return;
+        }
+        if (message.method === 'diagnostics' && message.params.file === fileName) {
+            renderDiagnostics(message.params.diagnostics, editor, fileName);
+        }
+    });
+
+    socket.addEventListener('close', function() {
+        pending.forEach(request => request.reject(new Error('editor session closed')));
+        pending.clear();
+        hideTooltip();
+    });
+
+    editor.session.on('change', function() {
+        clearTimeout(updateTimeout);
+        updateTimeout = setTimeout(flush, 300);
+    });
+
+    // Completion through Ace's language tools, when the extension is loaded
+    if (ace.require('ace/ext/language_tools')) {
+        editor.setOptions({ enableBasicAutocompletion: true, enableLiveAutocompletion: true });
+        editor.completers = [{
+            getCompletions: function(ed, session, position, prefix, callback) {
+                flush();
+                call('completion', at(position))
+                    .then(items => callback(null, (items || []).map((item, i) => ({
+                        caption: item.label,
+                        value: item.insertText,
+                        meta: item.kind || item.detail,
+                        docText: [item.detail, item.documentation].filter(Boolean).join('\n\n'),
+                        score: 1000 - i
+                    }))))
+                    .catch(() => callback(null, []));
+            }
+        }];
+    }
+
+    // Hover documentation after resting the mouse on an identifier
+    let hoverTimeout = null;
+    editor.on('mousemove', function(e) {
+        clearTimeout(hoverTimeout);
+        hideTooltip();
+        const x = e.domEvent.clientX;
+        const y = e.domEvent.clientY;
+        const position = e.getDocumentPosition();
+        hoverTimeout = setTimeout(() => {
+            flush();
+            call('hover', at(position))
+                .then(hover => { if (hover && hover.contents) showTooltip(hover.contents, x, y); })
+                .catch(() => {});
+        }, 600);
+    });
+    editor.container.addEventListener('mouseleave', () => { clearTimeout(hoverTimeout); hideTooltip(); });
+    editor.on('change', hideTooltip);
+
+    // Go to definition in the editor, or show where it is when it is in another file
+    function goToDefinition(position, x, y) {
+        flush();
+        call('definition', at(position))
+            .then(locations => {
+                const location = (locations || [])[0];
+                if (!location) return;
+                if (location.file === fileName) {
+                    editor.gotoLine(location.line, location.column - 1, true);
+                    editor.focus();
+                } else {
+                    const cursor = editor.renderer.textToScreenCoordinates(position.row, position.column);
+                    showTooltip(`Defined in ${location.file}:${location.line}`, x !== undefined ? x : cursor.pageX, y !== undefined ? y : cursor.pageY);
+                }
+            })
+            .catch(() => {});
+    }
+
+    editor.commands.addCommand({
+        name: 'goToDefinition',
+        bindKey: { win: 'F12', mac: 'F12' },
+        exec: ed => goToDefinition(ed.getCursorPosition())
+    });
+    editor.on('click', function(e) {
+        if (!e.domEvent.ctrlKey && !e.domEvent.metaKey) return;
+        goToDefinition(e.getDocumentPosition(), e.domEvent.clientX, e.domEvent.clientY);
+    });
+}
+
 // Handle form submissions with AJAX
 function handleFormSubmit(formElement, successCallback, errorCallback) {
     formElement.addEventListener('submit', function(e) {
@@ -677,4 +1261,101 @@ function initializeHints(hintsText) {
     } catch (error) {
         console.error('Error initializing hints:', error);
     }
-} 
\ No newline at end of file
+} 
+// Autosave the editor to /api/v1/drafts/{ref} and fill the draft history dropdown, whose versions can be restored.
+// Autosaves a few minutes apart become separate versions; restoring one adds a new version on top.
+function initDrafts(editor, ref, fileName = 'solution-template.go') {
+    const match = document.cookie.match(/(?:^|; )username=([^;]*)/);
+    const username = match ? decodeURIComponent(match[1]) : localStorage.getItem('githubUsername');
+    const dropdown = document.getElementById('draft-history');
+    if (!username) {
+        if (dropdown) dropdown.classList.add('d-none');
+        return;
+    }
+
+    const base = `/api/v1/drafts/${ref.split('/').map(encodeURIComponent).join('/')}`;
+    const query = `?username=${encodeURIComponent(username)}`;
+    let saveTimeout = null;
+    let restoring = false;
+
+    function save() {
+        saveTimeout = null;
+        return fetch(base + query, {
+            method: 'PUT',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ files: { [fileName]: editor.getValue() } })
+        }).catch(error => console.error('Error saving draft:', error));
+    }
+
+    editor.session.on('change', function() {
+        if (restoring || editor.getReadOnly()) return;
+        clearTimeout(saveTimeout);
+        saveTimeout = setTimeout(save, 2000);
+    });
+
+    // Save what is pending before the page goes away
+    window.addEventListener('beforeunload', function() {
+        if (saveTimeout === null) return;
+        clearTimeout(saveTimeout);
+        save();
+    });
+
+    if (!dropdown) return;
+    const list = document.getElementById('draft-history-list');
+
+    function renderHistory(versions) {
+        list.innerHTML = '';
+        if (!versions.length) {
+            list.innerHTML = '<li><span class="dropdown-item-text text-muted">No autosaved versions yet</span></li>';
+            return;
+        }
+        versions.forEach((version, i) => {
+            const item = document.createElement('button');
+            item.type = 'button';
+            item.className = 'dropdown-item d-flex justify-content-between gap-3';
+            const label = document.createElement('span');
+            label.textContent = `v${version.version} · ${new Date(version.savedAt).toLocaleString()}`;
+            const detail = document.createElement('span');
+            detail.className = 'text-muted';
+            detail.textContent = i === 0 ? 'current' : version.restoredFrom ? `restored v${version.restoredFrom}` : `${version.bytes} bytes`;
+            item.append(label, detail);
+            item.disabled = i === 0;
+            item.addEventListener('click', () => restore(version.version));
+            const li = document.createElement('li');
+            li.appendChild(item);
+            list.appendChild(li);
+        });
+    }
+
+    function restore(version) {
+        clearTimeout(saveTimeout);
+        saveTimeout = null;
+        fetch(`${base}/restore${query}`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ version: version })
+        })
+            .then(response => {
+                if (!response.ok) throw new Error(`HTTP ${response.status}`);
+                return response.json();
+            })
+            .then(draft => {
+                // The restored version is already saved, so the change is not autosaved again
+                restoring = true;
+                editor.setValue(draft.files[fileName] || '', -1);
+                restoring = false;
+            })
+            .catch(error => console.error('Error restoring draft:', error));
+    }
+
+    dropdown.addEventListener('show.bs.dropdown', function() {
+        // Save pending changes first, so the history ends with them
+        const pending = saveTimeout !== null;
+        clearTimeout(saveTimeout);
+        (pending ? save() : Promise.resolve())
+            .then(() => fetch(`${base}/history${query}`))
+            .then(response => response.ok ? response.json() : { versions: [] })
+            .then(history => renderHistory(history.versions || []))
+            .catch(error => console.error('Error loading draft history:', error));
+    });
+}